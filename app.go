@@ -1,16 +1,31 @@
 package copper
 
 import (
+	"context"
+	"errors"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/gocopper/copper/cclock"
 	"github.com/gocopper/copper/cconfig"
 	"github.com/gocopper/copper/clifecycle"
 	"github.com/gocopper/copper/clogger"
 )
 
+// Exit codes used by the app so that orchestrators can tell why it exited.
+const (
+	// ExitCodeRunFailed is used when one of the Runners fails.
+	ExitCodeRunFailed = 1
+
+	// ExitCodeStopFailed is used when one of the lifecycle's stop funcs fails.
+	ExitCodeStopFailed = 3
+
+	// ExitCodeStopTimedOut is used when one of the lifecycle's stop funcs does not finish before its timeout.
+	ExitCodeStopTimedOut = 4
+)
+
 // Runner provides an interface that can be run by a Copper app using the Run or Start funcs.
 // This interface is implemented by various packages within Copper including chttp.Server.
 type Runner interface {
@@ -28,12 +43,13 @@ func New() *App {
 }
 
 // NewApp creates a new Copper app and returns it along with the app's lifecycle manager,
-// config, and the logger.
+// config, and the logger. The app uses the system clock.
 func NewApp(lifecycle *clifecycle.Lifecycle, config cconfig.Loader, logger clogger.Logger) *App {
 	return &App{
 		Lifecycle: lifecycle,
 		Config:    config,
 		Logger:    logger,
+		Clock:     cclock.New(),
 	}
 }
 
@@ -44,37 +60,47 @@ type App struct {
 	Lifecycle *clifecycle.Lifecycle
 	Config    cconfig.Loader
 	Logger    clogger.Logger
+
+	// Clock is the clock used by the app's modules. It can be replaced (ex. with a fake clock in tests) before
+	// the modules are created.
+	Clock cclock.Clock
 }
 
 // Run runs the provided funcs. Once all of the functions complete their run,
 // the  lifecycle's stop funcs are also called. If any of the fns return an error,
-// the app exits with an exit code 1.
+// the app exits with ExitCodeRunFailed. If the stop funcs fail, the app exits with
+// ExitCodeStopFailed or ExitCodeStopTimedOut.
 // Run should be used when none of the fn are long-running. For long-running funcs like
 // an HTTP server, use Start.
 func (a *App) Run(fns ...Runner) {
+	a.exitIfConfigRequested()
+
 	for i := range fns {
 		err := fns[i].Run()
 		if err != nil {
 			a.Logger.Error("Failed to run", err)
-			a.Lifecycle.Stop(a.Logger)
-			os.Exit(1)
+			_ = a.Lifecycle.Stop(a.Logger)
+			os.Exit(ExitCodeRunFailed)
 		}
 	}
 
-	a.Lifecycle.Stop(a.Logger)
+	a.stop()
 }
 
 // Start runs the provided fns and then waits on the OS's INT and TERM signals from the
 // user to exit. Once the signal is received, the lifecycle's stop funcs are
 // called.
-// If any of the fns fail to run and returns an error, the app exits with exit code
-// 1.
+// If any of the fns fail to run and returns an error, the app exits with
+// ExitCodeRunFailed. If the stop funcs fail, the app exits with ExitCodeStopFailed or
+// ExitCodeStopTimedOut.
 func (a *App) Start(fns ...Runner) {
+	a.exitIfConfigRequested()
+
 	for i := range fns {
 		err := fns[i].Run()
 		if err != nil {
 			a.Logger.Error("Failed to run", err)
-			os.Exit(1)
+			os.Exit(ExitCodeRunFailed)
 		}
 	}
 
@@ -84,5 +110,48 @@ func (a *App) Start(fns ...Runner) {
 
 	<-osInt
 
-	a.Lifecycle.Stop(a.Logger)
+	a.stop()
+}
+
+// exitIfConfigRequested prints the config keys and exits if the app was started with --help-config, the effective
+// config with --dump-config, or the config reference with --config-reference. It runs before the Runners so that the
+// keys of every loaded config are listed.
+func (a *App) exitIfConfigRequested() {
+	switch {
+	case cconfig.HelpRequested(a.Config):
+		_ = cconfig.WriteKeys(os.Stdout, a.Config)
+	case cconfig.DumpRequested(a.Config):
+		_ = cconfig.WriteDump(os.Stdout, a.Config, nil)
+	case cconfig.ReferenceRequested(a.Config) != "":
+		err := cconfig.WriteReference(os.Stdout, a.Config, cconfig.ReferenceRequested(a.Config))
+		if err != nil {
+			a.Logger.Error("Failed to write config reference", err)
+			os.Exit(ExitCodeRunFailed)
+		}
+	default:
+		return
+	}
+
+	os.Exit(0)
+}
+
+func (a *App) stop() {
+	err := a.Lifecycle.Stop(a.Logger)
+	if err == nil {
+		return
+	}
+
+	os.Exit(StopExitCode(err))
+}
+
+// StopExitCode returns the exit code for an error returned by clifecycle.Lifecycle.Stop.
+func StopExitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, context.DeadlineExceeded):
+		return ExitCodeStopTimedOut
+	default:
+		return ExitCodeStopFailed
+	}
 }
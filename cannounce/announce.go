@@ -0,0 +1,222 @@
+package cannounce
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/csession"
+	"github.com/gocopper/copper/csql"
+	"gorm.io/gorm"
+)
+
+const dismissedSessionKey = "_cannounce_dismissed"
+
+// Levels that can be used for Announcement.Level
+const (
+	LevelInfo    = "info"
+	LevelWarning = "warning"
+)
+
+type (
+	// Announcement is a message that is shown between StartsAt and EndsAt. Audience is a comma-separated list of
+	// requirements (ex. "role:admin,flag:beta") that are checked with the app's chttp.Authorizer. Announcements
+	// without an audience are shown to everyone.
+	Announcement struct {
+		ID        uint   `gorm:"primaryKey"`
+		Message   string `gorm:"not null"`
+		Level     string `gorm:"not null"`
+		Audience  string
+		StartsAt  time.Time `gorm:"not null;index"`
+		EndsAt    time.Time `gorm:"not null;index"`
+		CreatedAt time.Time
+	}
+
+	// NewServiceParams holds the params needed to create a Service
+	NewServiceParams struct {
+		DB         *gorm.DB
+		Clock      cclock.Clock
+		Authorizer chttp.Authorizer
+	}
+
+	// Service schedules announcements and finds the ones that should be shown for a request.
+	Service struct {
+		db         *gorm.DB
+		clock      cclock.Clock
+		authorizer chttp.Authorizer
+	}
+
+	migration struct {
+		db *gorm.DB
+	}
+)
+
+// TableName is the table that announcements are stored in.
+func (Announcement) TableName() string {
+	return "announcements"
+}
+
+// Requirements returns the audience as a list of requirements.
+func (a *Announcement) Requirements() []string {
+	if a.Audience == "" {
+		return nil
+	}
+
+	reqs := strings.Split(a.Audience, ",")
+	for i := range reqs {
+		reqs[i] = strings.TrimSpace(reqs[i])
+	}
+
+	return reqs
+}
+
+// NewService creates a new Service.
+func NewService(p NewServiceParams) *Service {
+	return &Service{
+		db:         p.DB,
+		clock:      p.Clock,
+		authorizer: p.Authorizer,
+	}
+}
+
+// Migration returns a csql.Migration that creates the announcements table.
+func (s *Service) Migration() csql.Migration {
+	return &migration{db: s.db}
+}
+
+func (m *migration) Run() error {
+	err := m.db.AutoMigrate(&Announcement{})
+	if err != nil {
+		return cerrors.New(err, "failed to migrate announcements table", nil)
+	}
+
+	return nil
+}
+
+// Create schedules the given announcement. The level defaults to LevelInfo.
+func (s *Service) Create(ctx context.Context, a *Announcement) error {
+	if a.Level == "" {
+		a.Level = LevelInfo
+	}
+
+	err := csql.GetConn(ctx, s.db).Create(a).Error
+	if err != nil {
+		return cerrors.New(err, "failed to create announcement", nil)
+	}
+
+	return nil
+}
+
+// Delete removes the announcement with the given id.
+func (s *Service) Delete(ctx context.Context, id uint) error {
+	err := csql.GetConn(ctx, s.db).Delete(&Announcement{}, id).Error
+	if err != nil {
+		return cerrors.New(err, "failed to delete announcement", map[string]interface{}{
+			"id": id,
+		})
+	}
+
+	return nil
+}
+
+// Active returns the announcements that are scheduled for now, are meant for the request's user, and have not been
+// dismissed in the request's session. Announcements with an audience are hidden if no authorizer is configured.
+func (s *Service) Active(r *http.Request) ([]Announcement, error) {
+	var (
+		now       = s.clock.Now()
+		scheduled []Announcement
+	)
+
+	err := csql.GetConn(r.Context(), s.db).
+		Where("starts_at <= ? AND ends_at > ?", now, now).
+		Order("starts_at").
+		Find(&scheduled).Error
+	if err != nil {
+		return nil, cerrors.New(err, "failed to query announcements", nil)
+	}
+
+	dismissed := dismissedIDs(r.Context())
+	active := make([]Announcement, 0, len(scheduled))
+
+	for _, a := range scheduled {
+		if dismissed[a.ID] {
+			continue
+		}
+
+		ok, err := s.isInAudience(r, &a)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			active = append(active, a)
+		}
+	}
+
+	return active, nil
+}
+
+func (s *Service) isInAudience(r *http.Request, a *Announcement) (bool, error) {
+	reqs := a.Requirements()
+	if len(reqs) == 0 {
+		return true, nil
+	}
+
+	if s.authorizer == nil {
+		return false, nil
+	}
+
+	ok, err := s.authorizer.Authorize(r, reqs)
+	if err != nil {
+		return false, cerrors.New(err, "failed to check announcement audience", map[string]interface{}{
+			"id": a.ID,
+		})
+	}
+
+	return ok, nil
+}
+
+// Dismiss hides the announcement for the rest of the request's session.
+func (s *Service) Dismiss(ctx context.Context, id uint) error {
+	sess := csession.FromCtx(ctx)
+	if sess == nil {
+		return cerrors.New(nil, "announcements can only be dismissed with a session", nil)
+	}
+
+	var ids []uint
+
+	_, err := sess.Get(dismissedSessionKey, &ids)
+	if err != nil {
+		return err
+	}
+
+	for _, dismissed := range ids {
+		if dismissed == id {
+			return nil
+		}
+	}
+
+	return sess.Set(dismissedSessionKey, append(ids, id))
+}
+
+func dismissedIDs(ctx context.Context) map[uint]bool {
+	sess := csession.FromCtx(ctx)
+	if sess == nil {
+		return nil
+	}
+
+	var ids []uint
+
+	_, _ = sess.Get(dismissedSessionKey, &ids)
+
+	out := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		out[id] = true
+	}
+
+	return out
+}
@@ -0,0 +1,135 @@
+package cannounce_test
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cannounce"
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clifecycle"
+	"github.com/gocopper/copper/clogger"
+	"github.com/gocopper/copper/csession"
+	"github.com/gocopper/copper/csql"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAuthorizer struct{}
+
+func (testAuthorizer) Authorize(r *http.Request, requirements []string) (bool, error) {
+	return r.Header.Get("X-Role") == strings.TrimPrefix(requirements[0], "role:"), nil
+}
+
+func TestService_Active(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		logger = clogger.New()
+		lc     = clifecycle.New()
+		now    = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock  = cclock.NewFake(now)
+	)
+
+	defer lc.Stop(logger)
+
+	db, err := csql.NewDBConnection(lc, csql.Config{
+		Dialect: "sqlite",
+		DSN:     ":memory:",
+	}, logger)
+	assert.NoError(t, err)
+
+	svc := cannounce.NewService(cannounce.NewServiceParams{
+		DB:         db,
+		Clock:      clock,
+		Authorizer: testAuthorizer{},
+	})
+	assert.NoError(t, svc.Migration().Run())
+
+	for _, a := range []cannounce.Announcement{
+		{Message: "Maintenance tonight", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		{Message: "Admins only", Audience: "role:admin", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		{Message: "Later", StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)},
+	} {
+		a := a
+		assert.NoError(t, svc.Create(ctx, &a))
+	}
+
+	messages := func(r *http.Request) []string {
+		active, err := svc.Active(r)
+		assert.NoError(t, err)
+
+		out := make([]string, 0, len(active))
+		for _, a := range active {
+			out = append(out, a.Message)
+		}
+
+		return out
+	}
+
+	var (
+		sess = csession.New()
+		req  = httptest.NewRequest(http.MethodGet, "/", nil).
+			WithContext(csession.WithSession(ctx, sess))
+	)
+
+	assert.Equal(t, []string{"Maintenance tonight"}, messages(req))
+
+	req.Header.Set("X-Role", "admin")
+	assert.Equal(t, []string{"Maintenance tonight", "Admins only"}, messages(req))
+
+	assert.NoError(t, svc.Dismiss(req.Context(), 1))
+	assert.Equal(t, []string{"Admins only"}, messages(req))
+
+	html, err := cannounce.NewBannerRenderFunc(svc, logger).Func(req).(func() (template.HTML, error))()
+	assert.NoError(t, err)
+	assert.Contains(t, string(html), "Admins only")
+	assert.Contains(t, string(html), `action="/announcements/2/dismiss"`)
+
+	clock.Advance(time.Hour)
+	assert.Equal(t, []string{"Later"}, messages(req))
+}
+
+func TestRouter_HandleDismiss(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger = clogger.NewNoop()
+		sess   = csession.New()
+		router = cannounce.NewRouter(cannounce.NewRouterParams{
+			Service: cannounce.NewService(cannounce.NewServiceParams{Clock: cclock.New()}),
+			RW:      chttptest.NewReaderWriter(t),
+			Logger:  logger,
+		})
+		handler = chttp.NewHandler(chttp.NewHandlerParams{
+			Routers: []chttp.Router{router},
+			GlobalMiddlewares: []chttp.Middleware{chttp.HandleMiddleware(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					next.ServeHTTP(w, r.WithContext(csession.WithSession(r.Context(), sess)))
+				})
+			})},
+			Logger: logger,
+		})
+		req  = httptest.NewRequest(http.MethodPost, "/announcements/3/dismiss", nil)
+		resp = httptest.NewRecorder()
+	)
+
+	req.Header.Set("Referer", "https://evil.example.com/posts?page=2")
+
+	handler.ServeHTTP(resp, req)
+
+	var dismissed []uint
+
+	ok, err := sess.Get("_cannounce_dismissed", &dismissed)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []uint{3}, dismissed)
+	assert.Equal(t, http.StatusSeeOther, resp.Code)
+	assert.Equal(t, "/posts?page=2", resp.Header().Get("Location"))
+}
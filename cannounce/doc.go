@@ -0,0 +1,3 @@
+// Package cannounce schedules site-wide announcements (ex. planned maintenance) that are shown in a banner to the
+// users in their audience until they are dismissed.
+package cannounce
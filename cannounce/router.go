@@ -0,0 +1,122 @@
+package cannounce
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/clogger"
+	"github.com/gocopper/copper/csession"
+)
+
+//nolint:gochecknoglobals
+var bannerTmpl = template.Must(template.New("banner").Parse(`{{ range .Announcements -}}
+<div class="announcement announcement-{{ .Level }}" role="status">
+  <span>{{ .Message }}</span>
+  <form method="post" action="{{ $.DismissPath }}/{{ .ID }}/dismiss">
+    <input type="hidden" name="csrf_token" value="{{ $.CSRFToken }}">
+    <button type="submit" aria-label="Dismiss">&times;</button>
+  </form>
+</div>
+{{ end }}`))
+
+type (
+	// NewRouterParams holds the params needed to create a Router
+	NewRouterParams struct {
+		Service *Service
+		RW      *chttp.ReaderWriter
+		Logger  clogger.Logger
+	}
+
+	// Router provides the route that dismisses announcements.
+	Router struct {
+		service *Service
+		rw      *chttp.ReaderWriter
+		logger  clogger.Logger
+	}
+)
+
+// NewRouter creates a new Router.
+func NewRouter(p NewRouterParams) *Router {
+	return &Router{
+		service: p.Service,
+		rw:      p.RW,
+		logger:  p.Logger,
+	}
+}
+
+// Routes returns the announcement routes.
+func (ro *Router) Routes() []chttp.Route {
+	return []chttp.Route{
+		{
+			Path:    "/announcements/{id:[0-9]+}/dismiss",
+			Methods: []string{http.MethodPost},
+			Handler: ro.HandleDismiss,
+		},
+	}
+}
+
+// HandleDismiss dismisses the announcement for the session and redirects back to the page it was dismissed on.
+func (ro *Router) HandleDismiss(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chttp.URLParams(r)["id"], 10, 0)
+	if err != nil {
+		ro.rw.WriteJSON(w, chttp.WriteJSONParams{StatusCode: http.StatusBadRequest, Data: err})
+		return
+	}
+
+	err = ro.service.Dismiss(r.Context(), uint(id))
+	if err != nil {
+		ro.logger.Error("Failed to dismiss announcement", err)
+		ro.rw.WriteJSON(w, chttp.WriteJSONParams{StatusCode: http.StatusInternalServerError})
+
+		return
+	}
+
+	// Only the referer's path is used so that the redirect cannot lead to another site.
+	back := "/"
+	if u, err := url.Parse(r.Referer()); err == nil && strings.HasPrefix(u.Path, "/") {
+		back = u.RequestURI()
+	}
+
+	http.Redirect(w, r, back, http.StatusSeeOther)
+}
+
+// NewBannerRenderFunc creates the announcements template function that renders the active announcements with a
+// dismiss button. For example, in a layout:
+//
+//	<body>{{ announcements }} ...</body>
+func NewBannerRenderFunc(service *Service, logger clogger.Logger) chttp.HTMLRenderFunc {
+	return chttp.HTMLRenderFunc{
+		Name: "announcements",
+		Func: func(r *http.Request) interface{} {
+			return func() (template.HTML, error) {
+				active, err := service.Active(r)
+				if err != nil {
+					logger.Warn("Failed to load announcements", err)
+					return "", nil
+				}
+
+				if len(active) == 0 {
+					return "", nil
+				}
+
+				var out strings.Builder
+
+				err = bannerTmpl.Execute(&out, map[string]interface{}{
+					"Announcements": active,
+					"DismissPath":   chttp.WithBasePath(r.Context(), "/announcements"),
+					"CSRFToken":     csession.CSRFToken(r.Context()),
+				})
+				if err != nil {
+					return "", err
+				}
+
+				// nolint:gosec
+				return template.HTML(out.String()), nil
+			}
+		},
+	}
+}
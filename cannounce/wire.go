@@ -0,0 +1,11 @@
+package cannounce
+
+import "github.com/google/wire"
+
+// WireModule can be used as part of google/wire setup.
+var WireModule = wire.NewSet( //nolint:gochecknoglobals
+	wire.Struct(new(NewServiceParams), "*"),
+	NewService,
+	wire.Struct(new(NewRouterParams), "*"),
+	NewRouter,
+)
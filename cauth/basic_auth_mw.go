@@ -0,0 +1,81 @@
+package cauth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+
+	"github.com/gocopper/copper/clogger"
+)
+
+type (
+	// NewBasicAuthMiddlewareParams holds the params needed to create BasicAuthMiddleware
+	NewBasicAuthMiddlewareParams struct {
+		Config Config
+		Logger clogger.Logger
+	}
+
+	// BasicAuthMiddleware protects routes (ex. debug, metrics, or admin routes) with HTTP basic auth using the
+	// users in the config. It is meant for small deployments where a full auth system is not needed. If no users are
+	// configured, all requests are rejected.
+	BasicAuthMiddleware struct {
+		users  map[string]string
+		realm  string
+		logger clogger.Logger
+	}
+)
+
+// NewBasicAuthMiddleware creates a new BasicAuthMiddleware.
+func NewBasicAuthMiddleware(p NewBasicAuthMiddlewareParams) *BasicAuthMiddleware {
+	return &BasicAuthMiddleware{
+		users:  p.Config.Basic.Users,
+		realm:  p.Config.Basic.Realm,
+		logger: p.Logger,
+	}
+}
+
+// Handle responds with Unauthorized unless the request has the credentials of one of the configured users.
+func (mw *BasicAuthMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if ok && mw.check(username, password) {
+			next.ServeHTTP(w, r.WithContext(clogger.WithCtxTags(r.Context(), map[string]interface{}{
+				"userID": username,
+			})))
+
+			return
+		}
+
+		if ok {
+			mw.logger.WithTags(map[string]interface{}{
+				"url":  r.URL.Path,
+				"user": username,
+			}).Warn("Rejected request with invalid basic auth credentials", nil)
+		}
+
+		writeUnauthorized(w, "Basic realm="+strconv.Quote(mw.realm)+`, charset="UTF-8"`)
+	})
+}
+
+// check compares the credentials against every user in constant time so that the response time does not reveal
+// which usernames exist. The values are hashed first so that their lengths are not revealed either.
+func (mw *BasicAuthMiddleware) check(username, password string) bool {
+	var (
+		match        int
+		usernameHash = sha256.Sum256([]byte(username))
+		passwordHash = sha256.Sum256([]byte(password))
+	)
+
+	for u, p := range mw.users {
+		var (
+			uHash = sha256.Sum256([]byte(u))
+			pHash = sha256.Sum256([]byte(p))
+		)
+
+		match |= subtle.ConstantTimeCompare(usernameHash[:], uHash[:]) &
+			subtle.ConstantTimeCompare(passwordHash[:], pHash[:])
+	}
+
+	return match == 1
+}
@@ -0,0 +1,56 @@
+package cauth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/cauth"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasicAuthMiddleware(t *testing.T) {
+	t.Parallel()
+
+	handler := cauth.NewBasicAuthMiddleware(cauth.NewBasicAuthMiddlewareParams{
+		Config: cauth.Config{Basic: cauth.BasicAuthConfig{
+			Users: map[string]string{"admin": "secret"},
+			Realm: "Admin",
+		}},
+		Logger: clogger.NewNoop(),
+	}).Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	testCases := map[string]struct {
+		username string
+		password string
+		wantCode int
+	}{
+		"valid":          {username: "admin", password: "secret", wantCode: http.StatusOK},
+		"wrong password": {username: "admin", password: "wrong", wantCode: http.StatusUnauthorized},
+		"unknown user":   {username: "root", password: "secret", wantCode: http.StatusUnauthorized},
+		"missing":        {wantCode: http.StatusUnauthorized},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tc.username != "" {
+				req.SetBasicAuth(tc.username, tc.password)
+			}
+
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.wantCode, resp.Code)
+
+			if tc.wantCode == http.StatusUnauthorized {
+				assert.Equal(t, `Basic realm="Admin", charset="UTF-8"`, resp.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
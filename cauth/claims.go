@@ -0,0 +1,165 @@
+package cauth
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+type ctxKey string
+
+const claimsCtxKey = ctxKey("cauth/claims")
+
+// Claims holds the claims of a verified JWT. Numbers are kept as json.Number so that they can be read back without
+// losing precision.
+type Claims map[string]interface{}
+
+// ClaimsFromCtx returns the claims stored by JWTMiddleware. It returns nil if the request was not authenticated.
+func ClaimsFromCtx(ctx context.Context) Claims {
+	claims, ok := ctx.Value(claimsCtxKey).(Claims)
+	if !ok {
+		return nil
+	}
+
+	return claims
+}
+
+// WithClaims returns a context that holds the given claims.
+func WithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, claimsCtxKey, claims)
+}
+
+// Subject returns the sub claim.
+func (c Claims) Subject() string {
+	return c.String("sub")
+}
+
+// Issuer returns the iss claim.
+func (c Claims) Issuer() string {
+	return c.String("iss")
+}
+
+// Audience returns the aud claim, which can be a single string or a list of strings in the token.
+func (c Claims) Audience() []string {
+	if aud := c.String("aud"); aud != "" {
+		return []string{aud}
+	}
+
+	return c.Strings("aud")
+}
+
+// ExpiresAt returns the exp claim or the zero time if it is not set.
+func (c Claims) ExpiresAt() time.Time {
+	return c.Time("exp")
+}
+
+// String returns the claim with the given key or an empty string if it is not set or is not a string.
+func (c Claims) String(key string) string {
+	val, _ := c[key].(string)
+
+	return val
+}
+
+// Strings returns the claim with the given key if it is a list of strings.
+func (c Claims) Strings(key string) []string {
+	vals, ok := c[key].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	out := make([]string, 0, len(vals))
+
+	for _, v := range vals {
+		s, ok := v.(string)
+		if !ok {
+			return nil
+		}
+
+		out = append(out, s)
+	}
+
+	return out
+}
+
+// Int returns the claim with the given key or 0 if it is not set or is not an integer.
+func (c Claims) Int(key string) int64 {
+	switch v := c[key].(type) {
+	case json.Number:
+		i, _ := v.Int64()
+		return i
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	default:
+		return 0
+	}
+}
+
+// Bool returns the claim with the given key or false if it is not set or is not a bool.
+func (c Claims) Bool(key string) bool {
+	val, _ := c[key].(bool)
+
+	return val
+}
+
+// Time returns the claim with the given key as a time or the zero time if it is not set or is not a NumericDate
+// (seconds since epoch, which can have a fractional part).
+func (c Claims) Time(key string) time.Time {
+	t, _ := c.numericDate(key)
+
+	return t
+}
+
+// numericDate returns the claim with the given key as a time or the zero time if it is not set. It returns false if the
+// claim is set but is not a number.
+func (c Claims) numericDate(key string) (time.Time, bool) {
+	var secs float64
+
+	switch v := c[key].(type) {
+	case nil:
+		_, ok := c[key]
+		return time.Time{}, !ok
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return time.Unix(i, 0), true
+		}
+
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		secs = f
+	case float64:
+		secs = v
+	case int64:
+		return time.Unix(v, 0), true
+	case int:
+		return time.Unix(int64(v), 0), true
+	default:
+		return time.Time{}, false
+	}
+
+	whole, frac := math.Modf(secs)
+
+	return time.Unix(int64(whole), int64(frac*float64(time.Second))), true
+}
+
+// Decode decodes the claims into dest, which should be a pointer to a struct with json tags.
+func (c Claims) Decode(dest interface{}) error {
+	out, err := json.Marshal(c)
+	if err != nil {
+		return cerrors.New(err, "failed to marshal claims", nil)
+	}
+
+	err = json.Unmarshal(out, dest)
+	if err != nil {
+		return cerrors.New(err, "failed to unmarshal claims", nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package cauth
+
+import (
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cerrors"
+)
+
+const (
+	defaultJWKSRefreshInterval = time.Hour
+	defaultBasicAuthRealm      = "Restricted"
+)
+
+// LoadConfig loads the cauth config from the app config
+func LoadConfig(appConfig cconfig.Loader) (Config, error) {
+	var config Config
+
+	err := appConfig.Load("cauth", &config)
+	if err != nil {
+		return Config{}, cerrors.New(err, "failed to load auth config", nil)
+	}
+
+	if config.Basic.Realm == "" {
+		config.Basic.Realm = defaultBasicAuthRealm
+	}
+
+	if config.JWT.JWKSRefreshInterval == 0 {
+		config.JWT.JWKSRefreshInterval = defaultJWKSRefreshInterval
+	}
+
+	return config, nil
+}
+
+// Config configures the cauth module
+type Config struct {
+	JWT   JWTConfig       `toml:"jwt"`
+	Basic BasicAuthConfig `toml:"basic"`
+}
+
+// JWTConfig configures how JWTs are verified and signed. For example, to verify tokens from an identity provider:
+//
+//	[cauth.jwt]
+//	jwks_url = "https://example.auth0.com/.well-known/jwks.json"
+//	issuer = "https://example.auth0.com/"
+//	audience = "https://api.example.com"
+type JWTConfig struct {
+	// Secret verifies and signs HS256 tokens.
+	Secret string `toml:"secret"`
+
+	// PublicKey (PEM) verifies RS256 tokens. PrivateKey (PEM) signs them.
+	PublicKey  string `toml:"public_key"`
+	PrivateKey string `toml:"private_key"`
+
+	// JWKSURL is fetched to find the keys that verify RS256 tokens by their kid. The keys are refetched every
+	// JWKSRefreshInterval (defaults to 1h), or sooner when a token uses an unknown kid.
+	JWKSURL             string        `toml:"jwks_url"`
+	JWKSRefreshInterval time.Duration `toml:"jwks_refresh_interval"`
+
+	// Issuer and Audience are checked against the iss and aud claims, if they are set. They are also set on the
+	// tokens created by JWTSigner.
+	Issuer   string `toml:"issuer"`
+	Audience string `toml:"audience"`
+
+	// Leeway allows for clock skew when the exp and nbf claims are checked.
+	Leeway time.Duration `toml:"leeway"`
+
+	// TTL sets the exp claim of tokens created by JWTSigner. Tokens do not expire if it is not set.
+	TTL time.Duration `toml:"ttl"`
+}
+
+// BasicAuthConfig configures the users that can sign in with BasicAuthMiddleware. For example:
+//
+//	[cauth.basic]
+//	realm = "Admin"
+//	users = { admin = "correct-horse-battery-staple" }
+type BasicAuthConfig struct {
+	// Users maps usernames to their passwords.
+	Users map[string]string `toml:"users"`
+
+	// Realm is sent to the browser in the WWW-Authenticate header. It defaults to "Restricted".
+	Realm string `toml:"realm"`
+}
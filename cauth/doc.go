@@ -0,0 +1,3 @@
+// Package cauth authenticates HTTP requests. It provides middlewares that verify credentials (ex. bearer JWTs) and
+// store the authenticated identity in the request context, along with helpers to issue credentials.
+package cauth
@@ -0,0 +1,204 @@
+package cauth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cerrors"
+)
+
+const (
+	jwksFetchTimeout = 10 * time.Second
+
+	// jwksMinRefetchInterval limits how often unknown kids can trigger a refetch so that tokens with random kids
+	// cannot be used to flood the identity provider.
+	jwksMinRefetchInterval = time.Minute
+)
+
+type (
+	jwks struct {
+		url             string
+		refreshInterval time.Duration
+		clock           cclock.Clock
+		client          *http.Client
+
+		mu          sync.Mutex
+		keys        map[string]*rsa.PublicKey
+		fetchedAt   time.Time
+		attemptedAt time.Time
+		fetchErr    error
+		fetching    chan struct{}
+	}
+
+	jwksResponse struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+)
+
+func newJWKS(url string, refreshInterval time.Duration, clock cclock.Clock) *jwks {
+	return &jwks{
+		url:             url,
+		refreshInterval: refreshInterval,
+		clock:           clock,
+		client:          &http.Client{Timeout: jwksFetchTimeout},
+	}
+}
+
+// key returns the key with the given kid. If the kid is empty, the only key in the set is returned. The keys are
+// refetched once the refresh interval passes or when the kid is unknown, at most once per jwksMinRefetchInterval
+// whether or not the fetch succeeds. If a refetch fails, the keys from the last successful fetch are still used.
+func (j *jwks) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	j.mu.Lock()
+
+	var (
+		now   = j.clock.Now()
+		stale = j.keys == nil || now.Sub(j.fetchedAt) >= j.refreshInterval
+	)
+
+	cached, ok := j.lookup(kid)
+	if ok && !stale {
+		j.mu.Unlock()
+		return cached, nil
+	}
+
+	// Requests without a cached key wait for a fetch that is already in progress instead of failing
+	if fetching := j.fetching; fetching != nil && !ok {
+		j.mu.Unlock()
+
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+			return nil, cerrors.New(ctx.Err(), "failed to wait for jwks", nil)
+		}
+
+		return j.cachedKey(kid)
+	}
+
+	canFetch := j.fetching == nil && (j.attemptedAt.IsZero() || now.Sub(j.attemptedAt) >= jwksMinRefetchInterval)
+	if !canFetch {
+		j.mu.Unlock()
+		return j.cachedKey(kid)
+	}
+
+	fetching := make(chan struct{})
+	j.attemptedAt = now
+	j.fetching = fetching
+
+	j.mu.Unlock()
+
+	// The keys are fetched without holding the lock so that requests with known kids are not blocked by the fetch
+	keys, fetchErr := j.fetch(ctx)
+
+	j.mu.Lock()
+	if fetchErr == nil {
+		j.keys = keys
+		j.fetchedAt = now
+	}
+	j.fetchErr = fetchErr
+	j.fetching = nil
+	j.mu.Unlock()
+
+	close(fetching)
+
+	return j.cachedKey(kid)
+}
+
+// cachedKey returns the cached key with the given kid. If there is none and the last fetch failed, it returns the
+// fetch's error.
+func (j *jwks) cachedKey(kid string) (*rsa.PublicKey, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if key, ok := j.lookup(kid); ok {
+		return key, nil
+	}
+
+	if j.fetchErr != nil {
+		return nil, j.fetchErr
+	}
+
+	return nil, cerrors.New(ErrInvalidToken, "unknown signing key", map[string]interface{}{
+		"kid": kid,
+	})
+}
+
+func (j *jwks) lookup(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key, true
+		}
+	}
+
+	key, ok := j.keys[kid]
+
+	return key, ok
+}
+
+func (j *jwks) fetch(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to create jwks request", nil)
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to fetch jwks", map[string]interface{}{
+			"url": j.url,
+		})
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, cerrors.New(nil, "failed to fetch jwks", map[string]interface{}{
+			"url":    j.url,
+			"status": resp.StatusCode,
+		})
+	}
+
+	var body jwksResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to decode jwks", map[string]interface{}{
+			"url": j.url,
+		})
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(body.Keys))
+
+	for _, k := range body.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	return keys, nil
+}
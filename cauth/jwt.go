@@ -0,0 +1,387 @@
+package cauth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"strings"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cerrors"
+)
+
+// Signing algorithms supported by JWTVerifier and JWTSigner.
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+// ErrInvalidToken is returned by JWTVerifier.Verify if the token is malformed, has an invalid signature, or fails the
+// claim checks.
+var ErrInvalidToken = errors.New("invalid token")
+
+type (
+	jwtHeader struct {
+		Alg string `json:"alg"`
+		Typ string `json:"typ,omitempty"`
+		Kid string `json:"kid,omitempty"`
+	}
+
+	// NewJWTVerifierParams holds the params needed to create a JWTVerifier
+	NewJWTVerifierParams struct {
+		Config Config
+		Clock  cclock.Clock
+	}
+
+	// JWTVerifier verifies the signature and claims of JWTs.
+	JWTVerifier struct {
+		config    JWTConfig
+		clock     cclock.Clock
+		publicKey *rsa.PublicKey
+		jwks      *jwks
+	}
+
+	// NewJWTSignerParams holds the params needed to create a JWTSigner
+	NewJWTSignerParams struct {
+		Config Config
+		Clock  cclock.Clock
+	}
+
+	// JWTSigner creates signed JWTs. It signs with RS256 if a private key is configured and HS256 otherwise. It is
+	// not part of WireModule since services that only verify tokens do not have a signing key.
+	JWTSigner struct {
+		config     JWTConfig
+		clock      cclock.Clock
+		privateKey *rsa.PrivateKey
+	}
+)
+
+// NewJWTVerifier creates a new JWTVerifier. At least one of the secret, public key, or JWKS URL must be configured.
+func NewJWTVerifier(p NewJWTVerifierParams) (*JWTVerifier, error) {
+	v := JWTVerifier{
+		config: p.Config.JWT,
+		clock:  p.Clock,
+	}
+
+	if v.config.PublicKey != "" {
+		key, err := parseRSAPublicKey(v.config.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+
+		v.publicKey = key
+	}
+
+	if v.config.JWKSURL != "" {
+		v.jwks = newJWKS(v.config.JWKSURL, v.config.JWKSRefreshInterval, p.Clock)
+	}
+
+	if v.config.Secret == "" && v.publicKey == nil && v.jwks == nil {
+		return nil, cerrors.New(nil, "jwt verifier requires a secret, public key, or jwks url", nil)
+	}
+
+	return &v, nil
+}
+
+// Verify checks the token's signature and its exp, nbf, iss, and aud claims, and returns its claims. If the token is
+// not valid, the returned error wraps ErrInvalidToken.
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 { //nolint:gomnd
+		return nil, invalidToken("token is malformed", nil)
+	}
+
+	var header jwtHeader
+
+	err := decodeJWTPart(parts[0], &header)
+	if err != nil {
+		return nil, invalidToken("failed to decode header", err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalidToken("failed to decode signature", err)
+	}
+
+	err = v.verifySignature(ctx, header, parts[0]+"."+parts[1], sig)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+
+	err = decodeJWTPart(parts[1], &claims)
+	if err != nil {
+		return nil, invalidToken("failed to decode claims", err)
+	}
+
+	err = v.checkClaims(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (v *JWTVerifier) verifySignature(ctx context.Context, header jwtHeader, signed string, sig []byte) error {
+	switch header.Alg {
+	case AlgHS256:
+		if v.config.Secret == "" {
+			return invalidToken("hs256 tokens are not accepted", nil)
+		}
+
+		if !hmac.Equal(sig, signHS256(v.config.Secret, signed)) {
+			return invalidToken("signature is invalid", nil)
+		}
+
+		return nil
+	case AlgRS256:
+		key, err := v.rsaPublicKey(ctx, header.Kid)
+		if err != nil {
+			return err
+		}
+
+		digest := sha256.Sum256([]byte(signed))
+
+		err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig)
+		if err != nil {
+			return invalidToken("signature is invalid", err)
+		}
+
+		return nil
+	default:
+		return cerrors.New(ErrInvalidToken, "unsupported signing algorithm", map[string]interface{}{
+			"alg": header.Alg,
+		})
+	}
+}
+
+func (v *JWTVerifier) rsaPublicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	if v.jwks != nil && (kid != "" || v.publicKey == nil) {
+		key, err := v.jwks.key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+
+		return key, nil
+	}
+
+	if v.publicKey == nil {
+		return nil, invalidToken("rs256 tokens are not accepted", nil)
+	}
+
+	return v.publicKey, nil
+}
+
+func (v *JWTVerifier) checkClaims(claims Claims) error {
+	now := v.clock.Now()
+
+	// A claim that is set but cannot be parsed is rejected instead of being treated as absent, which would let the
+	// token be used forever.
+	exp, ok := claims.numericDate("exp")
+	if !ok {
+		return invalidToken("token has an invalid exp claim", nil)
+	}
+
+	if !exp.IsZero() && !now.Before(exp.Add(v.config.Leeway)) {
+		return invalidToken("token has expired", nil)
+	}
+
+	nbf, ok := claims.numericDate("nbf")
+	if !ok {
+		return invalidToken("token has an invalid nbf claim", nil)
+	}
+
+	if !nbf.IsZero() && now.Add(v.config.Leeway).Before(nbf) {
+		return invalidToken("token is not valid yet", nil)
+	}
+
+	if v.config.Issuer != "" && claims.Issuer() != v.config.Issuer {
+		return cerrors.New(ErrInvalidToken, "token has an unexpected issuer", map[string]interface{}{
+			"iss": claims.Issuer(),
+		})
+	}
+
+	if v.config.Audience == "" {
+		return nil
+	}
+
+	for _, aud := range claims.Audience() {
+		if aud == v.config.Audience {
+			return nil
+		}
+	}
+
+	return cerrors.New(ErrInvalidToken, "token has an unexpected audience", map[string]interface{}{
+		"aud": claims.Audience(),
+	})
+}
+
+// NewJWTSigner creates a new JWTSigner. Either the secret or the private key must be configured.
+func NewJWTSigner(p NewJWTSignerParams) (*JWTSigner, error) {
+	s := JWTSigner{
+		config: p.Config.JWT,
+		clock:  p.Clock,
+	}
+
+	if s.config.PrivateKey != "" {
+		key, err := parseRSAPrivateKey(s.config.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		s.privateKey = key
+	}
+
+	if s.config.Secret == "" && s.privateKey == nil {
+		return nil, cerrors.New(nil, "jwt signer requires a secret or private key", nil)
+	}
+
+	return &s, nil
+}
+
+// Sign creates a signed token with the given claims. The iat claim is set to the current time, and the iss, aud, and
+// exp claims are set from the config unless the claims already have them.
+func (s *JWTSigner) Sign(claims Claims) (string, error) {
+	now := s.clock.Now()
+
+	out := make(Claims, len(claims)+4) //nolint:gomnd
+	for k, v := range claims {
+		out[k] = v
+	}
+
+	out["iat"] = now.Unix()
+
+	if _, ok := out["iss"]; !ok && s.config.Issuer != "" {
+		out["iss"] = s.config.Issuer
+	}
+
+	if _, ok := out["aud"]; !ok && s.config.Audience != "" {
+		out["aud"] = s.config.Audience
+	}
+
+	if _, ok := out["exp"]; !ok && s.config.TTL > 0 {
+		out["exp"] = now.Add(s.config.TTL).Unix()
+	}
+
+	header := jwtHeader{Alg: AlgHS256, Typ: "JWT"}
+	if s.privateKey != nil {
+		header.Alg = AlgRS256
+	}
+
+	encodedHeader, err := encodeJWTPart(header)
+	if err != nil {
+		return "", err
+	}
+
+	encodedClaims, err := encodeJWTPart(out)
+	if err != nil {
+		return "", err
+	}
+
+	signed := encodedHeader + "." + encodedClaims
+
+	var sig []byte
+
+	if s.privateKey != nil {
+		digest := sha256.Sum256([]byte(signed))
+
+		sig, err = rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, digest[:])
+		if err != nil {
+			return "", cerrors.New(err, "failed to sign token", nil)
+		}
+	} else {
+		sig = signHS256(s.config.Secret, signed)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func signHS256(secret, signed string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(signed))
+
+	return mac.Sum(nil)
+}
+
+func encodeJWTPart(v interface{}) (string, error) {
+	out, err := json.Marshal(v)
+	if err != nil {
+		return "", cerrors.New(err, "failed to marshal jwt part", nil)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(out), nil
+}
+
+func decodeJWTPart(part string, dest interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	return dec.Decode(dest)
+}
+
+func invalidToken(msg string, cause error) error {
+	if cause == nil {
+		return cerrors.New(ErrInvalidToken, msg, nil)
+	}
+
+	return cerrors.New(ErrInvalidToken, msg, map[string]interface{}{
+		"cause": cause.Error(),
+	})
+}
+
+func parseRSAPublicKey(data string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, cerrors.New(nil, "failed to decode public key pem", nil)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to parse public key", nil)
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, cerrors.New(nil, "public key is not an rsa key", nil)
+	}
+
+	return rsaKey, nil
+}
+
+func parseRSAPrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, cerrors.New(nil, "failed to decode private key pem", nil)
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to parse private key", nil)
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, cerrors.New(nil, "private key is not an rsa key", nil)
+	}
+
+	return rsaKey, nil
+}
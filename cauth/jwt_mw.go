@@ -0,0 +1,69 @@
+package cauth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gocopper/copper/clogger"
+)
+
+type (
+	// NewJWTMiddlewareParams holds the params needed to create JWTMiddleware
+	NewJWTMiddlewareParams struct {
+		Verifier *JWTVerifier
+		Logger   clogger.Logger
+	}
+
+	// JWTMiddleware authenticates requests with a bearer JWT in the Authorization header. Requests without a valid
+	// token get an Unauthorized response. The claims of valid tokens are available with ClaimsFromCtx.
+	JWTMiddleware struct {
+		verifier *JWTVerifier
+		logger   clogger.Logger
+	}
+)
+
+// NewJWTMiddleware creates a new JWTMiddleware.
+func NewJWTMiddleware(p NewJWTMiddlewareParams) *JWTMiddleware {
+	return &JWTMiddleware{
+		verifier: p.Verifier,
+		logger:   p.Logger,
+	}
+}
+
+// Handle verifies the bearer token and stores its claims in the request context.
+func (mw *JWTMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const prefix = "bearer "
+
+		authorization := r.Header.Get("Authorization")
+		if len(authorization) <= len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+			writeUnauthorized(w, `Bearer`)
+			return
+		}
+
+		claims, err := mw.verifier.Verify(r.Context(), strings.TrimSpace(authorization[len(prefix):]))
+		if err != nil {
+			mw.logger.WithTags(map[string]interface{}{
+				"url": r.URL.Path,
+			}).Warn("Rejected request with an invalid token", err)
+
+			writeUnauthorized(w, `Bearer error="invalid_token"`)
+
+			return
+		}
+
+		ctx := clogger.WithCtxTags(WithClaims(r.Context(), claims), map[string]interface{}{
+			"userID": claims.Subject(),
+		})
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func writeUnauthorized(w http.ResponseWriter, challenge string) {
+	w.Header().Set("WWW-Authenticate", challenge)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnauthorized)
+
+	_, _ = w.Write([]byte(`{"error":"unauthorized"}` + "\n"))
+}
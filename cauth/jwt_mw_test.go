@@ -0,0 +1,61 @@
+package cauth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/cauth"
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWTMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		config = cauth.Config{JWT: cauth.JWTConfig{Secret: "test-secret"}}
+		clock  = cclock.New()
+	)
+
+	signer, err := cauth.NewJWTSigner(cauth.NewJWTSignerParams{Config: config, Clock: clock})
+	assert.NoError(t, err)
+
+	verifier, err := cauth.NewJWTVerifier(cauth.NewJWTVerifierParams{Config: config, Clock: clock})
+	assert.NoError(t, err)
+
+	var (
+		subject string
+		handler = cauth.NewJWTMiddleware(cauth.NewJWTMiddlewareParams{
+			Verifier: verifier,
+			Logger:   clogger.NewNoop(),
+		}).Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			subject = cauth.ClaimsFromCtx(r.Context()).Subject()
+		}))
+	)
+
+	token, err := signer.Sign(cauth.Claims{"sub": "user-1"})
+	assert.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Equal(t, "Bearer", resp.Header().Get("WWW-Authenticate"))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer invalid")
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Equal(t, `Bearer error="invalid_token"`, resp.Header().Get("WWW-Authenticate"))
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "user-1", subject)
+}
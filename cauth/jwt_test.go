@@ -0,0 +1,249 @@
+package cauth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cauth"
+	"github.com/gocopper/copper/cclock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWT_HS256(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		clock  = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		config = cauth.Config{JWT: cauth.JWTConfig{
+			Secret:   "test-secret",
+			Issuer:   "copper",
+			Audience: "api",
+			TTL:      time.Hour,
+		}}
+	)
+
+	signer, err := cauth.NewJWTSigner(cauth.NewJWTSignerParams{Config: config, Clock: clock})
+	assert.NoError(t, err)
+
+	verifier, err := cauth.NewJWTVerifier(cauth.NewJWTVerifierParams{Config: config, Clock: clock})
+	assert.NoError(t, err)
+
+	token, err := signer.Sign(cauth.Claims{"sub": "user-1", "admin": true, "roles": []string{"a", "b"}})
+	assert.NoError(t, err)
+
+	claims, err := verifier.Verify(ctx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject())
+	assert.Equal(t, "copper", claims.Issuer())
+	assert.Equal(t, []string{"api"}, claims.Audience())
+	assert.True(t, claims.Bool("admin"))
+	assert.Equal(t, []string{"a", "b"}, claims.Strings("roles"))
+	assert.Equal(t, clock.Now().Add(time.Hour).Unix(), claims.ExpiresAt().Unix())
+
+	var decoded struct {
+		Subject  string `json:"sub"`
+		IssuedAt int64  `json:"iat"`
+	}
+
+	assert.NoError(t, claims.Decode(&decoded))
+	assert.Equal(t, "user-1", decoded.Subject)
+	assert.Equal(t, clock.Now().Unix(), decoded.IssuedAt)
+
+	_, err = verifier.Verify(ctx, token[:len(token)-2]+"xx")
+	assert.ErrorIs(t, err, cauth.ErrInvalidToken)
+
+	otherAudience, err := signer.Sign(cauth.Claims{"aud": []string{"other"}})
+	assert.NoError(t, err)
+
+	_, err = verifier.Verify(ctx, otherAudience)
+	assert.ErrorIs(t, err, cauth.ErrInvalidToken)
+
+	clock.Advance(time.Hour)
+
+	_, err = verifier.Verify(ctx, token)
+	assert.ErrorIs(t, err, cauth.ErrInvalidToken)
+}
+
+func TestJWT_NumericDate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		now    = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock  = cclock.NewFake(now)
+		config = cauth.Config{JWT: cauth.JWTConfig{Secret: "test-secret"}}
+	)
+
+	signer, err := cauth.NewJWTSigner(cauth.NewJWTSignerParams{Config: config, Clock: clock})
+	assert.NoError(t, err)
+
+	verifier, err := cauth.NewJWTVerifier(cauth.NewJWTVerifierParams{Config: config, Clock: clock})
+	assert.NoError(t, err)
+
+	fractional, err := signer.Sign(cauth.Claims{"exp": float64(now.Unix()) + 60.5})
+	assert.NoError(t, err)
+
+	claims, err := verifier.Verify(ctx, fractional)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(60500*time.Millisecond), claims.ExpiresAt().UTC())
+
+	clock.Advance(time.Minute + time.Second)
+
+	_, err = verifier.Verify(ctx, fractional)
+	assert.ErrorIs(t, err, cauth.ErrInvalidToken)
+
+	for _, claims := range []cauth.Claims{{"exp": "never"}, {"exp": nil}, {"nbf": true}} {
+		invalid, err := signer.Sign(claims)
+		assert.NoError(t, err)
+
+		_, err = verifier.Verify(ctx, invalid)
+		assert.ErrorIs(t, err, cauth.ErrInvalidToken)
+	}
+}
+
+func TestJWT_AlgNone(t *testing.T) {
+	t.Parallel()
+
+	verifier, err := cauth.NewJWTVerifier(cauth.NewJWTVerifierParams{
+		Config: cauth.Config{JWT: cauth.JWTConfig{Secret: "test-secret"}},
+		Clock:  cclock.New(),
+	})
+	assert.NoError(t, err)
+
+	var (
+		header = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+		claims = base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user-1"}`))
+	)
+
+	_, err = verifier.Verify(context.Background(), header+"."+claims+".")
+	assert.ErrorIs(t, err, cauth.ErrInvalidToken)
+}
+
+func TestJWT_RS256_JWKS(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer jwksServer.Close()
+
+	privateKey := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+
+	signer, err := cauth.NewJWTSigner(cauth.NewJWTSignerParams{
+		Config: cauth.Config{JWT: cauth.JWTConfig{PrivateKey: string(privateKey)}},
+		Clock:  cclock.New(),
+	})
+	assert.NoError(t, err)
+
+	verifier, err := cauth.NewJWTVerifier(cauth.NewJWTVerifierParams{
+		Config: cauth.Config{JWT: cauth.JWTConfig{
+			JWKSURL:             jwksServer.URL,
+			JWKSRefreshInterval: time.Hour,
+		}},
+		Clock: cclock.New(),
+	})
+	assert.NoError(t, err)
+
+	token, err := signer.Sign(cauth.Claims{"sub": "user-1"})
+	assert.NoError(t, err)
+
+	claims, err := verifier.Verify(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject())
+}
+
+func TestJWT_RS256_JWKS_RefreshFails(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	var (
+		clock   = cclock.NewFake(time.Now())
+		fetches int32
+	)
+
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&fetches, 1) > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer jwksServer.Close()
+
+	privateKey := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+
+	signer, err := cauth.NewJWTSigner(cauth.NewJWTSignerParams{
+		Config: cauth.Config{JWT: cauth.JWTConfig{PrivateKey: string(privateKey), TTL: 24 * time.Hour}},
+		Clock:  clock,
+	})
+	assert.NoError(t, err)
+
+	verifier, err := cauth.NewJWTVerifier(cauth.NewJWTVerifierParams{
+		Config: cauth.Config{JWT: cauth.JWTConfig{
+			JWKSURL:             jwksServer.URL,
+			JWKSRefreshInterval: time.Hour,
+		}},
+		Clock: clock,
+	})
+	assert.NoError(t, err)
+
+	token, err := signer.Sign(cauth.Claims{"sub": "user-1"})
+	assert.NoError(t, err)
+
+	_, err = verifier.Verify(context.Background(), token)
+	assert.NoError(t, err)
+
+	clock.Advance(2 * time.Hour)
+
+	// The refresh fails, so the cached keys are used and the next refetch waits for the min interval
+	for i := 0; i < 3; i++ {
+		_, err = verifier.Verify(context.Background(), token)
+		assert.NoError(t, err)
+	}
+
+	unknownKid := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"random"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user-1"}`)) + ".c2ln"
+
+	_, err = verifier.Verify(context.Background(), unknownKid)
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+}
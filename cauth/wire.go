@@ -0,0 +1,14 @@
+package cauth
+
+import "github.com/google/wire"
+
+// WireModule can be used as part of google/wire setup.
+var WireModule = wire.NewSet( //nolint:gochecknoglobals
+	LoadConfig,
+	wire.Struct(new(NewJWTVerifierParams), "*"),
+	NewJWTVerifier,
+	wire.Struct(new(NewJWTMiddlewareParams), "*"),
+	NewJWTMiddleware,
+	wire.Struct(new(NewBasicAuthMiddlewareParams), "*"),
+	NewBasicAuthMiddleware,
+)
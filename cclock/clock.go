@@ -0,0 +1,24 @@
+package cclock
+
+import "time"
+
+// Clock provides the current time and timers. Use New for the system clock and NewFake in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// New returns a Clock that uses the system time.
+func New() Clock {
+	return &system{}
+}
+
+type system struct{}
+
+func (c *system) Now() time.Time {
+	return time.Now()
+}
+
+func (c *system) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
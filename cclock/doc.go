@@ -0,0 +1,3 @@
+// Package cclock provides a Clock interface that can be used in place of the time package so that time-sensitive
+// code (expiry, timeouts, schedules) can be controlled in tests.
+package cclock
@@ -0,0 +1,88 @@
+package cclock
+
+import (
+	"sync"
+	"time"
+)
+
+// NewFake returns a Clock that is frozen at the given time until it is moved forward using Advance or Set.
+// Useful in unit tests that need to verify expiry or timeouts without sleeping.
+func NewFake(now time.Time) *Fake {
+	return &Fake{
+		now:     now,
+		waiters: make([]fakeWaiter, 0),
+	}
+}
+
+// Fake is an implementation of Clock whose time only changes when Advance or Set is called.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// Now returns the fake's current time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// After returns a channel that receives the fake's time once it has been advanced by at least d.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+
+	if d <= 0 {
+		ch <- f.now
+		return ch
+	}
+
+	f.waiters = append(f.waiters, fakeWaiter{
+		deadline: f.now.Add(d),
+		ch:       ch,
+	})
+
+	return ch
+}
+
+// Advance moves the fake's time forward by d and fires any timers that are due.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.setLocked(f.now.Add(d))
+}
+
+// Set moves the fake's time to t and fires any timers that are due.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.setLocked(t)
+}
+
+func (f *Fake) setLocked(t time.Time) {
+	f.now = t
+
+	pending := f.waiters[:0]
+
+	for _, w := range f.waiters {
+		if w.deadline.After(f.now) {
+			pending = append(pending, w)
+			continue
+		}
+
+		w.ch <- f.now
+	}
+
+	f.waiters = pending
+}
@@ -0,0 +1,42 @@
+package cclock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake_Advance(t *testing.T) {
+	t.Parallel()
+
+	var (
+		start = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock = cclock.NewFake(start)
+		after = clock.After(time.Minute)
+	)
+
+	assert.Equal(t, start, clock.Now())
+
+	clock.Advance(30 * time.Second)
+
+	select {
+	case <-after:
+		assert.Fail(t, "timer fired before its deadline")
+	default:
+	}
+
+	clock.Advance(30 * time.Second)
+
+	assert.Equal(t, start.Add(time.Minute), <-after)
+	assert.Equal(t, start.Add(time.Minute), clock.Now())
+}
+
+func TestFake_After_NonPositive(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, start, <-cclock.NewFake(start).After(0))
+}
@@ -0,0 +1,145 @@
+package cconfig
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	flagSource     = "flag"
+	overrideSource = "override"
+	defaultSource  = "default"
+
+	redactedValue = "[REDACTED]"
+)
+
+// DefaultRedactPatterns are the key patterns whose values are redacted by Dump when no patterns are given. They are
+// matched against the last part of each key (ex. password for csql.password).
+//
+//nolint:gochecknoglobals
+var DefaultRedactPatterns = []string{
+	"*password*",
+	"*passwd*",
+	"*secret*",
+	"*token*",
+	"*key",
+	"*dsn*",
+	"*credential*",
+	"*private*",
+}
+
+// DumpRequested returns true if the loader was created with the --dump-config flag. Apps should print the effective
+// config with WriteDump and exit once their dependencies are created.
+func DumpRequested(l Loader) bool {
+	base, ok := baseLoader(l)
+
+	return ok && base.flags.Dump
+}
+
+// Dump returns the effective value of every loaded config key, after the config files, remote providers, environment
+// variables, and flags have been merged, along with where each value came from. The values of the keys that match
+// one of the redact patterns (see path.Match), and of the keys whose values were encrypted (see Encrypt), are replaced
+// with "[REDACTED]". If redact is nil, DefaultRedactPatterns are used.
+func Dump(l Loader, redact []string) []KeyInfo {
+	if redact == nil {
+		redact = DefaultRedactPatterns
+	}
+
+	keys := Keys(l)
+	encrypted := encryptedKeySet(l)
+
+	for i := range keys {
+		if keys[i].Value != "" && (encrypted[keys[i].Key] || shouldRedact(keys[i].Key, redact)) {
+			keys[i].Value = redactedValue
+		}
+	}
+
+	return keys
+}
+
+// WriteDump writes the keys returned by Dump as a table to answer "what value is the app actually using?".
+func WriteDump(w io.Writer, l Loader, redact []string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:gomnd
+
+	_, _ = fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+
+	for _, k := range Dump(l, redact) {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", k.Key, k.Value, k.Source)
+	}
+
+	return tw.Flush()
+}
+
+// source returns where the value of the key came from. It must be called with l.mu held.
+func (l *loader) source(key string) string {
+	if l.overrides != nil {
+		if _, ok := l.overrides.values()[key]; ok {
+			return overrideSource
+		}
+	}
+
+	if _, ok := l.flags.Values[key]; ok {
+		return flagSource
+	}
+
+	if l.env {
+		name := envName(l.envPrefix, key)
+		if _, ok := os.LookupEnv(name); ok {
+			return "env:" + name
+		}
+	}
+
+	if source, ok := l.sources[key]; ok {
+		return source
+	}
+
+	// Maps are loaded as a single key, but their entries are set individually in the config files
+	sources := make([]string, 0)
+
+	for k, source := range l.sources {
+		if strings.HasPrefix(k, key+".") {
+			sources = append(sources, source)
+		}
+	}
+
+	if len(sources) > 0 {
+		sort.Strings(sources)
+		return sources[0]
+	}
+
+	return defaultSource
+}
+
+func encryptedKeySet(l Loader) map[string]bool {
+	base, ok := baseLoader(l)
+	if !ok {
+		return nil
+	}
+
+	base.mu.RLock()
+	defer base.mu.RUnlock()
+
+	encrypted := make(map[string]bool, len(base.encrypted))
+	for k := range base.encrypted {
+		encrypted[k] = true
+	}
+
+	return encrypted
+}
+
+func shouldRedact(key string, patterns []string) bool {
+	name := strings.ToLower(key[strings.LastIndex(key, ".")+1:])
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), name); ok {
+			return true
+		}
+	}
+
+	return false
+}
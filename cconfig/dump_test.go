@@ -0,0 +1,81 @@
+package cconfig_test
+
+import (
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDump(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"base.toml": `
+			[db]
+			host = "localhost"
+			user = "app"
+			password = "hunter2"
+		`,
+		"prod.toml": `
+			extends = "base.toml"
+
+			[db]
+			host = "db.internal"
+			api_key = "abc"
+		`,
+	})
+
+	setenv(t, "DUMPTEST_DB_PORT", "6543")
+
+	var config struct {
+		Host     string `toml:"host"`
+		Port     int    `toml:"port"`
+		User     string `toml:"user"`
+		Name     string `toml:"name"`
+		Password string `toml:"password"`
+		APIKey   string `toml:"api_key"`
+		Timeout  string `toml:"timeout"`
+	}
+
+	loader, err := cconfig.NewWithFlagOverrides(cconfig.Path(path.Join(dir, "prod.toml")), "DUMPTEST_",
+		cconfig.FlagOverrides{Values: map[string]string{"db.name": "prod"}})
+	assert.NoError(t, err)
+
+	loader, err = cconfig.WithRemote(loader, &fakeKV{values: map[string]string{"db.timeout": "5s"}})
+	assert.NoError(t, err)
+
+	assert.NoError(t, loader.Load("db", &config))
+
+	assert.Equal(t, []cconfig.KeyInfo{
+		{Key: "db.api_key", Type: "string", Value: "[REDACTED]", Source: path.Join(dir, "prod.toml")},
+		{Key: "db.host", Type: "string", Value: "db.internal", Source: path.Join(dir, "prod.toml")},
+		{Key: "db.name", Type: "string", Value: "prod", Source: "flag"},
+		{Key: "db.password", Type: "string", Value: "[REDACTED]", Source: path.Join(dir, "base.toml")},
+		{Key: "db.port", Type: "int", Value: "6543", Source: "env:DUMPTEST_DB_PORT"},
+		{Key: "db.timeout", Type: "string", Value: "5s", Source: "remote"},
+		{Key: "db.user", Type: "string", Value: "app", Source: path.Join(dir, "base.toml")},
+	}, cconfig.Dump(loader, nil))
+
+	keys := cconfig.Dump(loader, []string{"user"})
+	assert.Equal(t, "hunter2", keys[3].Value)
+	assert.Equal(t, "[REDACTED]", keys[6].Value)
+
+	var out strings.Builder
+
+	assert.NoError(t, cconfig.WriteDump(&out, loader, nil))
+	assert.Contains(t, out.String(), "db.password  [REDACTED]")
+	assert.NotContains(t, out.String(), "hunter2")
+}
+
+func TestParseFlagOverrides_Dump(t *testing.T) {
+	t.Parallel()
+
+	overrides, rest := cconfig.ParseFlagOverrides([]string{"--dump-config", "-config", "x.toml"})
+
+	assert.True(t, overrides.Dump)
+	assert.Equal(t, []string{"-config", "x.toml"}, rest)
+}
@@ -0,0 +1,142 @@
+package cconfig
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+const (
+	// EncryptionKeyEnvVar is the environment variable with the key that decrypts the config values that have the
+	// "enc:" prefix. The key is 32 random bytes, base64-encoded, as returned by GenerateEncryptionKey.
+	EncryptionKeyEnvVar = "CONFIG_ENCRYPTION_KEY"
+
+	encryptedPrefix    = "enc:"
+	encryptionKeyBytes = 32
+)
+
+// GenerateEncryptionKey returns a new random key that can be set in CONFIG_ENCRYPTION_KEY.
+func GenerateEncryptionKey() (string, error) {
+	key := make([]byte, encryptionKeyBytes)
+
+	_, err := rand.Read(key)
+	if err != nil {
+		return "", cerrors.New(err, "failed to generate encryption key", nil)
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Encrypt encrypts the value with the base64-encoded key using AES-GCM. The result has the "enc:" prefix and can be
+// committed in config files, where it is decrypted by Load using the key in CONFIG_ENCRYPTION_KEY:
+//
+// # prod.toml
+// [cmailer]
+// api_key = "enc:GFtZ...=="
+func Encrypt(key, value string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", cerrors.New(err, "failed to generate nonce", nil)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value returned by Encrypt with the same key.
+func Decrypt(key, value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return "", cerrors.New(nil, "value is not encrypted", nil)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", cerrors.New(err, "invalid encrypted value", nil)
+	}
+
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", cerrors.New(err, "failed to decrypt value", nil)
+	}
+
+	return string(plaintext), nil
+}
+
+// decryptValues decrypts the strings in dest that have the "enc:" prefix with the key in CONFIG_ENCRYPTION_KEY.
+func decryptValues(dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr {
+		return nil
+	}
+
+	return replaceStrings(v.Elem(), func(val string) (string, error) {
+		if !strings.HasPrefix(val, encryptedPrefix) {
+			return val, nil
+		}
+
+		key, ok := os.LookupEnv(EncryptionKeyEnvVar)
+		if !ok {
+			return "", cerrors.New(nil, "config has encrypted values but the encryption key is not set", map[string]interface{}{
+				"env": EncryptionKeyEnvVar,
+			})
+		}
+
+		return Decrypt(key, val)
+	})
+}
+
+// encryptedKeys returns the keys of the fields in dest that have encrypted values, before they are decrypted.
+func encryptedKeys(key string, dest interface{}) []string {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	keys := make([]string, 0)
+
+	for _, k := range structKeys(key, v.Elem()) {
+		if strings.Contains(k.Value, encryptedPrefix) {
+			keys = append(keys, k.Key)
+		}
+	}
+
+	return keys
+}
+
+func newGCM(key string) (cipher.AEAD, error) {
+	keyBytes, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(keyBytes) != encryptionKeyBytes {
+		return nil, cerrors.New(err, "encryption key must be 32 base64-encoded bytes", nil)
+	}
+
+	block, err := aes.NewCipher(keyBytes)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to create cipher", nil)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to create gcm", nil)
+	}
+
+	return gcm, nil
+}
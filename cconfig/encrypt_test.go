@@ -0,0 +1,71 @@
+package cconfig_test
+
+import (
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncrypt(t *testing.T) {
+	t.Parallel()
+
+	key, err := cconfig.GenerateEncryptionKey()
+	assert.NoError(t, err)
+
+	otherKey, err := cconfig.GenerateEncryptionKey()
+	assert.NoError(t, err)
+
+	encrypted, err := cconfig.Encrypt(key, "s3cr3t")
+	assert.NoError(t, err)
+	assert.Contains(t, encrypted, "enc:")
+	assert.NotContains(t, encrypted, "s3cr3t")
+
+	decrypted, err := cconfig.Decrypt(key, encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", decrypted)
+
+	_, err = cconfig.Decrypt(otherKey, encrypted)
+	assert.Error(t, err)
+
+	_, err = cconfig.Encrypt("short", "s3cr3t")
+	assert.Error(t, err)
+
+	setenv(t, cconfig.EncryptionKeyEnvVar, key)
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[mailer]
+			api_key = "` + encrypted + `"
+			host = "smtp.example.com"
+			user = "` + encrypted + `"
+		`,
+	})
+
+	var config struct {
+		APIKey string `toml:"api_key"`
+		Host   string `toml:"host"`
+		User   string `toml:"user"`
+	}
+
+	loader, err := cconfig.New(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+
+	assert.NoError(t, loader.Load("mailer", &config))
+	assert.Equal(t, "s3cr3t", config.APIKey)
+	assert.Equal(t, "smtp.example.com", config.Host)
+	assert.Equal(t, "s3cr3t", config.User)
+
+	keys := cconfig.Dump(loader, []string{})
+	assert.Equal(t, "[REDACTED]", keys[0].Value)
+	assert.Equal(t, "smtp.example.com", keys[1].Value)
+	assert.Equal(t, "[REDACTED]", keys[2].Value)
+
+	var out strings.Builder
+
+	assert.NoError(t, cconfig.WriteKeys(&out, loader))
+	assert.NotContains(t, out.String(), "s3cr3t")
+}
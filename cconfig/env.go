@@ -0,0 +1,173 @@
+package cconfig
+
+import (
+	"encoding"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+// EnvPrefix is prepended to the names of the environment variables that override config values (ex. with the
+// "MYAPP_" prefix, MYAPP_CHTTP_PORT overrides chttp.port).
+type EnvPrefix string
+
+// NewWithEnvOverrides works exactly the same way as NewWithKeyOverrides except that environment variables can override
+// the values in the config files. The environment variable for a value is its key path in upper case, joined by
+// underscores, and prefixed with the given prefix. For example, with an empty prefix:
+//
+// # prod.toml
+// [chttp]
+// port = 7501
+//
+// $ CHTTP_PORT=8080 ./app
+//
+// loads 8080 into the port. Environment variables are matched against the fields of the struct passed to Load, so
+// they can set values that are not in the config files. Lists are comma-separated (ex. CHTTP_LISTEN=":80,:81").
+func NewWithEnvOverrides(fp Path, prefix EnvPrefix) (Loader, error) {
+	l, err := newLoader(string(fp), false)
+	if err != nil {
+		return nil, err
+	}
+
+	l.env = true
+	l.envPrefix = string(prefix)
+
+	return l, nil
+}
+
+// applyEnvOverrides sets the fields of dest (a pointer to a struct) that have a matching environment variable.
+func applyEnvOverrides(prefix, key string, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	return applyEnvOverridesToStruct(envName(prefix, key), v.Elem())
+}
+
+func applyEnvOverridesToStruct(name string, v reflect.Value) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fieldKey := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if fieldKey == "-" {
+			continue
+		}
+
+		if fieldKey == "" {
+			fieldKey = field.Name
+		}
+
+		var (
+			fieldName = envName(name+"_", fieldKey)
+			fieldVal  = v.Field(i)
+		)
+
+		if fieldVal.Kind() == reflect.Struct && fieldVal.Type() != reflect.TypeOf(time.Time{}) {
+			err := applyEnvOverridesToStruct(fieldName, fieldVal)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		raw, ok := os.LookupEnv(fieldName)
+		if !ok {
+			continue
+		}
+
+		err := setStringValue(fieldVal, raw)
+		if err != nil {
+			return cerrors.New(err, "invalid config value in environment variable", map[string]interface{}{
+				"env": fieldName,
+			})
+		}
+	}
+
+	return nil
+}
+
+func envName(prefix, key string) string {
+	return prefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+//nolint:cyclop
+func setStringValue(v reflect.Value, raw string) error {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(int64(d))
+
+		return nil
+	}
+
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(raw))
+		}
+	}
+
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(f)
+	case reflect.Slice:
+		items := strings.Split(raw, ",")
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+
+		for i := range items {
+			err := setStringValue(slice.Index(i), strings.TrimSpace(items[i]))
+			if err != nil {
+				return err
+			}
+		}
+
+		v.Set(slice)
+	default:
+		return cerrors.New(nil, "unsupported field type", map[string]interface{}{
+			"type": v.Type().String(),
+		})
+	}
+
+	return nil
+}
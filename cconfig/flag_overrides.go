@@ -0,0 +1,253 @@
+package cconfig
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+const (
+	helpConfigFlag = "help-config"
+	dumpConfigFlag = "dump-config"
+)
+
+// FlagOverrides holds the config values set with command-line flags (ex. --chttp.port=9000), as parsed by
+// ParseFlagOverrides.
+type FlagOverrides struct {
+	// Values maps keys (ex. chttp.port) to their values.
+	Values map[string]string
+
+	// Help is true if the --help-config flag was passed.
+	Help bool
+
+	// Dump is true if the --dump-config flag was passed.
+	Dump bool
+
+	// Reference is the format passed with the --config-reference flag (ex. markdown or json).
+	Reference string
+}
+
+// ParseFlagOverrides takes the config flags out of the command-line args and returns them along with the rest of the
+// args, which can be parsed with the flag package. Config flags are the flags whose names have a dot (ex.
+// --chttp.port=9000 or -clogger.level debug), --help-config, --dump-config, and --config-reference[=json].
+func ParseFlagOverrides(args []string) (FlagOverrides, []string) {
+	var (
+		overrides = FlagOverrides{Values: make(map[string]string)}
+		rest      = make([]string, 0, len(args))
+	)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		if name == arg {
+			rest = append(rest, arg)
+			continue
+		}
+
+		if name == helpConfigFlag {
+			overrides.Help = true
+			continue
+		}
+
+		if name == dumpConfigFlag {
+			overrides.Dump = true
+			continue
+		}
+
+		if name == configReferenceFlag || strings.HasPrefix(name, configReferenceFlag+"=") {
+			overrides.Reference = strings.TrimPrefix(strings.TrimPrefix(name, configReferenceFlag), "=")
+			if overrides.Reference == "" {
+				overrides.Reference = ReferenceMarkdown
+			}
+
+			continue
+		}
+
+		key := strings.SplitN(name, "=", 2)[0] //nolint:gomnd
+		if !strings.Contains(key, ".") {
+			rest = append(rest, arg)
+			continue
+		}
+
+		switch {
+		case len(key) < len(name):
+			overrides.Values[key] = name[len(key)+1:]
+		case i+1 < len(args) && !strings.HasPrefix(args[i+1], "-"):
+			overrides.Values[key] = args[i+1]
+			i++
+		default:
+			overrides.Values[key] = "true"
+		}
+	}
+
+	return overrides, rest
+}
+
+// NewWithFlagOverrides works exactly the same way as NewWithEnvOverrides except that the values set with command-line
+// flags override both the config files and the environment variables. Flags for keys that do not map to a field of
+// the struct passed to Load cause Load to return an error.
+func NewWithFlagOverrides(fp Path, prefix EnvPrefix, overrides FlagOverrides) (Loader, error) {
+	l, err := newLoader(string(fp), false)
+	if err != nil {
+		return nil, err
+	}
+
+	l.env = true
+	l.envPrefix = string(prefix)
+	l.flags = overrides
+
+	return l, nil
+}
+
+// HelpRequested returns true if the loader was created with the --help-config flag. Apps should print the config
+// keys with WriteKeys and exit once their dependencies are created.
+func HelpRequested(l Loader) bool {
+	base, ok := baseLoader(l)
+
+	return ok && base.flags.Help
+}
+
+// KeyInfo describes a config key that has been loaded.
+type KeyInfo struct {
+	Key   string
+	Type  string
+	Value string
+
+	// Source is where the value came from: "override" (see Overrides), "flag", "env:<name>", "remote", the path of a
+	// config file, or "default" if it was not set.
+	Source string
+}
+
+// Keys returns the keys of the fields in every struct that has been passed to Load along with their current values
+// and sources, sorted by key. The values are not redacted (see Dump).
+func Keys(l Loader) []KeyInfo {
+	base, ok := baseLoader(l)
+	if !ok {
+		return nil
+	}
+
+	base.mu.RLock()
+	defer base.mu.RUnlock()
+
+	keys := make([]KeyInfo, 0)
+
+	for key, v := range base.values {
+		keys = append(keys, structKeys(key, v)...)
+	}
+
+	for i := range keys {
+		keys[i].Source = base.source(keys[i].Key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Key < keys[j].Key
+	})
+
+	return keys
+}
+
+// WriteKeys writes the keys returned by Keys as a table. Each key can be set with a flag (ex. --chttp.port=9000). The
+// values of keys that match DefaultRedactPatterns are redacted the same way as in Dump.
+func WriteKeys(w io.Writer, l Loader) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:gomnd
+
+	_, _ = fmt.Fprintln(tw, "KEY\tTYPE\tVALUE")
+
+	for _, k := range Dump(l, nil) {
+		_, _ = fmt.Fprintf(tw, "--%s\t%s\t%s\n", k.Key, k.Type, k.Value)
+	}
+
+	return tw.Flush()
+}
+
+func structKeys(key string, v reflect.Value) []KeyInfo {
+	keys := make([]KeyInfo, 0)
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fieldKey := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if fieldKey == "-" {
+			continue
+		}
+
+		if fieldKey == "" {
+			fieldKey = strings.ToLower(field.Name)
+		}
+
+		fieldVal := v.Field(i)
+		if fieldVal.Kind() == reflect.Struct && fieldVal.Type() != reflect.TypeOf(time.Time{}) {
+			keys = append(keys, structKeys(key+"."+fieldKey, fieldVal)...)
+			continue
+		}
+
+		keys = append(keys, KeyInfo{
+			Key:   key + "." + fieldKey,
+			Type:  fieldVal.Type().String(),
+			Value: fmt.Sprint(fieldVal.Interface()),
+		})
+	}
+
+	return keys
+}
+
+// applyFlagOverrides sets the fields of dest that have a matching flag.
+func applyFlagOverrides(key string, overrides map[string]string, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	for flagKey, raw := range overrides {
+		if !strings.HasPrefix(flagKey, key+".") {
+			continue
+		}
+
+		fieldVal, ok := fieldByKeyPath(v.Elem(), strings.Split(strings.TrimPrefix(flagKey, key+"."), "."))
+		if !ok {
+			return cerrors.New(nil, "flag does not match a config key", map[string]interface{}{
+				"flag": flagKey,
+			})
+		}
+
+		err := setStringValue(fieldVal, raw)
+		if err != nil {
+			return cerrors.New(err, "invalid config value in flag", map[string]interface{}{
+				"flag": flagKey,
+			})
+		}
+	}
+
+	return nil
+}
+
+func fieldByKeyPath(v reflect.Value, path []string) (reflect.Value, bool) {
+	for _, part := range path {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+
+		field, ok := findField(v.Type(), part)
+		if !ok {
+			return reflect.Value{}, false
+		}
+
+		v = v.FieldByIndex(field.Index)
+	}
+
+	return v, true
+}
@@ -0,0 +1,88 @@
+package cconfig_test
+
+import (
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFlagOverrides(t *testing.T) {
+	t.Parallel()
+
+	overrides, rest := cconfig.ParseFlagOverrides([]string{
+		"-config", "./config/prod.toml",
+		"--chttp.port=9000",
+		"-clogger.level", "debug",
+		"--chttp.enable_h2c",
+		"--help-config",
+		"-env-prefix=APP_",
+	})
+
+	assert.Equal(t, cconfig.FlagOverrides{
+		Values: map[string]string{
+			"chttp.port":       "9000",
+			"clogger.level":    "debug",
+			"chttp.enable_h2c": "true",
+		},
+		Help: true,
+	}, overrides)
+	assert.Equal(t, []string{"-config", "./config/prod.toml", "-env-prefix=APP_"}, rest)
+}
+
+func TestNewWithFlagOverrides(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[server]
+			port = 7501
+			timeout = "1s"
+			secret = "hunter2"
+		`,
+	})
+
+	setenv(t, "FLAGTEST_SERVER_PORT", "8080")
+
+	var config struct {
+		Port    int           `toml:"port"`
+		Timeout time.Duration `toml:"timeout"`
+		Secret  string        `toml:"secret"`
+		TLS     struct {
+			Enabled bool `toml:"enabled"`
+		} `toml:"tls"`
+	}
+
+	loader, err := cconfig.NewWithFlagOverrides(cconfig.Path(path.Join(dir, "test.toml")), "FLAGTEST_",
+		cconfig.FlagOverrides{Values: map[string]string{
+			"server.port":        "9000",
+			"server.tls.enabled": "true",
+		}})
+	assert.NoError(t, err)
+
+	assert.NoError(t, loader.Load("server", &config))
+	assert.Equal(t, 9000, config.Port)
+	assert.Equal(t, time.Second, config.Timeout)
+	assert.True(t, config.TLS.Enabled)
+	assert.False(t, cconfig.HelpRequested(loader))
+
+	var b strings.Builder
+
+	assert.NoError(t, cconfig.WriteKeys(&b, loader))
+	assert.Equal(t, `KEY                   TYPE           VALUE
+--server.port         int            9000
+--server.secret       string         [REDACTED]
+--server.timeout      time.Duration  1s
+--server.tls.enabled  bool           true
+`, b.String())
+
+	loader, err = cconfig.NewWithFlagOverrides(cconfig.Path(path.Join(dir, "test.toml")), "",
+		cconfig.FlagOverrides{Values: map[string]string{"server.prot": "9000"}})
+	assert.NoError(t, err)
+
+	assert.Error(t, loader.Load("server", &config))
+}
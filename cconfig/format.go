@@ -0,0 +1,107 @@
+package cconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v3"
+)
+
+// loadFile reads the config file at the given path into a TOML tree. The file's format is picked based on its
+// extension: .yaml and .yml files are read as YAML, .json files as JSON, and all others as TOML. Since YAML and JSON
+// files are converted to TOML trees, they support the same 'extends' and 'include' keys and key override rules as
+// TOML files, and struct fields use the same `toml` tags for all formats.
+func loadFile(fp string) (*toml.Tree, error) {
+	ext := strings.ToLower(filepath.Ext(fp))
+	if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+		return toml.LoadFile(fp)
+	}
+
+	data, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+
+	if ext == ".json" {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+
+		err = dec.Decode(&values)
+	} else {
+		err = yaml.Unmarshal(data, &values)
+	}
+
+	if err != nil {
+		return nil, cerrors.New(err, "failed to parse config file", map[string]interface{}{
+			"format": strings.TrimPrefix(ext, "."),
+		})
+	}
+
+	normalized, err := normalizeValue(values)
+	if err != nil {
+		return nil, err
+	}
+
+	normalizedMap, _ := normalized.(map[string]interface{})
+
+	return toml.TreeFromMap(normalizedMap)
+}
+
+// normalizeValue converts the values decoded from YAML and JSON into the types supported by TOML trees. Null values
+// are dropped since TOML does not have them.
+func normalizeValue(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+
+		for k, item := range val {
+			if item == nil {
+				continue
+			}
+
+			normalized, err := normalizeValue(item)
+			if err != nil {
+				return nil, err
+			}
+
+			out[k] = normalized
+		}
+
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, 0, len(val))
+
+		for i := range val {
+			normalized, err := normalizeValue(val[i])
+			if err != nil {
+				return nil, err
+			}
+
+			out = append(out, normalized)
+		}
+
+		return out, nil
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i, nil
+		}
+
+		f, err := val.Float64()
+		if err != nil {
+			return nil, cerrors.New(err, "invalid number", map[string]interface{}{
+				"value": val.String(),
+			})
+		}
+
+		return f, nil
+	default:
+		return val, nil
+	}
+}
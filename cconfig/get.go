@@ -0,0 +1,228 @@
+package cconfig
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/pelletier/go-toml"
+)
+
+// ErrKeyNotSet is returned by Get when the key does not have a value.
+var ErrKeyNotSet = errors.New("config key is not set")
+
+// keyLoader is implemented by the loaders in this package to load a single value for Get.
+type keyLoader interface {
+	// loadKey loads the value at the key into dest and returns false if the key does not have a value.
+	loadKey(key string, dest interface{}) (bool, error)
+}
+
+// Get loads the value at the key (ex. "chttp.port") into a value of type T, so that small modules can read a single
+// key without declaring a config struct. Values are parsed, overridden, and decrypted the same way as they are by
+// Load. It returns ErrKeyNotSet if the key does not have a value. For example:
+//
+//	port, err := cconfig.Get[int](loader, "chttp.port")
+func Get[T any](l Loader, key string) (T, error) {
+	var val T
+
+	ok, err := loadKey(l, key, &val)
+	if err != nil {
+		return val, cerrors.New(err, "failed to load config key", map[string]interface{}{
+			"key": key,
+		})
+	}
+
+	if !ok {
+		return val, cerrors.New(ErrKeyNotSet, "failed to load config key", map[string]interface{}{
+			"key": key,
+		})
+	}
+
+	return val, nil
+}
+
+// GetOr works like Get except that it returns def if the key does not have a value. If T is a struct, the values in
+// the config table are loaded over def, so def can hold the defaults for the fields that are not set.
+func GetOr[T any](l Loader, key string, def T) (T, error) {
+	val := def
+
+	ok, err := loadKey(l, key, &val)
+	if err != nil {
+		return def, cerrors.New(err, "failed to load config key", map[string]interface{}{
+			"key": key,
+		})
+	}
+
+	if !ok {
+		return def, nil
+	}
+
+	return val, nil
+}
+
+// MustGet works like Get except that it panics if the value cannot be loaded.
+func MustGet[T any](l Loader, key string) T {
+	val, err := Get[T](l, key)
+	if err != nil {
+		panic(err)
+	}
+
+	return val
+}
+
+// MustGetOr works like GetOr except that it panics if the value cannot be loaded.
+func MustGetOr[T any](l Loader, key string, def T) T {
+	val, err := GetOr(l, key, def)
+	if err != nil {
+		panic(err)
+	}
+
+	return val
+}
+
+// loadKey loads the value at the key into dest. Structs are loaded with Load since they are config tables. Other
+// values are loaded by loaders that implement keyLoader, or with Load by wrapping them in a struct. dest is not changed
+// if the key does not have a value.
+func loadKey(l Loader, key string, dest interface{}) (bool, error) {
+	if t := reflect.TypeOf(dest).Elem(); t.Kind() == reflect.Struct && t != reflect.TypeOf(time.Time{}) {
+		found := true
+		if base, ok := baseLoader(l); ok {
+			found = base.has(key)
+		}
+
+		return found, l.Load(key, dest)
+	}
+
+	if kl, ok := l.(keyLoader); ok {
+		return kl.loadKey(key, dest)
+	}
+
+	i := strings.LastIndex(key, ".")
+	if i == -1 {
+		return false, cerrors.New(nil, "loader does not support top-level keys", nil)
+	}
+
+	holder := newKeyHolder(key[i+1:], reflect.TypeOf(dest).Elem())
+
+	err := l.Load(key[:i], holder.Interface())
+	if err != nil {
+		return false, err
+	}
+
+	val := holder.Elem().Field(0)
+	if val.IsZero() {
+		return false, nil
+	}
+
+	reflect.ValueOf(dest).Elem().Set(val)
+
+	return true, nil
+}
+
+// newKeyHolder returns a pointer to a struct with a single field of type t that is loaded from the given key.
+func newKeyHolder(key string, t reflect.Type) reflect.Value {
+	return reflect.New(reflect.StructOf([]reflect.StructField{{
+		Name: "Value",
+		Type: t,
+		Tag:  reflect.StructTag(`toml:"` + key + `"`),
+	}}))
+}
+
+func (l *loader) loadKey(key string, dest interface{}) (bool, error) {
+	l.mu.Lock()
+	tree := l.tree
+	l.loaded[strings.Split(key, ".")[0]] = true
+	l.mu.Unlock()
+
+	var (
+		holder = newKeyHolder("value", reflect.TypeOf(dest).Elem())
+		field  = holder.Elem().Field(0)
+		found  = tree.Has(key)
+	)
+
+	if found {
+		valTree, err := toml.TreeFromMap(map[string]interface{}{})
+		if err != nil {
+			return false, err
+		}
+
+		valTree.Set("value", tree.Get(key))
+
+		valTree, err = withConvertedStrings(valTree, holder.Interface())
+		if err != nil {
+			return false, err
+		}
+
+		err = valTree.Unmarshal(holder.Interface())
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if l.env {
+		name := envName(l.envPrefix, key)
+
+		if raw, ok := os.LookupEnv(name); ok {
+			err := setStringValue(field, raw)
+			if err != nil {
+				return false, cerrors.New(err, "invalid config value in environment variable", map[string]interface{}{
+					"env": name,
+				})
+			}
+
+			found = true
+		}
+	}
+
+	if raw, ok := l.flags.Values[key]; ok {
+		err := setStringValue(field, raw)
+		if err != nil {
+			return false, cerrors.New(err, "invalid config value in flag", map[string]interface{}{
+				"flag": key,
+			})
+		}
+
+		found = true
+	}
+
+	if raw, ok := l.overrideValues()[key]; ok {
+		err := setStringValue(field, raw)
+		if err != nil {
+			return false, cerrors.New(err, "invalid config value in override", map[string]interface{}{
+				"override": key,
+			})
+		}
+
+		found = true
+	}
+
+	err := decryptValues(holder.Interface())
+	if err != nil {
+		return false, err
+	}
+
+	if found {
+		reflect.ValueOf(dest).Elem().Set(field)
+	}
+
+	return found, nil
+}
+
+func (l *loader) has(key string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.tree.Has(key)
+}
+
+func (l *secretsLoader) loadKey(key string, dest interface{}) (bool, error) {
+	ok, err := loadKey(l.loader, key, dest)
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	return true, l.resolveSecrets(key, dest)
+}
@@ -0,0 +1,73 @@
+package cconfig_test
+
+import (
+	"errors"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			name = "app"
+
+			[server]
+			port = 7501
+			timeout = "30s"
+			max_body = "1MB"
+			hosts = "a.com, b.com"
+
+			[server.tls]
+			enabled = true
+		`,
+	})
+
+	setenv(t, "GETTEST_SERVER_PORT", "8080")
+
+	loader, err := cconfig.NewWithFlagOverrides(cconfig.Path(path.Join(dir, "test.toml")), "GETTEST_",
+		cconfig.FlagOverrides{Values: map[string]string{"server.debug": "true"}})
+	assert.NoError(t, err)
+
+	port, err := cconfig.Get[int](loader, "server.port")
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, port)
+
+	assert.Equal(t, "app", cconfig.MustGet[string](loader, "name"))
+	assert.Equal(t, 30*time.Second, cconfig.MustGet[time.Duration](loader, "server.timeout"))
+	assert.Equal(t, cconfig.MB, cconfig.MustGet[cconfig.ByteSize](loader, "server.max_body"))
+	assert.Equal(t, []string{"a.com", "b.com"}, cconfig.MustGet[[]string](loader, "server.hosts"))
+	assert.True(t, cconfig.MustGet[bool](loader, "server.debug"))
+
+	_, err = cconfig.Get[int](loader, "server.workers")
+	assert.True(t, errors.Is(err, cconfig.ErrKeyNotSet))
+
+	assert.Equal(t, 4, cconfig.MustGetOr(loader, "server.workers", 4))
+	assert.Equal(t, 8080, cconfig.MustGetOr(loader, "server.port", 80))
+
+	_, err = cconfig.Get[int](loader, "name")
+	assert.Error(t, err)
+
+	assert.Panics(t, func() {
+		cconfig.MustGet[int](loader, "server.workers")
+	})
+
+	type tlsConfig struct {
+		Enabled bool   `toml:"enabled"`
+		Cert    string `toml:"cert"`
+	}
+
+	tls, err := cconfig.GetOr(loader, "server.tls", tlsConfig{Cert: "cert.pem"})
+	assert.NoError(t, err)
+	assert.Equal(t, tlsConfig{Enabled: true, Cert: "cert.pem"}, tls)
+
+	tls, err = cconfig.GetOr(loader, "client.tls", tlsConfig{Cert: "client.pem"})
+	assert.NoError(t, err)
+	assert.Equal(t, tlsConfig{Cert: "client.pem"}, tls)
+}
@@ -0,0 +1,212 @@
+package cconfig
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/pelletier/go-toml"
+)
+
+const envRefPrefix = "env:"
+
+// interpolator expands the references to other keys (ex. ${db.host}) and environment variables (ex. ${env:HOME}) in
+// the string values of a tree.
+type interpolator struct {
+	tree      *toml.Tree
+	expanding map[string]bool
+	expanded  map[string]interface{}
+}
+
+// interpolate expands the references in the tree's string values. A reference can have a default value that is used
+// if the key or environment variable is not set (ex. ${env:PORT:-8080}), and "$${" is written as a literal "${". If
+// a string is a single reference to a key, it is replaced with the key's value as-is so that numbers, lists, etc.
+// keep their types.
+func interpolate(tree *toml.Tree) error {
+	in := &interpolator{
+		tree:      tree,
+		expanding: make(map[string]bool),
+		expanded:  make(map[string]interface{}),
+	}
+
+	for _, key := range leafKeys(tree, "") {
+		val, err := in.value(key)
+		if err != nil {
+			return err
+		}
+
+		if !reflect.DeepEqual(val, tree.Get(key)) {
+			tree.Set(key, val)
+		}
+	}
+
+	return nil
+}
+
+// value returns the key's value with its references expanded.
+func (in *interpolator) value(key string) (interface{}, error) {
+	if val, ok := in.expanded[key]; ok {
+		return val, nil
+	}
+
+	if in.expanding[key] {
+		return nil, cerrors.New(nil, "config values reference each other", map[string]interface{}{
+			"key": key,
+		})
+	}
+
+	in.expanding[key] = true
+	defer delete(in.expanding, key)
+
+	val, err := in.expandValue(key, in.tree.Get(key))
+	if err != nil {
+		return nil, err
+	}
+
+	in.expanded[key] = val
+
+	return val, nil
+}
+
+func (in *interpolator) expandValue(key string, val interface{}) (interface{}, error) {
+	switch v := val.(type) {
+	case string:
+		return in.expandString(key, v)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+
+		for i := range v {
+			item, err := in.expandValue(key, v[i])
+			if err != nil {
+				return nil, err
+			}
+
+			out[i] = item
+		}
+
+		return out, nil
+	case []*toml.Tree:
+		for _, t := range v {
+			for _, k := range leafKeys(t, "") {
+				item, err := in.expandValue(key+"."+k, t.Get(k))
+				if err != nil {
+					return nil, err
+				}
+
+				t.Set(k, item)
+			}
+		}
+
+		return v, nil
+	default:
+		return val, nil
+	}
+}
+
+func (in *interpolator) expandString(key, s string) (interface{}, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var (
+		out   strings.Builder
+		first = true
+	)
+
+	for ; ; first = false {
+		i := strings.Index(s, "${")
+		if i == -1 {
+			out.WriteString(s)
+			break
+		}
+
+		if i > 0 && s[i-1] == '$' {
+			out.WriteString(s[:i-1] + "${")
+			s = s[i+2:]
+
+			continue
+		}
+
+		end := strings.Index(s[i:], "}")
+		if end == -1 {
+			return nil, cerrors.New(nil, "config value has an unterminated reference", map[string]interface{}{
+				"key":   key,
+				"value": s,
+			})
+		}
+
+		ref := s[i+2 : i+end]
+
+		val, err := in.resolve(key, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		// A value that is a single reference keeps the referenced value's type
+		if first && i == 0 && end == len(s)-1 {
+			return val, nil
+		}
+
+		out.WriteString(s[:i])
+		out.WriteString(fmt.Sprint(val))
+
+		s = s[i+end+1:]
+	}
+
+	return out.String(), nil
+}
+
+// resolve returns the value for a reference (ex. db.host or env:HOME) found in the key's value.
+func (in *interpolator) resolve(key, ref string) (interface{}, error) {
+	name, def, hasDefault := ref, "", false
+	if i := strings.Index(ref, ":-"); i != -1 {
+		name, def, hasDefault = ref[:i], ref[i+2:], true
+	}
+
+	if strings.HasPrefix(name, envRefPrefix) {
+		val, ok := os.LookupEnv(strings.TrimPrefix(name, envRefPrefix))
+		if ok {
+			return val, nil
+		}
+
+		if hasDefault {
+			return def, nil
+		}
+
+		return nil, cerrors.New(nil, "config value references an environment variable that is not set",
+			map[string]interface{}{
+				"key": key,
+				"env": strings.TrimPrefix(name, envRefPrefix),
+			})
+	}
+
+	if !in.tree.Has(name) {
+		if hasDefault {
+			return def, nil
+		}
+
+		return nil, cerrors.New(nil, "config value references a key that is not set", map[string]interface{}{
+			"key": key,
+			"ref": name,
+		})
+	}
+
+	if _, ok := in.tree.Get(name).(*toml.Tree); ok {
+		return nil, cerrors.New(nil, "config value references a table", map[string]interface{}{
+			"key": key,
+			"ref": name,
+		})
+	}
+
+	val, err := in.value(name)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to expand referenced config value", map[string]interface{}{
+			"key": key,
+			"ref": name,
+		})
+	}
+
+	return val, nil
+}
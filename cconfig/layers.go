@@ -0,0 +1,98 @@
+package cconfig
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/pelletier/go-toml"
+)
+
+const (
+	// EnvVar is the environment variable that selects the app's environment (ex. prod). It defaults to dev. When the
+	// config path is a directory, the files in it are merged in this order, with later files overriding earlier ones:
+	//
+	//	base.toml     shared by all environments (optional)
+	//	<env>.toml    the environment's config (ex. prod.toml)
+	//	local.toml    overrides for the local machine, usually not committed (optional)
+	//
+	// Each file can be a TOML, YAML, or JSON file and can use the extends key.
+	EnvVar = "APP_ENV"
+
+	defaultEnv = "dev"
+)
+
+// configExts are the extensions of the layer files, in the order that they are looked up.
+var configExts = []string{".toml", ".yaml", ".yml", ".json"} //nolint:gochecknoglobals
+
+// Env returns the app's environment as set by APP_ENV. It defaults to dev.
+func Env() string {
+	env := os.Getenv(EnvVar)
+	if env == "" {
+		return defaultEnv
+	}
+
+	return env
+}
+
+// loadLayers loads and merges the config files in dir for the app's environment (see EnvVar). The paths of the
+// loaded files and the sources of the values are recorded in state.
+func loadLayers(dir string, disableKeyOverrides bool, state *loadState) (*toml.Tree, error) {
+	env := Env()
+
+	var tree *toml.Tree
+
+	for _, layer := range []string{"base", env, "local"} {
+		fp, ok := findLayerFile(dir, layer)
+		if !ok && layer == env {
+			return nil, cerrors.New(nil, "config file for environment does not exist", map[string]interface{}{
+				"dir": dir,
+				"env": env,
+			})
+		}
+
+		if !ok {
+			continue
+		}
+
+		layerState := newLoadState()
+
+		layerTree, err := loadTree(fp, disableKeyOverrides, layerState)
+		if err != nil {
+			return nil, err
+		}
+
+		state.files = append(state.files, layerState.files...)
+
+		for key, source := range layerState.sources {
+			state.sources[key] = source
+		}
+
+		if tree == nil {
+			tree = layerTree
+			continue
+		}
+
+		tree, err = mergeTrees(tree, layerTree, disableKeyOverrides)
+		if err != nil {
+			return nil, cerrors.New(err, "failed to merge config layer", map[string]interface{}{
+				"path": fp,
+			})
+		}
+	}
+
+	return tree, nil
+}
+
+func findLayerFile(dir, layer string) (string, bool) {
+	for _, ext := range configExts {
+		fp := filepath.Join(dir, layer+ext)
+
+		info, err := os.Stat(fp)
+		if err == nil && !info.IsDir() {
+			return fp, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,49 @@
+package cconfig_test
+
+import (
+	"path"
+	"testing"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWithKeyOverrides_Layers(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"base.toml": `
+			[server]
+			host = "0.0.0.0"
+			port = 7501
+			debug = true
+		`,
+		"prod.yaml":  "server:\n  port: 80\n  debug: false\n",
+		"local.json": `{"server": {"port": 8080}}`,
+	})
+
+	var config struct {
+		Host  string `toml:"host"`
+		Port  int    `toml:"port"`
+		Debug bool   `toml:"debug"`
+	}
+
+	_, err := cconfig.NewWithKeyOverrides(cconfig.Path(dir))
+	assert.Error(t, err, "dev.toml does not exist")
+
+	setenv(t, cconfig.EnvVar, "prod")
+
+	loader, err := cconfig.NewWithKeyOverrides(cconfig.Path(dir))
+	assert.NoError(t, err)
+
+	assert.NoError(t, loader.Load("server", &config))
+	assert.Equal(t, "0.0.0.0", config.Host)
+	assert.Equal(t, 8080, config.Port)
+	assert.False(t, config.Debug)
+
+	keys := cconfig.Dump(loader, nil)
+	assert.Equal(t, path.Join(dir, "prod.yaml"), keys[0].Source)
+	assert.Equal(t, path.Join(dir, "base.toml"), keys[1].Source)
+	assert.Equal(t, path.Join(dir, "local.json"), keys[2].Source)
+}
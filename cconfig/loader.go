@@ -1,6 +1,12 @@
 package cconfig
 
 import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
 	"github.com/gocopper/copper/cerrors"
 	"github.com/pelletier/go-toml"
 )
@@ -30,6 +36,14 @@ type Loader interface {
 	//
 	//   return config, nil
 	// }
+	//
+	// Durations (ex. "30s") and byte sizes (ex. "10MB", see ByteSize) are parsed from strings, and lists can be
+	// written as comma-separated strings (ex. "a, b, c") as well as arrays.
+	//
+	// String values with the "enc:" prefix are decrypted with the key in CONFIG_ENCRYPTION_KEY (see Encrypt).
+	//
+	// Fields can be validated with a `validate` tag (ex. `validate:"required,min=1"`). Load returns a
+	// *ValidationError that lists every invalid value if any of them do not pass. See ValidationError for the rules.
 	Load(key string, dest interface{}) error
 }
 
@@ -49,6 +63,26 @@ type Loader interface {
 // The extends key can support multiple files like so:
 // extends = ["base.toml", "secrets.toml"]
 //
+// Large configs can be split by concern with an 'include' key. The included files are merged into the file that
+// includes them, which can still override their values if key overrides are enabled:
+//
+// # prod.toml
+// include = ["db.toml", "mailer.toml"]
+//
+// Paths are relative to the file and can be glob patterns (ex. "conf.d/*.toml"). Files that extend or include each
+// other cause New to return an error.
+//
+// Values can reference other keys and environment variables, which are expanded when the config is loaded:
+//
+// url = "postgres://${db.user}@${db.host}/app?sslmode=${env:DB_SSLMODE:-require}"
+//
+// New returns an error if a referenced key or environment variable is not set and the reference has no default.
+//
+// Config files can also be written in YAML (.yaml or .yml) or JSON (.json), and files of different formats can extend
+// each other. The keys are matched with the same `toml` struct tags in all formats.
+//
+// The path can also be a directory with a config file for each environment. See EnvVar for how they are layered.
+//
 // If a config key is present in multiple files, New returns an error. For example, if prod.toml sets a value for 'key1'
 // that has already been set in base.toml, an error will be returned. To enable key overrides see NewWithKeyOverrides.
 func New(fp Path) (Loader, error) {
@@ -70,40 +104,228 @@ func NewWithKeyOverrides(fp Path) (Loader, error) {
 }
 
 func newLoader(fp string, disableKeyOverrides bool) (*loader, error) {
-	tree, err := loadTree(fp, disableKeyOverrides)
+	l := &loader{
+		fp:                  fp,
+		disableKeyOverrides: disableKeyOverrides,
+		loaded:              make(map[string]bool),
+		values:              make(map[string]reflect.Value),
+		encrypted:           make(map[string]bool),
+		watches:             make(map[int]*watch),
+	}
+
+	_, err := l.reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+type loader struct {
+	fp                  string
+	disableKeyOverrides bool
+	env                 bool
+	envPrefix           string
+	flags               FlagOverrides
+
+	mu        sync.RWMutex
+	strict    bool
+	loaded    map[string]bool
+	values    map[string]reflect.Value
+	encrypted map[string]bool
+	tree      *toml.Tree
+	files     []string
+	sources   map[string]string
+	remotes   []RemoteProvider
+	overrides *Overrides
+	watchers  []*Watcher
+
+	watchMu     sync.Mutex
+	watches     map[int]*watch
+	nextWatchID int
+}
+
+// reload reads the config files again and returns the top-level keys whose values changed.
+func (l *loader) reload() ([]string, error) {
+	var (
+		state = newLoadState()
+		tree  *toml.Tree
+		err   error
+	)
+
+	if info, statErr := os.Stat(l.fp); statErr == nil && info.IsDir() {
+		tree, err = loadLayers(l.fp, l.disableKeyOverrides, state)
+	} else {
+		tree, err = loadTree(l.fp, l.disableKeyOverrides, state)
+	}
+
 	if err != nil {
 		return nil, cerrors.New(err, "failed to load config tree", map[string]interface{}{
-			"path": fp,
+			"path": l.fp,
+		})
+	}
+
+	l.mu.RLock()
+	remotes := l.remotes
+	l.mu.RUnlock()
+
+	tree, err = mergeRemotes(context.Background(), tree, remotes, state.sources)
+	if err != nil {
+		return nil, err
+	}
+
+	err = interpolate(tree)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to expand config values", map[string]interface{}{
+			"path": l.fp,
 		})
 	}
 
-	return &loader{
-		tree: tree,
-	}, nil
+	l.mu.Lock()
+	oldTree := l.tree
+	l.tree = tree
+	l.files = state.files
+	l.sources = state.sources
+	l.mu.Unlock()
+
+	l.notifyWatches()
+
+	return changedKeys(oldTree, tree), nil
 }
 
-type loader struct {
-	tree *toml.Tree
+func (l *loader) hasRemotes() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return len(l.remotes) > 0
+}
+
+func (l *loader) watchedFiles() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return append([]string(nil), l.files...)
 }
 
 func (l *loader) Load(key string, dest interface{}) error {
-	if !l.tree.Has(key) {
-		return nil
+	unknown, err := l.loadTree(key, dest)
+	if err != nil {
+		return err
 	}
 
-	keyTree, ok := l.tree.Get(key).(*toml.Tree)
+	if l.env {
+		err = applyEnvOverrides(l.envPrefix, key, dest)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = applyFlagOverrides(key, l.flags.Values, dest)
+	if err != nil {
+		return err
+	}
+
+	err = applyRuntimeOverrides(key, l.overrideValues(), dest)
+	if err != nil {
+		return err
+	}
+
+	encrypted := encryptedKeys(key, dest)
+
+	err = decryptValues(dest)
+	if err != nil {
+		return cerrors.New(err, "failed to decrypt config values", map[string]interface{}{
+			"key": key,
+		})
+	}
+
+	errs := append(unknown, validate(key, dest)...)
+	if len(errs) > 0 {
+		return &ValidationError{Fields: errs}
+	}
+
+	l.recordValue(key, dest, encrypted)
+
+	return nil
+}
+
+// baseLoader returns the loader created by this package that l wraps, if any.
+func baseLoader(l Loader) (*loader, bool) {
+	for {
+		switch v := l.(type) {
+		case *loader:
+			return v, true
+		case interface{ unwrap() Loader }:
+			l = v.unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
+
+// recordValue keeps a copy of the loaded struct so that its keys can be listed by Keys. The keys whose values were
+// decrypted are recorded so that Dump redacts them.
+func (l *loader) recordValue(key string, dest interface{}, encrypted []string) {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	val := reflect.New(v.Elem().Type()).Elem()
+	val.Set(v.Elem())
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.values[key] = val
+
+	for k := range l.encrypted {
+		if strings.HasPrefix(k, key+".") {
+			delete(l.encrypted, k)
+		}
+	}
+
+	for _, k := range encrypted {
+		l.encrypted[k] = true
+	}
+}
+
+// loadTree unmarshals the values under the key into dest. In strict mode, it returns the keys that do not map to a
+// field of dest.
+func (l *loader) loadTree(key string, dest interface{}) ([]FieldError, error) {
+	l.mu.Lock()
+	tree, strict := l.tree, l.strict
+	l.loaded[key] = true
+	l.mu.Unlock()
+
+	if !tree.Has(key) {
+		return nil, nil
+	}
+
+	keyTree, ok := tree.Get(key).(*toml.Tree)
 	if !ok {
-		return cerrors.New(nil, "invalid key type", map[string]interface{}{
+		return nil, cerrors.New(nil, "invalid key type", map[string]interface{}{
 			"key": key,
 		})
 	}
 
-	err := keyTree.Unmarshal(dest)
+	valTree, err := withConvertedStrings(keyTree, dest)
 	if err != nil {
-		return cerrors.New(err, "failed to unmarshal config into dest", map[string]interface{}{
+		return nil, cerrors.New(err, "failed to convert config values", map[string]interface{}{
 			"key": key,
 		})
 	}
 
-	return nil
+	err = valTree.Unmarshal(dest)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to unmarshal config into dest", map[string]interface{}{
+			"key": key,
+		})
+	}
+
+	if !strict {
+		return nil, nil
+	}
+
+	return unknownKeys(key, keyTree, reflect.TypeOf(dest)), nil
 }
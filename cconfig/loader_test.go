@@ -1,8 +1,10 @@
 package cconfig_test
 
 import (
+	"os"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/gocopper/copper/cconfig"
 	"github.com/gocopper/copper/cconfig/cconfigtest"
@@ -53,3 +55,218 @@ func TestLoader_Load(t *testing.T) {
 		assert.Contains(t, err.Error(), "key is being overridden when key overrides are disabled")
 	})
 }
+
+func TestLoader_Load_Formats(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"base.json": `{
+			"group1": {"key1": "val1", "port": 8080, "ratio": 0.5, "unset": null},
+			"group2": {"items": [{"name": "a"}, {"name": "b"}]}
+		}`,
+		"test.yaml": "extends: base.json\n" +
+			"group1:\n" +
+			"  key1: val2\n" +
+			"  tags: [x, y]\n",
+	})
+
+	var testConfig struct {
+		Key1  string   `toml:"key1"`
+		Port  int      `toml:"port"`
+		Ratio float64  `toml:"ratio"`
+		Tags  []string `toml:"tags"`
+	}
+
+	configs, err := cconfig.NewWithKeyOverrides(cconfig.Path(path.Join(dir, "test.yaml")))
+	assert.NoError(t, err)
+
+	err = configs.Load("group1", &testConfig)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "val2", testConfig.Key1)
+	assert.Equal(t, 8080, testConfig.Port)
+	assert.Equal(t, 0.5, testConfig.Ratio)
+	assert.Equal(t, []string{"x", "y"}, testConfig.Tags)
+
+	var testConfig2 struct {
+		Items []struct {
+			Name string `toml:"name"`
+		} `toml:"items"`
+	}
+
+	err = configs.Load("group2", &testConfig2)
+	assert.NoError(t, err)
+
+	assert.Len(t, testConfig2.Items, 2)
+	assert.Equal(t, "b", testConfig2.Items[1].Name)
+}
+
+func TestLoader_Load_EnvOverrides(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[group1]
+			key1 = "val1"
+			port = 80
+		`,
+	})
+
+	setenv(t, "TEST_GROUP1_PORT", "8080")
+	setenv(t, "TEST_GROUP1_TIMEOUT", "5s")
+	setenv(t, "TEST_GROUP1_LISTEN", ":80, :81")
+	setenv(t, "TEST_GROUP1_NESTED_ENABLED", "true")
+
+	var testConfig struct {
+		Key1    string        `toml:"key1"`
+		Port    int           `toml:"port"`
+		Timeout time.Duration `toml:"timeout"`
+		Listen  []string      `toml:"listen"`
+		Nested  struct {
+			Enabled bool `toml:"enabled"`
+		} `toml:"nested"`
+	}
+
+	configs, err := cconfig.NewWithEnvOverrides(cconfig.Path(path.Join(dir, "test.toml")), "TEST_")
+	assert.NoError(t, err)
+
+	err = configs.Load("group1", &testConfig)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "val1", testConfig.Key1)
+	assert.Equal(t, 8080, testConfig.Port)
+	assert.Equal(t, 5*time.Second, testConfig.Timeout)
+	assert.Equal(t, []string{":80", ":81"}, testConfig.Listen)
+	assert.True(t, testConfig.Nested.Enabled)
+
+	setenv(t, "TEST_GROUP1_PORT", "eighty")
+
+	err = configs.Load("group1", &testConfig)
+	assert.Error(t, err)
+}
+
+func setenv(t *testing.T, key, val string) {
+	t.Helper()
+
+	assert.NoError(t, os.Setenv(key, val))
+
+	t.Cleanup(func() {
+		assert.NoError(t, os.Unsetenv(key))
+	})
+}
+
+func TestLoader_Load_Include(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"db.toml": `
+			[db]
+			host = "localhost"
+			port = 5432
+		`,
+		"mailer.yaml": "mailer:\n  from: noreply@example.com\n",
+		"test.toml": `
+			include = ["db.toml", "mailer.yaml"]
+
+			[db]
+			host = "db.internal"
+		`,
+		"a.toml": `
+			include = "b.toml"
+		`,
+		"b.toml": `
+			extends = "a.toml"
+		`,
+	})
+
+	var (
+		db struct {
+			Host string `toml:"host"`
+			Port int    `toml:"port"`
+		}
+		mailer struct {
+			From string `toml:"from"`
+		}
+	)
+
+	configs, err := cconfig.NewWithKeyOverrides(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+
+	assert.NoError(t, configs.Load("db", &db))
+	assert.NoError(t, configs.Load("mailer", &mailer))
+
+	assert.Equal(t, "db.internal", db.Host)
+	assert.Equal(t, 5432, db.Port)
+	assert.Equal(t, "noreply@example.com", mailer.From)
+
+	_, err = cconfig.New(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.Error(t, err)
+
+	_, err = cconfig.New(cconfig.Path(path.Join(dir, "a.toml")))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "config files extend or include each other")
+}
+
+func TestLoader_Load_Interpolation(t *testing.T) {
+	t.Parallel()
+
+	setenv(t, "INTERPOLATION_TEST_SSLMODE", "disable")
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[db]
+			user = "app"
+			host = "${db.hosts}"
+			hosts = "localhost"
+			port = 5432
+			db_port = "${db.port}"
+			url = "postgres://${db.user}@${db.host}:${db.port}/app?sslmode=${env:INTERPOLATION_TEST_SSLMODE}"
+			pool = "${env:INTERPOLATION_TEST_UNSET:-10}"
+			tags = ["${db.user}", "$${literal}"]
+		`,
+		"missing.toml": `
+			[db]
+			url = "postgres://${db.user}@localhost/app"
+		`,
+		"missing_env.toml": `
+			[db]
+			password = "${env:INTERPOLATION_TEST_UNSET}"
+		`,
+		"cycle.toml": `
+			[db]
+			a = "${db.b}"
+			b = "x${db.a}"
+		`,
+	})
+
+	var config struct {
+		Host   string   `toml:"host"`
+		DBPort int      `toml:"db_port"`
+		URL    string   `toml:"url"`
+		Pool   int      `toml:"pool"`
+		Tags   []string `toml:"tags"`
+	}
+
+	configs, err := cconfig.New(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+
+	assert.NoError(t, configs.Load("db", &config))
+	assert.Equal(t, "localhost", config.Host)
+	assert.Equal(t, 5432, config.DBPort)
+	assert.Equal(t, "postgres://app@localhost:5432/app?sslmode=disable", config.URL)
+	assert.Equal(t, 10, config.Pool)
+	assert.Equal(t, []string{"app", "${literal}"}, config.Tags)
+
+	_, err = cconfig.New(cconfig.Path(path.Join(dir, "missing.toml")))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "config value references a key that is not set")
+	assert.Contains(t, err.Error(), "ref=db.user")
+
+	_, err = cconfig.New(cconfig.Path(path.Join(dir, "missing_env.toml")))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "env=INTERPOLATION_TEST_UNSET")
+
+	_, err = cconfig.New(cconfig.Path(path.Join(dir, "cycle.toml")))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "config values reference each other")
+}
@@ -0,0 +1,357 @@
+package cconfig
+
+import (
+	"context"
+	"errors"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cerrors"
+)
+
+const defaultOverrideSweepInterval = time.Second
+
+// Actions recorded in an AuditEntry.
+const (
+	OverrideSet     = "set"
+	OverrideDeleted = "deleted"
+	OverrideExpired = "expired"
+)
+
+// ErrKeyNotOverridable is returned by Overrides.Set when the key does not match any of the allowed keys.
+var ErrKeyNotOverridable = errors.New("config key cannot be overridden")
+
+type (
+	// NewOverridesParams holds the params needed to create Overrides.
+	NewOverridesParams struct {
+		// Loader must be created by one of the constructors in this package, optionally wrapped by WithSecrets.
+		Loader Loader
+
+		// AllowedKeys lists the keys that can be overridden. A key can be a pattern (see path.Match) such as
+		// "ratelimit.*". No keys can be overridden if it is empty.
+		AllowedKeys []string
+
+		// Clock is used to expire overrides. It defaults to the system clock.
+		Clock cclock.Clock
+
+		// OnAudit is called with an entry for each change, including expiry, so that it can be written to an audit log.
+		OnAudit func(entry AuditEntry)
+	}
+
+	// Override is a value that temporarily replaces a config value in a running process.
+	Override struct {
+		Key       string    `json:"key"`
+		Value     string    `json:"value"`
+		Actor     string    `json:"actor,omitempty"`
+		CreatedAt time.Time `json:"created_at"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+
+	// AuditEntry records a change to the overrides.
+	AuditEntry struct {
+		Time      time.Time `json:"time"`
+		Action    string    `json:"action"`
+		Key       string    `json:"key"`
+		Value     string    `json:"value,omitempty"`
+		OldValue  string    `json:"old_value,omitempty"`
+		Actor     string    `json:"actor,omitempty"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+
+	// Overrides temporarily replaces whitelisted config values in a running process, for example to enable maintenance
+	// mode or to raise a rate limit during an incident. Overridden values take precedence over every other source,
+	// including flags, and are removed once they expire. They are not persisted, so they are lost when the process
+	// restarts. Each change is sent to the loader's watches (see KeyWatcher), and to the subscribers of the Watchers
+	// created for the loader, the same way as when the config files change.
+	Overrides struct {
+		src     *loader
+		allowed []string
+		clock   cclock.Clock
+		onAudit func(entry AuditEntry)
+
+		mu        sync.Mutex
+		overrides map[string]Override
+	}
+)
+
+// NewOverrides creates new Overrides and attaches them to the loader.
+func NewOverrides(p NewOverridesParams) (*Overrides, error) {
+	src, ok := baseLoader(p.Loader)
+	if !ok {
+		return nil, cerrors.New(nil, "loader does not support overrides", nil)
+	}
+
+	if p.Clock == nil {
+		p.Clock = cclock.New()
+	}
+
+	o := &Overrides{
+		src:       src,
+		allowed:   p.AllowedKeys,
+		clock:     p.Clock,
+		onAudit:   p.OnAudit,
+		overrides: make(map[string]Override),
+	}
+
+	src.mu.Lock()
+	src.overrides = o
+	src.mu.Unlock()
+
+	return o, nil
+}
+
+// Set overrides the value of the key (ex. "chttp.maintenance.enabled") until the ttl passes. The value is written the
+// same way as in a flag or an environment variable. The actor (ex. the user's email) is recorded in the audit entry.
+func (o *Overrides) Set(key, value string, ttl time.Duration, actor string) (Override, error) {
+	if !o.isAllowed(key) {
+		return Override{}, cerrors.New(ErrKeyNotOverridable, "failed to override config key", map[string]interface{}{
+			"key": key,
+		})
+	}
+
+	if ttl <= 0 {
+		return Override{}, cerrors.New(nil, "config override must expire", map[string]interface{}{
+			"key": key,
+			"ttl": ttl.String(),
+		})
+	}
+
+	err := o.src.checkValue(key, value)
+	if err != nil {
+		return Override{}, cerrors.New(err, "invalid config override value", map[string]interface{}{
+			"key": key,
+		})
+	}
+
+	o.Expire()
+
+	now := o.clock.Now()
+	override := Override{
+		Key:       key,
+		Value:     value,
+		Actor:     actor,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	o.mu.Lock()
+	old := o.overrides[key]
+	o.overrides[key] = override
+	o.mu.Unlock()
+
+	o.audit(AuditEntry{
+		Time:      now,
+		Action:    OverrideSet,
+		Key:       key,
+		Value:     value,
+		OldValue:  old.Value,
+		Actor:     actor,
+		ExpiresAt: override.ExpiresAt,
+	})
+	o.notify([]string{key})
+
+	return override, nil
+}
+
+// Delete removes the override for the key, if any, so that the key has its configured value again.
+func (o *Overrides) Delete(key, actor string) {
+	o.Expire()
+
+	o.mu.Lock()
+	old, ok := o.overrides[key]
+	delete(o.overrides, key)
+	o.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	o.audit(AuditEntry{
+		Time:      o.clock.Now(),
+		Action:    OverrideDeleted,
+		Key:       key,
+		OldValue:  old.Value,
+		Actor:     actor,
+		ExpiresAt: old.ExpiresAt,
+	})
+	o.notify([]string{key})
+}
+
+// List returns the overrides that have not expired, sorted by key.
+func (o *Overrides) List() []Override {
+	o.Expire()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	overrides := make([]Override, 0, len(o.overrides))
+	for _, override := range o.overrides {
+		overrides = append(overrides, override)
+	}
+
+	sort.Slice(overrides, func(i, j int) bool {
+		return overrides[i].Key < overrides[j].Key
+	})
+
+	return overrides
+}
+
+// Expire removes the overrides that have expired. Expired overrides are never loaded, but they are only removed (and
+// audited) when Expire is called, which is done by the other methods and by Run.
+func (o *Overrides) Expire() {
+	now := o.clock.Now()
+	expired := make([]Override, 0)
+
+	o.mu.Lock()
+	for key, override := range o.overrides {
+		if !now.Before(override.ExpiresAt) {
+			expired = append(expired, override)
+			delete(o.overrides, key)
+		}
+	}
+	o.mu.Unlock()
+
+	keys := make([]string, len(expired))
+
+	for i, override := range expired {
+		keys[i] = override.Key
+
+		o.audit(AuditEntry{
+			Time:      now,
+			Action:    OverrideExpired,
+			Key:       override.Key,
+			OldValue:  override.Value,
+			Actor:     override.Actor,
+			ExpiresAt: override.ExpiresAt,
+		})
+	}
+
+	o.notify(keys)
+}
+
+// Run removes the overrides as soon as they expire until ctx is canceled. It is only needed to notify the watches and
+// the Watchers' subscribers when an override expires.
+func (o *Overrides) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-o.clock.After(defaultOverrideSweepInterval):
+			o.Expire()
+		}
+	}
+}
+
+// values returns the values of the overrides that have not expired.
+func (o *Overrides) values() map[string]string {
+	now := o.clock.Now()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	values := make(map[string]string, len(o.overrides))
+
+	for key, override := range o.overrides {
+		if now.Before(override.ExpiresAt) {
+			values[key] = override.Value
+		}
+	}
+
+	return values
+}
+
+func (o *Overrides) isAllowed(key string) bool {
+	for _, pattern := range o.allowed {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o *Overrides) audit(entry AuditEntry) {
+	if o.onAudit != nil {
+		o.onAudit(entry)
+	}
+}
+
+func (o *Overrides) notify(keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	o.src.notifyWatches()
+	o.src.notifyWatchers(keys)
+}
+
+// overrideValues returns the values of the loader's runtime overrides, if any.
+func (l *loader) overrideValues() map[string]string {
+	l.mu.RLock()
+	overrides := l.overrides
+	l.mu.RUnlock()
+
+	if overrides == nil {
+		return nil
+	}
+
+	return overrides.values()
+}
+
+// applyRuntimeOverrides sets the fields of dest that have a runtime override. Overrides that do not match a field are
+// skipped since they may be for a key that is read with Get.
+func applyRuntimeOverrides(key string, overrides map[string]string, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	for overrideKey, raw := range overrides {
+		if !strings.HasPrefix(overrideKey, key+".") {
+			continue
+		}
+
+		fieldVal, ok := fieldByKeyPath(v.Elem(), strings.Split(strings.TrimPrefix(overrideKey, key+"."), "."))
+		if !ok {
+			continue
+		}
+
+		err := setStringValue(fieldVal, raw)
+		if err != nil {
+			return cerrors.New(err, "invalid config value in override", map[string]interface{}{
+				"override": overrideKey,
+			})
+		}
+	}
+
+	return nil
+}
+
+// checkValue returns an error if the key matches a field of a struct that has been loaded and the value cannot be set
+// into it. Keys that do not match a loaded field cannot be checked.
+func (l *loader) checkValue(key, value string) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for loadedKey, v := range l.values {
+		if !strings.HasPrefix(key, loadedKey+".") {
+			continue
+		}
+
+		val := reflect.New(v.Type()).Elem()
+
+		field, ok := fieldByKeyPath(val, strings.Split(strings.TrimPrefix(key, loadedKey+"."), "."))
+		if !ok {
+			continue
+		}
+
+		return setStringValue(field, value)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package cconfig_test
+
+import (
+	"errors"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverrides(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[ratelimit]
+			rps = 10
+			burst = 20
+		`,
+	})
+
+	type rateLimitConfig struct {
+		RPS   int `toml:"rps"`
+		Burst int `toml:"burst"`
+	}
+
+	loader, err := cconfig.New(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+
+	watcher, err := cconfig.NewWatcher(cconfig.NewWatcherParams{Loader: loader})
+	assert.NoError(t, err)
+
+	var (
+		clock   = cclock.NewFake(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+		entries = make([]cconfig.AuditEntry, 0)
+		changes = 0
+		config  rateLimitConfig
+	)
+
+	watcher.OnChange("ratelimit", func(l cconfig.Loader) {
+		changes++
+	})
+
+	overrides, err := cconfig.NewOverrides(cconfig.NewOverridesParams{
+		Loader:      loader,
+		AllowedKeys: []string{"ratelimit.rps"},
+		Clock:       clock,
+		OnAudit: func(entry cconfig.AuditEntry) {
+			entries = append(entries, entry)
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, loader.Load("ratelimit", &config))
+	assert.Equal(t, rateLimitConfig{RPS: 10, Burst: 20}, config)
+
+	_, err = overrides.Set("ratelimit.burst", "50", time.Minute, "ops")
+	assert.True(t, errors.Is(err, cconfig.ErrKeyNotOverridable))
+
+	_, err = overrides.Set("ratelimit.rps", "fast", time.Minute, "ops")
+	assert.Error(t, err)
+
+	_, err = overrides.Set("ratelimit.rps", "100", 0, "ops")
+	assert.Error(t, err)
+
+	override, err := overrides.Set("ratelimit.rps", "100", time.Minute, "ops")
+	assert.NoError(t, err)
+	assert.Equal(t, clock.Now().Add(time.Minute), override.ExpiresAt)
+	assert.Equal(t, []cconfig.Override{override}, overrides.List())
+	assert.Equal(t, 1, changes)
+
+	assert.NoError(t, loader.Load("ratelimit", &config))
+	assert.Equal(t, rateLimitConfig{RPS: 100, Burst: 20}, config)
+	assert.Equal(t, 100, cconfig.MustGet[int](loader, "ratelimit.rps"))
+	assert.Contains(t, cconfig.Dump(loader, nil), cconfig.KeyInfo{
+		Key:    "ratelimit.rps",
+		Type:   "int",
+		Value:  "100",
+		Source: "override",
+	})
+
+	clock.Advance(time.Minute)
+
+	assert.NoError(t, loader.Load("ratelimit", &config))
+	assert.Equal(t, rateLimitConfig{RPS: 10, Burst: 20}, config)
+	assert.Empty(t, overrides.List())
+	assert.Equal(t, 2, changes)
+
+	_, err = overrides.Set("ratelimit.rps", "200", time.Hour, "ops")
+	assert.NoError(t, err)
+
+	overrides.Delete("ratelimit.rps", "admin")
+	assert.Equal(t, 10, cconfig.MustGet[int](loader, "ratelimit.rps"))
+
+	actions := make([]string, len(entries))
+	for i, entry := range entries {
+		actions[i] = entry.Action
+	}
+
+	assert.Equal(t, []string{
+		cconfig.OverrideSet,
+		cconfig.OverrideExpired,
+		cconfig.OverrideSet,
+		cconfig.OverrideDeleted,
+	}, actions)
+	assert.Equal(t, cconfig.AuditEntry{
+		Time:      clock.Now(),
+		Action:    cconfig.OverrideDeleted,
+		Key:       "ratelimit.rps",
+		OldValue:  "200",
+		Actor:     "admin",
+		ExpiresAt: clock.Now().Add(time.Hour),
+	}, entries[3])
+}
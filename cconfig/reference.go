@@ -0,0 +1,157 @@
+package cconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+// Formats supported by WriteReference.
+const (
+	ReferenceMarkdown = "markdown"
+	ReferenceJSON     = "json"
+)
+
+const configReferenceFlag = "config-reference"
+
+// KeyDoc documents a config key for the reference written by WriteReference.
+type KeyDoc struct {
+	Key         string `json:"key"`
+	Type        string `json:"type"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// Reference documents the keys of every struct that has been passed to Load, sorted by key. The default and
+// description of a key are read from the `default` and `desc` tags of its field, and it is required if its `validate`
+// tag has the required rule. Load also sets the `default` value into fields of basic types (strings, numbers, and
+// bools) that are not in the config. For example:
+//
+//	type Config struct {
+//		Port uint `toml:"port" default:"7501" desc:"Port that the HTTP server listens on"`
+//	}
+func Reference(l Loader) []KeyDoc {
+	base, ok := baseLoader(l)
+	if !ok {
+		return nil
+	}
+
+	base.mu.RLock()
+	defer base.mu.RUnlock()
+
+	docs := make([]KeyDoc, 0)
+
+	for key, v := range base.values {
+		docs = append(docs, structDocs(key, v.Type())...)
+	}
+
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].Key < docs[j].Key
+	})
+
+	return docs
+}
+
+// WriteReference writes the reference returned by Reference as a Markdown table (ReferenceMarkdown) or as a JSON
+// list (ReferenceJSON), so deployment docs can be generated from code.
+func WriteReference(w io.Writer, l Loader, format string) error {
+	docs := Reference(l)
+
+	switch format {
+	case ReferenceJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(docs)
+	case ReferenceMarkdown, "":
+		_, _ = fmt.Fprintln(w, "| Key | Type | Default | Required | Description |")
+		_, _ = fmt.Fprintln(w, "| --- | --- | --- | --- | --- |")
+
+		for _, d := range docs {
+			required := ""
+			if d.Required {
+				required = "yes"
+			}
+
+			_, _ = fmt.Fprintf(w, "| `%s` | `%s` | %s | %s | %s |\n", d.Key, d.Type, markdownCell(d.Default), required,
+				markdownCell(d.Description))
+		}
+
+		return nil
+	default:
+		return cerrors.New(nil, "unknown config reference format", map[string]interface{}{
+			"format": format,
+		})
+	}
+}
+
+// ReferenceRequested returns the format passed with the --config-reference flag, or an empty string if it was not
+// passed. Apps should print the reference with WriteReference and exit once their dependencies are created.
+func ReferenceRequested(l Loader) string {
+	base, ok := baseLoader(l)
+	if !ok {
+		return ""
+	}
+
+	return base.flags.Reference
+}
+
+func structDocs(key string, t reflect.Type) []KeyDoc {
+	docs := make([]KeyDoc, 0)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fieldKey := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if fieldKey == "-" {
+			continue
+		}
+
+		if fieldKey == "" {
+			fieldKey = strings.ToLower(field.Name)
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}) {
+			docs = append(docs, structDocs(key+"."+fieldKey, field.Type)...)
+			continue
+		}
+
+		docs = append(docs, KeyDoc{
+			Key:         key + "." + fieldKey,
+			Type:        field.Type.String(),
+			Default:     field.Tag.Get("default"),
+			Description: field.Tag.Get("desc"),
+			Required:    hasRule(field.Tag.Get("validate"), "required"),
+		})
+	}
+
+	return docs
+}
+
+func hasRule(tag, rule string) bool {
+	for _, r := range strings.Split(tag, ",") {
+		if strings.TrimSpace(r) == rule {
+			return true
+		}
+	}
+
+	return false
+}
+
+func markdownCell(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
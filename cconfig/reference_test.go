@@ -0,0 +1,67 @@
+package cconfig_test
+
+import (
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReference(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[server]
+			port = 7501
+		`,
+	})
+
+	var config struct {
+		Port    uint          `toml:"port" validate:"required" desc:"Port that the server | listens on"`
+		Timeout time.Duration `toml:"timeout"`
+		Workers int           `toml:"workers" default:"4"`
+		TLS     struct {
+			Cert string `toml:"cert" desc:"Path to the certificate"`
+		} `toml:"tls"`
+	}
+
+	loader, err := cconfig.New(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+	assert.NoError(t, loader.Load("server", &config))
+	assert.Equal(t, 4, config.Workers)
+
+	assert.Equal(t, []cconfig.KeyDoc{
+		{Key: "server.port", Type: "uint", Description: "Port that the server | listens on", Required: true},
+		{Key: "server.timeout", Type: "time.Duration"},
+		{Key: "server.tls.cert", Type: "string", Description: "Path to the certificate"},
+		{Key: "server.workers", Type: "int", Default: "4"},
+	}, cconfig.Reference(loader))
+
+	var md strings.Builder
+
+	assert.NoError(t, cconfig.WriteReference(&md, loader, cconfig.ReferenceMarkdown))
+	assert.Contains(t, md.String(), "| `server.port` | `uint` |  | yes | Port that the server \\| listens on |")
+	assert.Contains(t, md.String(), "| `server.workers` | `int` | 4 |  |  |")
+
+	var js strings.Builder
+
+	assert.NoError(t, cconfig.WriteReference(&js, loader, cconfig.ReferenceJSON))
+	assert.Contains(t, js.String(), `"key": "server.tls.cert"`)
+
+	assert.Error(t, cconfig.WriteReference(&js, loader, "yaml"))
+}
+
+func TestParseFlagOverrides_Reference(t *testing.T) {
+	t.Parallel()
+
+	overrides, _ := cconfig.ParseFlagOverrides([]string{"--config-reference"})
+	assert.Equal(t, cconfig.ReferenceMarkdown, overrides.Reference)
+
+	overrides, _ = cconfig.ParseFlagOverrides([]string{"--config-reference=json"})
+	assert.Equal(t, cconfig.ReferenceJSON, overrides.Reference)
+}
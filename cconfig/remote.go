@@ -0,0 +1,78 @@
+package cconfig
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/pelletier/go-toml"
+)
+
+// RemoteProvider loads config values from a remote key-value store (ex. etcd or Consul KV) so that settings can be
+// shared by a fleet of app instances.
+type RemoteProvider interface {
+	// Fetch returns the values in the store with dot-separated keys (ex. chttp.port). Values are parsed as TOML
+	// values (ex. 8080, true, or ["a", "b"]), and values that cannot be parsed are loaded as strings.
+	Fetch(ctx context.Context) (map[string]string, error)
+}
+
+// WithRemote adds remote providers to a loader created by this package. Their values are merged over the values in
+// the config files, in order, under the same keys. Environment variables still override them. If the loader is used
+// with a Watcher, the providers are fetched again on every interval and subscribers are notified of the keys that
+// changed.
+func WithRemote(l Loader, providers ...RemoteProvider) (Loader, error) {
+	base, ok := baseLoader(l)
+	if !ok {
+		return nil, cerrors.New(nil, "loader does not support remote providers", nil)
+	}
+
+	err := base.setRemotes(providers)
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func (l *loader) setRemotes(providers []RemoteProvider) error {
+	l.mu.Lock()
+	l.remotes = append(l.remotes, providers...)
+	l.mu.Unlock()
+
+	_, err := l.reload()
+
+	return err
+}
+
+// remoteSource is the source of the values set by remote providers.
+const remoteSource = "remote"
+
+// mergeRemotes fetches the values from the providers and merges them over the tree. The keys that they set are
+// recorded in sources.
+func mergeRemotes(ctx context.Context, tree *toml.Tree, providers []RemoteProvider,
+	sources map[string]string) (*toml.Tree, error) {
+	for i := range providers {
+		values, err := providers[i].Fetch(ctx)
+		if err != nil {
+			return nil, cerrors.New(err, "failed to fetch remote config", map[string]interface{}{
+				"provider": i,
+			})
+		}
+
+		for key, raw := range values {
+			tree.SetPath(strings.Split(key, "."), parseTOMLValue(raw))
+			sources[key] = remoteSource
+		}
+	}
+
+	return tree, nil
+}
+
+func parseTOMLValue(raw string) interface{} {
+	t, err := toml.Load("v = " + raw)
+	if err != nil {
+		return raw
+	}
+
+	return t.Get("v")
+}
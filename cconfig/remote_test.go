@@ -0,0 +1,82 @@
+package cconfig_test
+
+import (
+	"context"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeKV struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (kv *fakeKV) Fetch(ctx context.Context) (map[string]string, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	values := make(map[string]string, len(kv.values))
+	for k, v := range kv.values {
+		values[k] = v
+	}
+
+	return values, nil
+}
+
+func TestWithRemote(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir = cconfigtest.SetupDirWithConfigs(t, map[string]string{
+			"test.toml": `
+				[server]
+				host = "localhost"
+				port = 7501
+			`,
+		})
+		kv = &fakeKV{values: map[string]string{
+			"server.port":          "8080",
+			"server.tags":          `["a", "b"]`,
+			"server.name":          "fleet",
+			"ratelimit.per_second": "100",
+		}}
+		config struct {
+			Host string   `toml:"host"`
+			Port int      `toml:"port"`
+			Name string   `toml:"name"`
+			Tags []string `toml:"tags"`
+		}
+	)
+
+	loader, err := cconfig.NewWithKeyOverrides(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+
+	loader, err = cconfig.WithRemote(loader, kv)
+	assert.NoError(t, err)
+
+	assert.NoError(t, loader.Load("server", &config))
+	assert.Equal(t, "localhost", config.Host)
+	assert.Equal(t, 8080, config.Port)
+	assert.Equal(t, "fleet", config.Name)
+	assert.Equal(t, []string{"a", "b"}, config.Tags)
+
+	watcher, err := cconfig.NewWatcher(cconfig.NewWatcherParams{Loader: loader})
+	assert.NoError(t, err)
+
+	var changed []string
+
+	watcher.OnChange("server", func(l cconfig.Loader) { changed = append(changed, "server") })
+	watcher.OnChange("ratelimit", func(l cconfig.Loader) { changed = append(changed, "ratelimit") })
+
+	kv.mu.Lock()
+	kv.values["ratelimit.per_second"] = "50"
+	kv.mu.Unlock()
+
+	assert.NoError(t, watcher.Reload())
+	assert.Equal(t, []string{"ratelimit"}, changed)
+}
@@ -0,0 +1,398 @@
+package cconfig
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cerrors"
+)
+
+const defaultSecretRenewBefore = time.Minute
+
+// Schemes of the built-in resolvers. They have a "secret+" prefix so that config values such as sqlite's
+// "file:app.db" URIs are not mistaken for secret references.
+const (
+	SecretSchemeEnv  = "secret+env"
+	SecretSchemeFile = "secret+file"
+)
+
+type (
+	// Secret is a value fetched by a SecretResolver.
+	Secret struct {
+		Value string
+
+		// ExpiresAt is when the secret's lease ends. Secrets without an expiry are cached until the app restarts.
+		ExpiresAt time.Time
+	}
+
+	// SecretResolver fetches secrets from a secrets backend (ex. Vault or AWS Secrets Manager). The ref is the part
+	// of the config value after the scheme (ex. "secret/db#password" for "vault:secret/db#password").
+	SecretResolver interface {
+		Resolve(ctx context.Context, ref string) (Secret, error)
+	}
+
+	// SecretRenewer can be implemented by a SecretResolver whose backend supports renewing a secret's lease instead
+	// of fetching the secret again.
+	SecretRenewer interface {
+		Renew(ctx context.Context, ref string, secret Secret) (Secret, error)
+	}
+
+	// NewSecretsParams holds the params needed to create Secrets.
+	NewSecretsParams struct {
+		// Resolvers maps schemes (ex. "vault") to the resolvers for them. The "secret+env" and "secret+file" schemes
+		// are always available to read secrets from environment variables and files.
+		Resolvers map[string]SecretResolver
+
+		// RenewBefore is how long before a secret's expiry it is renewed. It defaults to 1m.
+		RenewBefore time.Duration
+
+		// Clock is used to check when secrets expire. It defaults to the system clock.
+		Clock cclock.Clock
+	}
+
+	// Secrets resolves secret references in config values and caches them until they are about to expire.
+	Secrets struct {
+		resolvers   map[string]SecretResolver
+		renewBefore time.Duration
+		clock       cclock.Clock
+
+		mu      sync.Mutex
+		cache   map[string]Secret
+		uses    map[string]map[secretUse]bool
+		fetches map[string]*secretFetch
+	}
+
+	// secretFetch is a fetch of a secret that is in progress. Concurrent resolves of the same reference wait for it
+	// instead of fetching the secret again.
+	secretFetch struct {
+		done   chan struct{}
+		secret Secret
+		err    error
+	}
+
+	// secretUse is a config key whose value references a secret.
+	secretUse struct {
+		src *loader
+		key string
+	}
+
+	// SecretResolverFunc implements SecretResolver with a func.
+	SecretResolverFunc func(ctx context.Context, ref string) (Secret, error)
+)
+
+// Resolve calls the func.
+func (fn SecretResolverFunc) Resolve(ctx context.Context, ref string) (Secret, error) {
+	return fn(ctx, ref)
+}
+
+// NewSecrets creates new Secrets.
+func NewSecrets(p NewSecretsParams) *Secrets {
+	resolvers := map[string]SecretResolver{
+		SecretSchemeEnv:  SecretResolverFunc(resolveEnvSecret),
+		SecretSchemeFile: SecretResolverFunc(resolveFileSecret),
+	}
+
+	for scheme, r := range p.Resolvers {
+		resolvers[scheme] = r
+	}
+
+	if p.RenewBefore <= 0 {
+		p.RenewBefore = defaultSecretRenewBefore
+	}
+
+	if p.Clock == nil {
+		p.Clock = cclock.New()
+	}
+
+	return &Secrets{
+		resolvers:   resolvers,
+		renewBefore: p.RenewBefore,
+		clock:       p.Clock,
+		cache:       make(map[string]Secret),
+		uses:        make(map[string]map[secretUse]bool),
+		fetches:     make(map[string]*secretFetch),
+	}
+}
+
+// WithSecrets wraps the loader so that string values that reference a secret (ex. "vault:secret/db#password") are
+// replaced with the secret when they are loaded. Values whose scheme does not have a resolver are loaded as-is. This
+// keeps credentials out of config files:
+//
+// # prod.toml
+// [csql]
+// dsn = "vault:secret/db#dsn"
+func WithSecrets(l Loader, secrets *Secrets) Loader {
+	return &secretsLoader{loader: l, secrets: secrets}
+}
+
+// IsRef returns true if the value references a secret with a known scheme.
+func (s *Secrets) IsRef(val string) bool {
+	scheme, _, ok := splitSecretRef(val)
+	if !ok {
+		return false
+	}
+
+	_, ok = s.resolvers[scheme]
+
+	return ok
+}
+
+// Resolve returns the secret referenced by the value. Secrets are cached and renewed when they are within
+// RenewBefore of their expiry.
+func (s *Secrets) Resolve(ctx context.Context, val string) (string, error) {
+	secret, err := s.resolve(ctx, val)
+	if err != nil {
+		return "", err
+	}
+
+	return secret.Value, nil
+}
+
+func (s *Secrets) resolve(ctx context.Context, val string) (Secret, error) {
+	scheme, ref, ok := splitSecretRef(val)
+	if !ok {
+		return Secret{}, cerrors.New(nil, "invalid secret reference", nil)
+	}
+
+	resolver, ok := s.resolvers[scheme]
+	if !ok {
+		return Secret{}, cerrors.New(nil, "unknown secret scheme", map[string]interface{}{
+			"scheme": scheme,
+		})
+	}
+
+	s.mu.Lock()
+
+	secret, ok := s.cache[val]
+	if ok && !s.needsRenewal(secret) {
+		s.mu.Unlock()
+		return secret, nil
+	}
+
+	if f, fetching := s.fetches[val]; fetching {
+		s.mu.Unlock()
+
+		select {
+		case <-f.done:
+			return f.secret, f.err
+		case <-ctx.Done():
+			return Secret{}, cerrors.New(ctx.Err(), "failed to wait for secret", map[string]interface{}{
+				"scheme": scheme,
+				"ref":    ref,
+			})
+		}
+	}
+
+	f := &secretFetch{done: make(chan struct{})}
+	s.fetches[val] = f
+	s.mu.Unlock()
+
+	// The secret is fetched without holding the lock so that other secrets can be resolved in the meantime
+	if renewer, canRenew := resolver.(SecretRenewer); ok && canRenew {
+		f.secret, f.err = renewer.Renew(ctx, ref, secret)
+	} else {
+		f.secret, f.err = resolver.Resolve(ctx, ref)
+	}
+
+	if f.err != nil {
+		f.secret = Secret{}
+		f.err = cerrors.New(f.err, "failed to resolve secret", map[string]interface{}{
+			"scheme": scheme,
+			"ref":    ref,
+		})
+	}
+
+	s.mu.Lock()
+	if f.err == nil {
+		s.cache[val] = f.secret
+	}
+	delete(s.fetches, val)
+	s.mu.Unlock()
+
+	close(f.done)
+
+	return f.secret, f.err
+}
+
+// RenewDue renews the cached secrets that are within RenewBefore of their expiry so that their leases do not end.
+// It should be called periodically by apps that use secrets with leases. If a renewed secret has a new value, the
+// config keys that reference it are sent to their watches (see KeyWatcher) and to the subscribers of the loader's
+// Watchers so that they can load the new value. Structs loaded before then keep the old value.
+func (s *Secrets) RenewDue(ctx context.Context) error {
+	s.mu.Lock()
+
+	due := make(map[string]Secret)
+
+	for val, secret := range s.cache {
+		if s.needsRenewal(secret) {
+			due[val] = secret
+		}
+	}
+
+	s.mu.Unlock()
+
+	changed := make(map[*loader][]string)
+
+	for val, old := range due {
+		secret, err := s.resolve(ctx, val)
+		if err != nil {
+			return err
+		}
+
+		if secret.Value == old.Value {
+			continue
+		}
+
+		s.mu.Lock()
+		for use := range s.uses[val] {
+			changed[use.src] = append(changed[use.src], use.key)
+		}
+		s.mu.Unlock()
+	}
+
+	for src, keys := range changed {
+		src.notifyKeys(keys)
+	}
+
+	return nil
+}
+
+// track records that the config key references the secret so that it is notified when the secret is renewed.
+func (s *Secrets) track(val string, use secretUse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.uses[val] == nil {
+		s.uses[val] = make(map[secretUse]bool)
+	}
+
+	s.uses[val][use] = true
+}
+
+func (s *Secrets) needsRenewal(secret Secret) bool {
+	return !secret.ExpiresAt.IsZero() && !s.clock.Now().Add(s.renewBefore).Before(secret.ExpiresAt)
+}
+
+type secretsLoader struct {
+	loader  Loader
+	secrets *Secrets
+}
+
+func (l *secretsLoader) Load(key string, dest interface{}) error {
+	err := l.loader.Load(key, dest)
+	if err != nil {
+		return err
+	}
+
+	return l.resolveSecrets(key, dest)
+}
+
+// resolveSecrets replaces the secret references in dest with the secrets.
+func (l *secretsLoader) resolveSecrets(key string, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr {
+		return nil
+	}
+
+	err := replaceStrings(v.Elem(), func(val string) (string, error) {
+		if !l.secrets.IsRef(val) {
+			return val, nil
+		}
+
+		if src, ok := baseLoader(l.loader); ok {
+			l.secrets.track(val, secretUse{src: src, key: key})
+		}
+
+		return l.secrets.Resolve(context.Background(), val)
+	})
+	if err != nil {
+		return cerrors.New(err, "failed to resolve secrets in config", map[string]interface{}{
+			"key": key,
+		})
+	}
+
+	return nil
+}
+
+func (l *secretsLoader) unwrap() Loader {
+	return l.loader
+}
+
+// replaceStrings calls fn for each string in v, including the ones in nested structs, slices, and pointers, and
+// replaces them with the values it returns.
+func replaceStrings(v reflect.Value, fn func(val string) (string, error)) error {
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.String:
+		if !v.CanSet() {
+			return nil
+		}
+
+		val, err := fn(v.String())
+		if err != nil {
+			return err
+		}
+
+		v.SetString(val)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+
+			err := replaceStrings(v.Field(i), fn)
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			err := replaceStrings(v.Index(i), fn)
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return replaceStrings(v.Elem(), fn)
+		}
+	}
+
+	return nil
+}
+
+func splitSecretRef(val string) (string, string, bool) {
+	i := strings.Index(val, ":")
+	if i <= 0 || i == len(val)-1 {
+		return "", "", false
+	}
+
+	return val[:i], val[i+1:], true
+}
+
+func resolveEnvSecret(ctx context.Context, ref string) (Secret, error) {
+	val, ok := os.LookupEnv(ref)
+	if !ok {
+		return Secret{}, cerrors.New(nil, "environment variable is not set", map[string]interface{}{
+			"env": ref,
+		})
+	}
+
+	return Secret{Value: val}, nil
+}
+
+func resolveFileSecret(ctx context.Context, ref string) (Secret, error) {
+	data, err := ioutil.ReadFile(ref)
+	if err != nil {
+		return Secret{}, cerrors.New(err, "failed to read secret file", map[string]interface{}{
+			"path": ref,
+		})
+	}
+
+	return Secret{Value: strings.TrimSpace(string(data))}, nil
+}
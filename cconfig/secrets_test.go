@@ -0,0 +1,227 @@
+package cconfig_test
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeVault struct {
+	resolves int
+	renews   int
+	clock    cclock.Clock
+}
+
+func (v *fakeVault) Resolve(ctx context.Context, ref string) (cconfig.Secret, error) {
+	v.resolves++
+
+	return cconfig.Secret{Value: "secret for " + ref, ExpiresAt: v.clock.Now().Add(time.Hour)}, nil
+}
+
+func (v *fakeVault) Renew(ctx context.Context, ref string, secret cconfig.Secret) (cconfig.Secret, error) {
+	v.renews++
+
+	secret.ExpiresAt = v.clock.Now().Add(time.Hour)
+
+	return secret, nil
+}
+
+func TestWithSecrets(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[db]
+			dsn = "vault:secret/db#dsn"
+			url = "http://example.com"
+			hosts = ["secret+file:missing", "plain"]
+		`,
+		"hosts.txt": "db.internal\n",
+	})
+
+	var (
+		clock = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		vault = &fakeVault{clock: clock}
+		conf  struct {
+			DSN string `toml:"dsn"`
+			URL string `toml:"url"`
+		}
+	)
+
+	loader, err := cconfig.NewWithKeyOverrides(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+
+	secrets := cconfig.NewSecrets(cconfig.NewSecretsParams{
+		Resolvers: map[string]cconfig.SecretResolver{"vault": vault},
+		Clock:     clock,
+	})
+	loader = cconfig.WithSecrets(loader, secrets)
+
+	assert.NoError(t, loader.Load("db", &conf))
+	assert.Equal(t, "secret for secret/db#dsn", conf.DSN)
+	assert.Equal(t, "http://example.com", conf.URL)
+
+	var hostsConf struct {
+		Hosts []string `toml:"hosts"`
+	}
+
+	assert.Error(t, loader.Load("db", &hostsConf))
+
+	val, err := secrets.Resolve(context.Background(), "secret+file:"+path.Join(dir, "hosts.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "db.internal", val)
+
+	// cached until the lease is about to expire
+	assert.NoError(t, loader.Load("db", &conf))
+	assert.NoError(t, secrets.RenewDue(context.Background()))
+	assert.Equal(t, 1, vault.resolves)
+	assert.Equal(t, 0, vault.renews)
+
+	clock.Advance(59*time.Minute + time.Second)
+
+	assert.NoError(t, secrets.RenewDue(context.Background()))
+	assert.Equal(t, 1, vault.resolves)
+	assert.Equal(t, 1, vault.renews)
+}
+
+func TestSecrets_RenewDue_Notify(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[db]
+			password = "vault:secret/db#password"
+		`,
+	})
+
+	var (
+		clock    = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		version  = 0
+		resolver = cconfig.SecretResolverFunc(func(ctx context.Context, ref string) (cconfig.Secret, error) {
+			version++
+
+			return cconfig.Secret{
+				Value:     fmt.Sprintf("password-%d", version),
+				ExpiresAt: clock.Now().Add(time.Hour),
+			}, nil
+		})
+		conf struct {
+			Password string `toml:"password"`
+		}
+	)
+
+	loader, err := cconfig.NewWithKeyOverrides(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+
+	secrets := cconfig.NewSecrets(cconfig.NewSecretsParams{
+		Resolvers: map[string]cconfig.SecretResolver{"vault": resolver},
+		Clock:     clock,
+	})
+	loader = cconfig.WithSecrets(loader, secrets)
+
+	assert.NoError(t, loader.Load("db", &conf))
+	assert.Equal(t, "password-1", conf.Password)
+
+	db, cancel := cconfig.Watch(loader, "db")
+	defer cancel()
+
+	assert.NoError(t, secrets.RenewDue(context.Background()))
+	assert.Empty(t, db)
+
+	clock.Advance(time.Hour)
+
+	assert.NoError(t, secrets.RenewDue(context.Background()))
+
+	val := <-db
+	assert.NoError(t, val.Load(&conf))
+	assert.Equal(t, "password-2", conf.Password)
+}
+
+func TestNewSecrets_DefaultClock(t *testing.T) {
+	t.Parallel()
+
+	secrets := cconfig.NewSecrets(cconfig.NewSecretsParams{
+		Resolvers: map[string]cconfig.SecretResolver{
+			"vault": cconfig.SecretResolverFunc(func(ctx context.Context, ref string) (cconfig.Secret, error) {
+				return cconfig.Secret{Value: "secret", ExpiresAt: time.Now().Add(time.Hour)}, nil
+			}),
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		val, err := secrets.Resolve(context.Background(), "vault:secret/db#password")
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", val)
+	}
+}
+
+func TestWithSecrets_SQLiteURI(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[csql]
+			dsn = "file:app.db?cache=shared"
+		`,
+	})
+
+	var conf struct {
+		DSN string `toml:"dsn"`
+	}
+
+	loader, err := cconfig.NewWithKeyOverrides(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+
+	loader = cconfig.WithSecrets(loader, cconfig.NewSecrets(cconfig.NewSecretsParams{}))
+
+	assert.NoError(t, loader.Load("csql", &conf))
+	assert.Equal(t, "file:app.db?cache=shared", conf.DSN)
+}
+
+func TestSecrets_Resolve_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	var (
+		fetches int32
+		release = make(chan struct{})
+		secrets = cconfig.NewSecrets(cconfig.NewSecretsParams{
+			Resolvers: map[string]cconfig.SecretResolver{
+				"vault": cconfig.SecretResolverFunc(func(ctx context.Context, ref string) (cconfig.Secret, error) {
+					atomic.AddInt32(&fetches, 1)
+					<-release
+
+					return cconfig.Secret{Value: "secret"}, nil
+				}),
+			},
+		})
+		wg sync.WaitGroup
+	)
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			val, err := secrets.Resolve(context.Background(), "vault:secret/db#password")
+			assert.NoError(t, err)
+			assert.Equal(t, "secret", val)
+		}()
+	}
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&fetches) == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+}
@@ -0,0 +1,152 @@
+package cconfig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/pelletier/go-toml"
+)
+
+// Strict makes a loader created by this package reject config keys that do not map to a field of the struct passed
+// to Load. The unknown keys are reported in a *ValidationError, which catches typos (ex. chttp.prot instead of
+// chttp.port) that would otherwise fall back to the default value silently. Top-level keys that are never loaded can
+// be found with UnusedKeys.
+func Strict(l Loader) (Loader, error) {
+	base, ok := baseLoader(l)
+	if !ok {
+		return nil, cerrors.New(nil, "loader does not support strict mode", nil)
+	}
+
+	base.setStrict()
+
+	return l, nil
+}
+
+// UnusedKeys returns the top-level keys in the config that have not been loaded yet. It should be called after the
+// app's dependencies are created, when all of the config has been loaded.
+func UnusedKeys(l Loader) []string {
+	base, ok := baseLoader(l)
+	if !ok {
+		return nil
+	}
+
+	return base.unusedKeys()
+}
+
+func (l *loader) setStrict() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.strict = true
+}
+
+func (l *loader) unusedKeys() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	unused := make([]string, 0)
+
+	for _, key := range l.tree.Keys() {
+		if !l.loaded[key] && key != "extends" {
+			unused = append(unused, key)
+		}
+	}
+
+	sort.Strings(unused)
+
+	return unused
+}
+
+// unknownKeys returns an error for each key in the tree that does not map to a field of t.
+func unknownKeys(key string, tree *toml.Tree, t reflect.Type) []FieldError {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || t == reflect.TypeOf(time.Time{}) {
+		return nil
+	}
+
+	errs := make([]FieldError, 0)
+	keys := tree.Keys()
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fullKey := key + "." + k
+
+		field, ok := findField(t, k)
+		if !ok {
+			errs = append(errs, FieldError{Key: fullKey, Message: "is not a known config key"})
+			continue
+		}
+
+		errs = append(errs, unknownKeysInValue(fullKey, tree.Get(k), field.Type)...)
+	}
+
+	return errs
+}
+
+func unknownKeysInValue(key string, val interface{}, t reflect.Type) []FieldError {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch v := val.(type) {
+	case *toml.Tree:
+		if t.Kind() != reflect.Map {
+			return unknownKeys(key, v, t)
+		}
+
+		errs := make([]FieldError, 0)
+
+		for _, k := range v.Keys() {
+			errs = append(errs, unknownKeysInValue(key+"."+k, v.Get(k), t.Elem())...)
+		}
+
+		return errs
+	case []*toml.Tree:
+		if t.Kind() != reflect.Slice {
+			return nil
+		}
+
+		errs := make([]FieldError, 0)
+
+		for i := range v {
+			errs = append(errs, unknownKeys(fmt.Sprintf("%s[%d]", key, i), v[i], t.Elem())...)
+		}
+
+		return errs
+	default:
+		return nil
+	}
+}
+
+// findField returns the field that the key maps to, the same way that go-toml maps them when unmarshalling.
+func findField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := strings.Split(field.Tag.Get("toml"), ",")[0]
+
+		switch {
+		case tag == "-":
+			continue
+		case tag != "":
+			if tag == key {
+				return field, true
+			}
+		case strings.EqualFold(field.Name, key):
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
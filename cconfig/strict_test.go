@@ -0,0 +1,67 @@
+package cconfig_test
+
+import (
+	"errors"
+	"path"
+	"testing"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrict(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[chttp]
+			prot = 8080
+			Timeout = "1s"
+
+			[chttp.filters.admin]
+			allow = ["10.0.0.0/8"]
+			alow = ["10.0.0.1"]
+
+			[[chttp.proxies]]
+			prefix = "/api"
+			targt = "http://localhost"
+
+			[chtp]
+			port = 8080
+		`,
+	})
+
+	var config struct {
+		Port    int `toml:"port"`
+		Timeout string
+		Filters map[string]struct {
+			Allow []string `toml:"allow"`
+		} `toml:"filters"`
+		Proxies []struct {
+			Prefix string `toml:"prefix"`
+			Target string `toml:"target"`
+		} `toml:"proxies"`
+	}
+
+	loader, err := cconfig.NewWithKeyOverrides(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+
+	assert.NoError(t, loader.Load("chttp", &config))
+
+	loader, err = cconfig.Strict(loader)
+	assert.NoError(t, err)
+
+	err = loader.Load("chttp", &config)
+
+	var validationErr *cconfig.ValidationError
+
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []cconfig.FieldError{
+		{Key: "chttp.filters.admin.alow", Message: "is not a known config key"},
+		{Key: "chttp.prot", Message: "is not a known config key"},
+		{Key: "chttp.proxies[0].targt", Message: "is not a known config key"},
+	}, validationErr.Fields)
+
+	assert.Equal(t, []string{"chtp"}, cconfig.UnusedKeys(loader))
+}
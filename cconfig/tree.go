@@ -3,50 +3,74 @@ package cconfig
 import (
 	"path/filepath"
 	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/gocopper/copper/cerrors"
 	"github.com/pelletier/go-toml"
 )
 
+// loadState collects the files read while loading a config tree and the file that each value came from.
+type loadState struct {
+	files []string
+
+	// sources maps the keys of the values (ex. chttp.port) to the files that set them.
+	sources map[string]string
+
+	// stack holds the files that are being loaded, to detect files that extend or include each other.
+	stack []string
+}
+
+func newLoadState() *loadState {
+	return &loadState{sources: make(map[string]string)}
+}
+
+// loadTree loads the config file at fp along with the files it includes and extends. The paths of the loaded files
+// and the sources of the values are recorded in state.
+//
 //nolint:funlen
-func loadTree(fp string, disableKeyOverrides bool) (*toml.Tree, error) {
-	tree, err := toml.LoadFile(fp)
+func loadTree(fp string, disableKeyOverrides bool, state *loadState) (*toml.Tree, error) {
+	for _, loading := range state.stack {
+		if filepath.Clean(loading) == filepath.Clean(fp) {
+			return nil, cerrors.New(nil, "config files extend or include each other", map[string]interface{}{
+				"cycle": strings.Join(append(state.stack, fp), " -> "),
+			})
+		}
+	}
+
+	state.stack = append(state.stack, fp)
+	defer func() { state.stack = state.stack[:len(state.stack)-1] }()
+
+	tree, err := loadFile(fp)
 	if err != nil {
 		return nil, cerrors.New(err, "failed to load config file", map[string]interface{}{
 			"path": fp,
 		})
 	}
 
+	state.files = append(state.files, fp)
+
+	// Files override the files they include and extend, so a value is attributed to the first file that sets it
+	for _, key := range leafKeys(tree, "") {
+		if _, ok := state.sources[key]; !ok && key != "extends" && key != "include" {
+			state.sources[key] = fp
+		}
+	}
+
+	tree, err = mergeIncludes(fp, tree, disableKeyOverrides, state)
+	if err != nil {
+		return nil, err
+	}
+
 	// If the TOML tree does not have a top-level 'extends' key, we can return the tree as-is
 	if !tree.Has("extends") {
 		return tree, nil
 	}
 
-	parentFilePaths := make([]string, 0)
-
 	// The extends key can be a string or a list of strings representing the config file paths that need to be loaded
-	switch extends := tree.Get("extends").(type) {
-	case string:
-		parentFilePaths = append(parentFilePaths, extends)
-
-	// If extends is set to a list, verify each value is a valid string, and add it to parentFilePaths
-	case []interface{}:
-		for i := range extends {
-			parentFilePath, ok := extends[i].(string)
-			if !ok {
-				return nil, cerrors.New(nil, "extends can only contain strings", map[string]interface{}{
-					"path":  fp,
-					"value": extends[i],
-				})
-			}
-
-			parentFilePaths = append(parentFilePaths, parentFilePath)
-		}
-	default:
-		return nil, cerrors.New(nil, "'extends' must be string or []string", map[string]interface{}{
-			"path": fp,
-			"type": reflect.TypeOf(extends).String(),
-		})
+	parentFilePaths, err := filePaths(fp, tree, "extends")
+	if err != nil {
+		return nil, err
 	}
 
 	// Load each parentFilePath in-order
@@ -55,7 +79,7 @@ func loadTree(fp string, disableKeyOverrides bool) (*toml.Tree, error) {
 
 		// Load the parent tree at the given path defined by the extends key. Note that this is a recursive call
 		// that will load all ancestors.
-		parentTree, err := loadTree(parentFilePath, disableKeyOverrides)
+		parentTree, err := loadTree(parentFilePath, disableKeyOverrides, state)
 		if err != nil {
 			return nil, cerrors.New(err, "failed to load parent tree", map[string]interface{}{
 				"parentPath": parentFilePath,
@@ -74,6 +98,114 @@ func loadTree(fp string, disableKeyOverrides bool) (*toml.Tree, error) {
 	return tree, nil
 }
 
+// mergeIncludes loads the files listed in the tree's 'include' key and merges them under the tree. Later includes
+// override earlier ones, and the tree overrides all of them. Paths are relative to fp and can be glob patterns (ex.
+// "conf.d/*.toml").
+func mergeIncludes(fp string, tree *toml.Tree, disableKeyOverrides bool, state *loadState) (*toml.Tree, error) {
+	if !tree.Has("include") {
+		return tree, nil
+	}
+
+	patterns, err := filePaths(fp, tree, "include")
+	if err != nil {
+		return nil, err
+	}
+
+	_ = tree.Delete("include")
+
+	var (
+		included     *toml.Tree
+		includeState = newLoadState()
+	)
+
+	for _, pattern := range patterns {
+		includePaths, err := filepath.Glob(filepath.Join(filepath.Dir(fp), pattern))
+		if err != nil || len(includePaths) == 0 {
+			return nil, cerrors.New(err, "included config file does not exist", map[string]interface{}{
+				"path":    fp,
+				"include": pattern,
+			})
+		}
+
+		for _, includePath := range includePaths {
+			fileState := &loadState{sources: make(map[string]string), stack: state.stack}
+
+			includeTree, err := loadTree(includePath, disableKeyOverrides, fileState)
+			if err != nil {
+				return nil, cerrors.New(err, "failed to load included tree", map[string]interface{}{
+					"includePath": includePath,
+				})
+			}
+
+			includeState.files = append(includeState.files, fileState.files...)
+
+			for key, source := range fileState.sources {
+				includeState.sources[key] = source
+			}
+
+			if included == nil {
+				included = includeTree
+				continue
+			}
+
+			included, err = mergeTrees(included, includeTree, disableKeyOverrides)
+			if err != nil {
+				return nil, cerrors.New(err, "failed to merge included tree", map[string]interface{}{
+					"includePath": includePath,
+				})
+			}
+		}
+	}
+
+	state.files = append(state.files, includeState.files...)
+
+	for key, source := range includeState.sources {
+		if _, ok := state.sources[key]; !ok {
+			state.sources[key] = source
+		}
+	}
+
+	merged, err := mergeTrees(included, tree, disableKeyOverrides)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to merge with included trees", map[string]interface{}{
+			"path": fp,
+		})
+	}
+
+	return merged, nil
+}
+
+// filePaths returns the paths in the key, which can be a string or a list of strings.
+func filePaths(fp string, tree *toml.Tree, key string) ([]string, error) {
+	paths := make([]string, 0)
+
+	switch val := tree.Get(key).(type) {
+	case string:
+		paths = append(paths, val)
+
+	// If the key is set to a list, verify each value is a valid string
+	case []interface{}:
+		for i := range val {
+			p, ok := val[i].(string)
+			if !ok {
+				return nil, cerrors.New(nil, key+" can only contain strings", map[string]interface{}{
+					"path":  fp,
+					"value": val[i],
+				})
+			}
+
+			paths = append(paths, p)
+		}
+	default:
+		return nil, cerrors.New(nil, "'"+key+"' must be string or []string", map[string]interface{}{
+			"path": fp,
+			"type": reflect.TypeOf(val).String(),
+		})
+	}
+
+	return paths, nil
+}
+
 //nolint:funlen
 func mergeTrees(base, override *toml.Tree, disableKeyOverrides bool) (*toml.Tree, error) {
 	// For each key in the override tree, we need to apply it to the base tree
@@ -139,3 +271,48 @@ func mergeTrees(base, override *toml.Tree, disableKeyOverrides bool) (*toml.Tree
 
 	return base, nil
 }
+
+// changedKeys returns the top-level keys whose values are different in the two trees.
+func changedKeys(oldTree, newTree *toml.Tree) []string {
+	if oldTree == nil {
+		return nil
+	}
+
+	var (
+		oldMap  = oldTree.ToMap()
+		newMap  = newTree.ToMap()
+		changed = make([]string, 0)
+	)
+
+	for key, val := range newMap {
+		if !reflect.DeepEqual(oldMap[key], val) {
+			changed = append(changed, key)
+		}
+	}
+
+	for key := range oldMap {
+		if _, ok := newMap[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+
+	sort.Strings(changed)
+
+	return changed
+}
+
+// leafKeys returns the full keys of the values in the tree that are not tables.
+func leafKeys(tree *toml.Tree, prefix string) []string {
+	keys := make([]string, 0)
+
+	for _, key := range tree.Keys() {
+		if subTree, ok := tree.Get(key).(*toml.Tree); ok {
+			keys = append(keys, leafKeys(subTree, prefix+key+".")...)
+			continue
+		}
+
+		keys = append(keys, prefix+key)
+	}
+
+	return keys
+}
@@ -0,0 +1,208 @@
+package cconfig
+
+import (
+	"encoding"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/pelletier/go-toml"
+)
+
+// ByteSize is a number of bytes that can be written in config files with a unit (ex. "10MB" or "512KiB") or as a plain
+// number of bytes. KB, MB, GB, and TB are powers of 1000 while KiB, MiB, GiB, and TiB are powers of 1024. For example:
+//
+//	type Config struct {
+//		MaxBodySize cconfig.ByteSize `toml:"max_body_size"`
+//	}
+type ByteSize int64
+
+// Byte sizes that can be used in code.
+const (
+	Byte ByteSize = 1
+
+	KB = 1000 * Byte
+	MB = 1000 * KB
+	GB = 1000 * MB
+	TB = 1000 * GB
+
+	KiB = 1024 * Byte
+	MiB = 1024 * KiB
+	GiB = 1024 * MiB
+	TiB = 1024 * GiB
+)
+
+//nolint:gochecknoglobals
+var byteSizeUnits = map[string]ByteSize{
+	"":    Byte,
+	"b":   Byte,
+	"kb":  KB,
+	"mb":  MB,
+	"gb":  GB,
+	"tb":  TB,
+	"kib": KiB,
+	"mib": MiB,
+	"gib": GiB,
+	"tib": TiB,
+}
+
+// ParseByteSize parses a byte size like "10MB", "1.5GiB", or "2048".
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i == -1 {
+		i = len(s)
+	}
+
+	unit, ok := byteSizeUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if !ok {
+		return 0, cerrors.New(nil, "unknown byte size unit", map[string]interface{}{
+			"size": s,
+		})
+	}
+
+	n, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, cerrors.New(err, "invalid byte size", map[string]interface{}{
+			"size": s,
+		})
+	}
+
+	return ByteSize(n * float64(unit)), nil
+}
+
+// UnmarshalText parses the byte size with ParseByteSize.
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	size, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+
+	*b = size
+
+	return nil
+}
+
+// String formats the byte size with the largest binary unit that it is a multiple of (ex. "10MiB").
+func (b ByteSize) String() string {
+	units := []struct {
+		name string
+		size ByteSize
+	}{{"TiB", TiB}, {"GiB", GiB}, {"MiB", MiB}, {"KiB", KiB}}
+
+	for _, u := range units {
+		if b != 0 && b%u.size == 0 {
+			return strconv.FormatInt(int64(b/u.size), 10) + u.name
+		}
+	}
+
+	return strconv.FormatInt(int64(b), 10) + "B"
+}
+
+// convertStrings converts the strings in m to the types of the fields of t that they are set into. Strings for list
+// fields are split into their comma-separated items, so that lists can be written as "a, b, c" as well as
+// ["a", "b", "c"], and strings for number and bool fields are parsed (ex. values from ${env:PORT} references). It
+// returns true if m was changed.
+func convertStrings(m map[string]interface{}, t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || t == reflect.TypeOf(time.Time{}) {
+		return false
+	}
+
+	changed := false
+
+	for k, val := range m {
+		field, ok := findField(t, k)
+		if !ok {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		switch v := val.(type) {
+		case string:
+			if isTextType(fieldType) || fieldType == reflect.TypeOf(time.Duration(0)) {
+				continue
+			}
+
+			switch {
+			case fieldType.Kind() == reflect.Slice:
+				m[k] = splitList(v, fieldType.Elem())
+				changed = true
+			case isNumberOrBool(fieldType):
+				m[k] = parseTOMLValue(v)
+				changed = true
+			}
+		case map[string]interface{}:
+			if convertStrings(v, fieldType) {
+				changed = true
+			}
+		case []interface{}:
+			if fieldType.Kind() != reflect.Slice {
+				continue
+			}
+
+			for i := range v {
+				item, ok := v[i].(map[string]interface{})
+				if ok && convertStrings(item, fieldType.Elem()) {
+					changed = true
+				}
+			}
+		}
+	}
+
+	return changed
+}
+
+func splitList(raw string, elem reflect.Type) []interface{} {
+	items := make([]interface{}, 0)
+
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		if elem.Kind() == reflect.String || isTextType(elem) || elem == reflect.TypeOf(time.Duration(0)) {
+			items = append(items, item)
+		} else {
+			items = append(items, parseTOMLValue(item))
+		}
+	}
+
+	return items
+}
+
+// isTextType returns true if values of t are parsed from text (ex. ByteSize) instead of by their kind.
+func isTextType(t reflect.Type) bool {
+	return reflect.PtrTo(t).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem())
+}
+
+// isNumberOrBool returns true if t is a number or bool type.
+func isNumberOrBool(t reflect.Type) bool {
+	k := t.Kind()
+
+	return k == reflect.Bool || (k >= reflect.Int && k <= reflect.Float64)
+}
+
+// withConvertedStrings returns a copy of the tree with its strings converted for dest (see convertStrings), or the
+// tree itself if none of them need to be converted.
+func withConvertedStrings(tree *toml.Tree, dest interface{}) (*toml.Tree, error) {
+	m := tree.ToMap()
+	if !convertStrings(m, reflect.TypeOf(dest)) {
+		return tree, nil
+	}
+
+	return toml.TreeFromMap(m)
+}
@@ -0,0 +1,124 @@
+package cconfig_test
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteSize(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		in   string
+		want cconfig.ByteSize
+		err  bool
+	}{
+		{in: "2048", want: 2048},
+		{in: "10MB", want: 10 * cconfig.MB},
+		{in: "512 KiB", want: 512 * cconfig.KiB},
+		{in: "1.5gib", want: 3 * cconfig.GiB / 2},
+		{in: "10XB", err: true},
+		{in: "MB", err: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.in, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := cconfig.ParseByteSize(tc.in)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestByteSize_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "10MiB", (10 * cconfig.MiB).String())
+	assert.Equal(t, "1000B", cconfig.KB.String())
+	assert.Equal(t, "0B", cconfig.ByteSize(0).String())
+}
+
+func TestLoader_Load_Types(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[group1]
+			timeout = "30s"
+			max_body = "10MB"
+			max_file = 1024
+			hosts = "a.com, b.com"
+			ports = "80,443"
+			limits = ["1KiB", "2KiB"]
+
+			[[group1.servers]]
+			tags = "x, y"
+		`,
+	})
+
+	var testConfig struct {
+		Timeout time.Duration      `toml:"timeout"`
+		MaxBody cconfig.ByteSize   `toml:"max_body" validate:"max=1GB"`
+		MaxFile cconfig.ByteSize   `toml:"max_file"`
+		Hosts   []string           `toml:"hosts"`
+		Ports   []int              `toml:"ports"`
+		Limits  []cconfig.ByteSize `toml:"limits"`
+		Servers []struct {
+			Tags []string `toml:"tags"`
+		} `toml:"servers"`
+	}
+
+	configs, err := cconfig.New(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+
+	err = configs.Load("group1", &testConfig)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 30*time.Second, testConfig.Timeout)
+	assert.Equal(t, 10*cconfig.MB, testConfig.MaxBody)
+	assert.Equal(t, cconfig.KiB, testConfig.MaxFile)
+	assert.Equal(t, []string{"a.com", "b.com"}, testConfig.Hosts)
+	assert.Equal(t, []int{80, 443}, testConfig.Ports)
+	assert.Equal(t, []cconfig.ByteSize{cconfig.KiB, 2 * cconfig.KiB}, testConfig.Limits)
+	assert.Len(t, testConfig.Servers, 1)
+	assert.Equal(t, []string{"x", "y"}, testConfig.Servers[0].Tags)
+}
+
+func TestLoader_Load_Types_EnvOverrides(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[group1]
+			max_body = "10MB"
+		`,
+	})
+
+	setenv(t, "TYPES_GROUP1_MAX_BODY", "1GiB")
+
+	var testConfig struct {
+		MaxBody cconfig.ByteSize `toml:"max_body"`
+	}
+
+	configs, err := cconfig.NewWithEnvOverrides(cconfig.Path(path.Join(dir, "test.toml")), "TYPES_")
+	assert.NoError(t, err)
+
+	err = configs.Load("group1", &testConfig)
+	assert.NoError(t, err)
+
+	assert.Equal(t, cconfig.GiB, testConfig.MaxBody)
+}
@@ -0,0 +1,203 @@
+package cconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// ValidationError is returned by Load when config values do not pass the rules in their fields' `validate`
+	// tags, or in strict mode (see Strict), when there are unknown keys. It lists every invalid value so that they can
+	// all be fixed at once. The rules are comma-separated:
+	//
+	//	required    the value must be set
+	//	min=N       numbers (and durations and byte sizes) must be at least N; strings and lists must have at least N items
+	//	max=N       numbers (and durations and byte sizes) must be at most N; strings and lists must have at most N items
+	//	oneof=a b   the value must be one of the space-separated options
+	//
+	// Rules other than required are skipped for values that are not set. For example:
+	//
+	//	type Config struct {
+	//		Port    uint          `toml:"port" validate:"required,min=1,max=65535"`
+	//		Mode    string        `toml:"mode" validate:"oneof=dev prod"`
+	//		Timeout time.Duration `toml:"timeout" validate:"min=1s"`
+	//	}
+	ValidationError struct {
+		Fields []FieldError
+	}
+
+	// FieldError describes an invalid config value.
+	FieldError struct {
+		// Key is the full key of the value (ex. chttp.port).
+		Key     string
+		Message string
+	}
+)
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+
+	b.WriteString("invalid config:")
+
+	for _, f := range e.Fields {
+		b.WriteString("\n  ")
+		b.WriteString(f.Key)
+		b.WriteString(": ")
+		b.WriteString(f.Message)
+	}
+
+	return b.String()
+}
+
+// validate checks the fields of dest against the rules in their `validate` tags.
+func validate(key string, dest interface{}) []FieldError {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs []FieldError
+
+	validateStruct(key, v.Elem(), &errs)
+
+	return errs
+}
+
+func validateStruct(key string, v reflect.Value, errs *[]FieldError) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fieldKey := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if fieldKey == "-" {
+			continue
+		}
+
+		if fieldKey == "" {
+			fieldKey = strings.ToLower(field.Name)
+		}
+
+		fieldKey = key + "." + fieldKey
+		fieldVal := v.Field(i)
+
+		for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+			if rule == "" {
+				continue
+			}
+
+			msg := checkRule(rule, fieldVal)
+			if msg != "" {
+				*errs = append(*errs, FieldError{Key: fieldKey, Message: msg})
+				break
+			}
+		}
+
+		switch {
+		case fieldVal.Kind() == reflect.Struct && fieldVal.Type() != reflect.TypeOf(time.Time{}):
+			validateStruct(fieldKey, fieldVal, errs)
+		case fieldVal.Kind() == reflect.Slice && fieldVal.Type().Elem().Kind() == reflect.Struct:
+			for j := 0; j < fieldVal.Len(); j++ {
+				validateStruct(fmt.Sprintf("%s[%d]", fieldKey, j), fieldVal.Index(j), errs)
+			}
+		}
+	}
+}
+
+// checkRule returns a message describing why the value does not pass the rule, or an empty string if it does.
+func checkRule(rule string, v reflect.Value) string {
+	name, arg := rule, ""
+	if i := strings.Index(rule, "="); i >= 0 {
+		name, arg = rule[:i], rule[i+1:]
+	}
+
+	if name == "required" {
+		if v.IsZero() {
+			return "is required"
+		}
+
+		return ""
+	}
+
+	if v.IsZero() {
+		return ""
+	}
+
+	switch name {
+	case "min", "max":
+		return checkBound(name, arg, v)
+	case "oneof":
+		options := strings.Fields(arg)
+
+		for _, opt := range options {
+			if fmt.Sprint(v.Interface()) == opt {
+				return ""
+			}
+		}
+
+		return "must be one of " + strings.Join(options, ", ")
+	default:
+		return "has an unknown validation rule " + strconv.Quote(name)
+	}
+}
+
+//nolint:cyclop
+func checkBound(name, arg string, v reflect.Value) string {
+	var (
+		val, bound float64
+		err        error
+		unit       = ""
+	)
+
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		var d time.Duration
+
+		d, err = time.ParseDuration(arg)
+		val, bound = float64(v.Int()), float64(d)
+	case v.Type() == reflect.TypeOf(ByteSize(0)):
+		var b ByteSize
+
+		b, err = ParseByteSize(arg)
+		val, bound = float64(v.Int()), float64(b)
+	case v.Kind() == reflect.String || v.Kind() == reflect.Slice || v.Kind() == reflect.Map:
+		val = float64(v.Len())
+		bound, err = strconv.ParseFloat(arg, 64)
+		unit = " items"
+
+		if v.Kind() == reflect.String {
+			unit = " characters"
+		}
+	case v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64:
+		val = float64(v.Int())
+		bound, err = strconv.ParseFloat(arg, 64)
+	case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64:
+		val = float64(v.Uint())
+		bound, err = strconv.ParseFloat(arg, 64)
+	case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+		val = v.Float()
+		bound, err = strconv.ParseFloat(arg, 64)
+	default:
+		return "cannot be validated with " + name
+	}
+
+	if err != nil {
+		return "has an invalid " + name + " rule " + strconv.Quote(arg)
+	}
+
+	if name == "min" && val < bound {
+		return "must be at least " + arg + unit
+	}
+
+	if name == "max" && val > bound {
+		return "must be at most " + arg + unit
+	}
+
+	return ""
+}
@@ -0,0 +1,71 @@
+package cconfig_test
+
+import (
+	"errors"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoader_Load_Validate(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[server]
+			port = 70000
+			mode = "staging"
+			timeout = "500ms"
+			hosts = []
+
+			[[server.backends]]
+			url = ""
+
+			[valid]
+			port = 8080
+			mode = "prod"
+		`,
+	})
+
+	type config struct {
+		Name     string        `toml:"name" validate:"required"`
+		Port     int           `toml:"port" validate:"required,min=1,max=65535"`
+		Mode     string        `toml:"mode" validate:"oneof=dev prod"`
+		Timeout  time.Duration `toml:"timeout" validate:"min=1s"`
+		Hosts    []string      `toml:"hosts" validate:"max=2"`
+		Backends []struct {
+			URL string `toml:"url" validate:"required"`
+		} `toml:"backends"`
+	}
+
+	loader, err := cconfig.New(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+
+	var c config
+
+	err = loader.Load("server", &c)
+
+	var validationErr *cconfig.ValidationError
+
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []cconfig.FieldError{
+		{Key: "server.name", Message: "is required"},
+		{Key: "server.port", Message: "must be at most 65535"},
+		{Key: "server.mode", Message: "must be one of dev, prod"},
+		{Key: "server.timeout", Message: "must be at least 1s"},
+		{Key: "server.backends[0].url", Message: "is required"},
+	}, validationErr.Fields)
+	assert.Contains(t, err.Error(), "server.port: must be at most 65535")
+
+	var valid struct {
+		Port int    `toml:"port" validate:"required,min=1,max=65535"`
+		Mode string `toml:"mode" validate:"oneof=dev prod"`
+		Name string `toml:"name" validate:"oneof=a b"`
+	}
+
+	assert.NoError(t, loader.Load("valid", &valid))
+}
@@ -0,0 +1,193 @@
+package cconfig
+
+import (
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// KeyWatcher is a Loader that can watch its keys for changes. The loaders created by this package implement it. Use
+// the Watch func to watch a key with any Loader.
+type KeyWatcher interface {
+	Loader
+
+	// Watch returns a channel that receives a Value each time the value at the key (ex. "ratelimit.rps" or the
+	// "ratelimit" table) changes, so that a subsystem can react to the keys it uses instead of every change. Values
+	// change when the config is reloaded by a Watcher or when an override is set (see Overrides). A receiver that falls
+	// behind only gets the latest Value. The returned func stops the watch and closes the channel.
+	Watch(key string) (<-chan Value, func())
+}
+
+// Watch watches the key with the loader (see KeyWatcher). If the loader does not implement KeyWatcher, the key's
+// value never changes so the returned channel is closed right away.
+func Watch(l Loader, key string) (<-chan Value, func()) {
+	if w, ok := l.(KeyWatcher); ok {
+		return w.Watch(key)
+	}
+
+	ch := make(chan Value)
+	close(ch)
+
+	return ch, func() {}
+}
+
+// Value is sent by KeyWatcher.Watch when the value of a watched key changes.
+type Value struct {
+	// Key is the watched key.
+	Key string
+
+	// Set is false if the key no longer has a value.
+	Set bool
+
+	loader Loader
+}
+
+// Load loads the key's current value into dest the same way as Get. dest is not changed if the key does not have a
+// value.
+func (v Value) Load(dest interface{}) error {
+	_, err := loadKey(v.loader, v.Key, dest)
+	return err
+}
+
+// watch is a subscription created by KeyWatcher.Watch.
+type watch struct {
+	key    string
+	ch     chan Value
+	loader Loader
+	last   keySnapshot
+}
+
+func (l *loader) Watch(key string) (<-chan Value, func()) {
+	return l.watch(key, l)
+}
+
+func (l *secretsLoader) Watch(key string) (<-chan Value, func()) {
+	base, ok := baseLoader(l.loader)
+	if !ok {
+		return Watch(l.loader, key)
+	}
+
+	// The values are loaded through the secrets loader so that secret references are resolved
+	return base.watch(key, l)
+}
+
+func (l *loader) watch(key string, outer Loader) (<-chan Value, func()) {
+	w := &watch{
+		key:    key,
+		ch:     make(chan Value, 1),
+		loader: outer,
+		last:   l.snapshot(key),
+	}
+
+	l.watchMu.Lock()
+	id := l.nextWatchID
+	l.nextWatchID++
+	l.watches[id] = w
+	l.watchMu.Unlock()
+
+	return w.ch, func() {
+		l.watchMu.Lock()
+		defer l.watchMu.Unlock()
+
+		if _, ok := l.watches[id]; ok {
+			delete(l.watches, id)
+			close(w.ch)
+		}
+	}
+}
+
+// notifyWatches sends the new values of the watched keys that changed. If a watcher has not received the previous
+// value yet, it is replaced so that slow receivers only see the latest value.
+func (l *loader) notifyWatches() {
+	l.watchMu.Lock()
+	defer l.watchMu.Unlock()
+
+	for _, w := range l.watches {
+		snapshot := l.snapshot(w.key)
+		if reflect.DeepEqual(snapshot, w.last) {
+			continue
+		}
+
+		w.last = snapshot
+		w.send(Value{Key: w.key, Set: snapshot.set, loader: w.loader})
+	}
+}
+
+// notifyKeys sends the values of the watched keys that overlap the given keys even though the config did not change
+// (ex. when a secret that they reference is renewed). The subscribers of the loader's Watchers are notified as well.
+func (l *loader) notifyKeys(keys []string) {
+	l.watchMu.Lock()
+	for _, w := range l.watches {
+		for _, key := range keys {
+			if w.key == key || strings.HasPrefix(w.key, key+".") || strings.HasPrefix(key, w.key+".") {
+				w.send(Value{Key: w.key, Set: w.last.set, loader: w.loader})
+				break
+			}
+		}
+	}
+	l.watchMu.Unlock()
+
+	l.notifyWatchers(keys)
+}
+
+// send sends the value, replacing the previous one if it has not been received yet.
+func (w *watch) send(val Value) {
+	select {
+	case <-w.ch:
+	default:
+	}
+
+	w.ch <- val
+}
+
+// keySnapshot holds what the effective value of a key depends on, so that changes to it can be detected.
+type keySnapshot struct {
+	set       bool
+	value     interface{}
+	overrides map[string]string
+}
+
+// snapshot returns the parts of the loader's state that the key's value depends on. The value in the config tree is
+// left out if it is shadowed by an override, an environment variable, or a flag.
+func (l *loader) snapshot(key string) keySnapshot {
+	overrides := make(map[string]string)
+
+	for k, v := range l.overrideValues() {
+		if k == key || strings.HasPrefix(k, key+".") {
+			overrides[k] = v
+		}
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	_, hasFlag := l.flags.Values[key]
+	_, hasOverride := overrides[key]
+	shadowed := hasFlag || hasOverride || l.hasEnv(key)
+
+	var value interface{}
+	if l.tree.Has(key) && !shadowed {
+		value = l.tree.Get(key)
+		if tree, ok := value.(*toml.Tree); ok {
+			value = tree.ToMap()
+		}
+	}
+
+	return keySnapshot{
+		set:       l.tree.Has(key) || shadowed || len(overrides) > 0,
+		value:     value,
+		overrides: overrides,
+	}
+}
+
+func (l *loader) hasEnv(key string) bool {
+	if !l.env {
+		return false
+	}
+
+	_, ok := os.LookupEnv(envName(l.envPrefix, key))
+
+	return ok
+}
@@ -0,0 +1,108 @@
+package cconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoader_Watch(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[ratelimit]
+			rps = 10
+			burst = 20
+		`,
+	})
+
+	fp := path.Join(dir, "test.toml")
+
+	loader, err := cconfig.New(cconfig.Path(fp))
+	assert.NoError(t, err)
+
+	watcher, err := cconfig.NewWatcher(cconfig.NewWatcherParams{Loader: loader})
+	assert.NoError(t, err)
+
+	overrides, err := cconfig.NewOverrides(cconfig.NewOverridesParams{
+		Loader:      loader,
+		AllowedKeys: []string{"ratelimit.*"},
+	})
+	assert.NoError(t, err)
+
+	rps, cancelRPS := cconfig.Watch(loader, "ratelimit.rps")
+	table, cancelTable := cconfig.Watch(loader, "ratelimit")
+
+	defer cancelTable()
+
+	assert.NoError(t, ioutil.WriteFile(fp, []byte(`
+		[ratelimit]
+		rps = 10
+		burst = 30
+	`), os.ModePerm))
+	assert.NoError(t, watcher.Reload())
+
+	assert.Empty(t, rps)
+
+	val := <-table
+	assert.Equal(t, "ratelimit", val.Key)
+	assert.True(t, val.Set)
+
+	var config struct {
+		Burst int `toml:"burst"`
+	}
+
+	assert.NoError(t, val.Load(&config))
+	assert.Equal(t, 30, config.Burst)
+
+	_, err = overrides.Set("ratelimit.rps", "50", time.Minute, "ops")
+	assert.NoError(t, err)
+
+	val = <-rps
+
+	var n int
+
+	assert.NoError(t, val.Load(&n))
+	assert.Equal(t, 50, n)
+	assert.Len(t, table, 1)
+
+	assert.NoError(t, ioutil.WriteFile(fp, []byte(`
+		[ratelimit]
+		burst = 30
+	`), os.ModePerm))
+	assert.NoError(t, watcher.Reload())
+	assert.Empty(t, rps)
+
+	overrides.Delete("ratelimit.rps", "ops")
+
+	val = <-rps
+	assert.False(t, val.Set)
+
+	cancelRPS()
+
+	_, ok := <-rps
+	assert.False(t, ok)
+}
+
+type staticLoader struct{}
+
+func (staticLoader) Load(key string, dest interface{}) error {
+	return nil
+}
+
+func TestWatch_ExternalLoader(t *testing.T) {
+	t.Parallel()
+
+	values, cancel := cconfig.Watch(staticLoader{}, "ratelimit")
+	defer cancel()
+
+	_, ok := <-values
+	assert.False(t, ok)
+}
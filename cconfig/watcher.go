@@ -0,0 +1,223 @@
+package cconfig
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+const defaultWatchInterval = 5 * time.Second
+
+type (
+	// NewWatcherParams holds the params needed to create a Watcher.
+	NewWatcherParams struct {
+		// Loader must be created by one of the constructors in this package, optionally wrapped by WithSecrets.
+		Loader Loader
+
+		// Interval is how often the config files are checked for changes. It defaults to 5s.
+		Interval time.Duration
+
+		// OnError is called when the config files cannot be reloaded (ex. because of a syntax error). The previous
+		// config is kept in that case.
+		OnError func(err error)
+	}
+
+	// Watcher reloads the config files when they change, or when the process receives a SIGHUP, and notifies the
+	// subscribers of the keys whose values changed. Remote providers (see WithRemote) are fetched on every interval.
+	// This lets values like log levels (see clogger.WatchLevels) and feature toggles change without a restart.
+	Watcher struct {
+		loader   Loader
+		src      *loader
+		interval time.Duration
+		onError  func(err error)
+
+		mu       sync.Mutex
+		subs     map[string]map[int]func(l Loader)
+		nextID   int
+		modTimes map[string]time.Time
+	}
+)
+
+// NewWatcher creates a new Watcher.
+func NewWatcher(p NewWatcherParams) (*Watcher, error) {
+	src, ok := baseLoader(p.Loader)
+	if !ok {
+		return nil, cerrors.New(nil, "loader does not support reloading", nil)
+	}
+
+	if p.Interval <= 0 {
+		p.Interval = defaultWatchInterval
+	}
+
+	w := &Watcher{
+		loader:   p.Loader,
+		src:      src,
+		interval: p.Interval,
+		onError:  p.OnError,
+		subs:     make(map[string]map[int]func(l Loader)),
+	}
+
+	w.modTimes = w.readModTimes()
+
+	// The loader's runtime overrides notify the watcher's subscribers as well
+	src.mu.Lock()
+	src.watchers = append(src.watchers, w)
+	src.mu.Unlock()
+
+	return w, nil
+}
+
+// OnChange calls fn each time the values under the key change. fn should read the new values with the given
+// Loader's Load method, the same way they are read when the app starts. The returned func cancels the subscription.
+func (w *Watcher) OnChange(key string, fn func(l Loader)) (cancel func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id := w.nextID
+	w.nextID++
+
+	if w.subs[key] == nil {
+		w.subs[key] = make(map[int]func(l Loader))
+	}
+
+	w.subs[key][id] = fn
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		delete(w.subs[key], id)
+	}
+}
+
+// Reload reads the config files right away and notifies the subscribers of the keys that changed.
+func (w *Watcher) Reload() error {
+	changed, err := w.src.reload()
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	w.modTimes = w.readModTimes()
+	w.mu.Unlock()
+
+	w.notify(changed)
+
+	return nil
+}
+
+// notify calls the subscribers of the given top-level keys.
+func (w *Watcher) notify(keys []string) {
+	w.mu.Lock()
+
+	fns := make([]func(l Loader), 0)
+
+	for _, key := range keys {
+		for _, fn := range w.subs[key] {
+			fns = append(fns, fn)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, fn := range fns {
+		fn(w.loader)
+	}
+}
+
+// notifyWatchers notifies the subscribers of the loader's Watchers of a change to the given keys.
+func (l *loader) notifyWatchers(keys []string) {
+	l.mu.RLock()
+	watchers := append([]*Watcher{}, l.watchers...)
+	l.mu.RUnlock()
+
+	if len(watchers) == 0 {
+		return
+	}
+
+	seen := make(map[string]bool)
+	topKeys := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		topKey := strings.Split(key, ".")[0]
+		if !seen[topKey] {
+			seen[topKey] = true
+			topKeys = append(topKeys, topKey)
+		}
+	}
+
+	for _, w := range watchers {
+		w.notify(topKeys)
+	}
+}
+
+// Run checks the config files (and remote providers) for changes every interval, and reloads them on SIGHUP, until
+// ctx is canceled.
+func (w *Watcher) Run(ctx context.Context) error {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	defer signal.Stop(sighup)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sighup:
+			w.reload()
+		case <-ticker.C:
+			if w.src.hasRemotes() || w.filesChanged() {
+				w.reload()
+			}
+		}
+	}
+}
+
+func (w *Watcher) reload() {
+	err := w.Reload()
+	if err != nil && w.onError != nil {
+		w.onError(err)
+	}
+}
+
+func (w *Watcher) filesChanged() bool {
+	modTimes := w.readModTimes()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(modTimes) != len(w.modTimes) {
+		return true
+	}
+
+	for fp, t := range modTimes {
+		if !w.modTimes[fp].Equal(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *Watcher) readModTimes() map[string]time.Time {
+	modTimes := make(map[string]time.Time)
+
+	for _, fp := range w.src.watchedFiles() {
+		info, err := os.Stat(fp)
+		if err != nil {
+			continue
+		}
+
+		modTimes[fp] = info.ModTime()
+	}
+
+	return modTimes
+}
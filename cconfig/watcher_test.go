@@ -0,0 +1,95 @@
+package cconfig_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcher(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir = cconfigtest.SetupDirWithConfigs(t, map[string]string{
+			"base.toml": `
+				[group2]
+				key1 = "val1"
+			`,
+			"test.toml": `
+				extends = "base.toml"
+
+				[group1]
+				level = "info"
+			`,
+		})
+		fp      = path.Join(dir, "test.toml")
+		changes = make(chan string, 10)
+	)
+
+	loader, err := cconfig.NewWithKeyOverrides(cconfig.Path(fp))
+	assert.NoError(t, err)
+
+	watcher, err := cconfig.NewWatcher(cconfig.NewWatcherParams{
+		Loader:   loader,
+		Interval: 10 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+
+	watcher.OnChange("group1", func(l cconfig.Loader) {
+		var config struct {
+			Level string `toml:"level"`
+		}
+
+		assert.NoError(t, l.Load("group1", &config))
+
+		changes <- config.Level
+	})
+
+	cancelGroup2 := watcher.OnChange("group2", func(l cconfig.Loader) {
+		changes <- "group2"
+	})
+	cancelGroup2()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		assert.NoError(t, watcher.Run(ctx))
+	}()
+
+	assert.NoError(t, ioutil.WriteFile(fp, []byte(`
+		extends = "base.toml"
+
+		[group1]
+		level = "debug"
+	`), os.ModePerm))
+	assert.NoError(t, os.Chtimes(fp, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+
+	select {
+	case level := <-changes:
+		assert.Equal(t, "debug", level)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a change notification")
+	}
+
+	assert.NoError(t, ioutil.WriteFile(path.Join(dir, "base.toml"), []byte(`
+		[group2]
+		key1 = "val2"
+	`), os.ModePerm))
+	assert.NoError(t, watcher.Reload())
+
+	var group2 struct {
+		Key1 string `toml:"key1"`
+	}
+
+	assert.NoError(t, loader.Load("group2", &group2))
+	assert.Equal(t, "val2", group2.Key1)
+	assert.Empty(t, changes)
+}
@@ -0,0 +1,241 @@
+package cdebug
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCaptureSize         = 100
+	defaultCaptureMaxBodyBytes = 64 << 10
+	redactedHeaderValue        = "[REDACTED]"
+)
+
+// defaultRedactHeaders are always redacted from captures since they hold credentials.
+var defaultRedactHeaders = []string{ //nolint:gochecknoglobals
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Csrf-Token",
+}
+
+// defaultRedactFields are always redacted from JSON and form bodies in captures since they usually hold credentials.
+var defaultRedactFields = []string{ //nolint:gochecknoglobals
+	"password",
+	"token",
+	"secret",
+	"api_key",
+}
+
+// Capture is a request and its response as recorded by the CaptureMiddleware.
+type Capture struct {
+	ID       uint64        `json:"id"`
+	Time     time.Time     `json:"time"`
+	Duration time.Duration `json:"duration"`
+
+	Method                string      `json:"method"`
+	URL                   string      `json:"url"`
+	RequestHeaders        http.Header `json:"request_headers"`
+	RequestBody           string      `json:"request_body"`
+	RequestBodyTruncated  bool        `json:"request_body_truncated"`
+	StatusCode            int         `json:"status_code"`
+	ResponseHeaders       http.Header `json:"response_headers"`
+	ResponseBody          string      `json:"response_body"`
+	ResponseBodyTruncated bool        `json:"response_body_truncated"`
+}
+
+// NewCaptureBuffer creates a CaptureBuffer that holds up to Config.Capture.Size captures.
+func NewCaptureBuffer(config Config) *CaptureBuffer {
+	size := config.Capture.Size
+	if size <= 0 {
+		size = defaultCaptureSize
+	}
+
+	return &CaptureBuffer{
+		captures: make([]Capture, 0, size),
+		size:     size,
+	}
+}
+
+// CaptureBuffer is a ring buffer that holds the latest captures. Older captures are dropped once it is full.
+type CaptureBuffer struct {
+	mu       sync.Mutex
+	captures []Capture
+	size     int
+	next     int
+	lastID   uint64
+}
+
+// Add adds the capture to the buffer and assigns its ID.
+func (b *CaptureBuffer) Add(c Capture) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	c.ID = b.lastID
+
+	if len(b.captures) < b.size {
+		b.captures = append(b.captures, c)
+		return
+	}
+
+	b.captures[b.next] = c
+	b.next = (b.next + 1) % b.size
+}
+
+// List returns the captures in the buffer, newest first.
+func (b *CaptureBuffer) List() []Capture {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	list := make([]Capture, 0, len(b.captures))
+
+	for i := len(b.captures) - 1; i >= 0; i-- {
+		list = append(list, b.captures[(b.next+i)%len(b.captures)])
+	}
+
+	return list
+}
+
+func redactHeaders(h http.Header, redact []string) http.Header {
+	out := h.Clone()
+
+	for _, name := range redact {
+		if _, ok := out[http.CanonicalHeaderKey(name)]; ok {
+			out.Set(name, redactedHeaderValue)
+		}
+	}
+
+	return out
+}
+
+// redactBody replaces the values of the JSON fields and form keys in the body that contain one of the redacted names.
+// Multipart bodies are redacted entirely since their fields cannot be found reliably once they are truncated. Other
+// content types are returned as is. A JSON body that cannot be parsed (ex. because it was truncated) is redacted
+// entirely since its fields cannot be found.
+func redactBody(body, contentType string, redact []string) string {
+	if body == "" {
+		return body
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		return redactedHeaderValue
+	case mediaType == "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(body)
+		if err != nil {
+			return redactedHeaderValue
+		}
+
+		for key := range form {
+			if shouldRedactField(key, redact) {
+				form[key] = []string{redactedHeaderValue}
+			}
+		}
+
+		return form.Encode()
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var data interface{}
+
+		dec := json.NewDecoder(strings.NewReader(body))
+		dec.UseNumber()
+
+		if err := dec.Decode(&data); err != nil {
+			return redactedHeaderValue
+		}
+
+		var buf bytes.Buffer
+
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+
+		if err := enc.Encode(redactJSON(data, redact)); err != nil {
+			return redactedHeaderValue
+		}
+
+		return strings.TrimSuffix(buf.String(), "\n")
+	default:
+		return body
+	}
+}
+
+// redactURL returns the URL with the values of the query params that contain one of the redacted names replaced.
+func redactURL(u *url.URL, redact []string) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		query = url.Values{}
+	}
+
+	for key := range query {
+		if shouldRedactField(key, redact) {
+			query[key] = []string{redactedHeaderValue}
+		}
+	}
+
+	redacted := *u
+	redacted.RawQuery = query.Encode()
+
+	return redacted.String()
+}
+
+func redactJSON(data interface{}, redact []string) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			if shouldRedactField(key, redact) {
+				v[key] = redactedHeaderValue
+				continue
+			}
+
+			v[key] = redactJSON(val, redact)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactJSON(v[i], redact)
+		}
+	}
+
+	return data
+}
+
+func shouldRedactField(name string, redact []string) bool {
+	name = strings.ToLower(name)
+
+	for _, r := range redact {
+		if strings.Contains(name, strings.ToLower(r)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cappedBuffer keeps up to max bytes of what is written to it.
+type cappedBuffer struct {
+	buf       strings.Builder
+	max       int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) {
+	if remaining := b.max - b.buf.Len(); len(p) > remaining {
+		p = p[:remaining]
+		b.truncated = true
+	}
+
+	b.buf.Write(p)
+}
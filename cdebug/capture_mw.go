@@ -0,0 +1,155 @@
+package cdebug
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gocopper/copper/cclock"
+)
+
+var errRWIsNotHijacker = errors.New("internal response writer is not http.Hijacker")
+
+type (
+	// NewCaptureMiddlewareParams holds the params needed to create a CaptureMiddleware.
+	NewCaptureMiddlewareParams struct {
+		Config   Config
+		Captures *CaptureBuffer
+		Clock    cclock.Clock
+	}
+
+	// CaptureMiddleware records requests and responses, including their bodies, into a CaptureBuffer so that they
+	// can be inspected at /debug/captures. It is meant for debugging hard-to-reproduce API issues in staging and does
+	// nothing unless Config.Capture.Enabled is set. Bodies are capped at Config.Capture.MaxBodyBytes, and headers,
+	// JSON fields, and form keys that hold credentials are redacted.
+	CaptureMiddleware struct {
+		config       CaptureConfig
+		captures     *CaptureBuffer
+		clock        cclock.Clock
+		redact       []string
+		redactFields []string
+	}
+)
+
+// NewCaptureMiddleware creates a new CaptureMiddleware.
+func NewCaptureMiddleware(p NewCaptureMiddlewareParams) *CaptureMiddleware {
+	config := p.Config.Capture
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = defaultCaptureMaxBodyBytes
+	}
+
+	return &CaptureMiddleware{
+		config:       config,
+		captures:     p.Captures,
+		clock:        p.Clock,
+		redact:       append(append([]string{}, defaultRedactHeaders...), config.RedactHeaders...),
+		redactFields: append(append([]string{}, defaultRedactFields...), config.RedactFields...),
+	}
+}
+
+// Handle records the request and its response once the next handler returns. The request body is recorded as the
+// handler reads it so that the handler's own limits still apply. Requests for the debug endpoints are not recorded.
+func (mw *CaptureMiddleware) Handle(next http.Handler) http.Handler {
+	if !mw.config.Enabled {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/debug/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var (
+			start = mw.clock.Now()
+			rw    = captureRw{
+				internal:   w,
+				statusCode: http.StatusOK,
+				body:       cappedBuffer{max: mw.config.MaxBodyBytes},
+			}
+			reqBody = captureBody{
+				internal: r.Body,
+				body:     cappedBuffer{max: mw.config.MaxBodyBytes},
+			}
+		)
+
+		capture := Capture{
+			Time:           start,
+			Method:         r.Method,
+			URL:            redactURL(r.URL, mw.redactFields),
+			RequestHeaders: redactHeaders(r.Header, mw.redact),
+		}
+
+		if r.Body != nil {
+			r.Body = &reqBody
+		}
+
+		next.ServeHTTP(&rw, r)
+
+		capture.Duration = mw.clock.Now().Sub(start)
+		capture.RequestBody = redactBody(reqBody.body.buf.String(), r.Header.Get("Content-Type"), mw.redactFields)
+		capture.RequestBodyTruncated = reqBody.body.truncated
+		capture.StatusCode = rw.statusCode
+		capture.ResponseHeaders = redactHeaders(rw.Header(), mw.redact)
+		capture.ResponseBody = redactBody(rw.body.buf.String(), rw.Header().Get("Content-Type"), mw.redactFields)
+		capture.ResponseBodyTruncated = rw.body.truncated
+
+		mw.captures.Add(capture)
+	})
+}
+
+type captureBody struct {
+	internal io.ReadCloser
+	body     cappedBuffer
+}
+
+func (b *captureBody) Read(p []byte) (int, error) {
+	n, err := b.internal.Read(p)
+	b.body.Write(p[:n])
+
+	return n, err
+}
+
+func (b *captureBody) Close() error {
+	return b.internal.Close()
+}
+
+type captureRw struct {
+	internal   http.ResponseWriter
+	statusCode int
+	body       cappedBuffer
+}
+
+func (rw *captureRw) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := rw.internal.(http.Hijacker)
+	if !ok {
+		return nil, nil, errRWIsNotHijacker
+	}
+
+	return h.Hijack()
+}
+
+func (rw *captureRw) Flush() {
+	if f, ok := rw.internal.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (rw *captureRw) Header() http.Header {
+	return rw.internal.Header()
+}
+
+func (rw *captureRw) Write(b []byte) (int, error) {
+	n, err := rw.internal.Write(b)
+	rw.body.Write(b[:n])
+
+	return n, err
+}
+
+func (rw *captureRw) WriteHeader(statusCode int) {
+	rw.internal.WriteHeader(statusCode)
+	rw.statusCode = statusCode
+}
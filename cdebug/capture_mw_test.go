@@ -0,0 +1,195 @@
+package cdebug_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cdebug"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptureMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now    = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock  = cclock.NewFake(now)
+		config = cdebug.Config{
+			Enabled: true,
+			Capture: cdebug.CaptureConfig{
+				Enabled:       true,
+				Size:          2,
+				MaxBodyBytes:  8,
+				RedactHeaders: []string{"X-Session-Token"},
+			},
+		}
+		captures = cdebug.NewCaptureBuffer(config)
+	)
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		GlobalMiddlewares: []chttp.Middleware{cdebug.NewCaptureMiddleware(cdebug.NewCaptureMiddlewareParams{
+			Config:   config,
+			Captures: captures,
+			Clock:    clock,
+		})},
+		Routers: []chttp.Router{
+			cdebug.NewRouter(cdebug.NewRouterParams{Config: config, Captures: captures}),
+			chttptest.NewRouter([]chttp.Route{
+				{
+					Path: "/echo",
+					Handler: func(w http.ResponseWriter, r *http.Request) {
+						body, _ := ioutil.ReadAll(r.Body)
+
+						clock.Advance(time.Second)
+						w.Header().Set("Set-Cookie", "session=secret")
+						w.WriteHeader(http.StatusCreated)
+						_, _ = w.Write(body)
+					},
+				},
+			}),
+		},
+		Authorizer: headerAuthorizer{},
+		Logger:     clogger.NewNoop(),
+	}))
+	defer server.Close()
+
+	for _, body := range []string{"first", "second", "a long body"} {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/echo?q="+body[:1], strings.NewReader(body)) //nolint:noctx
+		assert.NoError(t, err)
+
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set("X-Session-Token", "secret")
+		req.Header.Set("X-Request-Source", "test")
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+	}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/debug/captures", nil) //nolint:noctx
+	assert.NoError(t, err)
+
+	req.Header.Set("X-Role", "admin")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	var list []cdebug.Capture
+
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, 2, len(list))
+	assert.Equal(t, []uint64{3, 2}, []uint64{list[0].ID, list[1].ID})
+
+	c := list[0]
+
+	assert.Equal(t, http.MethodPost, c.Method)
+	assert.Equal(t, "/echo?q=a", c.URL)
+	assert.Equal(t, time.Second, c.Duration)
+	assert.Equal(t, "a long b", c.RequestBody)
+	assert.True(t, c.RequestBodyTruncated)
+	assert.Equal(t, http.StatusCreated, c.StatusCode)
+	assert.Equal(t, "a long b", c.ResponseBody)
+	assert.True(t, c.ResponseBodyTruncated)
+	assert.Equal(t, "[REDACTED]", c.RequestHeaders.Get("Authorization"))
+	assert.Equal(t, "[REDACTED]", c.RequestHeaders.Get("X-Session-Token"))
+	assert.Equal(t, "test", c.RequestHeaders.Get("X-Request-Source"))
+	assert.Equal(t, "[REDACTED]", c.ResponseHeaders.Get("Set-Cookie"))
+
+	assert.Equal(t, "second", list[1].RequestBody)
+	assert.False(t, list[1].RequestBodyTruncated)
+}
+
+func TestCaptureMiddleware_Disabled(t *testing.T) {
+	t.Parallel()
+
+	var (
+		config   = cdebug.Config{Enabled: true}
+		captures = cdebug.NewCaptureBuffer(config)
+		mw       = cdebug.NewCaptureMiddleware(cdebug.NewCaptureMiddlewareParams{
+			Config:   config,
+			Captures: captures,
+			Clock:    cclock.New(),
+		})
+	)
+
+	resp := httptest.NewRecorder()
+	mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).
+		ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Empty(t, captures.List())
+}
+
+func TestCaptureMiddleware_RedactBody(t *testing.T) {
+	t.Parallel()
+
+	var (
+		config = cdebug.Config{
+			Capture: cdebug.CaptureConfig{
+				Enabled:      true,
+				RedactFields: []string{"ssn"},
+			},
+		}
+		captures = cdebug.NewCaptureBuffer(config)
+		mw       = cdebug.NewCaptureMiddleware(cdebug.NewCaptureMiddlewareParams{
+			Config:   config,
+			Captures: captures,
+			Clock:    cclock.New(),
+		})
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/login?next=%2Fhome&token=abc",
+		strings.NewReader("email=a%40b.com&Password=hunter2&ssn=1"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ioutil.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write([]byte(`{"access_token":"abc","users":[{"name":"a","id":12345678901234567890}]}`))
+	})).ServeHTTP(httptest.NewRecorder(), req)
+
+	list := captures.List()
+	assert.Equal(t, 1, len(list))
+	assert.Equal(t, "/login?next=%2Fhome&token=%5BREDACTED%5D", list[0].URL)
+	assert.Equal(t, "Password=%5BREDACTED%5D&email=a%40b.com&ssn=%5BREDACTED%5D", list[0].RequestBody)
+	assert.Equal(t, `{"access_token":"[REDACTED]","users":[{"id":12345678901234567890,"name":"a"}]}`, list[0].ResponseBody)
+}
+
+func TestCaptureMiddleware_RedactMultipartBody(t *testing.T) {
+	t.Parallel()
+
+	var (
+		config = cdebug.Config{
+			Capture: cdebug.CaptureConfig{Enabled: true},
+		}
+		captures = cdebug.NewCaptureBuffer(config)
+		mw       = cdebug.NewCaptureMiddleware(cdebug.NewCaptureMiddlewareParams{
+			Config:   config,
+			Captures: captures,
+			Clock:    cclock.New(),
+		})
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("--x\r\n"+
+		"Content-Disposition: form-data; name=\"password\"\r\n\r\nhunter2\r\n--x--\r\n"))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+
+	mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ioutil.ReadAll(r.Body)
+	})).ServeHTTP(httptest.NewRecorder(), req)
+
+	list := captures.List()
+	assert.Equal(t, 1, len(list))
+	assert.Equal(t, "[REDACTED]", list[0].RequestBody)
+}
@@ -0,0 +1,95 @@
+package cdebug
+
+import (
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cerrors"
+)
+
+// LoadConfig loads the cdebug config from the app config
+func LoadConfig(appConfig cconfig.Loader) (Config, error) {
+	var config Config
+
+	err := appConfig.Load("cdebug", &config)
+	if err != nil {
+		return Config{}, cerrors.New(err, "failed to load debug config", nil)
+	}
+
+	return config, nil
+}
+
+// Config configures the cdebug module
+type Config struct {
+	// Enabled serves the debug endpoints. They expose internal details of the app, so they are disabled by default.
+	Enabled bool `toml:"enabled"`
+
+	// Addr serves the debug endpoints on a separate server (ex. "127.0.0.1:6060") instead of the app's server. This
+	// keeps them off the public port.
+	Addr string `toml:"addr"`
+
+	// Requires lists the requirements that are checked by the chttp.Authorizer (see chttp.Route) for the debug
+	// endpoints served on the app's server. It defaults to "role:admin". Requests are rejected if the app has no
+	// authorizer. It is not checked on the separate server since that server has no authorizer.
+	Requires []string `toml:"requires"`
+
+	// Capture configures the CaptureMiddleware.
+	Capture CaptureConfig `toml:"capture"`
+
+	// Overrides configures the endpoints that override config values at runtime.
+	Overrides OverridesConfig `toml:"overrides"`
+}
+
+// OverridesConfig configures the /debug/config/overrides endpoints, which temporarily override config values in the
+// running process (see cconfig.Overrides). They are served on the app's server so that requests are checked by its
+// chttp.Authorizer, even if the other debug endpoints are served on a separate address. An override only changes the
+// values that are read again: values read with cconfig.Get or cconfig.MustGet, and watched values such as
+// chttp.maintenance.enabled (see chttp.MaintenanceMiddleware) or the log levels (see clogger.WatchLevels). For example:
+//
+//	[cdebug.overrides]
+//	enabled = true
+//	allowed_keys = ["chttp.maintenance.enabled", "clogger.level"]
+//	requires = ["role:admin"]
+type OverridesConfig struct {
+	// Enabled serves the override endpoints.
+	Enabled bool `toml:"enabled"`
+
+	// AllowedKeys lists the keys that can be overridden. A key can be a pattern (see path.Match).
+	AllowedKeys []string `toml:"allowed_keys"`
+
+	// Requires lists the requirements that are checked by the chttp.Authorizer (see chttp.Route). It defaults to
+	// Config.Requires.
+	Requires []string `toml:"requires"`
+
+	// MaxTTL is the longest an override can last. It defaults to 24h.
+	MaxTTL time.Duration `toml:"max_ttl"`
+}
+
+// CaptureConfig configures the CaptureMiddleware. The captures are served at /debug/captures when the debug endpoints
+// are enabled. For example:
+//
+//	[cdebug.capture]
+//	enabled = true
+//	size = 200
+//	redact_headers = ["X-Session-Token"]
+//	redact_fields = ["ssn"]
+type CaptureConfig struct {
+	// Enabled records requests and responses. Since captures include bodies, it should only be enabled in
+	// environments like staging.
+	Enabled bool `toml:"enabled"`
+
+	// Size is the number of captures that are kept. It defaults to 100.
+	Size int `toml:"size"`
+
+	// MaxBodyBytes caps the size of each recorded body. It defaults to 64KiB.
+	MaxBodyBytes int `toml:"max_body_bytes"`
+
+	// RedactHeaders lists headers to redact in addition to the ones that usually hold credentials (ex.
+	// Authorization and Cookie).
+	RedactHeaders []string `toml:"redact_headers"`
+
+	// RedactFields lists names of JSON fields and form keys to redact from bodies in addition to the ones that usually
+	// hold credentials (ex. password and token). A field is redacted if its name contains one of the names, ignoring
+	// case, so "token" also redacts "access_token".
+	RedactFields []string `toml:"redact_fields"`
+}
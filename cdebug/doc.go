@@ -0,0 +1,3 @@
+// Package cdebug provides runtime debug endpoints (pprof, expvar, goroutine dumps, request captures, and config
+// overrides) that can be enabled with config to diagnose an app in production without code changes.
+package cdebug
@@ -0,0 +1,160 @@
+package cdebug
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gocopper/copper/cauth"
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/clogger"
+)
+
+const defaultOverridesMaxTTL = 24 * time.Hour
+
+// NewConfigOverridesParams holds the params needed to create the config overrides served by the Router.
+type NewConfigOverridesParams struct {
+	Config Config
+	Loader cconfig.Loader
+	Clock  cclock.Clock
+	Logger clogger.Logger
+}
+
+// NewConfigOverrides creates the cconfig.Overrides for the keys allowed by the config. Each change is written to the
+// logger as an audit log entry.
+func NewConfigOverrides(p NewConfigOverridesParams) (*cconfig.Overrides, error) {
+	return cconfig.NewOverrides(cconfig.NewOverridesParams{
+		Loader:      p.Loader,
+		AllowedKeys: p.Config.Overrides.AllowedKeys,
+		Clock:       p.Clock,
+		OnAudit: func(entry cconfig.AuditEntry) {
+			p.Logger.WithTags(map[string]interface{}{
+				"action":    entry.Action,
+				"key":       entry.Key,
+				"value":     entry.Value,
+				"oldValue":  entry.OldValue,
+				"actor":     entry.Actor,
+				"expiresAt": entry.ExpiresAt,
+			}).Info("Config override changed")
+		},
+	})
+}
+
+type setOverrideBody struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	TTL   string `json:"ttl"`
+}
+
+func overrideRoutes(config OverridesConfig, overrides *cconfig.Overrides, debugRequires []string,
+	actor func(r *http.Request) string) []chttp.Route {
+	if !config.Enabled || overrides == nil {
+		return nil
+	}
+
+	requires := config.Requires
+	if len(requires) == 0 {
+		requires = debugRequires
+	}
+
+	maxTTL := config.MaxTTL
+	if maxTTL <= 0 {
+		maxTTL = defaultOverridesMaxTTL
+	}
+
+	return []chttp.Route{
+		{
+			Path:     "/debug/config/overrides",
+			Methods:  []string{http.MethodGet},
+			Requires: requires,
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(w, http.StatusOK, overrides.List())
+			},
+		},
+		{
+			Path:     "/debug/config/overrides",
+			Methods:  []string{http.MethodPost},
+			Requires: requires,
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				handleSetOverride(w, r, overrides, maxTTL, auditActor(r, actor))
+			},
+		},
+		{
+			Path:     "/debug/config/overrides/{key}",
+			Methods:  []string{http.MethodDelete},
+			Requires: requires,
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				overrides.Delete(chttp.URLParams(r)["key"], auditActor(r, actor))
+
+				w.WriteHeader(http.StatusNoContent)
+			},
+		},
+	}
+}
+
+func handleSetOverride(w http.ResponseWriter, r *http.Request, overrides *cconfig.Overrides, maxTTL time.Duration,
+	actor string) {
+	var body setOverrideBody
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, cerrors.New(err, "failed to decode request body", nil))
+		return
+	}
+
+	ttl, err := time.ParseDuration(body.TTL)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, cerrors.New(err, "invalid ttl", nil))
+		return
+	}
+
+	if ttl > maxTTL {
+		writeJSONError(w, http.StatusBadRequest, cerrors.New(nil, "ttl is longer than the max ttl", map[string]interface{}{
+			"maxTTL": maxTTL.String(),
+		}))
+
+		return
+	}
+
+	override, err := overrides.Set(body.Key, body.Value, ttl, actor)
+	if errors.Is(err, cconfig.ErrKeyNotOverridable) {
+		writeJSONError(w, http.StatusForbidden, err)
+		return
+	}
+
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, override)
+}
+
+// auditActor returns the identity of who made the request, or their IP if they do not have one.
+func auditActor(r *http.Request, actor func(r *http.Request) string) string {
+	if id := actor(r); id != "" {
+		return id
+	}
+
+	return chttp.ClientIP(r)
+}
+
+// claimsActor returns the subject of the claims set by the cauth middleware.
+func claimsActor(r *http.Request) string {
+	return cauth.ClaimsFromCtx(r.Context()).Subject()
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
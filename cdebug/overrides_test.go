@@ -0,0 +1,111 @@
+package cdebug_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/gocopper/copper/cdebug"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+type headerAuthorizer struct{}
+
+func (headerAuthorizer) Authorize(r *http.Request, requirements []string) (bool, error) {
+	return r.Header.Get("X-Role") == strings.TrimPrefix(requirements[0], "role:"), nil
+}
+
+func TestRouter_Overrides(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[chttp.maintenance]
+			enabled = false
+		`,
+	})
+
+	loader, err := cconfig.New(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+
+	config := cdebug.Config{
+		Addr: "127.0.0.1:0",
+		Overrides: cdebug.OverridesConfig{
+			Enabled:     true,
+			AllowedKeys: []string{"chttp.maintenance.enabled"},
+		},
+	}
+
+	overrides, err := cdebug.NewConfigOverrides(cdebug.NewConfigOverridesParams{
+		Config: config,
+		Loader: loader,
+		Clock:  cclock.New(),
+		Logger: clogger.NewNoop(),
+	})
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{cdebug.NewRouter(cdebug.NewRouterParams{
+			Config:    config,
+			Overrides: overrides,
+			Actor: func(r *http.Request) string {
+				return r.Header.Get("X-User")
+			},
+		})},
+		Authorizer: headerAuthorizer{},
+		Logger:     clogger.NewNoop(),
+	}))
+	defer server.Close()
+
+	do := func(method, url, body, role string) *http.Response {
+		req, err := http.NewRequest(method, server.URL+url, strings.NewReader(body)) //nolint:noctx
+		assert.NoError(t, err)
+
+		req.Header.Set("X-Role", role)
+		req.Header.Set("X-User", role+"@example.com")
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+
+		return resp
+	}
+
+	setBody := `{"key": "chttp.maintenance.enabled", "value": "true", "ttl": "15m"}`
+
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost, "/debug/config/overrides", setBody, "user").StatusCode)
+	assert.Equal(t, http.StatusCreated, do(http.MethodPost, "/debug/config/overrides", setBody, "admin").StatusCode)
+	assert.True(t, cconfig.MustGet[bool](loader, "chttp.maintenance.enabled"))
+
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost, "/debug/config/overrides",
+		`{"key": "chttp.port", "value": "80", "ttl": "15m"}`, "admin").StatusCode)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/debug/config/overrides",
+		`{"key": "chttp.maintenance.enabled", "value": "true", "ttl": "48h"}`, "admin").StatusCode)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/debug/config/overrides", nil) //nolint:noctx
+	assert.NoError(t, err)
+	req.Header.Set("X-Role", "admin")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	var list []cconfig.Override
+
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	assert.NoError(t, resp.Body.Close())
+	assert.Len(t, list, 1)
+	assert.Equal(t, "true", list[0].Value)
+	assert.Equal(t, "admin@example.com", list[0].Actor)
+
+	assert.Equal(t, http.StatusNoContent,
+		do(http.MethodDelete, "/debug/config/overrides/chttp.maintenance.enabled", "", "admin").StatusCode)
+	assert.False(t, cconfig.MustGetOr(loader, "chttp.maintenance.enabled", true))
+}
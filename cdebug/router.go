@@ -0,0 +1,130 @@
+package cdebug
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	rpprof "runtime/pprof"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/chttp"
+)
+
+const defaultRequirement = "role:admin"
+
+type (
+	// NewRouterParams holds the params needed to create a Router.
+	NewRouterParams struct {
+		Config    Config
+		Captures  *CaptureBuffer
+		Overrides *cconfig.Overrides
+
+		// Actor returns who is changing a config override for the audit log (ex. the user that the app's
+		// Authorizer identified). It defaults to the subject of the request's cauth claims. The client's IP is used
+		// when there is no identity.
+		Actor func(r *http.Request) string `wire:"-"`
+	}
+
+	// Router provides the debug routes on the app's server. It has no routes unless the debug endpoints are enabled
+	// without a separate address. Every route requires Config.Requires:
+	//
+	//	/debug/pprof/       pprof index and profiles (heap, goroutine, allocs, etc.)
+	//	/debug/vars         expvar variables
+	//	/debug/goroutines   stack traces of all goroutines as text
+	//	/debug/routes       the app's routes as JSON (see chttp.Routes), only on the app's server
+	//	/debug/captures     requests and responses recorded by the CaptureMiddleware as JSON, newest first
+	//
+	// The config override endpoints are served separately when they are enabled (see OverridesConfig), and require
+	// OverridesConfig.Requires:
+	//
+	//	GET    /debug/config/overrides         active overrides as JSON
+	//	POST   /debug/config/overrides         sets an override from a JSON body (ex. {"key": "..", "value": "..",
+	//	                                       "ttl": "15m"})
+	//	DELETE /debug/config/overrides/{key}   removes an override
+	Router struct {
+		config    Config
+		captures  *CaptureBuffer
+		overrides *cconfig.Overrides
+		actor     func(r *http.Request) string
+	}
+)
+
+// NewRouter creates a new Router.
+func NewRouter(p NewRouterParams) *Router {
+	actor := p.Actor
+	if actor == nil {
+		actor = claimsActor
+	}
+
+	return &Router{config: p.Config, captures: p.Captures, overrides: p.Overrides, actor: actor}
+}
+
+// Routes defines the HTTP routes for this router.
+func (ro *Router) Routes() []chttp.Route {
+	requires := ro.config.Requires
+	if len(requires) == 0 {
+		requires = []string{defaultRequirement}
+	}
+
+	// The override endpoints are only served on the app's server since they need its authorizer
+	overrides := overrideRoutes(ro.config.Overrides, ro.overrides, requires, ro.actor)
+
+	if !ro.config.Enabled || ro.config.Addr != "" {
+		return overrides
+	}
+
+	// The routes endpoint is only served on the app's server since a separate server does not have the app's routes.
+	return append(append(routes(ro.config, ro.captures, requires), overrides...), chttp.Route{
+		Path:     "/debug/routes",
+		Methods:  []string{http.MethodGet},
+		Requires: requires,
+		Handler:  handleRoutes,
+	})
+}
+
+func routes(config Config, captures *CaptureBuffer, requires []string) []chttp.Route {
+	get := []string{http.MethodGet}
+
+	routes := []chttp.Route{
+		{Path: "/debug/pprof/cmdline", Methods: get, Handler: pprof.Cmdline},
+		{Path: "/debug/pprof/profile", Methods: get, Handler: pprof.Profile},
+		{Path: "/debug/pprof/symbol", Methods: []string{http.MethodGet, http.MethodPost}, Handler: pprof.Symbol},
+		{Path: "/debug/pprof/trace", Methods: get, Handler: pprof.Trace},
+		{Path: "/debug/pprof/{profile:.*}", Methods: get, Handler: pprof.Index},
+		{Path: "/debug/vars", Methods: get, Handler: expvar.Handler().ServeHTTP},
+		{Path: "/debug/goroutines", Methods: get, Handler: handleGoroutines},
+	}
+
+	if config.Capture.Enabled && captures != nil {
+		routes = append(routes, chttp.Route{
+			Path:    "/debug/captures",
+			Methods: get,
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				_ = json.NewEncoder(w).Encode(captures.List())
+			},
+		})
+	}
+
+	for i := range routes {
+		routes[i].Requires = requires
+	}
+
+	return routes
+}
+
+func handleRoutes(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	_ = json.NewEncoder(w).Encode(chttp.Routes(r.Context()))
+}
+
+func handleGoroutines(w http.ResponseWriter, r *http.Request) {
+	const debugLevel = 2 // print stack traces in the same format as an unrecovered panic
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	_ = rpprof.Lookup("goroutine").WriteTo(w, debugLevel)
+}
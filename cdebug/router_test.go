@@ -0,0 +1,54 @@
+package cdebug_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/cdebug"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_Routes(t *testing.T) {
+	t.Parallel()
+
+	router := cdebug.NewRouter(cdebug.NewRouterParams{Config: cdebug.Config{Enabled: true}})
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers:    []chttp.Router{router},
+		Authorizer: headerAuthorizer{},
+		Logger:     clogger.NewNoop(),
+	}))
+	defer server.Close()
+
+	for _, path := range []string{"/debug/routes", "/debug/vars", "/debug/goroutines", "/debug/pprof/"} {
+		resp, err := http.Get(server.URL + path) //nolint:noctx
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, path)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/debug/routes", nil) //nolint:noctx
+	assert.NoError(t, err)
+
+	req.Header.Set("X-Role", "admin")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	var routes []chttp.RouteInfo
+
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&routes))
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, chttp.ListRoutes([]chttp.Router{router}), routes)
+	assert.Contains(t, routes, chttp.RouteInfo{
+		Path:     "/debug/routes",
+		Methods:  []string{http.MethodGet},
+		Handler:  "github.com/gocopper/copper/cdebug.handleRoutes",
+		Requires: []string{"role:admin"},
+	})
+}
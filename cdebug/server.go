@@ -0,0 +1,97 @@
+package cdebug
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/clifecycle"
+	"github.com/gocopper/copper/clogger"
+)
+
+type (
+	// NewServerParams holds the params needed to create a Server.
+	NewServerParams struct {
+		Config    Config
+		Captures  *CaptureBuffer
+		Lifecycle *clifecycle.Lifecycle
+		Logger    clogger.Logger
+	}
+
+	// Server serves the debug endpoints on Config.Addr, separate from the app's server.
+	Server struct {
+		config   Config
+		captures *CaptureBuffer
+		lc       *clifecycle.Lifecycle
+		logger   clogger.Logger
+		internal http.Server
+		addr     net.Addr
+	}
+)
+
+// NewServer creates a new Server.
+func NewServer(p NewServerParams) *Server {
+	return &Server{
+		config:   p.Config,
+		captures: p.Captures,
+		lc:       p.Lifecycle,
+		logger:   p.Logger,
+	}
+}
+
+// Addr returns the address that the server is listening on. It is nil until Run returns.
+func (s *Server) Addr() net.Addr {
+	return s.addr
+}
+
+// Run starts the debug server if the debug endpoints are enabled with a separate address. It returns once the server
+// is listening. The server is stopped with the app's lifecycle.
+func (s *Server) Run() error {
+	if !s.config.Enabled || s.config.Addr == "" {
+		return nil
+	}
+
+	s.internal.Handler = chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{debugRouter{config: s.config, captures: s.captures}},
+		Logger:  s.logger,
+	})
+
+	ln, err := net.Listen("tcp", s.config.Addr)
+	if err != nil {
+		return cerrors.New(err, "failed to listen for debug requests", map[string]interface{}{
+			"addr": s.config.Addr,
+		})
+	}
+
+	s.addr = ln.Addr()
+
+	s.lc.OnStopNamed("debug server", func(ctx context.Context) error {
+		return s.internal.Shutdown(ctx)
+	})
+
+	s.logger.WithTags(map[string]interface{}{
+		"addr": s.addr.String(),
+	}).Info("Serving debug endpoints..")
+
+	go func() {
+		err := s.internal.Serve(ln)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Debug server did not close cleanly", err)
+		}
+	}()
+
+	return nil
+}
+
+type debugRouter struct {
+	config   Config
+	captures *CaptureBuffer
+}
+
+func (ro debugRouter) Routes() []chttp.Route {
+	// The separate server has no authorizer, so it relies on its address not being public
+	return routes(ro.config, ro.captures, nil)
+}
@@ -0,0 +1,60 @@
+package cdebug_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/gocopper/copper/cdebug"
+	"github.com/gocopper/copper/clifecycle"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Run(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger = clogger.NewNoop()
+		lc     = clifecycle.New()
+		config = cdebug.Config{Enabled: true, Addr: "127.0.0.1:0"}
+	)
+
+	defer lc.Stop(logger)
+
+	server := cdebug.NewServer(cdebug.NewServerParams{
+		Config:    config,
+		Lifecycle: lc,
+		Logger:    logger,
+	})
+
+	assert.NoError(t, server.Run())
+	assert.Nil(t, cdebug.NewRouter(cdebug.NewRouterParams{Config: config}).Routes())
+
+	for path, want := range map[string]string{
+		"/debug/pprof/":        "heap",
+		"/debug/vars":          "memstats",
+		"/debug/goroutines":    "goroutine",
+		"/debug/pprof/heap":    "",
+		"/debug/pprof/cmdline": "",
+	} {
+		resp, err := http.Get("http://" + server.Addr().String() + path) //nolint:noctx
+		assert.NoError(t, err)
+
+		body, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+		assert.Contains(t, string(body), want, path)
+	}
+}
+
+func TestRouter_Disabled(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, cdebug.NewRouter(cdebug.NewRouterParams{}).Routes())
+	assert.NotEmpty(t, cdebug.NewRouter(cdebug.NewRouterParams{
+		Config: cdebug.Config{Enabled: true},
+	}).Routes())
+}
@@ -0,0 +1,17 @@
+package cdebug
+
+import "github.com/google/wire"
+
+// WireModule can be used as part of google/wire setup.
+var WireModule = wire.NewSet( //nolint:gochecknoglobals
+	LoadConfig,
+	NewCaptureBuffer,
+	wire.Struct(new(NewCaptureMiddlewareParams), "*"),
+	NewCaptureMiddleware,
+	wire.Struct(new(NewConfigOverridesParams), "*"),
+	NewConfigOverrides,
+	wire.Struct(new(NewRouterParams), "*"),
+	NewRouter,
+	wire.Struct(new(NewServerParams), "*"),
+	NewServer,
+)
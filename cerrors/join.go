@@ -0,0 +1,66 @@
+package cerrors
+
+import (
+	"errors"
+	"strings"
+)
+
+// Join returns an error that wraps the given errors. Nil errors are discarded. If all of the errors are nil, Join
+// returns nil. If only one of them is not nil, it is returned as is. Otherwise, the returned error is a Joined.
+func Join(errs ...error) error {
+	joined := make([]error, 0, len(errs))
+
+	for _, err := range errs {
+		if err != nil {
+			joined = append(joined, err)
+		}
+	}
+
+	switch len(joined) {
+	case 0:
+		return nil
+	case 1:
+		return joined[0]
+	}
+
+	return Joined{Errors: joined}
+}
+
+// Joined holds multiple errors, such as the failures of independent steps that all ran. errors.Is and errors.As
+// match any of the errors.
+type Joined struct {
+	Errors []error
+}
+
+// Error returns the message of each error on its own line.
+func (e Joined) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+
+	for _, err := range e.Errors {
+		msgs = append(msgs, "- "+strings.ReplaceAll(err.Error(), "\n", "\n  "))
+	}
+
+	return "multiple errors:\n" + strings.Join(msgs, "\n")
+}
+
+// Is reports whether any of the errors match the target.
+func (e Joined) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first error that matches the target.
+func (e Joined) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
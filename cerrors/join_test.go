@@ -0,0 +1,58 @@
+package cerrors_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoin_Nil(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, cerrors.Join())
+	assert.Nil(t, cerrors.Join(nil, nil))
+}
+
+func TestJoin_One(t *testing.T) {
+	t.Parallel()
+
+	err := errors.New("test-err") //nolint:goerr113
+
+	assert.Equal(t, err, cerrors.Join(nil, err))
+}
+
+func TestJoin_Many(t *testing.T) {
+	t.Parallel()
+
+	var (
+		errA = cerrors.New(context.DeadlineExceeded, "a failed", nil)
+		errB = cerrors.New(nil, "b failed", map[string]interface{}{"key": "val"})
+	)
+
+	err := cerrors.Join(errA, nil, errB)
+
+	var joined cerrors.Joined
+
+	assert.True(t, errors.As(err, &joined))
+	assert.Equal(t, []error{errA, errB}, joined.Errors)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.False(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, `multiple errors:
+- a failed because
+  > context deadline exceeded
+- b failed where key=val`, err.Error())
+}
+
+func TestJoin_As(t *testing.T) {
+	t.Parallel()
+
+	err := cerrors.Join(errors.New("test-err"), cerrors.New(nil, "c failed", nil)) //nolint:goerr113
+
+	var cErr cerrors.Error
+
+	assert.True(t, errors.As(err, &cErr))
+	assert.Equal(t, "c failed", cErr.Message)
+}
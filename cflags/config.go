@@ -0,0 +1,39 @@
+package cflags
+
+import (
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cerrors"
+)
+
+const defaultCacheTTL = 10 * time.Second
+
+// LoadConfig loads the cflags config from the app config
+func LoadConfig(appConfig cconfig.Loader) (Config, error) {
+	var config Config
+
+	err := appConfig.Load("cflags", &config)
+	if err != nil {
+		return Config{}, cerrors.New(err, "failed to load feature flags config", nil)
+	}
+
+	return config.withDefaults(), nil
+}
+
+// Config configures the cflags module
+type Config struct {
+	// CacheTTL is how long the flags are cached before they are read from the store again. It defaults to 10s.
+	CacheTTL time.Duration `toml:"cache_ttl"`
+
+	// Flags are the flags used by ConfigStore, by name.
+	Flags map[string]Flag `toml:"flags"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.CacheTTL <= 0 {
+		c.CacheTTL = defaultCacheTTL
+	}
+
+	return c
+}
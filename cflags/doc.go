@@ -0,0 +1,4 @@
+// Package cflags provides feature flags that can be turned on for everyone, for a percentage of users, or for users
+// and tenants with certain attributes. Flags are read from a Store (the app config, a SQL database, or a remote
+// provider) and evaluated for each request, so features can be rolled out without a redeploy.
+package cflags
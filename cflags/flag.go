@@ -0,0 +1,130 @@
+package cflags
+
+import (
+	"context"
+	"hash/fnv"
+)
+
+type ctxKey string
+
+const subjectCtxKey = ctxKey("cflags/subject")
+
+// percentageBuckets is the number of buckets that subjects are hashed into for percentage rollouts, which allows
+// percentages with two decimals (ex. 0.25).
+const percentageBuckets = 10000
+
+// Target keys that match the subject's ids instead of its attributes.
+const (
+	TargetUser   = "user"
+	TargetTenant = "tenant"
+)
+
+type (
+	// Flag is a feature flag. A flag is on for a subject if it is enabled, the subject matches its targets, and the
+	// subject falls in its percentage. For example, in the app config:
+	//
+	//	[cflags.flags.new_checkout]
+	//	enabled = true
+	//	percentage = 25.0
+	//	targets = { plan = ["pro", "team"] }
+	Flag struct {
+		Name string `toml:"-"`
+
+		// Enabled turns the flag on. Disabled flags are off for everyone, so it doubles as a kill switch.
+		Enabled bool `toml:"enabled"`
+
+		// Percentage (0-100) of the subjects that match the targets that the flag is on for. Subjects are bucketed
+		// by their user id (or tenant id) so a subject keeps getting the same result. If it is not set, the flag is
+		// on for everyone, while 0 turns it off for everyone so that a rollout can be ramped down to nobody.
+		Percentage *float64 `toml:"percentage" validate:"min=0,max=100"`
+
+		// Targets limits the flag to the subjects whose attributes have one of the listed values. The "user" and
+		// "tenant" targets match the subject's ids. All targets must match.
+		Targets map[string][]string `toml:"targets"`
+	}
+
+	// Subject is who a flag is evaluated for. It is usually set for each request by SubjectMiddleware.
+	Subject struct {
+		UserID     string
+		TenantID   string
+		Attributes map[string]string
+	}
+)
+
+// WithSubject returns a context that holds the given subject.
+func WithSubject(ctx context.Context, s Subject) context.Context {
+	return context.WithValue(ctx, subjectCtxKey, s)
+}
+
+// SubjectFromCtx returns the subject stored in the context or an empty subject if it does not have one.
+func SubjectFromCtx(ctx context.Context) Subject {
+	s, _ := ctx.Value(subjectCtxKey).(Subject)
+
+	return s
+}
+
+// IsOn returns true if the flag is on for the subject.
+func (f *Flag) IsOn(s Subject) bool {
+	if !f.Enabled {
+		return false
+	}
+
+	for key, values := range f.Targets {
+		if !contains(values, s.attribute(key)) {
+			return false
+		}
+	}
+
+	if f.Percentage == nil || *f.Percentage >= 100 {
+		return true
+	}
+
+	if *f.Percentage <= 0 {
+		return false
+	}
+
+	id := s.UserID
+	if id == "" {
+		id = s.TenantID
+	}
+
+	if id == "" {
+		return false
+	}
+
+	return bucket(f.Name, id) < int(*f.Percentage*percentageBuckets/100)
+}
+
+func (s Subject) attribute(key string) string {
+	switch key {
+	case TargetUser:
+		return s.UserID
+	case TargetTenant:
+		return s.TenantID
+	default:
+		return s.Attributes[key]
+	}
+}
+
+// bucket hashes the id with the flag's name so that the same subjects are not always the first to get every flag.
+func bucket(name, id string) int {
+	h := fnv.New32a()
+
+	_, _ = h.Write([]byte(name + ":" + id))
+
+	return int(h.Sum32() % percentageBuckets)
+}
+
+func contains(values []string, val string) bool {
+	if val == "" {
+		return false
+	}
+
+	for _, v := range values {
+		if v == val {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,109 @@
+package cflags_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/gocopper/copper/cflags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlag_IsOn(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		flag    cflags.Flag
+		subject cflags.Subject
+		want    bool
+	}{
+		{
+			name: "disabled",
+			flag: cflags.Flag{Enabled: false},
+			want: false,
+		},
+		{
+			name: "enabled for everyone",
+			flag: cflags.Flag{Enabled: true},
+			want: true,
+		},
+		{
+			name:    "matching target",
+			flag:    cflags.Flag{Enabled: true, Targets: map[string][]string{"plan": {"pro", "team"}}},
+			subject: cflags.Subject{Attributes: map[string]string{"plan": "team"}},
+			want:    true,
+		},
+		{
+			name:    "non-matching target",
+			flag:    cflags.Flag{Enabled: true, Targets: map[string][]string{"plan": {"pro"}}},
+			subject: cflags.Subject{Attributes: map[string]string{"plan": "free"}},
+			want:    false,
+		},
+		{
+			name: "all targets must match",
+			flag: cflags.Flag{Enabled: true, Targets: map[string][]string{
+				"tenant": {"acme"},
+				"plan":   {"pro"},
+			}},
+			subject: cflags.Subject{TenantID: "acme", Attributes: map[string]string{"plan": "free"}},
+			want:    false,
+		},
+		{
+			name:    "user target",
+			flag:    cflags.Flag{Enabled: true, Targets: map[string][]string{"user": {"42"}}},
+			subject: cflags.Subject{UserID: "42"},
+			want:    true,
+		},
+		{
+			name: "percentage without a subject",
+			flag: cflags.Flag{Enabled: true, Percentage: percentage(50)},
+			want: false,
+		},
+		{
+			name:    "zero percentage",
+			flag:    cflags.Flag{Enabled: true, Percentage: percentage(0)},
+			subject: cflags.Subject{UserID: "42"},
+			want:    false,
+		},
+		{
+			name: "full percentage without a subject",
+			flag: cflags.Flag{Enabled: true, Percentage: percentage(100)},
+			want: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, tc.flag.IsOn(tc.subject))
+		})
+	}
+}
+
+func TestFlag_IsOn_Percentage(t *testing.T) {
+	t.Parallel()
+
+	var (
+		flag = cflags.Flag{Name: "new_checkout", Enabled: true, Percentage: percentage(25)}
+		on   = 0
+	)
+
+	for i := 0; i < 10000; i++ {
+		subject := cflags.Subject{UserID: strconv.Itoa(i)}
+
+		if flag.IsOn(subject) {
+			on++
+		}
+
+		assert.Equal(t, flag.IsOn(subject), flag.IsOn(subject))
+	}
+
+	assert.InDelta(t, 2500, on, 200)
+}
+
+func percentage(p float64) *float64 {
+	return &p
+}
@@ -0,0 +1,53 @@
+package cflags
+
+import (
+	"net/http"
+
+	"github.com/gocopper/copper/chttp"
+)
+
+// SubjectMiddleware sets the subject that flags are evaluated for on each request. The func usually reads the user
+// and tenant from the session or auth claims, so it should run after the middleware that sets them.
+func SubjectMiddleware(fn func(r *http.Request) Subject) chttp.Middleware {
+	return chttp.HandleMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(WithSubject(r.Context(), fn(r))))
+		})
+	})
+}
+
+// RequireFlag returns a middleware that gates a route behind the flag. If the flag is off for the request's
+// subject, it responds with 404 Not Found so that the route looks like it does not exist. For example:
+//
+//	{
+//		Path:        "/checkout/v2",
+//		Middlewares: []chttp.Middleware{flags.RequireFlag("new_checkout")},
+//		Handler:     ro.HandleCheckoutV2,
+//	}
+func (s *Service) RequireFlag(name string) chttp.Middleware {
+	return chttp.HandleMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !s.IsOn(r.Context(), name) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	})
+}
+
+// NewFlagRenderFunc creates the flag template function that returns true if a flag is on for the request's subject.
+// For example:
+//
+//	{{ if flag "new_checkout" }} ... {{ end }}
+func NewFlagRenderFunc(service *Service) chttp.HTMLRenderFunc {
+	return chttp.HTMLRenderFunc{
+		Name: "flag",
+		Func: func(r *http.Request) interface{} {
+			return func(name string) bool {
+				return service.IsOn(r.Context(), name)
+			}
+		},
+	}
+}
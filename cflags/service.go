@@ -0,0 +1,118 @@
+package cflags
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/clogger"
+)
+
+type (
+	// NewServiceParams holds the params needed to create a Service
+	NewServiceParams struct {
+		Store  Store
+		Config Config
+		Clock  cclock.Clock
+		Logger clogger.Logger
+	}
+
+	// Service evaluates feature flags. The flags are cached for Config.CacheTTL.
+	Service struct {
+		store  Store
+		config Config
+		clock  cclock.Clock
+		logger clogger.Logger
+
+		mu        sync.Mutex
+		flags     map[string]Flag
+		fetchedAt time.Time
+		fetching  chan struct{}
+	}
+)
+
+// NewService creates a new Service.
+func NewService(p NewServiceParams) *Service {
+	return &Service{
+		store:  p.Store,
+		config: p.Config.withDefaults(),
+		clock:  p.Clock,
+		logger: p.Logger,
+	}
+}
+
+// IsOn returns true if the flag is on for the subject in the context (see WithSubject). Flags that do not exist are
+// off. If the flags cannot be read from the store, the last flags that were read are used.
+func (s *Service) IsOn(ctx context.Context, name string) bool {
+	flag, ok := s.flag(ctx, name)
+
+	return ok && flag.IsOn(SubjectFromCtx(ctx))
+}
+
+// Flag returns the flag with the given name and true, or false if it does not exist.
+func (s *Service) Flag(ctx context.Context, name string) (Flag, bool) {
+	return s.flag(ctx, name)
+}
+
+// Invalidate clears the cached flags so that changes to the store (ex. with SQLStore.Set) take effect right away.
+func (s *Service) Invalidate() {
+	s.mu.Lock()
+	s.fetchedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func (s *Service) flag(ctx context.Context, name string) (Flag, bool) {
+	s.mu.Lock()
+
+	now := s.clock.Now()
+	stale := s.fetchedAt.IsZero() || !now.Before(s.fetchedAt.Add(s.config.CacheTTL))
+
+	switch {
+	case stale && s.fetching == nil:
+		// The fetch time is updated before the fetch so that other calls keep using the cached flags (instead of
+		// fetching them too) and so that a failing store is not retried on every call
+		s.fetchedAt = now
+		s.fetching = make(chan struct{})
+		s.mu.Unlock()
+
+		s.fetch(ctx)
+
+		s.mu.Lock()
+	case s.fetching != nil && s.flags == nil:
+		// The flags have never been read so wait for the first fetch instead of turning every flag off
+		fetching := s.fetching
+		s.mu.Unlock()
+
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+		}
+
+		s.mu.Lock()
+	}
+
+	flag, ok := s.flags[name]
+
+	s.mu.Unlock()
+
+	return flag, ok
+}
+
+// fetch reads the flags from the store without holding the lock since it may be a slow (ex. SQL or remote) call.
+func (s *Service) fetch(ctx context.Context) {
+	flags, err := s.store.Flags(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to load feature flags", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		s.flags = flags
+	}
+
+	close(s.fetching)
+	s.fetching = nil
+}
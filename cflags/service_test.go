@@ -0,0 +1,257 @@
+package cflags_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/gocopper/copper/cflags"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/clifecycle"
+	"github.com/gocopper/copper/clogger"
+	"github.com/gocopper/copper/csql"
+	"github.com/stretchr/testify/assert"
+)
+
+type remoteProvider map[string]string
+
+func (p remoteProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	return p, nil
+}
+
+type slowStore struct {
+	flags   map[string]cflags.Flag
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (s *slowStore) Flags(ctx context.Context) (map[string]cflags.Flag, error) {
+	s.entered <- struct{}{}
+	<-s.release
+
+	return s.flags, nil
+}
+
+func TestService_ConfigStore(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[cflags.flags.new_checkout]
+			enabled = true
+			targets = { plan = ["pro"] }
+
+			[cflags.flags.old_checkout]
+			enabled = false
+
+			[cflags.flags.ramped_down]
+			enabled = true
+			percentage = 0.0
+		`,
+	})
+
+	loader, err := cconfig.New(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+
+	config, err := cflags.LoadConfig(loader)
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Second, config.CacheTTL)
+
+	svc := cflags.NewService(cflags.NewServiceParams{
+		Store:  cflags.NewConfigStore(loader),
+		Config: config,
+		Clock:  cclock.New(),
+		Logger: clogger.NewNoop(),
+	})
+
+	pro := cflags.WithSubject(context.Background(), cflags.Subject{Attributes: map[string]string{"plan": "pro"}})
+
+	assert.True(t, svc.IsOn(pro, "new_checkout"))
+	assert.False(t, svc.IsOn(context.Background(), "new_checkout"))
+	assert.False(t, svc.IsOn(pro, "old_checkout"))
+	assert.False(t, svc.IsOn(pro, "ramped_down"))
+	assert.False(t, svc.IsOn(pro, "unknown"))
+
+	flag, ok := svc.Flag(pro, "new_checkout")
+	assert.True(t, ok)
+	assert.Equal(t, "new_checkout", flag.Name)
+}
+
+func TestService_SQLStore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		logger = clogger.NewNoop()
+		lc     = clifecycle.New()
+		clock  = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	)
+
+	defer lc.Stop(logger)
+
+	db, err := csql.NewDBConnection(lc, csql.Config{
+		Dialect: "sqlite",
+		DSN:     ":memory:",
+	}, logger)
+	assert.NoError(t, err)
+
+	store := cflags.NewSQLStore(db)
+	assert.NoError(t, store.Migration().Run())
+
+	svc := cflags.NewService(cflags.NewServiceParams{
+		Store:  store,
+		Config: cflags.Config{CacheTTL: time.Minute},
+		Clock:  clock,
+		Logger: logger,
+	})
+
+	ctx = cflags.WithSubject(ctx, cflags.Subject{TenantID: "acme"})
+
+	assert.NoError(t, store.Set(ctx, cflags.Flag{
+		Name:    "beta",
+		Enabled: true,
+		Targets: map[string][]string{"tenant": {"acme"}},
+	}))
+	assert.True(t, svc.IsOn(ctx, "beta"))
+
+	assert.NoError(t, store.Set(ctx, cflags.Flag{Name: "beta", Enabled: false}))
+	assert.True(t, svc.IsOn(ctx, "beta"), "flags are cached")
+
+	clock.Advance(time.Minute)
+	assert.False(t, svc.IsOn(ctx, "beta"))
+
+	assert.NoError(t, store.Set(ctx, cflags.Flag{Name: "beta", Enabled: true}))
+	svc.Invalidate()
+	assert.True(t, svc.IsOn(ctx, "beta"))
+
+	assert.NoError(t, store.Set(ctx, cflags.Flag{Name: "beta", Enabled: true, Percentage: percentage(0)}))
+	svc.Invalidate()
+	assert.False(t, svc.IsOn(ctx, "beta"))
+
+	assert.NoError(t, store.Delete(ctx, "beta"))
+	svc.Invalidate()
+	assert.False(t, svc.IsOn(ctx, "beta"))
+}
+
+func TestService_SlowStore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = cflags.WithSubject(context.Background(), cflags.Subject{UserID: "1"})
+		clock = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		store = &slowStore{
+			flags:   map[string]cflags.Flag{"beta": {Name: "beta", Enabled: true}},
+			entered: make(chan struct{}, 1),
+			release: make(chan struct{}, 1),
+		}
+		svc = cflags.NewService(cflags.NewServiceParams{
+			Store:  store,
+			Config: cflags.Config{CacheTTL: time.Minute},
+			Clock:  clock,
+			Logger: clogger.NewNoop(),
+		})
+		done = make(chan bool)
+	)
+
+	store.release <- struct{}{}
+	assert.True(t, svc.IsOn(ctx, "beta"))
+	<-store.entered
+
+	clock.Advance(time.Minute)
+
+	go func() {
+		done <- svc.IsOn(ctx, "beta")
+	}()
+
+	<-store.entered
+
+	// The cached flags are used while the store is being read
+	assert.True(t, svc.IsOn(ctx, "beta"))
+
+	store.release <- struct{}{}
+	assert.True(t, <-done)
+}
+
+func TestRemoteStore_Flags(t *testing.T) {
+	t.Parallel()
+
+	store := cflags.NewRemoteStore(remoteProvider{
+		"beta.enabled":      "true",
+		"beta.percentage":   "10",
+		"beta.targets.plan": `["pro", "team"]`,
+	})
+
+	flags, err := store.Flags(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]cflags.Flag{
+		"beta": {
+			Name:       "beta",
+			Enabled:    true,
+			Percentage: percentage(10),
+			Targets:    map[string][]string{"plan": {"pro", "team"}},
+		},
+	}, flags)
+
+	_, err = cflags.NewRemoteStore(remoteProvider{"beta.enabled": "yes please"}).Flags(context.Background())
+	assert.Error(t, err)
+}
+
+func TestService_RequireFlag(t *testing.T) {
+	t.Parallel()
+
+	var (
+		store = cflags.NewRemoteStore(remoteProvider{
+			"beta.enabled":      "true",
+			"beta.targets.user": `["1"]`,
+		})
+		svc = cflags.NewService(cflags.NewServiceParams{
+			Store:  store,
+			Clock:  cclock.New(),
+			Logger: clogger.NewNoop(),
+		})
+		logger  = clogger.NewNoop()
+		handler = chttp.NewHandler(chttp.NewHandlerParams{
+			Routers: []chttp.Router{routerFunc(func() []chttp.Route {
+				return []chttp.Route{{
+					Path:        "/beta",
+					Methods:     []string{http.MethodGet},
+					Middlewares: []chttp.Middleware{svc.RequireFlag("beta")},
+					Handler: func(w http.ResponseWriter, r *http.Request) {
+						w.WriteHeader(http.StatusOK)
+					},
+				}}
+			})},
+			GlobalMiddlewares: []chttp.Middleware{cflags.SubjectMiddleware(func(r *http.Request) cflags.Subject {
+				return cflags.Subject{UserID: r.Header.Get("X-User")}
+			})},
+			Logger: logger,
+		})
+	)
+
+	for user, code := range map[string]int{"1": http.StatusOK, "2": http.StatusNotFound} {
+		req := httptest.NewRequest(http.MethodGet, "/beta", nil)
+		req.Header.Set("X-User", user)
+
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		assert.Equal(t, code, resp.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(cflags.WithSubject(req.Context(), cflags.Subject{UserID: "1"}))
+
+	assert.True(t, cflags.NewFlagRenderFunc(svc).Func(req).(func(string) bool)("beta"))
+}
+
+type routerFunc func() []chttp.Route
+
+func (fn routerFunc) Routes() []chttp.Route {
+	return fn()
+}
@@ -0,0 +1,128 @@
+package cflags
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/csql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	// SQLStore keeps the flags in the feature_flags table of the app's SQL database so that they can be changed at
+	// runtime (ex. from an admin page). It is provided by SQLWireModule. The table is created by the migration
+	// returned from SQLStore.Migration.
+	SQLStore struct {
+		db *gorm.DB
+	}
+
+	sqlFlag struct {
+		Name       string `gorm:"primaryKey"`
+		Enabled    bool   `gorm:"not null"`
+		Percentage *float64
+		Targets    string
+	}
+
+	sqlStoreMigration struct {
+		db *gorm.DB
+	}
+)
+
+// NewSQLStore creates a new SQLStore.
+func NewSQLStore(db *gorm.DB) *SQLStore {
+	return &SQLStore{db: db}
+}
+
+func (sqlFlag) TableName() string {
+	return "feature_flags"
+}
+
+// Migration returns a csql.Migration that creates the feature_flags table.
+func (s *SQLStore) Migration() csql.Migration {
+	return &sqlStoreMigration{db: s.db}
+}
+
+func (m *sqlStoreMigration) Run() error {
+	err := m.db.AutoMigrate(&sqlFlag{})
+	if err != nil {
+		return cerrors.New(err, "failed to migrate feature flags table", nil)
+	}
+
+	return nil
+}
+
+// Flags returns all of the flags in the table.
+func (s *SQLStore) Flags(ctx context.Context) (map[string]Flag, error) {
+	var rows []sqlFlag
+
+	err := csql.GetConn(ctx, s.db).Find(&rows).Error
+	if err != nil {
+		return nil, cerrors.New(err, "failed to query feature flags", nil)
+	}
+
+	flags := make(map[string]Flag, len(rows))
+
+	for _, row := range rows {
+		flag := Flag{
+			Name:       row.Name,
+			Enabled:    row.Enabled,
+			Percentage: row.Percentage,
+		}
+
+		if row.Targets != "" {
+			err = json.Unmarshal([]byte(row.Targets), &flag.Targets)
+			if err != nil {
+				return nil, cerrors.New(err, "failed to unmarshal feature flag targets", map[string]interface{}{
+					"flag": row.Name,
+				})
+			}
+		}
+
+		flags[row.Name] = flag
+	}
+
+	return flags, nil
+}
+
+// Set creates or updates the flag.
+func (s *SQLStore) Set(ctx context.Context, flag Flag) error {
+	row := sqlFlag{
+		Name:       flag.Name,
+		Enabled:    flag.Enabled,
+		Percentage: flag.Percentage,
+	}
+
+	if len(flag.Targets) > 0 {
+		targets, err := json.Marshal(flag.Targets)
+		if err != nil {
+			return cerrors.New(err, "failed to marshal feature flag targets", nil)
+		}
+
+		row.Targets = string(targets)
+	}
+
+	err := csql.GetConn(ctx, s.db).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&row).Error
+	if err != nil {
+		return cerrors.New(err, "failed to save feature flag", map[string]interface{}{
+			"flag": flag.Name,
+		})
+	}
+
+	return nil
+}
+
+// Delete removes the flag, which turns it off.
+func (s *SQLStore) Delete(ctx context.Context, name string) error {
+	err := csql.GetConn(ctx, s.db).Delete(&sqlFlag{Name: name}).Error
+	if err != nil {
+		return cerrors.New(err, "failed to delete feature flag", map[string]interface{}{
+			"flag": name,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package cflags
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cerrors"
+)
+
+type (
+	// Store provides the feature flags.
+	Store interface {
+		// Flags returns all of the flags by name.
+		Flags(ctx context.Context) (map[string]Flag, error)
+	}
+
+	// ConfigStore reads the flags from the cflags.flags table of the app config. It is provided by WireModule. With
+	// a cconfig.Watcher or remote providers (see cconfig.WithRemote), flags can be changed without a redeploy.
+	ConfigStore struct {
+		loader cconfig.Loader
+	}
+
+	// RemoteStore reads the flags from a remote key-value store (ex. etcd or Consul KV). The keys are the flag's
+	// name and field (ex. new_checkout.enabled, new_checkout.percentage, or new_checkout.targets.plan), and targets
+	// are JSON lists (ex. ["pro", "team"]).
+	RemoteStore struct {
+		provider cconfig.RemoteProvider
+	}
+)
+
+// NewConfigStore creates a new ConfigStore.
+func NewConfigStore(loader cconfig.Loader) *ConfigStore {
+	return &ConfigStore{loader: loader}
+}
+
+// Flags loads the flags from the app config.
+func (s *ConfigStore) Flags(ctx context.Context) (map[string]Flag, error) {
+	config, err := LoadConfig(s.loader)
+	if err != nil {
+		return nil, err
+	}
+
+	return withNames(config.Flags), nil
+}
+
+// NewRemoteStore creates a new RemoteStore.
+func NewRemoteStore(provider cconfig.RemoteProvider) *RemoteStore {
+	return &RemoteStore{provider: provider}
+}
+
+// Flags fetches the flags from the remote provider.
+func (s *RemoteStore) Flags(ctx context.Context) (map[string]Flag, error) {
+	values, err := s.provider.Fetch(ctx)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to fetch remote feature flags", nil)
+	}
+
+	flags := make(map[string]Flag)
+
+	for key, raw := range values {
+		parts := strings.SplitN(key, ".", 3) //nolint:gomnd
+		if len(parts) < 2 {                  //nolint:gomnd
+			continue
+		}
+
+		flag := flags[parts[0]]
+
+		err = setRemoteField(&flag, parts[1:], raw)
+		if err != nil {
+			return nil, cerrors.New(err, "invalid remote feature flag value", map[string]interface{}{
+				"key": key,
+			})
+		}
+
+		flags[parts[0]] = flag
+	}
+
+	return withNames(flags), nil
+}
+
+func setRemoteField(flag *Flag, field []string, raw string) error {
+	var err error
+
+	switch {
+	case field[0] == "enabled":
+		flag.Enabled, err = strconv.ParseBool(raw)
+	case field[0] == "percentage":
+		var percentage float64
+
+		percentage, err = strconv.ParseFloat(raw, 64)
+		flag.Percentage = &percentage
+	case field[0] == "targets" && len(field) == 2:
+		var values []string
+
+		err = json.Unmarshal([]byte(raw), &values)
+
+		if flag.Targets == nil {
+			flag.Targets = make(map[string][]string)
+		}
+
+		flag.Targets[field[1]] = values
+	}
+
+	return err
+}
+
+func withNames(flags map[string]Flag) map[string]Flag {
+	for name, flag := range flags {
+		flag.Name = name
+		flags[name] = flag
+	}
+
+	return flags
+}
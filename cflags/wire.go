@@ -0,0 +1,21 @@
+package cflags
+
+import "github.com/google/wire"
+
+// WireModule can be used as part of google/wire setup. It reads the flags from the app config.
+var WireModule = wire.NewSet( //nolint:gochecknoglobals
+	LoadConfig,
+	NewConfigStore,
+	wire.Bind(new(Store), new(*ConfigStore)),
+	wire.Struct(new(NewServiceParams), "*"),
+	NewService,
+)
+
+// SQLWireModule can be used in place of WireModule to keep the flags in the app's SQL database.
+var SQLWireModule = wire.NewSet( //nolint:gochecknoglobals
+	LoadConfig,
+	NewSQLStore,
+	wire.Bind(new(Store), new(*SQLStore)),
+	wire.Struct(new(NewServiceParams), "*"),
+	NewService,
+)
@@ -0,0 +1,36 @@
+package chealth
+
+import (
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cerrors"
+)
+
+const (
+	defaultCheckTimeout = 2 * time.Second
+	defaultCacheTTL     = time.Second
+)
+
+// LoadConfig loads the chealth config from the app config
+func LoadConfig(appConfig cconfig.Loader) (Config, error) {
+	var config Config
+
+	err := appConfig.Load("chealth", &config)
+	if err != nil {
+		return Config{}, cerrors.New(err, "failed to load health config", nil)
+	}
+
+	return config, nil
+}
+
+// Config configures the chealth module
+type Config struct {
+	// CheckTimeout limits how long each check can run. A check that takes longer is reported as failed, even if it
+	// does not respect its context. It defaults to 2s.
+	CheckTimeout time.Duration `toml:"check_timeout"`
+
+	// CacheTTL is how long check results are reused so that frequent probes do not overload dependencies. It
+	// defaults to 1s. Set it to a negative value to disable caching.
+	CacheTTL time.Duration `toml:"cache_ttl"`
+}
@@ -0,0 +1,14 @@
+// Package chealth runs health checks registered by the app's modules and serves them over HTTP so that load
+// balancers and orchestrators can tell if the app is alive and ready to serve traffic.
+//
+// The health routes and checks are opt-in. Add the Router to the app's routers, and register a checker for each
+// dependency that the app needs to serve traffic, ex. the database checker provided by csql:
+//
+//	health.Register("db", csql.NewHealthChecker(db))
+//
+// They are not added automatically because the app's routers are listed by the app's own wire injector, and chttp
+// cannot depend on chealth (chealth's router is built on chttp). Modules like csql provide a checker instead of
+// registering it so that an app without chealth does not pull it in.
+//
+// The /health/ready route reports the app as ready if no checks are registered.
+package chealth
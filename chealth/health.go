@@ -0,0 +1,222 @@
+package chealth
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cerrors"
+)
+
+// Statuses reported for checks and reports.
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+type (
+	// Checker checks the health of a single dependency (ex. a database connection). It should return an error if
+	// the dependency is unhealthy and respect the context's deadline.
+	Checker interface {
+		Check(ctx context.Context) error
+	}
+
+	// CheckerFunc allows a func to be used as a Checker.
+	CheckerFunc func(ctx context.Context) error
+
+	// CheckResult is the result of a single check.
+	CheckResult struct {
+		Status    string `json:"status"`
+		LatencyMS int64  `json:"latency_ms"`
+		Error     string `json:"error,omitempty"`
+	}
+
+	// Report holds the results of all checks. Its status is StatusFail if any of the checks failed.
+	Report struct {
+		Status string                 `json:"status"`
+		Checks map[string]CheckResult `json:"checks"`
+	}
+
+	// NewParams holds the params needed to create Health.
+	NewParams struct {
+		Config Config
+
+		// Clock is used to time the checks and cache the report. It defaults to the system clock.
+		Clock cclock.Clock
+	}
+
+	// Health holds the checkers registered by the app's modules and runs them.
+	Health struct {
+		config Config
+		clock  cclock.Clock
+
+		mu       sync.Mutex
+		checkers map[string]Checker
+		cached   *Report
+		cachedAt time.Time
+	}
+)
+
+// Check calls fn.
+func (fn CheckerFunc) Check(ctx context.Context) error {
+	return fn(ctx)
+}
+
+// New creates a new Health.
+func New(p NewParams) *Health {
+	if p.Config.CheckTimeout <= 0 {
+		p.Config.CheckTimeout = defaultCheckTimeout
+	}
+
+	if p.Config.CacheTTL == 0 {
+		p.Config.CacheTTL = defaultCacheTTL
+	}
+
+	if p.Clock == nil {
+		p.Clock = cclock.New()
+	}
+
+	return &Health{
+		config:   p.Config,
+		clock:    p.Clock,
+		checkers: make(map[string]Checker),
+	}
+}
+
+// Register adds a named checker that must pass for the app to be ready. Registering a checker with the same name
+// replaces the existing one.
+func (h *Health) Register(name string, checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checkers[name] = checker
+	h.cached = nil
+}
+
+// Names returns the names of the registered checkers in sorted order.
+func (h *Health) Names() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	names := make([]string, 0, len(h.checkers))
+	for name := range h.checkers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Run runs all checks concurrently, each with its own timeout, and returns a report. The report is cached for
+// Config.CacheTTL so that a hung dependency cannot make every probe wait for the timeout. The checks are not canceled
+// with ctx so that a probe that disconnects early (ex. because of its own short timeout) does not fail them and get
+// the failed report cached for the other probes.
+func (h *Health) Run(ctx context.Context) Report {
+	ctx = withoutCancel(ctx)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cached != nil && h.clock.Now().Sub(h.cachedAt) < h.config.CacheTTL {
+		return *h.cached
+	}
+
+	var (
+		wg      sync.WaitGroup
+		resMu   sync.Mutex
+		results = make(map[string]CheckResult, len(h.checkers))
+	)
+
+	for name, checker := range h.checkers {
+		wg.Add(1)
+
+		go func(name string, checker Checker) {
+			defer wg.Done()
+
+			result := h.runCheck(ctx, name, checker)
+
+			resMu.Lock()
+			results[name] = result
+			resMu.Unlock()
+		}(name, checker)
+	}
+
+	wg.Wait()
+
+	report := Report{
+		Status: StatusOK,
+		Checks: results,
+	}
+
+	for _, result := range results {
+		if result.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+
+	h.cached = &report
+	h.cachedAt = h.clock.Now()
+
+	return report
+}
+
+// withoutCancel returns a context that has the values of ctx but is never canceled.
+func withoutCancel(ctx context.Context) context.Context {
+	return detachedCtx{ctx}
+}
+
+type detachedCtx struct {
+	context.Context
+}
+
+func (detachedCtx) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedCtx) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedCtx) Err() error {
+	return nil
+}
+
+func (h *Health) runCheck(ctx context.Context, name string, checker Checker) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, h.config.CheckTimeout)
+	defer cancel()
+
+	var (
+		start = time.Now()
+		done  = make(chan error, 1)
+		err   error
+	)
+
+	go func() {
+		done <- checker.Check(ctx)
+	}()
+
+	// The check may not respect its context, so the timeout is enforced here as well.
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = cerrors.New(ctx.Err(), "check timed out", map[string]interface{}{
+			"check":   name,
+			"timeout": h.config.CheckTimeout.String(),
+		})
+	}
+
+	result := CheckResult{
+		Status:    StatusOK,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+
+	return result
+}
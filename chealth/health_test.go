@@ -0,0 +1,96 @@
+package chealth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/chealth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealth_Run(t *testing.T) {
+	t.Parallel()
+
+	var (
+		clock = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		calls = 0
+		hung  = make(chan struct{})
+	)
+
+	defer close(hung)
+
+	health := chealth.New(chealth.NewParams{
+		Config: chealth.Config{
+			CheckTimeout: 20 * time.Millisecond,
+			CacheTTL:     time.Second,
+		},
+		Clock: clock,
+	})
+
+	health.Register("db", chealth.CheckerFunc(func(ctx context.Context) error {
+		calls++
+		return nil
+	}))
+
+	health.Register("cache", chealth.CheckerFunc(func(ctx context.Context) error {
+		return errors.New("connection refused") //nolint:goerr113
+	}))
+
+	// The hung check ignores its context, so the timeout has to be enforced by Health.
+	health.Register("queue", chealth.CheckerFunc(func(ctx context.Context) error {
+		<-hung
+		return nil
+	}))
+
+	report := health.Run(context.Background())
+
+	assert.Equal(t, []string{"cache", "db", "queue"}, health.Names())
+	assert.Equal(t, chealth.StatusFail, report.Status)
+	assert.Equal(t, chealth.StatusOK, report.Checks["db"].Status)
+	assert.Equal(t, "connection refused", report.Checks["cache"].Error)
+	assert.Equal(t, chealth.StatusFail, report.Checks["queue"].Status)
+	assert.Contains(t, report.Checks["queue"].Error, "check timed out")
+
+	health.Run(context.Background())
+	assert.Equal(t, 1, calls)
+
+	clock.Advance(time.Second)
+
+	health.Run(context.Background())
+	assert.Equal(t, 2, calls)
+}
+
+func TestHealth_Run_CanceledProbe(t *testing.T) {
+	t.Parallel()
+
+	health := chealth.New(chealth.NewParams{
+		Config: chealth.Config{CheckTimeout: time.Second},
+		Clock:  cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)),
+	})
+
+	health.Register("db", chealth.CheckerFunc(func(ctx context.Context) error {
+		return ctx.Err()
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Equal(t, chealth.StatusOK, health.Run(ctx).Status)
+	assert.Equal(t, chealth.StatusOK, health.Run(context.Background()).Status)
+}
+
+func TestNew_DefaultClock(t *testing.T) {
+	t.Parallel()
+
+	health := chealth.New(chealth.NewParams{})
+
+	health.Register("db", chealth.CheckerFunc(func(ctx context.Context) error {
+		return nil
+	}))
+
+	report := health.Run(context.Background())
+	assert.Equal(t, chealth.StatusOK, report.Status)
+}
@@ -0,0 +1,73 @@
+package chealth
+
+import (
+	"net/http"
+
+	"github.com/gocopper/copper/chttp"
+)
+
+type (
+	// NewRouterParams holds the params needed to create a Router.
+	NewRouterParams struct {
+		Health *Health
+		RW     *chttp.ReaderWriter
+	}
+
+	// Router provides the health check routes:
+	//
+	//	GET /health/live   responds with 200 as long as the app can serve requests
+	//	GET /health/ready  runs the registered checks and responds with 503 if any of them fail
+	Router struct {
+		health *Health
+		rw     *chttp.ReaderWriter
+	}
+)
+
+// NewRouter creates a new Router.
+func NewRouter(p NewRouterParams) *Router {
+	return &Router{
+		health: p.Health,
+		rw:     p.RW,
+	}
+}
+
+// Routes defines the HTTP routes for this router.
+func (ro *Router) Routes() []chttp.Route {
+	return []chttp.Route{
+		{
+			Path:    "/health/live",
+			Methods: []string{http.MethodGet},
+			Handler: ro.HandleLive,
+		},
+		{
+			Path:    "/health/ready",
+			Methods: []string{http.MethodGet},
+			Handler: ro.HandleReady,
+		},
+	}
+}
+
+// HandleLive responds with an ok status. It does not run any checks so that a failing dependency does not get the
+// app restarted.
+func (ro *Router) HandleLive(w http.ResponseWriter, r *http.Request) {
+	ro.rw.WriteJSON(w, chttp.WriteJSONParams{
+		Data: map[string]string{"status": StatusOK},
+	})
+}
+
+// HandleReady runs the registered checks and writes the report.
+func (ro *Router) HandleReady(w http.ResponseWriter, r *http.Request) {
+	report := ro.health.Run(r.Context())
+
+	statusCode := http.StatusOK
+	if report.Status != StatusOK {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+
+	ro.rw.WriteJSON(w, chttp.WriteJSONParams{
+		StatusCode: statusCode,
+		Data:       report,
+	})
+}
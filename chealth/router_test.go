@@ -0,0 +1,71 @@
+package chealth_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/chealth"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		healthy = true
+		health  = chealth.New(chealth.NewParams{
+			Config: chealth.Config{CacheTTL: -1},
+			Clock:  cclock.New(),
+		})
+	)
+
+	health.Register("db", chealth.CheckerFunc(func(ctx context.Context) error {
+		if !healthy {
+			return context.DeadlineExceeded
+		}
+
+		return nil
+	}))
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{chealth.NewRouter(chealth.NewRouterParams{
+			Health: health,
+			RW:     chttptest.NewReaderWriter(t),
+		})},
+		Logger: clogger.NewNoop(),
+	}))
+	defer server.Close()
+
+	get := func(path string) (int, chealth.Report) {
+		var report chealth.Report
+
+		resp, err := http.Get(server.URL + path) //nolint:noctx
+		assert.NoError(t, err)
+
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		assert.NoError(t, resp.Body.Close())
+
+		return resp.StatusCode, report
+	}
+
+	code, report := get("/health/ready")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, chealth.StatusOK, report.Checks["db"].Status)
+
+	healthy = false
+
+	code, report = get("/health/ready")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, chealth.StatusFail, report.Status)
+
+	code, report = get("/health/live")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, chealth.StatusOK, report.Status)
+}
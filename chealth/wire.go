@@ -0,0 +1,12 @@
+package chealth
+
+import "github.com/google/wire"
+
+// WireModule can be used as part of google/wire setup.
+var WireModule = wire.NewSet( //nolint:gochecknoglobals
+	LoadConfig,
+	wire.Struct(new(NewParams), "*"),
+	New,
+	wire.Struct(new(NewRouterParams), "*"),
+	NewRouter,
+)
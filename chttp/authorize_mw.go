@@ -0,0 +1,49 @@
+package chttp
+
+import (
+	"net/http"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/clogger"
+)
+
+// Authorizer decides if a request satisfies the requirements declared on a Route using Route.Requires. The
+// requirements are opaque to chttp so they can represent roles (ex. "role:admin"), permissions, feature flags, etc.
+type Authorizer interface {
+	Authorize(r *http.Request, requirements []string) (bool, error)
+}
+
+func authorizeMiddleware(authorizer Authorizer, requirements []string, logger clogger.Logger) Middleware {
+	mw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			log := logger.WithTags(map[string]interface{}{
+				"path":     r.URL.Path,
+				"requires": requirements,
+			})
+
+			if authorizer == nil {
+				log.Error("Route requires authorization but no authorizer is configured", nil)
+				w.WriteHeader(http.StatusForbidden)
+
+				return
+			}
+
+			ok, err := authorizer.Authorize(r, requirements)
+			if err != nil {
+				log.Error("Failed to authorize request", cerrors.New(err, "authorizer returned an error", nil))
+				w.WriteHeader(http.StatusInternalServerError)
+
+				return
+			}
+
+			if !ok {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	return HandleMiddleware(mw)
+}
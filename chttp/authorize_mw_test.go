@@ -0,0 +1,91 @@
+package chttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+type headerAuthorizer struct{}
+
+func (a *headerAuthorizer) Authorize(r *http.Request, requirements []string) (bool, error) {
+	for _, req := range requirements {
+		if r.Header.Get("X-Role") != req {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func TestAuthorizeMiddleware(t *testing.T) {
+	t.Parallel()
+
+	router := chttptest.NewRouter([]chttp.Route{
+		{
+			Path:     "/admin",
+			Methods:  []string{http.MethodGet},
+			Requires: []string{"admin"},
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+		},
+	})
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers:    []chttp.Router{router},
+		Authorizer: &headerAuthorizer{},
+		Logger:     clogger.NewNoop(),
+	}))
+	defer server.Close()
+
+	for role, statusCode := range map[string]int{
+		"admin": http.StatusOK,
+		"user":  http.StatusForbidden,
+	} {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/admin", nil) //nolint:noctx
+		assert.NoError(t, err)
+
+		req.Header.Set("X-Role", role)
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+
+		assert.Equal(t, statusCode, resp.StatusCode)
+	}
+}
+
+func TestAuthorizeMiddleware_NoAuthorizer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logs   = make([]clogger.RecordedLog, 0)
+		router = chttptest.NewRouter([]chttp.Route{
+			{
+				Path:     "/admin",
+				Requires: []string{"admin"},
+				Handler:  func(w http.ResponseWriter, r *http.Request) {},
+			},
+		})
+	)
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{router},
+		Logger:  clogger.NewRecorder(&logs),
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/admin") //nolint:noctx
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, 1, len(logs))
+	assert.Equal(t, clogger.LevelError, logs[0].Level)
+}
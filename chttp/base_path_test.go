@@ -0,0 +1,73 @@
+package chttp_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHandler_BasePath(t *testing.T) {
+	t.Parallel()
+
+	rw := chttptest.NewReaderWriter(t)
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		BasePath: "/preview/pr-1/",
+		Routers: []chttp.Router{chttptest.NewRouter([]chttp.Route{
+			{
+				Name:    "post",
+				Path:    "/posts/{id}",
+				Methods: []string{http.MethodGet},
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					url, err := chttp.URL(r, "post", map[string]string{"id": "2"})
+					assert.NoError(t, err)
+
+					_, _ = w.Write([]byte(chttp.RawRoutePath(r) + " " + url))
+				},
+			},
+			{
+				Path:    "/old",
+				Methods: []string{http.MethodGet},
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					rw.Redirect(w, r, "/posts/1", 0)
+				},
+			},
+		})},
+		Logger: clogger.NewNoop(),
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/preview/pr-1/posts/1") //nolint:noctx
+	assert.NoError(t, err)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, "/posts/{id} /preview/pr-1/posts/2", string(body))
+
+	client := http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err = client.Get(server.URL + "/preview/pr-1/old") //nolint:noctx
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusSeeOther, resp.StatusCode)
+	assert.Equal(t, "/preview/pr-1/posts/1", resp.Header.Get("Location"))
+
+	resp, err = http.Get(server.URL + "/posts/1") //nolint:noctx
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
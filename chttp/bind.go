@@ -0,0 +1,91 @@
+package chttp
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+// bindValues sets the fields of the dest struct that have the given tag using the values returned by lookup. Fields
+// without the tag, or whose value is not found, are left as-is.
+func bindValues(dest interface{}, tag string, lookup func(name string) (string, bool)) error {
+	destVal := reflect.ValueOf(dest)
+	if !destVal.IsValid() {
+		return cerrors.New(nil, "dest must be a pointer to a struct", map[string]interface{}{
+			"type": "nil",
+		})
+	}
+
+	if destVal.Kind() != reflect.Ptr || destVal.Elem().Kind() != reflect.Struct {
+		return cerrors.New(nil, "dest must be a pointer to a struct", map[string]interface{}{
+			"type": destVal.Type().String(),
+		})
+	}
+
+	structVal := destVal.Elem()
+
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+
+		name, ok := field.Tag.Lookup(tag)
+		if !ok || name == "-" || field.PkgPath != "" {
+			continue
+		}
+
+		val, ok := lookup(name)
+		if !ok {
+			continue
+		}
+
+		err := setFieldFromString(structVal.Field(i), val)
+		if err != nil {
+			return cerrors.New(err, "failed to set field", map[string]interface{}{
+				tag: name,
+			})
+		}
+	}
+
+	return nil
+}
+
+func setFieldFromString(field reflect.Value, val string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(val)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(val, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(val, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(val, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetFloat(n)
+	default:
+		return cerrors.New(nil, "unsupported field type", map[string]interface{}{
+			"type": field.Type().String(),
+		})
+	}
+
+	return nil
+}
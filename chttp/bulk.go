@@ -0,0 +1,221 @@
+package chttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/gocopper/copper/cerrors"
+)
+
+// BulkMode controls how the items of a bulk request are applied.
+type BulkMode string
+
+// BulkModes supported by ReaderWriter.WriteBulk.
+const (
+	// BulkModePerItem applies each item independently (in its own transaction if BulkParams.RunInTx is set), so
+	// some items can succeed while others fail.
+	BulkModePerItem = BulkMode("per-item")
+
+	// BulkModeAllOrNothing applies all items in a single transaction and rolls it back if any item fails.
+	BulkModeAllOrNothing = BulkMode("all-or-nothing")
+)
+
+var errBulkNoTxRunner = errors.New("all-or-nothing bulk mode requires RunInTx")
+
+type (
+	// BulkParams holds the params needed for ReaderWriter.WriteBulk.
+	BulkParams struct {
+		// Count is the number of items in the request.
+		Count int
+
+		// Mode defaults to BulkModePerItem.
+		Mode BulkMode
+
+		// Apply applies the item at index i. The returned data is included in the item's result. The given context
+		// holds the transaction (if any) that the item should run in.
+		Apply func(ctx context.Context, i int) (interface{}, error)
+
+		// RunInTx runs fn in a transaction. It is optional for BulkModePerItem and required for
+		// BulkModeAllOrNothing. With csql, it can be set to:
+		//
+		//	func(ctx context.Context, fn func(context.Context) error) error {
+		//	  return csql.RunInTx(ctx, db, fn)
+		//	}
+		RunInTx func(ctx context.Context, fn func(ctx context.Context) error) error
+	}
+
+	// BulkItemResult is the result of a single item in a bulk request.
+	BulkItemResult struct {
+		Index      int               `json:"index"`
+		StatusCode int               `json:"status"`
+		Data       interface{}       `json:"data,omitempty"`
+		Error      string            `json:"error,omitempty"`
+		Fields     map[string]string `json:"fields,omitempty"`
+	}
+
+	// BulkItemError can be returned by BulkParams.Apply to set the status code of an item's result. The message of
+	// Err is sent back as is, so it should be safe to show to the client. Other errors are reported with an
+	// UnprocessableEntity status.
+	BulkItemError struct {
+		StatusCode int
+		Err        error
+	}
+)
+
+func (e BulkItemError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e BulkItemError) Unwrap() error {
+	return e.Err
+}
+
+// WriteBulk applies the items of a bulk request using the given BulkParams and writes a MultiStatus response with
+// the result of each item:
+//
+//	{"results": [{"index": 0, "status": 200, "data": {...}}, {"index": 1, "status": 422, "error": "..."}]}
+//
+// Error messages come from the outermost cerrors.Error in the chain, or from a BulkItemError, so that internal
+// details are not exposed. Other errors are reported with a generic message. Validation errors from govalidator
+// also include the errors for each field. With BulkModeAllOrNothing, the items after a
+// failed item are not applied and every item other than the failed one is reported with a FailedDependency status.
+func (rw *ReaderWriter) WriteBulk(w http.ResponseWriter, r *http.Request, p BulkParams) {
+	var results []BulkItemResult
+
+	switch p.Mode {
+	case BulkModeAllOrNothing:
+		if p.RunInTx == nil {
+			rw.WriteJSON(w, WriteJSONParams{
+				StatusCode: http.StatusInternalServerError,
+				Data:       errBulkNoTxRunner,
+			})
+
+			return
+		}
+
+		results = applyBulkAllOrNothing(r.Context(), p)
+	default:
+		results = applyBulkPerItem(r.Context(), p)
+	}
+
+	rw.WriteJSON(w, WriteJSONParams{
+		StatusCode: http.StatusMultiStatus,
+		Data: map[string]interface{}{
+			"results": results,
+		},
+	})
+}
+
+func applyBulkPerItem(ctx context.Context, p BulkParams) []BulkItemResult {
+	results := make([]BulkItemResult, p.Count)
+
+	for i := 0; i < p.Count; i++ {
+		var data interface{}
+
+		apply := func(ctx context.Context) error {
+			var err error
+
+			data, err = p.Apply(ctx, i)
+
+			return err
+		}
+
+		var err error
+		if p.RunInTx != nil {
+			err = p.RunInTx(ctx, apply)
+		} else {
+			err = apply(ctx)
+		}
+
+		results[i] = newBulkItemResult(i, data, err)
+	}
+
+	return results
+}
+
+func applyBulkAllOrNothing(ctx context.Context, p BulkParams) []BulkItemResult {
+	var (
+		results = make([]BulkItemResult, p.Count)
+		failed  = -1
+	)
+
+	txErr := p.RunInTx(ctx, func(ctx context.Context) error {
+		for i := 0; i < p.Count; i++ {
+			data, err := p.Apply(ctx, i)
+
+			results[i] = newBulkItemResult(i, data, err)
+
+			if err != nil {
+				failed = i
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if txErr == nil {
+		return results
+	}
+
+	for i := range results {
+		if i == failed {
+			continue
+		}
+
+		results[i] = BulkItemResult{
+			Index:      i,
+			StatusCode: http.StatusFailedDependency,
+			Error:      "not applied because another item failed",
+		}
+	}
+
+	// The transaction itself failed (ex. on commit) so no single item is to blame.
+	if failed == -1 {
+		for i := range results {
+			results[i].Error = "transaction failed"
+		}
+	}
+
+	return results
+}
+
+func newBulkItemResult(i int, data interface{}, err error) BulkItemResult {
+	if err == nil {
+		return BulkItemResult{
+			Index:      i,
+			StatusCode: http.StatusOK,
+			Data:       data,
+		}
+	}
+
+	// Other errors may include internal details (ex. from the database) so only a generic message is sent back
+	result := BulkItemResult{
+		Index:      i,
+		StatusCode: http.StatusUnprocessableEntity,
+		Error:      "failed to apply item",
+	}
+
+	var itemErr BulkItemError
+	if errors.As(err, &itemErr) {
+		result.StatusCode = itemErr.StatusCode
+		err = itemErr.Err
+		result.Error = err.Error()
+	}
+
+	var cerr cerrors.Error
+	if errors.As(err, &cerr) {
+		result.Error = cerr.Message
+	}
+
+	var validationErrs govalidator.Errors
+	if errors.As(err, &validationErrs) {
+		result.Error = "validation failed"
+		result.Fields = govalidator.ErrorsByField(err)
+	}
+
+	return result
+}
@@ -0,0 +1,109 @@
+package chttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReaderWriter_WriteBulk(t *testing.T) {
+	t.Parallel()
+
+	var (
+		errNotFound = errors.New("not found")
+		items       = []string{"a", "", "c"}
+	)
+
+	apply := func(ctx context.Context, i int) (interface{}, error) {
+		switch items[i] {
+		case "":
+			return nil, chttp.BulkItemError{
+				StatusCode: http.StatusNotFound,
+				Err:        cerrors.New(errNotFound, "item does not exist", nil),
+			}
+		default:
+			return items[i], nil
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		mode         chttp.BulkMode
+		wantStatuses []int
+		wantTxs      int
+	}{
+		{"PerItem", chttp.BulkModePerItem, []int{200, 404, 200}, 3},
+		{"AllOrNothing", chttp.BulkModeAllOrNothing, []int{424, 404, 424}, 1},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				rw   = chttptest.NewReaderWriter(t)
+				resp = httptest.NewRecorder()
+				txs  = 0
+				body struct {
+					Results []chttp.BulkItemResult `json:"results"`
+				}
+			)
+
+			rw.WriteBulk(resp, httptest.NewRequest(http.MethodPost, "/", nil), chttp.BulkParams{
+				Count: len(items),
+				Mode:  tc.mode,
+				Apply: apply,
+				RunInTx: func(ctx context.Context, fn func(ctx context.Context) error) error {
+					txs++
+					return fn(ctx)
+				},
+			})
+
+			assert.Equal(t, http.StatusMultiStatus, resp.Code)
+			assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+			assert.Equal(t, tc.wantTxs, txs)
+
+			statuses := make([]int, 0)
+			for _, result := range body.Results {
+				statuses = append(statuses, result.StatusCode)
+			}
+
+			assert.Equal(t, tc.wantStatuses, statuses)
+			assert.Equal(t, "item does not exist", body.Results[1].Error)
+		})
+	}
+}
+
+func TestReaderWriter_WriteBulk_InternalError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rw   = chttptest.NewReaderWriter(t)
+		resp = httptest.NewRecorder()
+		body struct {
+			Results []chttp.BulkItemResult `json:"results"`
+		}
+	)
+
+	rw.WriteBulk(resp, httptest.NewRequest(http.MethodPost, "/", nil), chttp.BulkParams{
+		Count: 1,
+		Apply: func(ctx context.Context, i int) (interface{}, error) {
+			return nil, errors.New("duplicate key value violates unique constraint \"users_email_key\"")
+		},
+	})
+
+	assert.Equal(t, http.StatusMultiStatus, resp.Code)
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, http.StatusUnprocessableEntity, body.Results[0].StatusCode)
+	assert.Equal(t, "failed to apply item", body.Results[0].Error)
+}
@@ -0,0 +1,174 @@
+package chttp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+type ctxClientIP string
+
+const ctxClientIPKey = ctxClientIP("chttp/client-ip")
+
+// ClientIP returns the IP address of the client that made the request. If the request came through one of the
+// trusted proxies (see NewHandlerParams.TrustedProxies), it is read from the X-Forwarded-For, Forwarded, or X-Real-IP
+// header (in that order). Otherwise, the headers are ignored since they can be set by anyone, and the address of the
+// peer is returned.
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(ctxClientIPKey).(string); ok {
+		return ip
+	}
+
+	return peerIP(r)
+}
+
+// parseIPNets parses a list of CIDRs (ex. 10.0.0.0/8) and IP addresses.
+func parseIPNets(values []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(values))
+
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, cerrors.New(nil, "invalid ip address", map[string]interface{}{
+					"value": value,
+				})
+			}
+
+			if v4 := ip.To4(); v4 != nil {
+				ip = v4
+			}
+
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}) //nolint:gomnd
+
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, cerrors.New(err, "invalid cidr", map[string]interface{}{
+				"value": value,
+			})
+		}
+
+		nets = append(nets, ipNet)
+	}
+
+	return nets, nil
+}
+
+// clientIPMiddleware resolves the client's IP address once so that ClientIP can return it.
+func clientIPMiddleware(trusted []*net.IPNet) Middleware {
+	mw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := resolveClientIP(r, trusted)
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxClientIPKey, ip)))
+		})
+	}
+
+	return HandleMiddleware(mw)
+}
+
+// resolveClientIP walks the addresses in the forwarding headers from the closest one (the last) to the farthest one.
+// Each address is the client of the one after it, so the first address that is not a trusted proxy is the client.
+// Addresses before it can be set by the client and are ignored.
+func resolveClientIP(r *http.Request, trusted []*net.IPNet) string {
+	ip := peerIP(r)
+	if !ipNetsContain(trusted, ip) {
+		return ip
+	}
+
+	hops := forwardedHops(r)
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		if net.ParseIP(hops[i]) == nil {
+			break
+		}
+
+		ip = hops[i]
+
+		if !ipNetsContain(trusted, ip) {
+			break
+		}
+	}
+
+	return ip
+}
+
+func forwardedHops(r *http.Request) []string {
+	if values := r.Header.Values("X-Forwarded-For"); len(values) > 0 {
+		hops := make([]string, 0)
+
+		for _, hop := range strings.Split(strings.Join(values, ","), ",") {
+			hops = append(hops, stripPort(strings.TrimSpace(hop)))
+		}
+
+		return hops
+	}
+
+	if values := r.Header.Values("Forwarded"); len(values) > 0 {
+		return forwardedForHops(strings.Join(values, ","))
+	}
+
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+		return []string{ip}
+	}
+
+	return nil
+}
+
+// forwardedForHops returns the for= addresses of a Forwarded header (ex. for=192.0.2.60;proto=http,
+// for="[2001:db8::17]:4711").
+func forwardedForHops(header string) []string {
+	hops := make([]string, 0)
+
+	for _, element := range strings.Split(header, ",") {
+		for _, pair := range strings.Split(element, ";") {
+			kv := strings.SplitN(strings.TrimSpace(pair), "=", 2) //nolint:gomnd
+			if len(kv) != 2 || !strings.EqualFold(kv[0], "for") {
+				continue
+			}
+
+			hops = append(hops, stripPort(strings.Trim(kv[1], `"`)))
+		}
+	}
+
+	return hops
+}
+
+func peerIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func ipNetsContain(nets []*net.IPNet, ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, ipNet := range nets {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stripPort removes the port and brackets from an address (ex. [2001:db8::17]:4711 or 192.0.2.60:80).
+func stripPort(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+}
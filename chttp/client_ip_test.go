@@ -0,0 +1,90 @@
+package chttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	t.Parallel()
+
+	handler := chttp.NewHandler(chttp.NewHandlerParams{
+		TrustedProxies: []string{"10.0.0.0/8", "2001:db8::1"},
+		Routers: []chttp.Router{chttptest.NewRouter([]chttp.Route{
+			{
+				Path: "/",
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(chttp.ClientIP(r)))
+				},
+			},
+		})},
+		Logger: clogger.NewNoop(),
+	})
+
+	testCases := map[string]struct {
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		"no proxy":       {"203.0.113.7:1234", nil, "203.0.113.7"},
+		"untrusted peer": {"203.0.113.7:1234", map[string]string{"X-Forwarded-For": "198.51.100.1"}, "203.0.113.7"},
+		"trusted peer":   {"10.0.0.1:1234", map[string]string{"X-Forwarded-For": "198.51.100.1"}, "198.51.100.1"},
+		"spoofed":        {"10.0.0.1:1234", map[string]string{"X-Forwarded-For": "1.1.1.1, 198.51.100.1"}, "198.51.100.1"},
+		"proxy chain":    {"10.0.0.1:1234", map[string]string{"X-Forwarded-For": "198.51.100.1, 10.0.0.2"}, "198.51.100.1"},
+		"all trusted":    {"10.0.0.1:1234", map[string]string{"X-Forwarded-For": "10.0.0.3, 10.0.0.2"}, "10.0.0.3"},
+		"invalid hop":    {"10.0.0.1:1234", map[string]string{"X-Forwarded-For": "198.51.100.1, junk"}, "10.0.0.1"},
+		"no headers":     {"10.0.0.1:1234", nil, "10.0.0.1"},
+		"x-real-ip":      {"10.0.0.1:1234", map[string]string{"X-Real-IP": "198.51.100.1"}, "198.51.100.1"},
+		"forwarded": {
+			"10.0.0.1:1234",
+			map[string]string{"Forwarded": `for=1.1.1.1, for="198.51.100.1:80";proto=https`},
+			"198.51.100.1",
+		},
+		"forwarded ipv6": {
+			"[2001:db8::1]:1234",
+			map[string]string{"Forwarded": `for="[2001:db8::17]:4711"`},
+			"2001:db8::17",
+		},
+		"xff over others": {
+			"10.0.0.1:1234",
+			map[string]string{"X-Forwarded-For": "198.51.100.1", "X-Real-IP": "1.1.1.1"},
+			"198.51.100.1",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tc.remoteAddr
+
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.want, resp.Body.String())
+		})
+	}
+}
+
+func TestClientIP_NoTrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	assert.Equal(t, "10.0.0.1", chttp.ClientIP(req))
+}
@@ -0,0 +1,328 @@
+package chttp
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gocopper/copper/clogger"
+)
+
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+
+	defaultCompressMinBytes = 1024
+)
+
+//nolint:gochecknoglobals
+var (
+	gzipWriterPool = sync.Pool{New: func() interface{} {
+		return gzip.NewWriter(io.Discard)
+	}}
+
+	brotliWriterPool = sync.Pool{New: func() interface{} {
+		return brotli.NewWriter(io.Discard)
+	}}
+
+	compressibleContentTypes = []string{
+		"text/",
+		"application/json",
+		"application/problem+json",
+		"application/javascript",
+		"application/xml",
+		"image/svg+xml",
+	}
+)
+
+// NewCompressMiddleware creates a new CompressMiddleware.
+func NewCompressMiddleware(config Config, logger clogger.Logger) *CompressMiddleware {
+	mw := CompressMiddleware{
+		minBytes: config.CompressMinBytes,
+		logger:   logger,
+	}
+
+	if mw.minBytes == 0 {
+		mw.minBytes = defaultCompressMinBytes
+	}
+
+	return &mw
+}
+
+// CompressMiddleware compresses responses using brotli or gzip based on the request's Accept-Encoding header.
+// Only responses with a compressible content type (HTML, CSS, JS, JSON, etc.) that are at least
+// Config.CompressMinBytes long are compressed.
+type CompressMiddleware struct {
+	minBytes int
+	logger   clogger.Logger
+}
+
+// Handle wraps the response writer so that the response body is compressed once it is known to be compressible.
+func (mw *CompressMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		compressRw := compressRw{
+			internal:   w,
+			encoding:   encoding,
+			minBytes:   mw.minBytes,
+			statusCode: http.StatusOK,
+		}
+
+		next.ServeHTTP(&compressRw, r)
+
+		err := compressRw.close()
+		if err != nil {
+			mw.logger.WithTags(map[string]interface{}{
+				"url":      r.URL.Path,
+				"encoding": encoding,
+			}).Warn("Failed to write compressed response", err)
+		}
+	})
+}
+
+// negotiateEncoding returns the supported encoding with the highest q-value in the Accept-Encoding header, preferring
+// brotli on ties. Encodings that are not listed get the q-value of "*", if any.
+func negotiateEncoding(acceptEncoding string) string {
+	var (
+		qs       = make(map[string]float64)
+		wildcard = 0.0
+	)
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		var (
+			params = strings.Split(strings.TrimSpace(part), ";")
+			name   = strings.ToLower(strings.TrimSpace(params[0]))
+			q      = 1.0
+		)
+
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+
+			val, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+			if err == nil {
+				q = val
+			}
+		}
+
+		if name == "*" {
+			wildcard = q
+			continue
+		}
+
+		qs[name] = q
+	}
+
+	var (
+		best  = ""
+		bestQ = 0.0
+	)
+
+	for _, encoding := range []string{encodingBrotli, encodingGzip} {
+		q, ok := qs[encoding]
+		if !ok {
+			q = wildcard
+		}
+
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+
+	return best
+}
+
+func isCompressibleContentType(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+
+	for _, prefix := range compressibleContentTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compressRw buffers the response body until it is known if the response should be compressed. The response is
+// compressed once minBytes have been written and the content type is compressible.
+type compressRw struct {
+	internal   http.ResponseWriter
+	encoding   string
+	minBytes   int
+	statusCode int
+
+	buf         []byte
+	decided     bool
+	wroteHeader bool
+	hijacked    bool
+	compressor  io.WriteCloser
+}
+
+func (rw *compressRw) Header() http.Header {
+	return rw.internal.Header()
+}
+
+func (rw *compressRw) WriteHeader(statusCode int) {
+	if rw.wroteHeader {
+		return
+	}
+
+	rw.wroteHeader = true
+	rw.statusCode = statusCode
+}
+
+func (rw *compressRw) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+
+	if rw.decided {
+		return rw.writeBody(b)
+	}
+
+	rw.buf = append(rw.buf, b...)
+
+	if len(rw.buf) < rw.minBytes {
+		return len(b), nil
+	}
+
+	err := rw.decide(true)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+func (rw *compressRw) Flush() {
+	if !rw.decided {
+		_ = rw.decide(true)
+	}
+
+	if f, ok := rw.compressor.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+
+	if f, ok := rw.internal.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (rw *compressRw) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := rw.internal.(http.Hijacker)
+	if !ok {
+		return nil, nil, errRWIsNotHijacker
+	}
+
+	conn, brw, err := h.Hijack()
+	if err == nil {
+		rw.hijacked = true
+	}
+
+	return conn, brw, err
+}
+
+func (rw *compressRw) close() error {
+	// The connection belongs to the handler once it is hijacked so nothing should be written to it
+	if rw.hijacked {
+		return nil
+	}
+
+	if !rw.decided {
+		err := rw.decide(false)
+		if err != nil {
+			return err
+		}
+	}
+
+	if rw.compressor == nil {
+		return nil
+	}
+
+	err := rw.compressor.Close()
+
+	switch c := rw.compressor.(type) {
+	case *gzip.Writer:
+		gzipWriterPool.Put(c)
+	case *brotli.Writer:
+		brotliWriterPool.Put(c)
+	}
+
+	rw.compressor = nil
+
+	return err
+}
+
+// decide writes the status code and the buffered body, compressing it if canCompress is true and the response is
+// eligible for compression.
+func (rw *compressRw) decide(canCompress bool) error {
+	rw.decided = true
+
+	h := rw.internal.Header()
+
+	if h.Get("Content-Type") == "" && len(rw.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(rw.buf))
+	}
+
+	shouldCompress := canCompress &&
+		rw.statusCode != http.StatusNoContent &&
+		rw.statusCode != http.StatusNotModified &&
+		h.Get("Content-Encoding") == "" &&
+		h.Get("Content-Range") == "" &&
+		isCompressibleContentType(h.Get("Content-Type"))
+
+	if shouldCompress {
+		h.Set("Content-Encoding", rw.encoding)
+		h.Del("Content-Length")
+
+		// A strong ETag promises byte-identical bodies, which the compressed body is not. Conditional requests still
+		// match since If-None-Match uses weak comparison.
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+
+		switch rw.encoding {
+		case encodingBrotli:
+			bw := brotliWriterPool.Get().(*brotli.Writer)
+			bw.Reset(rw.internal)
+			rw.compressor = bw
+		default:
+			gw := gzipWriterPool.Get().(*gzip.Writer)
+			gw.Reset(rw.internal)
+			rw.compressor = gw
+		}
+	}
+
+	rw.internal.WriteHeader(rw.statusCode)
+
+	if len(rw.buf) == 0 {
+		return nil
+	}
+
+	_, err := rw.writeBody(rw.buf)
+	rw.buf = nil
+
+	return err
+}
+
+func (rw *compressRw) writeBody(b []byte) (int, error) {
+	if rw.compressor != nil {
+		return rw.compressor.Write(b)
+	}
+
+	return rw.internal.Write(b)
+}
@@ -0,0 +1,200 @@
+package chttp_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCompressTestHandler(body string) http.Handler {
+	mw := chttp.NewCompressMiddleware(chttp.Config{CompressMinBytes: 100}, clogger.NewNoop())
+
+	return mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestCompressMiddleware_Gzip(t *testing.T) {
+	t.Parallel()
+
+	var (
+		body = `"` + strings.Repeat("a", 200) + `"`
+		resp = httptest.NewRecorder()
+		req  = httptest.NewRequest(http.MethodGet, "/", nil)
+	)
+
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+
+	newCompressTestHandler(body).ServeHTTP(resp, req)
+
+	assert.Equal(t, "gzip", resp.Header().Get("Content-Encoding"))
+
+	gr, err := gzip.NewReader(resp.Body)
+	assert.NoError(t, err)
+
+	out, err := ioutil.ReadAll(gr)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(out))
+}
+
+func TestCompressMiddleware_Brotli(t *testing.T) {
+	t.Parallel()
+
+	var (
+		body = `"` + strings.Repeat("a", 200) + `"`
+		resp = httptest.NewRecorder()
+		req  = httptest.NewRequest(http.MethodGet, "/", nil)
+	)
+
+	req.Header.Set("Accept-Encoding", "gzip, br")
+
+	newCompressTestHandler(body).ServeHTTP(resp, req)
+
+	assert.Equal(t, "br", resp.Header().Get("Content-Encoding"))
+
+	out, err := ioutil.ReadAll(brotli.NewReader(resp.Body))
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(out))
+}
+
+func TestCompressMiddleware_DefaultMinBytes(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mw   = chttp.NewCompressMiddleware(chttp.Config{}, clogger.NewNoop())
+		body = `"` + strings.Repeat("a", 200) + `"`
+		resp = httptest.NewRecorder()
+		req  = httptest.NewRequest(http.MethodGet, "/", nil)
+	)
+
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	})).ServeHTTP(resp, req)
+
+	assert.Empty(t, resp.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, resp.Body.String())
+}
+
+func TestCompressMiddleware_Negotiate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"gzip, br":             "br",
+		"br;q=0.1, gzip":       "gzip",
+		"gzip;q=0.5, br;q=0.8": "br",
+		"br;q=0, gzip":         "gzip",
+		"*":                    "br",
+		"gzip;q=0.2, *;q=0.1":  "gzip",
+		"br;q=0, *":            "gzip",
+		"deflate":              "",
+		"gzip;q=0, br;q=0":     "",
+	}
+
+	for acceptEncoding, want := range testCases {
+		acceptEncoding, want := acceptEncoding, want
+
+		t.Run(acceptEncoding, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				resp = httptest.NewRecorder()
+				req  = httptest.NewRequest(http.MethodGet, "/", nil)
+			)
+
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+
+			newCompressTestHandler(`"`+strings.Repeat("a", 200)+`"`).ServeHTTP(resp, req)
+
+			assert.Equal(t, want, resp.Header().Get("Content-Encoding"))
+		})
+	}
+}
+
+func TestCompressMiddleware_WeakensETag(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mw   = chttp.NewCompressMiddleware(chttp.Config{CompressMinBytes: 100}, clogger.NewNoop())
+		resp = httptest.NewRecorder()
+		req  = httptest.NewRequest(http.MethodGet, "/", nil)
+	)
+
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"abc"`)
+		_, _ = w.Write([]byte(`"` + strings.Repeat("a", 200) + `"`))
+	})).ServeHTTP(resp, req)
+
+	assert.Equal(t, "gzip", resp.Header().Get("Content-Encoding"))
+	assert.Equal(t, `W/"abc"`, resp.Header().Get("ETag"))
+}
+
+func TestCompressMiddleware_BelowMinBytes(t *testing.T) {
+	t.Parallel()
+
+	var (
+		resp = httptest.NewRecorder()
+		req  = httptest.NewRequest(http.MethodGet, "/", nil)
+	)
+
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	newCompressTestHandler(`{"key":"val"}`).ServeHTTP(resp, req)
+
+	assert.Empty(t, resp.Header().Get("Content-Encoding"))
+	assert.Equal(t, `{"key":"val"}`, resp.Body.String())
+}
+
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+
+	hijacked         bool
+	wroteAfterHijack bool
+}
+
+func (r *hijackRecorder) WriteHeader(statusCode int) {
+	r.wroteAfterHijack = r.wroteAfterHijack || r.hijacked
+	r.ResponseRecorder.WriteHeader(statusCode)
+}
+
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+
+	return nil, nil, nil
+}
+
+func TestCompressMiddleware_Hijack(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mw   = chttp.NewCompressMiddleware(chttp.Config{}, clogger.NewNoop())
+		resp = &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+		req  = httptest.NewRequest(http.MethodGet, "/", nil)
+	)
+
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, err := w.(http.Hijacker).Hijack()
+		assert.NoError(t, err)
+	})).ServeHTTP(resp, req)
+
+	assert.True(t, resp.hijacked)
+	assert.False(t, resp.wroteAfterHijack)
+}
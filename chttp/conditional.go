@@ -0,0 +1,87 @@
+package chttp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// validators holds the options used to set the ETag and Last-Modified headers on a response and to respond to
+// conditional requests (If-None-Match and If-Modified-Since) with NotModified.
+type validators struct {
+	r            *http.Request
+	etag         bool
+	lastModified time.Time
+}
+
+// writeNotModified sets the validator headers for the response and, if the request's conditions show that the client
+// already has the response, writes a NotModified response and returns true. Only successful responses to GET and HEAD
+// requests are considered.
+func (v validators) writeNotModified(w http.ResponseWriter, statusCode int, body []byte) bool {
+	if !v.etag && v.lastModified.IsZero() {
+		return false
+	}
+
+	if statusCode != 0 && statusCode != http.StatusOK {
+		return false
+	}
+
+	var etag string
+
+	if v.etag {
+		sum := sha256.Sum256(body)
+		etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+
+		w.Header().Set("ETag", etag)
+	}
+
+	if !v.lastModified.IsZero() {
+		w.Header().Set("Last-Modified", v.lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if v.r == nil || (v.r.Method != http.MethodGet && v.r.Method != http.MethodHead) {
+		return false
+	}
+
+	if !v.isNotModified(etag) {
+		return false
+	}
+
+	// Content headers are not sent with NotModified responses.
+	w.Header().Del("Content-Type")
+	w.Header().Del("Content-Length")
+	w.WriteHeader(http.StatusNotModified)
+
+	return true
+}
+
+func (v validators) isNotModified(etag string) bool {
+	// If-None-Match takes precedence over If-Modified-Since (RFC 7232, section 6).
+	if inm := v.r.Header.Get("If-None-Match"); inm != "" {
+		if etag == "" {
+			return false
+		}
+
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if ims := v.r.Header.Get("If-Modified-Since"); ims != "" && !v.lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+
+		return !v.lastModified.Truncate(time.Second).After(t)
+	}
+
+	return false
+}
@@ -0,0 +1,83 @@
+package chttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReaderWriter_WriteJSON_ETag(t *testing.T) {
+	t.Parallel()
+
+	rw := chttptest.NewReaderWriter(t)
+
+	write := func(r *http.Request) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+
+		rw.WriteJSON(resp, chttp.WriteJSONParams{
+			Data:    map[string]string{"key": "value"},
+			Request: r,
+			ETag:    true,
+		})
+
+		return resp
+	}
+
+	resp := write(httptest.NewRequest(http.MethodGet, "/", nil))
+	etag := resp.Header().Get("ETag")
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotEmpty(t, etag)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", `"other", `+etag)
+
+	resp = write(req)
+
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+	assert.Empty(t, resp.Body.String())
+	assert.Equal(t, etag, resp.Header().Get("ETag"))
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", `"other"`)
+
+	assert.Equal(t, http.StatusOK, write(req).Code)
+}
+
+func TestReaderWriter_WriteJSON_LastModified(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rw           = chttptest.NewReaderWriter(t)
+		lastModified = time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+	)
+
+	testCases := []struct {
+		ifModifiedSince time.Time
+		wantCode        int
+	}{
+		{lastModified, http.StatusNotModified},
+		{lastModified.Add(-time.Hour), http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("If-Modified-Since", tc.ifModifiedSince.Format(http.TimeFormat))
+
+		resp := httptest.NewRecorder()
+
+		rw.WriteJSON(resp, chttp.WriteJSONParams{
+			Data:         map[string]string{"key": "value"},
+			Request:      req,
+			LastModified: lastModified,
+		})
+
+		assert.Equal(t, tc.wantCode, resp.Code)
+		assert.Equal(t, "Fri, 01 Jan 2021 10:00:00 GMT", resp.Header().Get("Last-Modified"))
+	}
+}
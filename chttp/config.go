@@ -1,6 +1,8 @@
 package chttp
 
 import (
+	"time"
+
 	"github.com/gocopper/copper/cconfig"
 	"github.com/gocopper/copper/cerrors"
 )
@@ -14,13 +16,221 @@ func LoadConfig(appConfig cconfig.Loader) (Config, error) {
 		return Config{}, cerrors.New(err, "failed to load chttp config", nil)
 	}
 
+	switch config.TrailingSlash {
+	case "", TrailingSlashRedirect, TrailingSlashRewrite:
+	default:
+		return Config{}, cerrors.New(nil, "invalid trailing slash option", map[string]interface{}{
+			"trailingSlash": config.TrailingSlash,
+		})
+	}
+
+	_, err = parseIPNets(config.TrustedProxies)
+	if err != nil {
+		return Config{}, cerrors.New(err, "invalid trusted proxies", nil)
+	}
+
+	_, err = newIPFilters(config.IPFilters)
+	if err != nil {
+		return Config{}, err
+	}
+
 	return config, nil
 }
 
 // Config holds the params needed to configure Server
 type Config struct {
-	Port                    uint `default:"7501"`
+	// Port is the port the server listens on. If it is set to 0, the OS picks an available port that can be found
+	// using Server.Addrs.
+	Port uint `default:"7501"`
+
+	// Listen overrides Port with a list of addresses the server should listen on. Each address can be a TCP address
+	// (ex. "127.0.0.1:8080"), a unix socket path with the "unix:" prefix (ex. "unix:/run/app.sock"), or "systemd" to
+	// use the sockets passed in by systemd socket activation.
+	Listen []string `toml:"listen"`
+
+	// UnixSocketMode sets the file permissions (in octal, ex. "0660") of the unix sockets created by the server.
+	UnixSocketMode string `toml:"unix_socket_mode"`
+
+	// DrainTimeout limits how long the server waits for in-flight requests to complete when it is shutting down.
+	// If it is not set, the server waits until the app lifecycle's stop timeout.
+	DrainTimeout time.Duration `toml:"drain_timeout"`
+
+	// ReadTimeout, ReadHeaderTimeout, WriteTimeout, and IdleTimeout configure the matching http.Server timeouts.
+	// ReadHeaderTimeout defaults to 10s so that slow clients cannot hold connections open indefinitely. The others
+	// default to no timeout since streaming responses (ex. server-sent events) can run for a long time.
+	ReadTimeout       time.Duration `toml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `toml:"read_header_timeout"`
+	WriteTimeout      time.Duration `toml:"write_timeout"`
+	IdleTimeout       time.Duration `toml:"idle_timeout"`
+
+	// MaxHeaderBytes limits the size of the request headers. If it is not set, http.DefaultMaxHeaderBytes is used.
+	MaxHeaderBytes int `toml:"max_header_bytes"`
+
+	// MaxBodyBytes limits the size of request bodies when the MaxBodySizeMiddleware is used. Routes can override it
+	// with Route.MaxBodyBytes. There is no limit if it is not set.
+	MaxBodyBytes int64 `toml:"max_body_bytes"`
+
+	// OpenAPI serves an OpenAPI document generated from the routes. It should be passed to NewHandlerParams.OpenAPI.
+	OpenAPI OpenAPIConfig `toml:"openapi"`
+
+	// Redirects are applied to requests before they are routed. They should be passed to NewHandlerParams.Redirects.
+	Redirects []RedirectRule `toml:"redirects"`
+
+	// MethodOverride lets POST requests target PUT, PATCH, and DELETE routes with a _method form field or the
+	// X-HTTP-Method-Override header. It should be passed to NewHandlerParams.MethodOverride.
+	MethodOverride bool `toml:"method_override"`
+
+	// TrailingSlash controls requests for a path that only has a route with (or without) a trailing slash. It can be
+	// set to "redirect" to redirect them to the route's path, or "rewrite" to serve them with the route. By default,
+	// they are not found. It should be passed to NewHandlerParams.TrailingSlash.
+	TrailingSlash string `toml:"trailing_slash"`
+
+	// CaseInsensitivePaths serves requests that do not match a route with the route for the lowercase path, if any.
+	// They are redirected instead when TrailingSlash is "redirect". It should be passed to
+	// NewHandlerParams.CaseInsensitivePaths.
+	CaseInsensitivePaths bool `toml:"case_insensitive_paths"`
+
+	// TrustedProxies lists the CIDRs (ex. 10.0.0.0/8) and IPs of the proxies in front of the app. ClientIP only reads
+	// the client's IP from forwarding headers (ex. X-Forwarded-For) when the request came through one of them. It
+	// should be passed to NewHandlerParams.TrustedProxies.
+	TrustedProxies []string `toml:"trusted_proxies"`
+
+	// IPFilters are named lists of networks that are allowed or denied access to groups of routes. They are applied
+	// to routes with IPFilter.Middleware. For example:
+	//
+	//	[chttp.ip_filters.admin]
+	//	allow = ["10.0.0.0/8", "192.0.2.10"]
+	IPFilters map[string]IPFilterConfig `toml:"ip_filters"`
+
+	// IdempotencyTTL is how long the IdempotencyMiddleware replays a response for retries with the same
+	// Idempotency-Key. It defaults to 24h.
+	IdempotencyTTL time.Duration `toml:"idempotency_ttl"`
+
+	// IdempotencyLockTTL is how long a key is reserved while its first request is in progress. Retries get a
+	// Conflict response until then, so it should be longer than the slowest request but short enough that keys are
+	// not stuck if the app crashes mid-request. It defaults to 1m.
+	IdempotencyLockTTL time.Duration `toml:"idempotency_lock_ttl"`
+
+	// IdempotencyMaxBodyBytes limits the size of the request bodies that the IdempotencyMiddleware reads to compare
+	// retries. Larger requests get a RequestEntityTooLarge response. It defaults to 1MiB.
+	IdempotencyMaxBodyBytes int64 `toml:"idempotency_max_body_bytes"`
+
+	// IdempotencyScopeCookies lists the cookies that identify the client (ex. the session cookie) in addition to the
+	// Authorization header, so that clients of cookie-based apps cannot replay each other's responses. It defaults
+	// to "session", the default csession cookie.
+	IdempotencyScopeCookies []string `toml:"idempotency_scope_cookies"`
+
+	// Maintenance configures the MaintenanceMiddleware.
+	Maintenance MaintenanceConfig `toml:"maintenance"`
+
+	// Proxies mounts reverse proxies using the ProxyRouter.
+	Proxies []ProxyConfig `toml:"proxies"`
+
+	// DeterministicHTML normalizes the HTML written by ReaderWriter.WriteHTML by sorting attributes and collapsing
+	// whitespace so that response hashes, caches, and golden tests are stable across template changes that do not
+	// change the page's content.
+	DeterministicHTML bool `toml:"deterministic_html"`
+
+	// CheckTemplateData warns about fields that templates access but the handler's data does not have (which render
+	// as empty values) and data that templates do not use. It is meant for development since it analyzes the
+	// templates on every render.
+	CheckTemplateData bool `toml:"check_template_data"`
+
+	// WarnHTMLResponseBytes and WarnJSONResponseBytes make the RequestLoggerMiddleware log a warning with the route
+	// when an HTML or JSON response is larger than the given number of bytes. There are no warnings if they are not
+	// set.
+	WarnHTMLResponseBytes int `toml:"warn_html_response_bytes"`
+	WarnJSONResponseBytes int `toml:"warn_json_response_bytes"`
+
+	// MaxPartialDepth limits how deeply partials can be nested when rendering HTML. It defaults to 32. A partial
+	// that includes itself, directly or through other partials, always fails to render.
+	MaxPartialDepth int `toml:"max_partial_depth"`
+
+	// SecureHeaders configures the headers set by the SecureHeadersMiddleware.
+	SecureHeaders SecureHeadersConfig `toml:"secure_headers"`
+
+	// BasePath is the path prefix that the app is mounted at (ex. /preview/pr-123). It should be passed to
+	// NewHandlerParams.BasePath.
+	BasePath string `toml:"base_path"`
+
+	// CompressMinBytes is the smallest response body, in bytes, that the CompressMiddleware compresses since
+	// compressing small bodies costs more than it saves. It defaults to 1024.
+	CompressMinBytes int `toml:"compress_min_bytes"`
+
 	UseLocalHTML            bool `toml:"use_local_html"`
 	RenderHTMLError         bool `toml:"render_html_error"`
 	EnableSinglePageRouting bool `toml:"enable_single_page_routing"`
+
+	// ErrorDebugToken lets trusted operators see the debug error page when render_html_error is disabled by sending
+	// the token in the X-Copper-Debug request header.
+	ErrorDebugToken string `toml:"error_debug_token"`
+
+	// ResponseSchemaValidation controls the ResponseSchemaMiddleware. It can be set to "log" to log responses that
+	// do not match their route's schema, or "fail" to also replace them with a 500 response. Validation is disabled
+	// if it is not set, which is recommended in production.
+	ResponseSchemaValidation string `toml:"response_schema_validation"`
+
+	// TLSCertFile and TLSKeyFile enable TLS (and HTTP/2) when both are set.
+	TLSCertFile string `toml:"tls_cert_file"`
+	TLSKeyFile  string `toml:"tls_key_file"`
+
+	// EnableH2C serves HTTP/2 without TLS. This is useful when the server is behind a proxy that terminates TLS and
+	// talks HTTP/2 to the app. It is ignored when TLS is enabled.
+	EnableH2C bool `toml:"enable_h2c"`
+
+	// AutoCertDomains enables automatic TLS certificates from Let's Encrypt for the given domains. Certificates are
+	// cached in AutoCertCacheDir and the HTTP-01 challenge is served on AutoCertHTTPPort, which also redirects all
+	// other requests to HTTPS.
+	AutoCertDomains  []string `toml:"autocert_domains"`
+	AutoCertCacheDir string   `toml:"autocert_cache_dir" default:"./certs"`
+	AutoCertEmail    string   `toml:"autocert_email"`
+	AutoCertHTTPPort uint     `toml:"autocert_http_port" default:"80"`
+}
+
+// ProxyConfig configures a reverse proxy mounted at a path prefix. For example:
+//
+//	[[chttp.proxies]]
+//	prefix = "/legacy"
+//	target = "http://legacy.internal:8080"
+//	strip_prefix = true
+//	timeout = "30s"
+//	set_headers = { "X-Forwarded-Service" = "legacy" }
+//	remove_headers = ["Cookie"]
+type ProxyConfig struct {
+	Prefix string `toml:"prefix"`
+	Target string `toml:"target"`
+
+	// StripPrefix removes Prefix from the request path before it is sent to the target.
+	StripPrefix bool `toml:"strip_prefix"`
+
+	// Timeout limits how long the target has to respond. There is no limit if it is not set.
+	Timeout time.Duration `toml:"timeout"`
+
+	// SetHeaders and RemoveHeaders rewrite the request headers before they are sent to the target.
+	SetHeaders    map[string]string `toml:"set_headers"`
+	RemoveHeaders []string          `toml:"remove_headers"`
+}
+
+// SecureHeadersConfig configures the SecureHeadersMiddleware. Headers that are not set use a default that is safe
+// for most apps. A header can be disabled by setting it to "-". For example:
+//
+//	[chttp.secure_headers]
+//	frame_options = "SAMEORIGIN"
+//	permissions_policy = "-"
+type SecureHeadersConfig struct {
+	// HSTSMaxAge is the max-age of the Strict-Transport-Security header, which is only sent over HTTPS. It
+	// defaults to 1 year. Set it to a negative value to disable the header.
+	HSTSMaxAge            time.Duration `toml:"hsts_max_age"`
+	HSTSIncludeSubdomains bool          `toml:"hsts_include_subdomains"`
+	HSTSPreload           bool          `toml:"hsts_preload"`
+
+	// FrameOptions is the X-Frame-Options header. It defaults to DENY.
+	FrameOptions string `toml:"frame_options"`
+
+	// ReferrerPolicy is the Referrer-Policy header. It defaults to strict-origin-when-cross-origin.
+	ReferrerPolicy string `toml:"referrer_policy"`
+
+	// PermissionsPolicy is the Permissions-Policy header. It defaults to disabling the camera, microphone, and
+	// geolocation.
+	PermissionsPolicy string `toml:"permissions_policy"`
 }
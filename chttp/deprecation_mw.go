@@ -0,0 +1,136 @@
+package chttp
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/clogger"
+)
+
+const defaultDeprecationLogInterval = time.Hour
+
+// Deprecation describes when a route was deprecated, when it will be removed, and what replaces it.
+// All fields are optional.
+type Deprecation struct {
+	// Since is when the route was deprecated. If it is not set, the route is marked as deprecated without a date.
+	Since time.Time
+
+	// Sunset is when the route is expected to stop responding.
+	Sunset time.Time
+
+	// Replacement is a link to the route or documentation that clients should migrate to.
+	Replacement string
+
+	// LogInterval is how often the use of the route is logged for each client. It defaults to 1h.
+	LogInterval time.Duration
+}
+
+// deprecationMiddleware sets the Deprecation (RFC 9745), Sunset (RFC 8594), and Link headers on every response of a
+// deprecated route, and logs its use by each client so that the remaining callers can be found before the route is
+// removed. Each client's use is logged at most once per Deprecation.LogInterval along with the number of requests it
+// made since it was last logged.
+func deprecationMiddleware(d Deprecation, path string, logger clogger.Logger) Middleware {
+	if d.LogInterval <= 0 {
+		d.LogInterval = defaultDeprecationLogInterval
+	}
+
+	usage := &deprecationUsage{
+		interval: d.LogInterval,
+		clock:    cclock.New(),
+		clients:  make(map[string]*deprecationClientUsage),
+	}
+
+	mw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if d.Since.IsZero() {
+				w.Header().Set("Deprecation", "true")
+			} else {
+				w.Header().Set("Deprecation", fmt.Sprintf("@%d", d.Since.Unix()))
+			}
+
+			if !d.Sunset.IsZero() {
+				w.Header().Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+			}
+
+			if d.Replacement != "" {
+				w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, d.Replacement))
+			}
+
+			client := deprecatedRouteClient(r)
+
+			if uses, ok := usage.record(client); ok {
+				logger.WithTags(map[string]interface{}{
+					"route":     path,
+					"method":    r.Method,
+					"client":    client,
+					"userAgent": r.UserAgent(),
+					"uses":      uses,
+				}).Warn("Deprecated route was used", nil)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	return HandleMiddleware(mw)
+}
+
+// deprecationUsage counts the requests that each client makes to a deprecated route.
+type deprecationUsage struct {
+	interval time.Duration
+	clock    cclock.Clock
+
+	mu      sync.Mutex
+	clients map[string]*deprecationClientUsage
+}
+
+type deprecationClientUsage struct {
+	loggedAt time.Time
+	uses     int
+}
+
+// record counts a request from the client. It returns true with the number of requests made by the client since
+// it was last logged if the request should be logged.
+func (u *deprecationUsage) record(client string) (int, bool) {
+	now := u.clock.Now()
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	c, ok := u.clients[client]
+	if !ok {
+		c = &deprecationClientUsage{}
+		u.clients[client] = c
+	}
+
+	c.uses++
+
+	if ok && now.Sub(c.loggedAt) < u.interval {
+		return 0, false
+	}
+
+	uses := c.uses
+	c.loggedAt = now
+	c.uses = 0
+
+	// Clients that have not used the route since they were last logged are removed so that the map does not grow
+	// with every client that ever used the route
+	for k, other := range u.clients {
+		if other.uses == 0 && now.Sub(other.loggedAt) >= u.interval {
+			delete(u.clients, k)
+		}
+	}
+
+	return uses, true
+}
+
+func deprecatedRouteClient(r *http.Request) string {
+	if user, _, ok := r.BasicAuth(); ok {
+		return user
+	}
+
+	return ClientIP(r)
+}
@@ -0,0 +1,65 @@
+package chttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecation(t *testing.T) {
+	t.Parallel()
+
+	logs := make([]clogger.RecordedLog, 0)
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{chttptest.NewRouter([]chttp.Route{
+			{
+				Path:    "/v1/posts",
+				Methods: []string{http.MethodGet},
+				Handler: func(w http.ResponseWriter, r *http.Request) {},
+				Deprecation: &chttp.Deprecation{
+					Since:       time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+					Sunset:      time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+					Replacement: "/v2/posts",
+				},
+			},
+		})},
+		Logger: clogger.NewRecorder(&logs),
+	}))
+	defer server.Close()
+
+	get := func(client string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/v1/posts", nil) //nolint:noctx
+		assert.NoError(t, err)
+
+		req.SetBasicAuth(client, "")
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+
+		return resp
+	}
+
+	resp := get("client-a")
+
+	assert.Equal(t, "@1609459200", resp.Header.Get("Deprecation"))
+	assert.Equal(t, "Tue, 01 Jun 2021 00:00:00 GMT", resp.Header.Get("Sunset"))
+	assert.Equal(t, `</v2/posts>; rel="successor-version"`, resp.Header.Get("Link"))
+
+	assert.Equal(t, 1, len(logs))
+	assert.Equal(t, "client-a", logs[0].Tags["client"])
+	assert.Equal(t, 1, logs[0].Tags["uses"])
+
+	get("client-a")
+	get("client-b")
+
+	assert.Equal(t, 2, len(logs))
+	assert.Equal(t, "client-b", logs[1].Tags["client"])
+}
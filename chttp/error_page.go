@@ -0,0 +1,180 @@
+package chttp
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httputil"
+	"path"
+	"regexp"
+	"runtime/debug"
+	"sort"
+	"strconv"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+// ErrorDebugHeader can be set on a request to the value of Config.ErrorDebugToken to see the debug error page in
+// environments where render_html_error is disabled.
+const ErrorDebugHeader = "X-Copper-Debug"
+
+//nolint:gochecknoglobals
+var (
+	errorHTMLTmpl = template.Must(template.New("chttp/error.html").Parse(errorHTML))
+
+	// Matches template errors such as "template: index.html:12:5: executing ..."
+	templateErrLocationRe = regexp.MustCompile(`template: ([^:\s]+):(\d+)`)
+)
+
+type (
+	errorPageData struct {
+		Error    string
+		Chain    []errorPageLayer
+		Request  string
+		Stack    string
+		Template *errorPageTemplate
+	}
+
+	errorPageLayer struct {
+		Message string
+		Tags    []string
+	}
+
+	errorPageTemplate struct {
+		Name  string
+		Lines []errorPageTemplateLine
+	}
+
+	errorPageTemplateLine struct {
+		Number  int
+		Text    string
+		Current bool
+	}
+)
+
+// canRenderErrorDebug returns true if the debug error page can be shown for the request. It is always shown if
+// render_html_error is enabled. Otherwise, trusted operators can see it by setting the ErrorDebugHeader to the
+// configured error_debug_token.
+func (rw *ReaderWriter) canRenderErrorDebug(r *http.Request) bool {
+	if rw.config.RenderHTMLError {
+		return true
+	}
+
+	token := rw.config.ErrorDebugToken
+	if token == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get(ErrorDebugHeader)), []byte(token)) == 1
+}
+
+func (rw *ReaderWriter) writeErrorDebugPage(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	w.Header().Set("content-type", "text/html")
+	w.WriteHeader(statusCode)
+
+	_ = errorHTMLTmpl.Execute(w, errorPageData{
+		Error:    err.Error(),
+		Chain:    errorChain(err),
+		Request:  dumpRequest(r),
+		Stack:    string(debug.Stack()),
+		Template: rw.templateExcerpt(err),
+	})
+}
+
+// errorChain unwraps the error and returns the message and cerrors tags for each error in the chain.
+func errorChain(err error) []errorPageLayer {
+	chain := make([]errorPageLayer, 0)
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		var (
+			layer    = errorPageLayer{Message: err.Error()}
+			cerr, ok = err.(cerrors.Error) //nolint:errorlint
+		)
+
+		if ok {
+			layer.Message = cerr.Message
+
+			for tag, val := range cerr.Tags {
+				layer.Tags = append(layer.Tags, fmt.Sprintf("%s=%+v", tag, val))
+			}
+
+			sort.Strings(layer.Tags)
+		}
+
+		// Some errors (ex. template.ExecError) wrap an error with the same message, which is not useful to repeat.
+		if len(chain) > 0 && len(layer.Tags) == 0 && chain[len(chain)-1].Message == layer.Message {
+			continue
+		}
+
+		chain = append(chain, layer)
+	}
+
+	return chain
+}
+
+// dumpRequest returns the request line and headers. Headers that may hold credentials are redacted.
+func dumpRequest(r *http.Request) string {
+	clone := r.Clone(r.Context())
+
+	for _, h := range []string{"Authorization", "Cookie", "Proxy-Authorization", ErrorDebugHeader} {
+		if clone.Header.Get(h) != "" {
+			clone.Header.Set(h, "[redacted]")
+		}
+	}
+
+	out, err := httputil.DumpRequest(clone, false)
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(out)
+}
+
+// templateExcerpt finds the template file and line number mentioned in a template error and returns the lines
+// around it.
+func (rw *ReaderWriter) templateExcerpt(err error) *errorPageTemplate {
+	const context = 3
+
+	match := templateErrLocationRe.FindStringSubmatch(err.Error())
+	if match == nil || rw.html == nil || rw.html.htmlDir == nil {
+		return nil
+	}
+
+	name := match[1]
+
+	lineNum, convErr := strconv.Atoi(match[2])
+	if convErr != nil {
+		return nil
+	}
+
+	for _, dir := range []string{"pages", "layouts", "partials"} {
+		f, openErr := rw.html.htmlDir.Open(path.Join("src", dir, name))
+		if openErr != nil {
+			continue
+		}
+
+		excerpt := errorPageTemplate{Name: path.Join(dir, name)}
+		scanner := bufio.NewScanner(f)
+
+		for n := 1; scanner.Scan(); n++ {
+			if n < lineNum-context || n > lineNum+context {
+				continue
+			}
+
+			excerpt.Lines = append(excerpt.Lines, errorPageTemplateLine{
+				Number:  n,
+				Text:    scanner.Text(),
+				Current: n == lineNum,
+			})
+		}
+
+		_ = f.Close()
+
+		return &excerpt
+	}
+
+	return nil
+}
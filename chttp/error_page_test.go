@@ -0,0 +1,67 @@
+package chttp_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReaderWriter_WriteHTMLError_Debug(t *testing.T) {
+	t.Parallel()
+
+	r, err := chttp.NewHTMLRenderer(chttp.NewHTMLRendererParams{
+		HTMLDir: chttptest.HTMLDir,
+		Logger:  clogger.NewNoop(),
+	})
+	assert.NoError(t, err)
+
+	var (
+		rw   = chttp.NewReaderWriter(r, chttp.Config{ErrorDebugToken: "secret"}, clogger.NewNoop())
+		resp = httptest.NewRecorder()
+		req  = httptest.NewRequest(http.MethodGet, "/posts", nil)
+	)
+
+	req.Header.Set(chttp.ErrorDebugHeader, "secret")
+	req.Header.Set("Cookie", "session=abc")
+
+	rw.WriteHTMLError(resp, req, cerrors.New(errors.New("db is down"), "failed to get posts", map[string]interface{}{
+		"userID": 1,
+	}))
+
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Contains(t, resp.Body.String(), "failed to get posts")
+	assert.Contains(t, resp.Body.String(), "userID=1")
+	assert.Contains(t, resp.Body.String(), "GET /posts")
+	assert.Contains(t, resp.Body.String(), "Cookie: [redacted]")
+	assert.NotContains(t, resp.Body.String(), "session=abc")
+}
+
+func TestReaderWriter_WriteHTMLError_NoDebug(t *testing.T) {
+	t.Parallel()
+
+	r, err := chttp.NewHTMLRenderer(chttp.NewHTMLRendererParams{
+		HTMLDir: chttptest.HTMLDir,
+		Logger:  clogger.NewNoop(),
+	})
+	assert.NoError(t, err)
+
+	var (
+		rw   = chttp.NewReaderWriter(r, chttp.Config{ErrorDebugToken: "secret"}, clogger.NewNoop())
+		resp = httptest.NewRecorder()
+		req  = httptest.NewRequest(http.MethodGet, "/posts", nil)
+	)
+
+	req.Header.Set(chttp.ErrorDebugHeader, "wrong")
+
+	rw.WriteHTMLError(resp, req, errors.New("db is down"))
+
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.NotContains(t, resp.Body.String(), "db is down")
+}
@@ -0,0 +1,101 @@
+package chttp
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+var errFileNotSeekable = errors.New("file does not implement io.Seeker")
+
+// WriteFileParams holds the params for the WriteFile function in ReaderWriter.
+type WriteFileParams struct {
+	// Name is used to detect the content type (if the Content-Type header is not already set) and as the filename
+	// in the Content-Disposition header.
+	Name string
+
+	// Content is the file's content. It is read as needed, so large files are streamed instead of being loaded
+	// into memory.
+	Content io.ReadSeeker
+
+	// ModTime is sent in the Last-Modified header and used for If-Modified-Since and If-Range requests.
+	ModTime time.Time
+
+	// Attachment prompts the browser to download the file instead of displaying it.
+	Attachment bool
+}
+
+// WriteFile writes the file described by WriteFileParams using http.ServeContent. This supports Range requests so
+// that large downloads (ex. videos, exports) can be resumed or streamed, as well as conditional requests.
+func (rw *ReaderWriter) WriteFile(w http.ResponseWriter, r *http.Request, p WriteFileParams) {
+	disposition := "inline"
+	if p.Attachment {
+		disposition = "attachment"
+	}
+
+	if p.Name != "" {
+		disposition = mime.FormatMediaType(disposition, map[string]string{
+			"filename": path.Base(p.Name),
+		})
+	}
+
+	w.Header().Set("Content-Disposition", disposition)
+
+	http.ServeContent(w, r, p.Name, p.ModTime, p.Content)
+}
+
+// WriteFSFile writes the named file from the given fs.FS using WriteFile. If the file does not exist, a NotFound
+// response is sent back.
+func (rw *ReaderWriter) WriteFSFile(w http.ResponseWriter, r *http.Request, fsys fs.FS, name string, attachment bool) {
+	f, err := fsys.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		rw.logger.Error("Failed to open file", cerrors.New(err, "failed to open file", map[string]interface{}{
+			"name": name,
+		}))
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	defer func() { _ = f.Close() }()
+
+	stat, err := f.Stat()
+	if err != nil {
+		rw.logger.Error("Failed to stat file", cerrors.New(err, "failed to stat file", map[string]interface{}{
+			"name": name,
+		}))
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	content, ok := f.(io.ReadSeeker)
+	if !ok || stat.IsDir() {
+		err = cerrors.New(errFileNotSeekable, "cannot serve file", map[string]interface{}{
+			"name": name,
+		})
+
+		rw.logger.Error("Failed to write file", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	rw.WriteFile(w, r, WriteFileParams{
+		Name:       name,
+		Content:    content,
+		ModTime:    stat.ModTime(),
+		Attachment: attachment,
+	})
+}
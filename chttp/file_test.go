@@ -0,0 +1,45 @@
+package chttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReaderWriter_WriteFSFile(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rw   = chttptest.NewReaderWriter(t)
+		fsys = fstest.MapFS{
+			"exports/report.csv": &fstest.MapFile{
+				Data:    []byte("id,name\n1,a\n2,b\n"),
+				ModTime: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		}
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Range", "bytes=8-11")
+
+	resp := httptest.NewRecorder()
+
+	rw.WriteFSFile(resp, req, fsys, "exports/report.csv", true)
+
+	assert.Equal(t, http.StatusPartialContent, resp.Code)
+	assert.Equal(t, "1,a\n", resp.Body.String())
+	assert.Equal(t, "bytes 8-11/16", resp.Header().Get("Content-Range"))
+	assert.Equal(t, "attachment; filename=report.csv", resp.Header().Get("Content-Disposition"))
+	assert.Equal(t, "Fri, 01 Jan 2021 00:00:00 GMT", resp.Header().Get("Last-Modified"))
+
+	resp = httptest.NewRecorder()
+
+	rw.WriteFSFile(resp, httptest.NewRequest(http.MethodGet, "/", nil), fsys, "missing.csv", false)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -12,10 +12,33 @@ import (
 )
 
 // NewHandlerParams holds the params needed for NewHandler.
+// RW and ErrorReporter are optional. If RW is set, it is used to render an error page (or a JSON error) when a
+// handler panics. If ErrorReporter is set, panics are reported to it.
+// BasePath mounts the app under a path prefix (ex. /preview/pr-123), usually from Config.BasePath. Routes are
+// declared without it, and generated URLs, redirects, and asset paths include it.
+// Redirects are applied before the request is routed, usually from Config.Redirects. Apps that keep redirects in a
+// database can load them at startup and append them here.
+// OpenAPI serves an OpenAPI document for the routes with RouteAPI metadata, usually from Config.OpenAPI.
+// MethodOverride lets HTML forms target PUT, PATCH, and DELETE routes with a _method field (see methodField in
+// templates) or the X-HTTP-Method-Override header, usually from Config.MethodOverride.
+// TrailingSlash and CaseInsensitivePaths control how requests that do not match a route because of a trailing slash
+// or the path's case are handled, usually from Config.TrailingSlash and Config.CaseInsensitivePaths.
+// TrustedProxies lists the CIDRs and IPs of the proxies that are trusted to report the client's IP address (see
+// ClientIP), usually from Config.TrustedProxies.
 type NewHandlerParams struct {
-	Routers           []Router
-	GlobalMiddlewares []Middleware
-	Logger            clogger.Logger
+	BasePath             string
+	TrustedProxies       []string
+	Redirects            []RedirectRule
+	MethodOverride       bool
+	TrailingSlash        string
+	CaseInsensitivePaths bool
+	OpenAPI              OpenAPIConfig
+	Routers              []Router
+	GlobalMiddlewares    []Middleware
+	Authorizer           Authorizer
+	RW                   *ReaderWriter
+	ErrorReporter        ErrorReporter
+	Logger               clogger.Logger
 }
 
 // NewHandler creates a http.Handler with the given routes and middlewares.
@@ -31,11 +54,34 @@ func NewHandler(p NewHandlerParams) http.Handler {
 		routes = append(routes, router.Routes()...)
 	}
 
+	basePath := strings.TrimSuffix("/"+strings.Trim(p.BasePath, "/"), "/")
+
+	if p.OpenAPI.Enabled {
+		routes = append(routes, openAPIRoutes(p.OpenAPI, basePath, routes)...)
+	}
+
+	table := newRouteTable(routes, basePath)
+	routes = expandLocalizedRoutes(routes)
+	table.infos = newRouteInfos(routes)
+
 	sortRoutes(routes)
 
 	for _, route := range routes {
 		handler := http.Handler(route.Handler)
 
+		if len(route.RequestHooks) > 0 || len(route.ResponseHooks) > 0 {
+			handler = setRouteHooksInCtxMiddleware(routeHooks{
+				request:  route.RequestHooks,
+				response: route.ResponseHooks,
+			}).Handle(handler)
+		}
+
+		// Authorization runs after all the middlewares so that they can populate the request (ex. with the current
+		// user) before the route's requirements are checked.
+		if len(route.Requires) > 0 {
+			handler = authorizeMiddleware(p.Authorizer, route.Requires, p.Logger).Handle(handler)
+		}
+
 		// Register route-level handlers
 		// Since we are wrapping the handler in middleware functions, the outermost one will run first.
 		// By applying the middlewares in reverse, we ensure that the first middleware in the list is the outermost one.
@@ -48,29 +94,91 @@ func NewHandler(p NewHandlerParams) http.Handler {
 			handler = p.GlobalMiddlewares[i].Handle(handler)
 		}
 
+		// The route's body limit is applied before the global middlewares so that it takes precedence over the
+		// limit set by a global MaxBodySizeMiddleware.
+		if route.MaxBodyBytes > 0 {
+			handler = maxBodySizeMiddleware(route.MaxBodyBytes).Handle(handler)
+		}
+
+		if route.Deprecation != nil {
+			handler = deprecationMiddleware(*route.Deprecation, route.Path, p.Logger).Handle(handler)
+		}
+
+		if route.locale != "" {
+			handler = setLocaleInCtxMiddleware(route.locale).Handle(handler)
+		}
+
+		if p.Logger != nil {
+			handler = setLoggerInCtxMiddleware(p.Logger, route.Path).Handle(handler)
+		}
+
 		handler = setRoutePathInCtxMiddleware(route.Path).Handle(handler)
-		handler = panicLoggerMiddleware(p.Logger).Handle(handler)
+		handler = setRouteTableInCtxMiddleware(table).Handle(handler)
+		handler = panicLoggerMiddleware(p.Logger, p.RW, p.ErrorReporter).Handle(handler)
 
-		muxRoute := muxRouter.Handle(route.Path, handler)
+		muxRoute := muxRouter.Handle(expandRouteParamTypes(route.Path), handler)
 
 		if len(route.Methods) > 0 {
 			muxRoute.Methods(route.Methods...)
 		}
 	}
 
-	muxHandler.Handle("/", muxRouter)
+	router := http.Handler(muxRouter)
+
+	if p.TrailingSlash != "" || p.CaseInsensitivePaths {
+		router = pathMatchMiddleware(muxRouter, p.TrailingSlash, p.CaseInsensitivePaths, table).Handle(router)
+	}
+
+	if len(p.Redirects) > 0 {
+		router = redirectMiddleware(p.Redirects, table, p.Logger).Handle(router)
+	}
+
+	if p.MethodOverride {
+		router = methodOverrideMiddleware().Handle(router)
+	}
+
+	if len(p.TrustedProxies) > 0 {
+		trusted, err := parseIPNets(p.TrustedProxies)
+		if err != nil {
+			p.Logger.Warn("Failed to parse trusted proxies; client ips will not be read from headers", err)
+		}
+
+		router = clientIPMiddleware(trusted).Handle(router)
+	}
+
+	if basePath == "" {
+		muxHandler.Handle("/", router)
+	} else {
+		muxHandler.Handle(basePath+"/", http.StripPrefix(basePath, router))
+	}
 
 	return muxHandler
 }
 
 func sortRoutes(routes []Route) {
-	const matcherPlaceholder = "{{matcher}}"
-
-	re := regexp.MustCompile(`(?U)(\{.*\})`)
+	// Vars are replaced with placeholders for their rank before the paths are split since their expressions may
+	// have slashes.
+	var (
+		re           = regexp.MustCompile(`(?U)(\{.*\})`)
+		placeholders = map[int]string{
+			rankConstrained:   "{{constrained}}",
+			rankUnconstrained: "{{matcher}}",
+			rankCatchAll:      "{{catchall}}",
+		}
+		ranks = map[string]int{
+			placeholders[rankConstrained]:   rankConstrained,
+			placeholders[rankUnconstrained]: rankUnconstrained,
+			placeholders[rankCatchAll]:      rankCatchAll,
+		}
+	)
 
 	sort.Slice(routes, func(i, j int) bool {
-		aPath := re.ReplaceAllString(routes[i].Path, matcherPlaceholder)
-		bPath := re.ReplaceAllString(routes[j].Path, matcherPlaceholder)
+		aPath := re.ReplaceAllStringFunc(routes[i].Path, func(v string) string {
+			return placeholders[routeParamRank(v)]
+		})
+		bPath := re.ReplaceAllStringFunc(routes[j].Path, func(v string) string {
+			return placeholders[routeParamRank(v)]
+		})
 
 		aParts := strings.Split(aPath, "/")
 		bParts := strings.Split(bPath, "/")
@@ -88,14 +196,10 @@ func sortRoutes(routes []Route) {
 		}
 
 		for i, aPart := range aParts {
-			bPart := bParts[i]
-
-			if aPart == matcherPlaceholder {
-				return false
-			}
+			aRank, bRank := ranks[aPart], ranks[bParts[i]]
 
-			if bPart == matcherPlaceholder {
-				return true
+			if aRank != rankStatic || bRank != rankStatic {
+				return aRank < bRank
 			}
 		}
 
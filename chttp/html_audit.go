@@ -0,0 +1,288 @@
+package chttp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gocopper/copper/cerrors"
+	"golang.org/x/net/html"
+)
+
+// Kinds of issues found by AuditHTML.
+const (
+	AuditIssueBrokenLink  = "broken-link"
+	AuditIssueMissingAlt  = "missing-alt"
+	AuditIssueDuplicateID = "duplicate-id"
+)
+
+// ErrHTMLAuditFailed is returned by HTMLAuditCommand.Run when issues are found in the audited pages.
+var ErrHTMLAuditFailed = errors.New("html audit found issues")
+
+type (
+	// AuditHTMLParams holds the params needed for AuditHTML.
+	AuditHTMLParams struct {
+		// Handler serves the pages that are audited. It is usually the handler created by NewHandler.
+		Handler http.Handler
+
+		// Routers provide the routes that are audited. Only GET routes are audited.
+		Routers []Router
+
+		// PathParams holds sample values for route path variables (ex. "id" => "1" for /posts/{id}). Routes with
+		// variables that do not have a sample value are skipped.
+		PathParams map[string]string
+	}
+
+	// AuditIssue is a single problem found in a page.
+	AuditIssue struct {
+		Page   string
+		Kind   string
+		Detail string
+	}
+
+	// AuditReport holds the pages that were audited and the issues found in them.
+	AuditReport struct {
+		Pages   []string
+		Skipped []string
+		Issues  []AuditIssue
+	}
+
+	// NewHTMLAuditCommandParams holds the params needed for NewHTMLAuditCommand. Out defaults to os.Stdout.
+	NewHTMLAuditCommandParams struct {
+		Audit AuditHTMLParams
+		Out   io.Writer
+	}
+
+	// HTMLAuditCommand is a dev command that audits the app's pages with AuditHTML and prints the report. It can be
+	// run by a copper.App from a separate main package (ex. cmd/audit):
+	//
+	//	app.Run(chttp.NewHTMLAuditCommand(chttp.NewHTMLAuditCommandParams{Audit: ...}))
+	HTMLAuditCommand struct {
+		audit AuditHTMLParams
+		out   io.Writer
+	}
+)
+
+// NewHTMLAuditCommand creates a new HTMLAuditCommand.
+func NewHTMLAuditCommand(p NewHTMLAuditCommandParams) *HTMLAuditCommand {
+	if p.Out == nil {
+		p.Out = os.Stdout
+	}
+
+	return &HTMLAuditCommand{
+		audit: p.Audit,
+		out:   p.Out,
+	}
+}
+
+// Run audits the pages and prints the report. It returns ErrHTMLAuditFailed if any issues are found so that the
+// command exits with an error (ex. in CI).
+func (c *HTMLAuditCommand) Run() error {
+	report := AuditHTML(c.audit)
+
+	_, err := io.WriteString(c.out, report.String())
+	if err != nil {
+		return cerrors.New(err, "failed to write html audit report", nil)
+	}
+
+	if len(report.Issues) > 0 {
+		return cerrors.New(ErrHTMLAuditFailed, "html audit failed", map[string]interface{}{
+			"issues": len(report.Issues),
+		})
+	}
+
+	return nil
+}
+
+// AuditHTML renders every GET route that returns HTML and checks it for broken internal links, images without alt
+// attributes, and duplicate element ids (which break DOM diffing). The pages are rendered in-process with the given
+// handler, so no server needs to be running. It can be run as part of a test or with HTMLAuditCommand.
+func AuditHTML(p AuditHTMLParams) AuditReport {
+	var (
+		report     AuditReport
+		linkStatus = make(map[string]int)
+	)
+
+	for _, router := range p.Routers {
+		for _, route := range router.Routes() {
+			if !routeAllowsGet(route) || strings.HasPrefix(route.Path, "/static/") {
+				continue
+			}
+
+			missingParam := false
+			page := routeVarRe.ReplaceAllStringFunc(route.Path, func(v string) string {
+				name := routeVarRe.FindStringSubmatch(v)[1]
+
+				val, ok := p.PathParams[name]
+				if !ok {
+					missingParam = true
+				}
+
+				return val
+			})
+
+			if missingParam {
+				report.Skipped = append(report.Skipped, route.Path)
+				continue
+			}
+
+			resp := auditGet(p.Handler, page)
+			if !strings.HasPrefix(resp.Header().Get("Content-Type"), "text/html") {
+				continue
+			}
+
+			report.Pages = append(report.Pages, page)
+
+			doc, err := html.Parse(resp.Body)
+			if err != nil {
+				continue
+			}
+
+			report.Issues = append(report.Issues, auditDocument(page, doc, func(link string) int {
+				status, ok := linkStatus[link]
+				if !ok {
+					status = auditGet(p.Handler, link).Code
+					linkStatus[link] = status
+				}
+
+				return status
+			})...)
+		}
+	}
+
+	return report
+}
+
+// String formats the report so that it can be printed by HTMLAuditCommand.
+func (r AuditReport) String() string {
+	var out strings.Builder
+
+	_, _ = fmt.Fprintf(&out, "Audited %d pages, skipped %d, found %d issues\n", len(r.Pages), len(r.Skipped),
+		len(r.Issues))
+
+	for _, issue := range r.Issues {
+		_, _ = fmt.Fprintf(&out, "%s: [%s] %s\n", issue.Page, issue.Kind, issue.Detail)
+	}
+
+	return out.String()
+}
+
+func auditDocument(page string, doc *html.Node, linkStatus func(link string) int) []AuditIssue {
+	var (
+		issues = make([]AuditIssue, 0)
+		ids    = make(map[string]int)
+		walk   func(n *html.Node)
+	)
+
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			attrs := make(map[string]string, len(n.Attr))
+			for _, a := range n.Attr {
+				attrs[a.Key] = a.Val
+			}
+
+			if id, ok := attrs["id"]; ok && id != "" {
+				ids[id]++
+			}
+
+			if _, ok := attrs["alt"]; n.Data == "img" && !ok {
+				issues = append(issues, AuditIssue{
+					Page:   page,
+					Kind:   AuditIssueMissingAlt,
+					Detail: fmt.Sprintf("<img src=%q> has no alt attribute", attrs["src"]),
+				})
+			}
+
+			if href, ok := attrs["href"]; n.Data == "a" && ok && isInternalLink(href) {
+				link := resolveLink(page, href)
+
+				if status := linkStatus(link); status >= http.StatusBadRequest {
+					issues = append(issues, AuditIssue{
+						Page:   page,
+						Kind:   AuditIssueBrokenLink,
+						Detail: fmt.Sprintf("%s returned %d", link, status),
+					})
+				}
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+
+	walk(doc)
+
+	dupIDs := make([]string, 0)
+
+	for id, count := range ids {
+		if count > 1 {
+			dupIDs = append(dupIDs, id)
+		}
+	}
+
+	sort.Strings(dupIDs)
+
+	for _, id := range dupIDs {
+		issues = append(issues, AuditIssue{
+			Page:   page,
+			Kind:   AuditIssueDuplicateID,
+			Detail: fmt.Sprintf("id %q is used by %d elements", id, ids[id]),
+		})
+	}
+
+	return issues
+}
+
+func auditGet(handler http.Handler, target string) *httptest.ResponseRecorder {
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, target, nil))
+
+	return resp
+}
+
+func routeAllowsGet(route Route) bool {
+	if len(route.Methods) == 0 {
+		return true
+	}
+
+	for _, m := range route.Methods {
+		if m == http.MethodGet {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isInternalLink(href string) bool {
+	u, err := url.Parse(href)
+	if err != nil {
+		return false
+	}
+
+	return u.Scheme == "" && u.Host == "" && u.Path != ""
+}
+
+func resolveLink(page, href string) string {
+	base, err := url.Parse(page)
+	if err != nil {
+		return href
+	}
+
+	ref, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+
+	resolved := base.ResolveReference(ref)
+	resolved.Fragment = ""
+
+	return resolved.String()
+}
@@ -0,0 +1,93 @@
+package chttp_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditHTML(t *testing.T) {
+	t.Parallel()
+
+	writeHTML := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(body))
+		}
+	}
+
+	router := chttptest.NewRouter([]chttp.Route{
+		{
+			Path:    "/",
+			Methods: []string{http.MethodGet},
+			Handler: writeHTML(`<a href="/posts/1">Post</a><a href="/missing">Missing</a><img src="/logo.png">`),
+		},
+		{
+			Path:    "/posts/{id:[0-9]+}",
+			Methods: []string{http.MethodGet},
+			Handler: writeHTML(`<div id="post"></div><div id="post"></div><img src="/a.png" alt="">`),
+		},
+		{
+			Path:    "/users/{username}",
+			Methods: []string{http.MethodGet},
+			Handler: writeHTML(``),
+		},
+	})
+
+	report := chttp.AuditHTML(chttp.AuditHTMLParams{
+		Handler: chttp.NewHandler(chttp.NewHandlerParams{
+			Routers: []chttp.Router{router},
+			Logger:  clogger.NewNoop(),
+		}),
+		Routers:    []chttp.Router{router},
+		PathParams: map[string]string{"id": "1"},
+	})
+
+	assert.ElementsMatch(t, []string{"/", "/posts/1"}, report.Pages)
+	assert.Equal(t, []string{"/users/{username}"}, report.Skipped)
+	assert.ElementsMatch(t, []chttp.AuditIssue{
+		{Page: "/", Kind: chttp.AuditIssueBrokenLink, Detail: "/missing returned 404"},
+		{Page: "/", Kind: chttp.AuditIssueMissingAlt, Detail: `<img src="/logo.png"> has no alt attribute`},
+		{Page: "/posts/1", Kind: chttp.AuditIssueDuplicateID, Detail: `id "post" is used by 2 elements`},
+	}, report.Issues)
+}
+
+func TestHTMLAuditCommand(t *testing.T) {
+	t.Parallel()
+
+	var (
+		out    bytes.Buffer
+		router = chttptest.NewRouter([]chttp.Route{
+			{
+				Path:    "/",
+				Methods: []string{http.MethodGet},
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "text/html")
+					_, _ = w.Write([]byte(`<img src="/logo.png">`))
+				},
+			},
+		})
+	)
+
+	cmd := chttp.NewHTMLAuditCommand(chttp.NewHTMLAuditCommandParams{
+		Audit: chttp.AuditHTMLParams{
+			Handler: chttp.NewHandler(chttp.NewHandlerParams{
+				Routers: []chttp.Router{router},
+				Logger:  clogger.NewNoop(),
+			}),
+			Routers: []chttp.Router{router},
+		},
+		Out: &out,
+	})
+
+	err := cmd.Run()
+	assert.True(t, errors.Is(err, chttp.ErrHTMLAuditFailed))
+	assert.Equal(t, "Audited 1 pages, skipped 0, found 1 issues\n"+
+		"/: [missing-alt] <img src=\"/logo.png\"> has no alt attribute\n", out.String())
+}
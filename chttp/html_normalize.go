@@ -0,0 +1,79 @@
+package chttp
+
+import (
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+//nolint:gochecknoglobals
+var whitespaceRe = regexp.MustCompile(`\s+`)
+
+// normalizeHTML rewrites the given HTML so that equivalent templates always produce the same output. Attributes are
+// sorted by name and runs of whitespace in text are collapsed into a single space. The content of elements where
+// whitespace matters (pre, textarea, script, and style) is left as-is. Unlike html.Parse, the tokenizer does not add
+// missing elements (ex. <html> or <body>), so partials are normalized without being turned into documents.
+func normalizeHTML(in string) (string, error) {
+	var (
+		out     strings.Builder
+		z       = html.NewTokenizer(strings.NewReader(in))
+		rawTags = 0
+	)
+
+	for {
+		tt := z.Next()
+
+		switch tt {
+		case html.ErrorToken:
+			if z.Err() == io.EOF {
+				return out.String(), nil
+			}
+
+			return "", z.Err()
+		case html.TextToken:
+			if rawTags > 0 {
+				out.Write(z.Raw())
+				continue
+			}
+
+			tok := z.Token()
+			tok.Data = whitespaceRe.ReplaceAllString(tok.Data, " ")
+
+			out.WriteString(tok.String())
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+
+			sort.SliceStable(tok.Attr, func(i, j int) bool {
+				return tok.Attr[i].Key < tok.Attr[j].Key
+			})
+
+			if tt == html.StartTagToken && isWhitespaceSensitiveTag(tok.Data) {
+				rawTags++
+			}
+
+			out.WriteString(tok.String())
+		case html.EndTagToken:
+			tok := z.Token()
+
+			if isWhitespaceSensitiveTag(tok.Data) && rawTags > 0 {
+				rawTags--
+			}
+
+			out.WriteString(tok.String())
+		case html.CommentToken, html.DoctypeToken:
+			out.Write(z.Raw())
+		}
+	}
+}
+
+func isWhitespaceSensitiveTag(tag string) bool {
+	switch tag {
+	case "pre", "textarea", "script", "style":
+		return true
+	default:
+		return false
+	}
+}
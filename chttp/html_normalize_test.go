@@ -0,0 +1,37 @@
+package chttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReaderWriter_WriteHTML_Deterministic(t *testing.T) {
+	t.Parallel()
+
+	config := chttp.Config{DeterministicHTML: true}
+
+	renderer, err := chttp.NewHTMLRenderer(chttp.NewHTMLRendererParams{
+		HTMLDir: chttptest.HTMLDir,
+		Config:  config,
+		Logger:  clogger.NewNoop(),
+	})
+	assert.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+
+	chttp.NewReaderWriter(renderer, config, clogger.NewNoop()).WriteHTML(resp,
+		httptest.NewRequest(http.MethodGet, "/", nil), chttp.WriteHTMLParams{
+			PageTemplate: "index.html",
+		})
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `<!doctype html> <html> <head> <meta charset="UTF-8"/> `+
+		`<meta content="width=device-width, initial-scale=1.0" name="viewport"/> <title>Test Page</title> </head> `+
+		`<body> Test Page </body> </html> `, resp.Body.String())
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/gocopper/copper/cerrors"
 )
 
+const defaultMaxPartialDepth = 32
+
 type (
 	// HTMLDir is a directory that can be embedded or found on the host system. It should contain sub-directories
 	// and files to support the WriteHTML function in ReaderWriter.
@@ -24,9 +26,12 @@ type (
 
 	// HTMLRenderer provides functionality in rendering templatized HTML along with HTML components
 	HTMLRenderer struct {
-		htmlDir     HTMLDir
-		staticDir   StaticDir
-		renderFuncs []HTMLRenderFunc
+		htmlDir         HTMLDir
+		staticDir       StaticDir
+		renderFuncs     []HTMLRenderFunc
+		checkData       bool
+		maxPartialDepth int
+		logger          clogger.Logger
 	}
 
 	// HTMLRenderFunc can be used to register new template functions
@@ -53,9 +58,16 @@ type (
 // components
 func NewHTMLRenderer(p NewHTMLRendererParams) (*HTMLRenderer, error) {
 	hr := HTMLRenderer{
-		htmlDir:     p.HTMLDir,
-		staticDir:   p.StaticDir,
-		renderFuncs: p.RenderFuncs,
+		htmlDir:         p.HTMLDir,
+		staticDir:       p.StaticDir,
+		renderFuncs:     p.RenderFuncs,
+		checkData:       p.Config.CheckTemplateData,
+		maxPartialDepth: p.Config.MaxPartialDepth,
+		logger:          p.Logger,
+	}
+
+	if hr.maxPartialDepth == 0 {
+		hr.maxPartialDepth = defaultMaxPartialDepth
 	}
 
 	if p.Config.UseLocalHTML {
@@ -70,9 +82,16 @@ func NewHTMLRenderer(p NewHTMLRendererParams) (*HTMLRenderer, error) {
 	return &hr, nil
 }
 
-func (r *HTMLRenderer) funcMap(req *http.Request) template.FuncMap {
+// funcMap returns the template functions for a template. The stack holds the names of the partials that are being
+// rendered around the template so that partial can detect include cycles.
+func (r *HTMLRenderer) funcMap(req *http.Request, stack []string) template.FuncMap {
 	var funcMap = template.FuncMap{
-		"partial": r.partial(req),
+		"partial":     r.partial(req, stack),
+		"url":         urlFunc(req),
+		"methodField": methodField,
+		"asset": func(p string) string {
+			return WithBasePath(req.Context(), p)
+		},
 	}
 
 	for i := range r.renderFuncs {
@@ -82,12 +101,26 @@ func (r *HTMLRenderer) funcMap(req *http.Request) template.FuncMap {
 	return funcMap
 }
 
+// dir returns the HTML dir for the request, with the request's theme (see WithTheme) layered over the app's HTMLDir.
+func (r *HTMLRenderer) dir(req *http.Request) fs.FS {
+	theme, ok := ThemeFromCtx(req.Context())
+	if !ok || theme.HTMLDir == nil {
+		return r.htmlDir
+	}
+
+	if r.htmlDir == nil {
+		return theme.HTMLDir
+	}
+
+	return overlayFS{top: theme.HTMLDir, base: r.htmlDir}
+}
+
 func (r *HTMLRenderer) render(req *http.Request, layout, page string, data interface{}) (template.HTML, error) {
 	var dest strings.Builder
 
 	tmpl, err := template.New(layout).
-		Funcs(r.funcMap(req)).
-		ParseFS(r.htmlDir,
+		Funcs(r.funcMap(req, nil)).
+		ParseFS(r.dir(req),
 			path.Join("src", "layouts", layout),
 			path.Join("src", "pages", page),
 		)
@@ -98,6 +131,8 @@ func (r *HTMLRenderer) render(req *http.Request, layout, page string, data inter
 		})
 	}
 
+	r.checkTemplateData(tmpl, layout, page, data)
+
 	err = tmpl.Execute(&dest, data)
 	if err != nil {
 		return "", cerrors.New(err, "failed to execute template", nil)
@@ -107,13 +142,22 @@ func (r *HTMLRenderer) render(req *http.Request, layout, page string, data inter
 	return template.HTML(dest.String()), nil
 }
 
-func (r *HTMLRenderer) partial(req *http.Request) func(name string, data interface{}) (template.HTML, error) {
+func (r *HTMLRenderer) partial(req *http.Request, stack []string) func(name string, data interface{}) (template.HTML,
+	error) {
 	return func(name string, data interface{}) (template.HTML, error) {
 		var dest strings.Builder
 
+		err := r.checkPartialStack(stack, name)
+		if err != nil {
+			return "", err
+		}
+
+		// Copy the stack so that sibling partials do not share the backing array.
+		partialStack := append(append(make([]string, 0, len(stack)+1), stack...), name)
+
 		tmpl, err := template.New(name+".html").
-			Funcs(r.funcMap(req)).
-			ParseFS(r.htmlDir,
+			Funcs(r.funcMap(req, partialStack)).
+			ParseFS(r.dir(req),
 				path.Join("src", "partials", "*.html"),
 			)
 		if err != nil {
@@ -122,6 +166,8 @@ func (r *HTMLRenderer) partial(req *http.Request) func(name string, data interfa
 			})
 		}
 
+		r.checkTemplateData(tmpl, "", name+".html", data)
+
 		err = tmpl.Execute(&dest, data)
 		if err != nil {
 			return "", cerrors.New(err, "failed to execute partial template", map[string]interface{}{
@@ -133,3 +179,61 @@ func (r *HTMLRenderer) partial(req *http.Request) func(name string, data interfa
 		return template.HTML(dest.String()), nil
 	}
 }
+
+// checkPartialStack returns an error if rendering the partial with the given name would include a partial that is
+// already being rendered, or if it would exceed the max partial depth.
+func (r *HTMLRenderer) checkPartialStack(stack []string, name string) error {
+	for _, s := range stack {
+		if s == name {
+			return cerrors.New(nil, "partial includes itself", map[string]interface{}{
+				"name":  name,
+				"chain": strings.Join(append(stack, name), " -> "),
+			})
+		}
+	}
+
+	if len(stack) >= r.maxPartialDepth {
+		return cerrors.New(nil, "partials are nested too deeply", map[string]interface{}{
+			"name":     name,
+			"maxDepth": r.maxPartialDepth,
+			"chain":    strings.Join(append(stack, name), " -> "),
+		})
+	}
+
+	return nil
+}
+
+// checkTemplateData logs a warning if the template accesses fields that are missing from the data (which would
+// otherwise silently render as empty) or if the data has fields that the template does not use.
+func (r *HTMLRenderer) checkTemplateData(tmpl *template.Template, layout, page string, data interface{}) {
+	if !r.checkData {
+		return
+	}
+
+	missing, unused := newTemplateContract(tmpl, tmpl.Name()).check(data)
+	if len(missing) == 0 && len(unused) == 0 {
+		return
+	}
+
+	r.logger.WithTags(map[string]interface{}{
+		"layout":  layout,
+		"page":    page,
+		"missing": missing,
+		"unused":  unused,
+	}).Warn("Template data does not match the fields used by the template", nil)
+}
+
+// urlFunc generates route URLs in templates using URL. The vars are given as name/value pairs, for example:
+//
+//	<a href="{{ url "post" "id" .ID }}">
+func urlFunc(req *http.Request) func(name string, pairs ...string) (string, error) {
+	return func(name string, pairs ...string) (string, error) {
+		vars := make(map[string]string, len(pairs)/2) //nolint:gomnd
+
+		for i := 0; i+1 < len(pairs); i += 2 {
+			vars[pairs[i]] = pairs[i+1]
+		}
+
+		return URL(req, name, vars)
+	}
+}
@@ -0,0 +1,93 @@
+package chttp_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLRenderer_PartialRecursion(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		maxDepth int
+		partials map[string]string
+		wantErr  string
+		wantTags map[string]interface{}
+	}{
+		"cycle": {
+			partials: map[string]string{
+				"a": `{{ partial "b" . }}`,
+				"b": `{{ partial "a" . }}`,
+			},
+			wantErr:  "partial includes itself",
+			wantTags: map[string]interface{}{"name": "a", "chain": "a -> b -> a"},
+		},
+		"depth": {
+			maxDepth: 2,
+			partials: map[string]string{
+				"a": `{{ partial "b" . }}`,
+				"b": `{{ partial "c" . }}`,
+				"c": `c`,
+			},
+			wantErr:  "partials are nested too deeply",
+			wantTags: map[string]interface{}{"name": "c", "maxDepth": 2, "chain": "a -> b -> c"},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				logs    = make([]clogger.RecordedLog, 0)
+				htmlDir = fstest.MapFS{
+					"src/layouts/main.html": {Data: []byte(`{{ partial "a" . }}`)},
+					"src/pages/index.html":  {Data: []byte(``)},
+				}
+				config = chttp.Config{MaxPartialDepth: tc.maxDepth}
+			)
+
+			for name, data := range tc.partials {
+				htmlDir["src/partials/"+name+".html"] = &fstest.MapFile{Data: []byte(data)}
+			}
+
+			renderer, err := chttp.NewHTMLRenderer(chttp.NewHTMLRendererParams{
+				HTMLDir: htmlDir,
+				Config:  config,
+				Logger:  clogger.NewNoop(),
+			})
+			assert.NoError(t, err)
+
+			resp := httptest.NewRecorder()
+
+			chttp.NewReaderWriter(renderer, config, clogger.NewRecorder(&logs)).WriteHTML(resp,
+				httptest.NewRequest(http.MethodGet, "/", nil), chttp.WriteHTMLParams{
+					PageTemplate: "index.html",
+				})
+
+			assert.Equal(t, http.StatusInternalServerError, resp.Code)
+			assert.Equal(t, 1, len(logs))
+			assert.Contains(t, logs[0].Error.Error(), tc.wantErr)
+
+			var cerr cerrors.Error
+
+			for err := logs[0].Error; err != nil; err = errors.Unwrap(err) {
+				if e, ok := err.(cerrors.Error); ok && e.Message == tc.wantErr { //nolint:errorlint
+					cerr = e
+				}
+			}
+
+			assert.Equal(t, tc.wantTags, cerr.Tags)
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package chttp
 
 import (
+	"io/fs"
 	"net/http"
 	"path"
+	"strings"
 )
 
 type (
@@ -52,8 +54,16 @@ func (ro *HTMLRouter) Routes() []Route {
 }
 
 // HandleStaticFile serves the requested static file as found in the web/public directory. In non-dev env, the static
-// files are embedded in the binary.
+// files are embedded in the binary. Files in the request's theme (see WithTheme) take precedence.
 func (ro *HTMLRouter) HandleStaticFile(w http.ResponseWriter, r *http.Request) {
+	if theme, ok := ThemeFromCtx(r.Context()); ok && theme.StaticDir != nil {
+		info, err := fs.Stat(theme.StaticDir, strings.TrimPrefix(r.URL.Path, "/"))
+		if err == nil && !info.IsDir() {
+			http.FileServer(http.FS(theme.StaticDir)).ServeHTTP(w, r)
+			return
+		}
+	}
+
 	if ro.config.UseLocalHTML {
 		http.ServeFile(w, r, path.Join("web", "public", URLParams(r)["path"]))
 		return
@@ -6,11 +6,36 @@ import (
 
 // Route represents a single HTTP route (ex. /api/profile) that can be configured with middlewares, path,
 // HTTP methods, and a handler.
+// Path vars can be constrained with a regular expression (ex. {id:[0-9]+}) or a type from RouteParamTypes (ex.
+// {id:int}) so that malformed values are not found instead of reaching the handler. When routes overlap, constrained
+// vars are matched before unconstrained ones.
+// Requires lists the roles, permissions, or flags a request must satisfy to reach the handler. These are checked by
+// the Authorizer configured with NewHandler.
+// MaxBodyBytes limits the size of the request body for the route, overriding Config.MaxBodyBytes.
+// Name identifies the route so that its URL can be generated with URL. LocalizedPaths registers locale-specific
+// aliases of the path (ex. "fr" => "/fr/a-propos") that are handled by the same handler with the locale set in the
+// request context (see Locale).
+// Deprecation marks the route as deprecated so that clients are told about it in the response headers.
+// API describes the route's request and response bodies so that it is included in the OpenAPI document.
+// RequestHooks and ResponseHooks transform the bodies read and written by ReaderWriter so that cross-cutting
+// transformations can be registered with the route instead of in its handler.
 type Route struct {
-	Middlewares []Middleware
-	Path        string
-	Methods     []string
-	Handler     http.HandlerFunc
+	Middlewares  []Middleware
+	Path         string
+	Methods      []string
+	Handler      http.HandlerFunc
+	Requires     []string
+	MaxBodyBytes int64
+	Deprecation  *Deprecation
+	API          *RouteAPI
+
+	RequestHooks  []RequestHook
+	ResponseHooks []ResponseHook
+
+	Name           string
+	LocalizedPaths map[string]string
+
+	locale string
 }
 
 // Router is used to group routes together that are returned by the Routes method.
@@ -0,0 +1,316 @@
+package chttp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/clogger"
+)
+
+const (
+	// IdempotencyKeyHeader is the header that clients set to make a request idempotent.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader is set to "true" on responses that are replayed by the IdempotencyMiddleware.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	defaultIdempotencyTTL         = 24 * time.Hour
+	defaultIdempotencyLockTTL     = time.Minute
+	defaultIdempotencyScopeCookie = "session"
+	defaultIdempotencyMaxBody     = 1 << 20
+
+	// idempotencyStoreTimeout limits how long saving or releasing a key can take once the request is done.
+	idempotencyStoreTimeout = 10 * time.Second
+)
+
+var errIdempotentBodyTooLarge = errors.New("request body is too large")
+
+type (
+	// NewIdempotencyMiddlewareParams holds the params needed to create an IdempotencyMiddleware.
+	NewIdempotencyMiddlewareParams struct {
+		Config Config
+		Store  IdempotencyStore
+		Clock  cclock.Clock
+		Logger clogger.Logger
+	}
+
+	// IdempotencyMiddleware makes POST and PATCH requests with an Idempotency-Key header safe to retry. The first
+	// response for a key is stored and replayed for retries with the same key until Config.IdempotencyTTL passes,
+	// so that duplicate submissions (ex. of a payment or a webhook) do not run the handler twice. Retries that arrive
+	// while the first request is in progress get a Conflict response until Config.IdempotencyLockTTL passes. Server
+	// errors are not stored so that the request can be retried. Retries with a different body get an Unprocessable
+	// Entity response.
+	// Keys are scoped to the request's method, path, Authorization header, and the cookies in
+	// Config.IdempotencyScopeCookies so that clients cannot replay each other's responses. Set-Cookie headers are not
+	// stored.
+	IdempotencyMiddleware struct {
+		store        IdempotencyStore
+		ttl          time.Duration
+		lockTTL      time.Duration
+		maxBodyBytes int64
+		scopeCookies []string
+		clock        cclock.Clock
+		logger       clogger.Logger
+	}
+)
+
+// NewIdempotencyMiddleware creates a new IdempotencyMiddleware.
+func NewIdempotencyMiddleware(p NewIdempotencyMiddlewareParams) *IdempotencyMiddleware {
+	ttl := p.Config.IdempotencyTTL
+	if ttl == 0 {
+		ttl = defaultIdempotencyTTL
+	}
+
+	lockTTL := p.Config.IdempotencyLockTTL
+	if lockTTL == 0 {
+		lockTTL = defaultIdempotencyLockTTL
+	}
+
+	maxBodyBytes := p.Config.IdempotencyMaxBodyBytes
+	if maxBodyBytes == 0 {
+		maxBodyBytes = defaultIdempotencyMaxBody
+	}
+
+	scopeCookies := p.Config.IdempotencyScopeCookies
+	if len(scopeCookies) == 0 {
+		scopeCookies = []string{defaultIdempotencyScopeCookie}
+	}
+
+	return &IdempotencyMiddleware{
+		store:        p.Store,
+		ttl:          ttl,
+		lockTTL:      lockTTL,
+		maxBodyBytes: maxBodyBytes,
+		scopeCookies: scopeCookies,
+		clock:        p.Clock,
+		logger:       p.Logger,
+	}
+}
+
+// Handle replays the stored response for the request's idempotency key, if any. Otherwise, it runs the next handler
+// and stores its response.
+func (mw *IdempotencyMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+		if idempotencyKey == "" || (r.Method != http.MethodPost && r.Method != http.MethodPatch) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var (
+			key = idempotencyStoreKey(r, idempotencyKey, mw.scopeCookies)
+			log = mw.logger.WithTags(map[string]interface{}{
+				"method": r.Method,
+				"path":   r.URL.Path,
+			})
+		)
+
+		reqHash, err := hashRequestBody(r, mw.maxBodyBytes)
+		if errors.Is(err, errIdempotentBodyTooLarge) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		if err != nil {
+			log.Warn("Failed to read request body", err)
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		resp, reserved, err := mw.store.Reserve(r.Context(), key, mw.clock.Now().Add(mw.lockTTL))
+		if err != nil {
+			log.Error("Failed to reserve idempotency key", cerrors.New(err, "idempotency store failed", nil))
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		if resp != nil && resp.RequestHash != reqHash {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+
+			_, _ = w.Write([]byte(`{"error":"the idempotency key was used with a different request body"}` + "\n"))
+
+			return
+		}
+
+		if resp != nil {
+			writeIdempotentResponse(w, resp)
+			return
+		}
+
+		if !reserved {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+
+			_, _ = w.Write([]byte(`{"error":"a request with this idempotency key is in progress"}` + "\n"))
+
+			return
+		}
+
+		rw := idempotencyRw{internal: w, statusCode: http.StatusOK}
+		saved := false
+
+		// The reservation is released if the response is not saved (including when the handler panics) so that
+		// the request can be retried.
+		defer func() {
+			if saved {
+				return
+			}
+
+			ctx, cancel := idempotencyStoreCtx(r)
+			defer cancel()
+
+			err := mw.store.Release(ctx, key)
+			if err != nil {
+				log.Warn("Failed to release idempotency key", err)
+			}
+		}()
+
+		next.ServeHTTP(&rw, r)
+
+		if rw.statusCode >= http.StatusInternalServerError {
+			return
+		}
+
+		// Cookies are not replayed since they may hold the session of the client that made the first request
+		header := rw.Header().Clone()
+		header.Del("Set-Cookie")
+
+		ctx, cancel := idempotencyStoreCtx(r)
+		defer cancel()
+
+		err = mw.store.Save(ctx, key, IdempotentResponse{
+			StatusCode:  rw.statusCode,
+			Header:      header,
+			Body:        rw.body.Bytes(),
+			RequestHash: reqHash,
+		}, mw.clock.Now().Add(mw.ttl))
+		if err != nil {
+			log.Warn("Failed to save idempotent response", err)
+			return
+		}
+
+		saved = true
+	})
+}
+
+// idempotencyStoreCtx returns the context used to save or release the key once the request is done. It is not
+// canceled when the client disconnects so that the key is not left reserved.
+func idempotencyStoreCtx(r *http.Request) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(detachedCtx{r.Context()}, idempotencyStoreTimeout)
+}
+
+// detachedCtx has the values of its parent but is never canceled.
+type detachedCtx struct {
+	context.Context
+}
+
+func (detachedCtx) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedCtx) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedCtx) Err() error {
+	return nil
+}
+
+func idempotencyStoreKey(r *http.Request, key string, scopeCookies []string) string {
+	h := sha256.New()
+
+	parts := []string{r.Method, r.URL.Path, r.Header.Get("Authorization")}
+
+	for _, name := range scopeCookies {
+		var val string
+
+		if cookie, err := r.Cookie(name); err == nil {
+			val = cookie.Value
+		}
+
+		parts = append(parts, val)
+	}
+
+	for _, part := range append(parts, key) {
+		_, _ = h.Write([]byte(part))
+		_, _ = h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashRequestBody reads the request's body and returns its hash. The body is replaced so that the next handler can
+// still read it. It returns errIdempotentBodyTooLarge if the body is larger than maxBytes.
+func hashRequestBody(r *http.Request, maxBytes int64) (string, error) {
+	var body []byte
+
+	if r.Body != nil {
+		var err error
+
+		body, err = ioutil.ReadAll(io.LimitReader(r.Body, maxBytes+1))
+		if err != nil {
+			return "", cerrors.New(err, "failed to read body", nil)
+		}
+
+		if int64(len(body)) > maxBytes {
+			return "", errIdempotentBodyTooLarge
+		}
+
+		_ = r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	sum := sha256.Sum256(body)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func writeIdempotentResponse(w http.ResponseWriter, resp *IdempotentResponse) {
+	for name, values := range resp.Header {
+		w.Header()[name] = append([]string(nil), values...)
+	}
+
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(resp.StatusCode)
+
+	_, _ = w.Write(resp.Body)
+}
+
+type idempotencyRw struct {
+	internal   http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *idempotencyRw) Flush() {
+	if f, ok := rw.internal.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (rw *idempotencyRw) Header() http.Header {
+	return rw.internal.Header()
+}
+
+func (rw *idempotencyRw) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+
+	return rw.internal.Write(b)
+}
+
+func (rw *idempotencyRw) WriteHeader(statusCode int) {
+	rw.internal.WriteHeader(statusCode)
+	rw.statusCode = statusCode
+}
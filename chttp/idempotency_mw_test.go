@@ -0,0 +1,281 @@
+package chttp_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		clock = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		calls = 0
+		mw    = chttp.NewIdempotencyMiddleware(chttp.NewIdempotencyMiddlewareParams{
+			Config: chttp.Config{IdempotencyTTL: time.Hour},
+			Store:  chttp.NewMemoryIdempotencyStore(clock),
+			Clock:  clock,
+			Logger: clogger.NewNoop(),
+		})
+	)
+
+	handler := mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, `{"amount":1}`, string(body))
+
+		w.Header().Set("X-Charge-ID", "ch_1")
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "new"})
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"charged":true}`))
+	}))
+
+	do := func(method, key, auth, session, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/charges", strings.NewReader(body))
+		req.Header.Set(chttp.IdempotencyKeyHeader, key)
+		req.Header.Set("Authorization", auth)
+		req.AddCookie(&http.Cookie{Name: "session", Value: session})
+
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		return resp
+	}
+
+	first := do(http.MethodPost, "key-1", "user-1", "s1", `{"amount":1}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, "", first.Header().Get(chttp.IdempotentReplayedHeader))
+	assert.NotEmpty(t, first.Header().Get("Set-Cookie"))
+
+	retry := do(http.MethodPost, "key-1", "user-1", "s1", `{"amount":1}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, `{"charged":true}`, retry.Body.String())
+	assert.Equal(t, "ch_1", retry.Header().Get("X-Charge-ID"))
+	assert.Equal(t, "", retry.Header().Get("Set-Cookie"))
+	assert.Equal(t, "true", retry.Header().Get(chttp.IdempotentReplayedHeader))
+	assert.Equal(t, 1, calls)
+
+	changed := do(http.MethodPost, "key-1", "user-1", "s1", `{"amount":2}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, changed.Code)
+	assert.Equal(t, 1, calls)
+
+	do(http.MethodPost, "key-1", "user-2", "s1", `{"amount":1}`)
+	do(http.MethodPost, "key-1", "user-1", "s2", `{"amount":1}`)
+	do(http.MethodPost, "key-2", "user-1", "s1", `{"amount":1}`)
+	do(http.MethodPut, "key-1", "user-1", "s1", `{"amount":1}`)
+	do(http.MethodPost, "", "user-1", "s1", `{"amount":1}`)
+	assert.Equal(t, 6, calls)
+
+	clock.Advance(time.Hour)
+
+	expired := do(http.MethodPost, "key-1", "user-1", "s1", `{"amount":1}`)
+	assert.Equal(t, "", expired.Header().Get(chttp.IdempotentReplayedHeader))
+	assert.Equal(t, 7, calls)
+}
+
+func TestIdempotencyMiddleware_InProgress(t *testing.T) {
+	t.Parallel()
+
+	var (
+		clock   = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		started = make(chan struct{})
+		release = make(chan struct{})
+		mw      = chttp.NewIdempotencyMiddleware(chttp.NewIdempotencyMiddlewareParams{
+			Store:  chttp.NewMemoryIdempotencyStore(clock),
+			Clock:  clock,
+			Logger: clogger.NewNoop(),
+		})
+		done = make(chan struct{})
+	)
+
+	handler := mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+
+	newReq := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/charges", nil)
+		req.Header.Set(chttp.IdempotencyKeyHeader, "key-1")
+
+		return req
+	}
+
+	go func() {
+		handler.ServeHTTP(httptest.NewRecorder(), newReq())
+		close(done)
+	}()
+
+	<-started
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, newReq())
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	close(release)
+	<-done
+}
+
+func TestIdempotencyMiddleware_ServerError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		clock = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		calls = 0
+		mw    = chttp.NewIdempotencyMiddleware(chttp.NewIdempotencyMiddlewareParams{
+			Store:  chttp.NewMemoryIdempotencyStore(clock),
+			Clock:  clock,
+			Logger: clogger.NewNoop(),
+		})
+	)
+
+	handler := mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/charges", nil)
+		req.Header.Set(chttp.IdempotencyKeyHeader, "key-1")
+
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadGateway, resp.Code)
+	}
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_StaleReservation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		clock   = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		calls   int32
+		started = make(chan struct{})
+		release = make(chan struct{})
+		mw      = chttp.NewIdempotencyMiddleware(chttp.NewIdempotencyMiddlewareParams{
+			Config: chttp.Config{IdempotencyLockTTL: time.Minute},
+			Store:  chttp.NewMemoryIdempotencyStore(clock),
+			Clock:  clock,
+			Logger: clogger.NewNoop(),
+		})
+	)
+
+	defer close(release)
+
+	// The first request never finishes, as if the app crashed while handling it
+	handler := mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+			<-release
+		}
+	}))
+
+	newReq := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/charges", nil)
+		req.Header.Set(chttp.IdempotencyKeyHeader, "key-1")
+
+		return req
+	}
+
+	go handler.ServeHTTP(httptest.NewRecorder(), newReq())
+
+	<-started
+
+	clock.Advance(time.Minute)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, newReq())
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+type ctxIdempotencyStore struct {
+	*chttp.MemoryIdempotencyStore
+}
+
+func (s ctxIdempotencyStore) Save(ctx context.Context, key string, resp chttp.IdempotentResponse,
+	expiresAt time.Time) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return s.MemoryIdempotencyStore.Save(ctx, key, resp, expiresAt)
+}
+
+func TestIdempotencyMiddleware_ClientDisconnected(t *testing.T) {
+	t.Parallel()
+
+	var (
+		clock = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		calls = 0
+		mw    = chttp.NewIdempotencyMiddleware(chttp.NewIdempotencyMiddlewareParams{
+			Store:  ctxIdempotencyStore{chttp.NewMemoryIdempotencyStore(clock)},
+			Clock:  clock,
+			Logger: clogger.NewNoop(),
+		})
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	handler := mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		// The client disconnects before the response is written
+		cancel()
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	for _, reqCtx := range []context.Context{ctx, context.Background()} {
+		req := httptest.NewRequest(http.MethodPost, "/charges", nil).WithContext(reqCtx)
+		req.Header.Set(chttp.IdempotencyKeyHeader, "key-1")
+
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotencyMiddleware_BodyTooLarge(t *testing.T) {
+	t.Parallel()
+
+	var (
+		clock = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		calls = 0
+		mw    = chttp.NewIdempotencyMiddleware(chttp.NewIdempotencyMiddlewareParams{
+			Config: chttp.Config{IdempotencyMaxBodyBytes: 4},
+			Store:  chttp.NewMemoryIdempotencyStore(clock),
+			Clock:  clock,
+			Logger: clogger.NewNoop(),
+		})
+	)
+
+	handler := mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/charges", strings.NewReader("12345"))
+	req.Header.Set(chttp.IdempotencyKeyHeader, "key-1")
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+	assert.Equal(t, 0, calls)
+}
@@ -0,0 +1,113 @@
+package chttp
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+)
+
+// memoryIdempotencySweepInterval is how often the MemoryIdempotencyStore removes the expired entries.
+const memoryIdempotencySweepInterval = time.Minute
+
+type (
+	// IdempotentResponse is a response stored by the IdempotencyMiddleware so that it can be replayed.
+	IdempotentResponse struct {
+		StatusCode int
+		Header     http.Header
+		Body       []byte
+
+		// RequestHash is the hash of the body of the request that produced the response. Retries with a different
+		// body are rejected.
+		RequestHash string
+	}
+
+	// IdempotencyStore stores the responses of the IdempotencyMiddleware. Implementations backed by a shared
+	// database (ex. SQL or Redis) make the middleware work across multiple instances of the app.
+	IdempotencyStore interface {
+		// Reserve atomically reserves the key until expiresAt if it is not reserved yet. If the key already has a
+		// response, it is returned. If the key is reserved by a request that is still in progress, it returns false.
+		Reserve(ctx context.Context, key string, expiresAt time.Time) (*IdempotentResponse, bool, error)
+
+		// Save stores the response for a reserved key until expiresAt.
+		Save(ctx context.Context, key string, resp IdempotentResponse, expiresAt time.Time) error
+
+		// Release removes the reservation for a key that has no response so that the request can be retried.
+		Release(ctx context.Context, key string) error
+	}
+)
+
+// NewMemoryIdempotencyStore creates a new MemoryIdempotencyStore.
+func NewMemoryIdempotencyStore(clock cclock.Clock) *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		clock:   clock,
+		entries: make(map[string]memoryIdempotencyEntry),
+	}
+}
+
+// MemoryIdempotencyStore keeps responses in memory. They are lost when the app restarts and are not shared between
+// instances of the app, so it is best suited for development and single-instance apps.
+type MemoryIdempotencyStore struct {
+	clock cclock.Clock
+
+	mu      sync.Mutex
+	entries map[string]memoryIdempotencyEntry
+	sweptAt time.Time
+}
+
+type memoryIdempotencyEntry struct {
+	resp      *IdempotentResponse
+	expiresAt time.Time
+}
+
+// Reserve reserves the key or returns its response. The expired entries of other keys are removed periodically so
+// that the store does not grow with every key that was ever used.
+func (s *MemoryIdempotencyStore) Reserve(ctx context.Context, key string, expiresAt time.Time) (*IdempotentResponse,
+	bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+
+	if now.Sub(s.sweptAt) >= memoryIdempotencySweepInterval {
+		for k, entry := range s.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+
+		s.sweptAt = now
+	}
+
+	entry, ok := s.entries[key]
+	if ok && now.Before(entry.expiresAt) {
+		return entry.resp, false, nil
+	}
+
+	s.entries[key] = memoryIdempotencyEntry{expiresAt: expiresAt}
+
+	return nil, true, nil
+}
+
+// Save stores the response for the key.
+func (s *MemoryIdempotencyStore) Save(ctx context.Context, key string, resp IdempotentResponse,
+	expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = memoryIdempotencyEntry{resp: &resp, expiresAt: expiresAt}
+
+	return nil
+}
+
+// Release removes the key's reservation.
+func (s *MemoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package chttp
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/clogger"
+)
+
+// IPFilterConfig lists the networks (CIDRs or IPs) that are allowed or denied access. Requests from a denied network
+// are always rejected. If Allow is not empty, requests that are not from an allowed network are rejected as well.
+type IPFilterConfig struct {
+	Allow []string `toml:"allow"`
+	Deny  []string `toml:"deny"`
+}
+
+type ipFilter struct {
+	allow []*net.IPNet
+	deny  []*net.IPNet
+}
+
+// NewIPFilter creates a new IPFilter with the lists in Config.IPFilters.
+func NewIPFilter(config Config, logger clogger.Logger) (*IPFilter, error) {
+	filters, err := newIPFilters(config.IPFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IPFilter{
+		filters: filters,
+		logger:  logger,
+	}, nil
+}
+
+// IPFilter restricts groups of routes (ex. an admin panel or webhooks) to known networks using the named lists in
+// Config.IPFilters. The client's address is resolved with ClientIP, so the app's proxies should be listed in
+// Config.TrustedProxies.
+type IPFilter struct {
+	filters map[string]ipFilter
+	logger  clogger.Logger
+}
+
+// Middleware returns a middleware that rejects requests that are not allowed by the list with the given name with a
+// Forbidden response. If there is no list with the name, all requests are rejected.
+func (f *IPFilter) Middleware(name string) Middleware {
+	filter, ok := f.filters[name]
+
+	mw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := ClientIP(r)
+
+			if !ok {
+				f.logger.WithTags(map[string]interface{}{
+					"filter": name,
+					"path":   r.URL.Path,
+				}).Error("Route uses an ip filter that is not configured", nil)
+				w.WriteHeader(http.StatusForbidden)
+
+				return
+			}
+
+			if !filter.allows(ip) {
+				f.logger.WithTags(map[string]interface{}{
+					"filter": name,
+					"path":   r.URL.Path,
+					"ip":     ip,
+				}).Warn("Request was blocked by ip filter", nil)
+				w.WriteHeader(http.StatusForbidden)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	return HandleMiddleware(mw)
+}
+
+func (f ipFilter) allows(ip string) bool {
+	if ipNetsContain(f.deny, ip) {
+		return false
+	}
+
+	return len(f.allow) == 0 || ipNetsContain(f.allow, ip)
+}
+
+func newIPFilters(configs map[string]IPFilterConfig) (map[string]ipFilter, error) {
+	filters := make(map[string]ipFilter, len(configs))
+
+	for name, c := range configs {
+		allow, err := parseIPNets(c.Allow)
+		if err != nil {
+			return nil, cerrors.New(err, "invalid ip filter", map[string]interface{}{
+				"filter": name,
+			})
+		}
+
+		deny, err := parseIPNets(c.Deny)
+		if err != nil {
+			return nil, cerrors.New(err, "invalid ip filter", map[string]interface{}{
+				"filter": name,
+			})
+		}
+
+		filters[name] = ipFilter{allow: allow, deny: deny}
+	}
+
+	return filters, nil
+}
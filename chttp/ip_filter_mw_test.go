@@ -0,0 +1,64 @@
+package chttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPFilter_Middleware(t *testing.T) {
+	t.Parallel()
+
+	filter, err := chttp.NewIPFilter(chttp.Config{
+		IPFilters: map[string]chttp.IPFilterConfig{
+			"admin":    {Allow: []string{"10.0.0.0/8", "192.0.2.10"}, Deny: []string{"10.0.0.66"}},
+			"webhooks": {Deny: []string{"203.0.113.0/24"}},
+		},
+	}, clogger.NewNoop())
+	assert.NoError(t, err)
+
+	testCases := map[string]struct {
+		filter   string
+		ip       string
+		wantCode int
+	}{
+		"allowed cidr":   {"admin", "10.1.2.3", http.StatusOK},
+		"allowed ip":     {"admin", "192.0.2.10", http.StatusOK},
+		"not allowed":    {"admin", "192.0.2.11", http.StatusForbidden},
+		"denied":         {"admin", "10.0.0.66", http.StatusForbidden},
+		"deny only":      {"webhooks", "203.0.113.5", http.StatusForbidden},
+		"not denied":     {"webhooks", "198.51.100.1", http.StatusOK},
+		"unknown filter": {"unknown", "10.1.2.3", http.StatusForbidden},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tc.ip + ":1234"
+
+			resp := httptest.NewRecorder()
+			filter.Middleware(tc.filter).Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).
+				ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.wantCode, resp.Code)
+		})
+	}
+}
+
+func TestNewIPFilter_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := chttp.NewIPFilter(chttp.Config{
+		IPFilters: map[string]chttp.IPFilterConfig{"admin": {Allow: []string{"10.0.0.0/33"}}},
+	}, clogger.NewNoop())
+
+	assert.Error(t, err)
+}
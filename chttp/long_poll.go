@@ -0,0 +1,137 @@
+package chttp
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cerrors"
+)
+
+const (
+	defaultLongPollWait     = 30 * time.Second
+	defaultLongPollInterval = time.Second
+)
+
+// LongPollParams holds the params for the LongPoll function in ReaderWriter.
+type LongPollParams struct {
+	// Cursor is the position that the client has already seen (ex. read from a query param). It is empty on the
+	// client's first request.
+	Cursor string
+
+	// Wait is how long the request is held open waiting for new data. It defaults to 30s and should be shorter than
+	// the server's write timeout.
+	Wait time.Duration
+
+	// Interval is how often Fetch is called while waiting. It defaults to 1s.
+	Interval time.Duration
+
+	// Clock is used to wait for the interval and the wait time. It defaults to the system clock.
+	Clock cclock.Clock
+
+	// Fetch returns the data after the given cursor along with the cursor that the client should resume from. It
+	// should return nil data if there is nothing new.
+	Fetch func(ctx context.Context, cursor string) (data interface{}, next string, err error)
+}
+
+// LongPoll holds the request open until Fetch returns new data after the client's cursor, or until the wait time
+// runs out. New data is written as a JSON response with the cursor that the client should send on its next request:
+//
+//	{"cursor": "42", "data": [...]}
+//
+// If there is no new data in time, a NoContent response is sent back and the client should poll again with the same
+// cursor. This gives clients that cannot use server-sent events or websockets near real-time updates.
+func (rw *ReaderWriter) LongPoll(w http.ResponseWriter, r *http.Request, p LongPollParams) {
+	if p.Wait <= 0 {
+		p.Wait = defaultLongPollWait
+	}
+
+	if p.Interval <= 0 {
+		p.Interval = defaultLongPollInterval
+	}
+
+	if p.Clock == nil {
+		p.Clock = cclock.New()
+	}
+
+	var (
+		ctx      = r.Context()
+		deadline = p.Clock.After(p.Wait)
+	)
+
+	for {
+		data, next, err := p.Fetch(ctx, p.Cursor)
+		if err != nil {
+			rw.logger.Error("Failed to fetch long poll data", cerrors.New(err, "long poll fetch failed", map[string]interface{}{
+				"url":    r.URL.String(),
+				"cursor": p.Cursor,
+			}))
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		if data != nil {
+			rw.WriteJSON(w, WriteJSONParams{
+				Data: map[string]interface{}{
+					"cursor": next,
+					"data":   data,
+				},
+			})
+
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline:
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case <-p.Clock.After(p.Interval):
+		}
+	}
+}
+
+// WaitForEventParams holds the params for the WaitForEvent function in ReaderWriter.
+type WaitForEventParams struct {
+	// Events is the channel that the request waits on (ex. a subscription to a pub/sub topic). Only the first event
+	// is written.
+	Events <-chan interface{}
+
+	// Wait is how long the request is held open waiting for an event. It defaults to 30s and should be shorter than
+	// the server's write timeout.
+	Wait time.Duration
+
+	// Clock is used to wait for the wait time. It defaults to the system clock.
+	Clock cclock.Clock
+}
+
+// WaitForEvent holds the request open until an event arrives on p.Events and writes it as a JSON response. If no event
+// arrives in time, or if the channel is closed, a NoContent response is sent back and the client should poll again.
+// Unlike LongPoll, it does not call a Fetch func on an interval, so it suits notifications that are pushed from
+// within the app.
+func (rw *ReaderWriter) WaitForEvent(w http.ResponseWriter, r *http.Request, p WaitForEventParams) {
+	if p.Wait <= 0 {
+		p.Wait = defaultLongPollWait
+	}
+
+	if p.Clock == nil {
+		p.Clock = cclock.New()
+	}
+
+	select {
+	case <-r.Context().Done():
+		return
+	case <-p.Clock.After(p.Wait):
+		w.WriteHeader(http.StatusNoContent)
+	case event, ok := <-p.Events:
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		rw.WriteJSON(w, WriteJSONParams{Data: event})
+	}
+}
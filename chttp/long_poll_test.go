@@ -0,0 +1,140 @@
+package chttp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReaderWriter_LongPoll(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rw    = chttptest.NewReaderWriter(t)
+		resp  = httptest.NewRecorder()
+		calls = 0
+	)
+
+	rw.LongPoll(resp, httptest.NewRequest(http.MethodGet, "/", nil), chttp.LongPollParams{
+		Cursor:   "1",
+		Wait:     time.Second,
+		Interval: time.Millisecond,
+		Fetch: func(ctx context.Context, cursor string) (interface{}, string, error) {
+			calls++
+			if calls < 3 {
+				return nil, cursor, nil
+			}
+
+			return []string{"event"}, "2", nil
+		},
+	})
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 3, calls)
+	assert.JSONEq(t, `{"cursor":"2","data":["event"]}`, resp.Body.String())
+}
+
+func TestReaderWriter_LongPoll_Timeout(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rw    = chttptest.NewReaderWriter(t)
+		resp  = httptest.NewRecorder()
+		clock = cclock.NewFake(time.Now())
+	)
+
+	rw.LongPoll(resp, httptest.NewRequest(http.MethodGet, "/", nil), chttp.LongPollParams{
+		Cursor:   "1",
+		Wait:     time.Minute,
+		Interval: time.Second,
+		Clock:    clock,
+		Fetch: func(ctx context.Context, cursor string) (interface{}, string, error) {
+			clock.Advance(time.Minute)
+
+			return nil, cursor, nil
+		},
+	})
+
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+}
+
+func TestReaderWriter_WaitForEvent(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rw     = chttptest.NewReaderWriter(t)
+		resp   = httptest.NewRecorder()
+		events = make(chan interface{})
+	)
+
+	go func() {
+		events <- map[string]string{"type": "message"}
+	}()
+
+	rw.WaitForEvent(resp, httptest.NewRequest(http.MethodGet, "/", nil), chttp.WaitForEventParams{
+		Events: events,
+		Wait:   time.Second,
+	})
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"type":"message"}`, resp.Body.String())
+}
+
+func TestReaderWriter_WaitForEvent_Timeout(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rw    = chttptest.NewReaderWriter(t)
+		resp  = httptest.NewRecorder()
+		clock = cclock.NewFake(time.Now())
+		done  = make(chan struct{})
+	)
+
+	defer close(done)
+
+	// The clock is advanced until the request returns since the timer may not be waiting yet
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Millisecond):
+				clock.Advance(time.Minute)
+			}
+		}
+	}()
+
+	rw.WaitForEvent(resp, httptest.NewRequest(http.MethodGet, "/", nil), chttp.WaitForEventParams{
+		Events: make(chan interface{}),
+		Wait:   time.Minute,
+		Clock:  clock,
+	})
+
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+}
+
+func TestReaderWriter_WaitForEvent_Closed(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rw     = chttptest.NewReaderWriter(t)
+		resp   = httptest.NewRecorder()
+		events = make(chan interface{})
+	)
+
+	close(events)
+
+	rw.WaitForEvent(resp, httptest.NewRequest(http.MethodGet, "/", nil), chttp.WaitForEventParams{
+		Events: events,
+		Wait:   time.Second,
+	})
+
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+}
@@ -0,0 +1,180 @@
+package chttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/clogger"
+)
+
+const defaultMaintenanceAPIPrefix = "/api/"
+
+// MaintenanceConfig configures the MaintenanceMiddleware. For example:
+//
+//	[chttp.maintenance]
+//	enabled = true
+//	exempt_paths = ["/healthz", "/static/"]
+//	retry_after = "15m"
+type MaintenanceConfig struct {
+	// Enabled turns maintenance mode on. It is followed at runtime when the config is reloaded (see cconfig.Watcher)
+	// or overridden (see cconfig.Overrides). It can also be toggled with MaintenanceMiddleware.SetEnabled or
+	// MaintenanceMiddleware.ToggleHandler.
+	Enabled bool `toml:"enabled"`
+
+	// ExemptPaths lists the path prefixes that are served as usual during maintenance (ex. health checks).
+	ExemptPaths []string `toml:"exempt_paths"`
+
+	// APIPrefix is the path prefix of API routes, which get a JSON response during maintenance. It defaults to
+	// /api/. Requests that accept JSON also get a JSON response.
+	APIPrefix string `toml:"api_prefix"`
+
+	// RetryAfter sets the Retry-After header on maintenance responses, if it is set.
+	RetryAfter time.Duration `toml:"retry_after"`
+}
+
+// NewMaintenanceMiddleware creates a new MaintenanceMiddleware. If appConfig is not nil, maintenance mode follows the
+// changes to chttp.maintenance.enabled in appConfig (see cconfig.Watch).
+func NewMaintenanceMiddleware(config Config, appConfig cconfig.Loader, rw *ReaderWriter,
+	logger clogger.Logger) *MaintenanceMiddleware {
+	c := config.Maintenance
+	if c.APIPrefix == "" {
+		c.APIPrefix = defaultMaintenanceAPIPrefix
+	}
+
+	mw := &MaintenanceMiddleware{
+		config: c,
+		rw:     rw,
+		logger: logger,
+	}
+
+	mw.SetEnabled(c.Enabled)
+
+	if appConfig != nil {
+		mw.watch(appConfig)
+	}
+
+	return mw
+}
+
+// MaintenanceMiddleware responds with a Service Unavailable error to requests for paths that are not exempt while
+// maintenance mode is on, so that deploys and migrations do not need load balancer changes. API requests get a JSON
+// error and other requests get the maintenance.html page, which the app's HTML dir should have.
+type MaintenanceMiddleware struct {
+	config  MaintenanceConfig
+	rw      *ReaderWriter
+	logger  clogger.Logger
+	enabled int32
+}
+
+// Enabled returns true if maintenance mode is on.
+func (mw *MaintenanceMiddleware) Enabled() bool {
+	return atomic.LoadInt32(&mw.enabled) == 1
+}
+
+// SetEnabled turns maintenance mode on or off.
+func (mw *MaintenanceMiddleware) SetEnabled(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+
+	if atomic.SwapInt32(&mw.enabled, v) != v {
+		mw.logger.WithTags(map[string]interface{}{
+			"enabled": enabled,
+		}).Info("Maintenance mode changed")
+	}
+}
+
+// Handle responds with the maintenance error if maintenance mode is on and the path is not exempt. Otherwise, it
+// calls the next handler.
+func (mw *MaintenanceMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !mw.Enabled() || mw.isExempt(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if mw.config.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(mw.config.RetryAfter.Seconds())))
+		}
+
+		if strings.HasPrefix(r.URL.Path, mw.config.APIPrefix) ||
+			strings.Contains(r.Header.Get("Accept"), "application/json") {
+			mw.rw.WriteJSON(w, WriteJSONParams{
+				StatusCode: http.StatusServiceUnavailable,
+				Data:       map[string]string{"error": "the service is down for maintenance"},
+			})
+
+			return
+		}
+
+		mw.rw.WriteHTML(w, r, WriteHTMLParams{
+			StatusCode:   http.StatusServiceUnavailable,
+			PageTemplate: "maintenance.html",
+		})
+	})
+}
+
+// ToggleHandler returns a handler that shows maintenance mode's status as JSON on GET requests and changes it on POST
+// requests with a JSON body (ex. {"enabled": true}). It should be mounted on an admin route that is protected (ex.
+// with IPFilter.Middleware) and exempt from maintenance mode.
+func (mw *MaintenanceMiddleware) ToggleHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var body struct {
+				Enabled *bool `json:"enabled"`
+			}
+
+			err := json.NewDecoder(r.Body).Decode(&body)
+			if err != nil || body.Enabled == nil {
+				mw.rw.WriteJSON(w, WriteJSONParams{
+					StatusCode: http.StatusBadRequest,
+					Data:       map[string]string{"error": "expected a body like {\"enabled\": true}"},
+				})
+
+				return
+			}
+
+			mw.SetEnabled(*body.Enabled)
+		}
+
+		mw.rw.WriteJSON(w, WriteJSONParams{
+			Data: map[string]bool{"enabled": mw.Enabled()},
+		})
+	}
+}
+
+// watch sets maintenance mode each time the chttp.maintenance config changes. The watch lasts as long as the process
+// since the middleware does too.
+func (mw *MaintenanceMiddleware) watch(appConfig cconfig.Loader) {
+	values, _ := cconfig.Watch(appConfig, "chttp.maintenance")
+
+	go func() {
+		for val := range values {
+			var config MaintenanceConfig
+
+			err := val.Load(&config)
+			if err != nil {
+				mw.logger.Warn("Failed to reload maintenance config", err)
+				continue
+			}
+
+			mw.SetEnabled(config.Enabled)
+		}
+	}()
+}
+
+func (mw *MaintenanceMiddleware) isExempt(path string) bool {
+	for _, prefix := range mw.config.ExemptPaths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
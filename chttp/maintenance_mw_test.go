@@ -0,0 +1,143 @@
+package chttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceMiddleware(t *testing.T) {
+	t.Parallel()
+
+	config := chttp.Config{Maintenance: chttp.MaintenanceConfig{
+		Enabled:     true,
+		ExemptPaths: []string{"/healthz"},
+		RetryAfter:  time.Minute,
+	}}
+
+	renderer, err := chttp.NewHTMLRenderer(chttp.NewHTMLRendererParams{
+		HTMLDir: fstest.MapFS{
+			"src/layouts/main.html":      {Data: []byte(`{{ template "content" . }}`)},
+			"src/pages/maintenance.html": {Data: []byte(`{{ define "content" }}down for maintenance{{ end }}`)},
+		},
+		Config: config,
+		Logger: clogger.NewNoop(),
+	})
+	assert.NoError(t, err)
+
+	var (
+		rw      = chttp.NewReaderWriter(renderer, config, clogger.NewNoop())
+		mw      = chttp.NewMaintenanceMiddleware(config, nil, rw, clogger.NewNoop())
+		handler = mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}))
+	)
+
+	testCases := map[string]struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		"page":   {"/", http.StatusServiceUnavailable, "down for maintenance"},
+		"api":    {"/api/users", http.StatusServiceUnavailable, `{"error":"the service is down for maintenance"}`},
+		"exempt": {"/healthz", http.StatusOK, "ok"},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, tc.wantStatus, resp.Code)
+			assert.Equal(t, tc.wantBody, strings.TrimSpace(resp.Body.String()))
+
+			if tc.wantStatus == http.StatusServiceUnavailable {
+				assert.Equal(t, "60", resp.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestMaintenanceMiddleware_ToggleHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rw      = chttptest.NewReaderWriter(t)
+		mw      = chttp.NewMaintenanceMiddleware(chttp.Config{}, nil, rw, clogger.NewNoop())
+		handler = mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		toggle  = mw.ToggleHandler()
+	)
+
+	resp := httptest.NewRecorder()
+	toggle.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/admin/maintenance", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = httptest.NewRecorder()
+	toggle.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/admin/maintenance",
+		strings.NewReader(`{"enabled":true}`)))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"enabled":true}`, resp.Body.String())
+	assert.True(t, mw.Enabled())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+
+	mw.SetEnabled(false)
+
+	resp = httptest.NewRecorder()
+	toggle.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/admin/maintenance", nil))
+	assert.JSONEq(t, `{"enabled":false}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestMaintenanceMiddleware_Watch(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[chttp.maintenance]
+			enabled = false
+		`,
+	})
+
+	loader, err := cconfig.New(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+
+	config, err := chttp.LoadConfig(loader)
+	assert.NoError(t, err)
+
+	overrides, err := cconfig.NewOverrides(cconfig.NewOverridesParams{
+		Loader:      loader,
+		AllowedKeys: []string{"chttp.maintenance.enabled"},
+	})
+	assert.NoError(t, err)
+
+	mw := chttp.NewMaintenanceMiddleware(config, loader, chttptest.NewReaderWriter(t), clogger.NewNoop())
+	assert.False(t, mw.Enabled())
+
+	_, err = overrides.Set("chttp.maintenance.enabled", "true", time.Minute, "ops")
+	assert.NoError(t, err)
+	assert.Eventually(t, mw.Enabled, time.Second, time.Millisecond)
+
+	overrides.Delete("chttp.maintenance.enabled", "ops")
+	assert.Eventually(t, func() bool { return !mw.Enabled() }, time.Second, time.Millisecond)
+}
@@ -0,0 +1,69 @@
+package chttp
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type ctxBodyLimited string
+
+const ctxBodyLimitedKey = ctxBodyLimited("chttp/body-limited")
+
+// NewMaxBodySizeMiddleware creates a new MaxBodySizeMiddleware.
+func NewMaxBodySizeMiddleware(config Config) *MaxBodySizeMiddleware {
+	return &MaxBodySizeMiddleware{maxBytes: config.MaxBodyBytes}
+}
+
+// MaxBodySizeMiddleware limits the size of request bodies to Config.MaxBodyBytes to protect the app from running out
+// of memory. Requests that declare a larger Content-Length are rejected right away with a RequestEntityTooLarge
+// response. Otherwise, reading past the limit fails, and ReaderWriter.ReadJSON and ReaderWriter.ReadForm respond with
+// RequestEntityTooLarge. Routes with Route.MaxBodyBytes set use their own limit instead.
+type MaxBodySizeMiddleware struct {
+	maxBytes int64
+}
+
+// Handle limits the request body unless the route has its own limit.
+func (mw *MaxBodySizeMiddleware) Handle(next http.Handler) http.Handler {
+	if mw.maxBytes <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if limited, _ := r.Context().Value(ctxBodyLimitedKey).(bool); limited {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		maxBodySizeMiddleware(mw.maxBytes).Handle(next).ServeHTTP(w, r)
+	})
+}
+
+func maxBodySizeMiddleware(maxBytes int64) Middleware {
+	mw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				writeBodyTooLarge(w)
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxBodyLimitedKey, true)))
+		})
+	}
+
+	return HandleMiddleware(mw)
+}
+
+func writeBodyTooLarge(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+
+	_, _ = w.Write([]byte(`{"error":"request body too large"}` + "\n"))
+}
+
+// isBodyTooLarge checks if the error was returned by a reader created with http.MaxBytesReader.
+func isBodyTooLarge(err error) bool {
+	return strings.Contains(err.Error(), "http: request body too large")
+}
@@ -0,0 +1,83 @@
+package chttp_test
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxBodySizeMiddleware(t *testing.T) {
+	t.Parallel()
+
+	rw := chttptest.NewReaderWriter(t)
+
+	readJSON := func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Key string `json:"key"`
+		}
+
+		if !rw.ReadJSON(w, r, &body) {
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{chttptest.NewRouter([]chttp.Route{
+			{
+				Path:    "/small",
+				Methods: []string{http.MethodPost},
+				Handler: readJSON,
+			},
+			{
+				Path:         "/large",
+				Methods:      []string{http.MethodPost},
+				Handler:      readJSON,
+				MaxBodyBytes: 1024,
+			},
+		})},
+		GlobalMiddlewares: []chttp.Middleware{
+			chttp.NewMaxBodySizeMiddleware(chttp.Config{MaxBodyBytes: 16}),
+		},
+		Logger: clogger.NewNoop(),
+	}))
+	defer server.Close()
+
+	body := `{"key":"` + strings.Repeat("a", 32) + `"}`
+
+	testCases := []struct {
+		name     string
+		path     string
+		body     io.Reader
+		wantCode int
+	}{
+		{"ContentLength", "/small", strings.NewReader(body), http.StatusRequestEntityTooLarge},
+		// io.MultiReader hides the length of the body so that it is sent chunked and the limit is hit while reading.
+		{"Chunked", "/small", io.MultiReader(strings.NewReader(body)), http.StatusRequestEntityTooLarge},
+		{"RouteOverride", "/large", strings.NewReader(body), http.StatusNoContent},
+	}
+
+	for _, tc := range testCases {
+		resp, err := http.Post(server.URL+tc.path, "application/json", tc.body) //nolint:noctx
+		assert.NoError(t, err)
+
+		respBody, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+
+		assert.Equal(t, tc.wantCode, resp.StatusCode, tc.name)
+
+		if tc.wantCode == http.StatusRequestEntityTooLarge {
+			assert.Equal(t, `{"error":"request body too large"}`+"\n", string(respBody), tc.name)
+		}
+	}
+}
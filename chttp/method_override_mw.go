@@ -0,0 +1,62 @@
+package chttp
+
+import (
+	"html/template"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const (
+	// MethodOverrideHeader is the header that overrides the method of POST requests when method overrides are
+	// enabled with NewHandlerParams.MethodOverride.
+	MethodOverrideHeader = "X-HTTP-Method-Override"
+
+	// MethodOverrideField is the form field that overrides the method of POST requests when method overrides are
+	// enabled with NewHandlerParams.MethodOverride.
+	MethodOverrideField = "_method"
+)
+
+// methodOverrideMiddleware lets POST requests target PUT, PATCH, and DELETE routes using the MethodOverrideHeader
+// header or the MethodOverrideField form field, since HTML forms can only be submitted with GET or POST. It runs
+// before the request is routed so that the route is matched with the overridden method. The form field is only read
+// from url-encoded bodies so that large multipart bodies are not parsed before routing.
+func methodOverrideMiddleware() Middleware {
+	mw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			method := r.Header.Get(MethodOverrideHeader)
+			if method == "" && isURLEncodedForm(r) {
+				method = r.PostFormValue(MethodOverrideField)
+			}
+
+			switch method = strings.ToUpper(method); method {
+			case http.MethodPut, http.MethodPatch, http.MethodDelete:
+				r.Method = method
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	return HandleMiddleware(mw)
+}
+
+func isURLEncodedForm(r *http.Request) bool {
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	return err == nil && contentType == "application/x-www-form-urlencoded"
+}
+
+// methodField renders the hidden form field that overrides a form's method. For example:
+//
+//	<form method="post" action="/posts/1">{{ methodField "DELETE" }}</form>
+func methodField(method string) template.HTML {
+	// nolint:gosec
+	return template.HTML(`<input type="hidden" name="` + MethodOverrideField + `" value="` +
+		template.HTMLEscapeString(strings.ToUpper(method)) + `">`)
+}
@@ -0,0 +1,69 @@
+package chttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHandler_MethodOverride(t *testing.T) {
+	t.Parallel()
+
+	handler := chttp.NewHandler(chttp.NewHandlerParams{
+		MethodOverride: true,
+		Routers: []chttp.Router{chttptest.NewRouter([]chttp.Route{
+			{
+				Path:    "/posts/1",
+				Methods: []string{http.MethodPost, http.MethodDelete},
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(r.Method + " " + r.PostFormValue("title")))
+				},
+			},
+		})},
+		Logger: clogger.NewNoop(),
+	})
+
+	testCases := map[string]struct {
+		method      string
+		contentType string
+		header      string
+		body        string
+		want        string
+	}{
+		"form field":      {http.MethodPost, "application/x-www-form-urlencoded", "", "_method=delete&title=a", "DELETE a"},
+		"header":          {http.MethodPost, "application/json", "DELETE", "{}", "DELETE "},
+		"no override":     {http.MethodPost, "application/x-www-form-urlencoded", "", "title=a", "POST a"},
+		"not allowed":     {http.MethodPost, "application/x-www-form-urlencoded", "", "_method=GET&title=a", "POST a"},
+		"multipart":       {http.MethodPost, "multipart/form-data; boundary=x", "", "", "POST "},
+		"not post":        {http.MethodGet, "", http.MethodDelete, "", "405"},
+		"header and form": {http.MethodPost, "application/x-www-form-urlencoded", "delete", "_method=PUT", "DELETE "},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tc.method, "/posts/1", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			req.Header.Set(chttp.MethodOverrideHeader, tc.header)
+
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			if resp.Code == http.StatusMethodNotAllowed {
+				assert.Equal(t, "405", tc.want)
+				return
+			}
+
+			assert.Equal(t, tc.want, resp.Body.String())
+		})
+	}
+}
@@ -0,0 +1,367 @@
+package chttp
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultOpenAPIPath    = "/openapi.json"
+	defaultOpenAPITitle   = "API"
+	defaultOpenAPIVersion = "1.0.0"
+)
+
+type (
+	// OpenAPIConfig configures the OpenAPI document generated from the routes that have RouteAPI metadata.
+	OpenAPIConfig struct {
+		// Enabled serves the document at Path, which defaults to /openapi.json.
+		Enabled bool   `toml:"enabled"`
+		Path    string `toml:"path"`
+
+		// SwaggerUIPath serves Swagger UI for the document at the given path (ex. /docs). It is disabled if not set.
+		SwaggerUIPath string `toml:"swagger_ui_path"`
+
+		// Title and Version describe the API in the document's info object.
+		Title   string `toml:"title"`
+		Version string `toml:"version"`
+	}
+
+	// RouteAPI describes a JSON API route so that it can be documented in the OpenAPI document. Request and
+	// Response are values of the types that the route reads and writes (ex. CreatePostBody{} or []Post{}).
+	RouteAPI struct {
+		Summary     string
+		Description string
+		Tags        []string
+
+		Request  interface{}
+		Response interface{}
+
+		// ResponseStatus is the status code of successful responses. It defaults to OK.
+		ResponseStatus int
+	}
+
+	// openAPISchemas generates JSON schemas for Go types. Named struct types are added to the document's components
+	// and referenced so that recursive types are supported.
+	openAPISchemas struct {
+		names      map[reflect.Type]string
+		components map[string]interface{}
+	}
+)
+
+// NewOpenAPIDocument generates an OpenAPI 3 document for the routes that have RouteAPI metadata. Path vars are
+// documented as required string parameters.
+func NewOpenAPIDocument(config OpenAPIConfig, basePath string, routes []Route) map[string]interface{} {
+	var (
+		schemas = openAPISchemas{
+			names:      make(map[reflect.Type]string),
+			components: make(map[string]interface{}),
+		}
+		paths = make(map[string]interface{})
+	)
+
+	for _, route := range routes {
+		if route.API == nil {
+			continue
+		}
+
+		var (
+			path    = routeVarRe.ReplaceAllString(route.Path, "{$1}")
+			item, _ = paths[path].(map[string]interface{})
+			methods = route.Methods
+		)
+
+		if item == nil {
+			item = make(map[string]interface{})
+			paths[path] = item
+		}
+
+		if len(methods) == 0 {
+			methods = []string{http.MethodGet}
+		}
+
+		for _, method := range methods {
+			item[strings.ToLower(method)] = schemas.operation(route)
+		}
+	}
+
+	doc := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   stringOrDefault(config.Title, defaultOpenAPITitle),
+			"version": stringOrDefault(config.Version, defaultOpenAPIVersion),
+		},
+		"paths": paths,
+	}
+
+	if basePath != "" {
+		doc["servers"] = []interface{}{map[string]interface{}{"url": basePath}}
+	}
+
+	if len(schemas.components) > 0 {
+		doc["components"] = map[string]interface{}{"schemas": schemas.components}
+	}
+
+	return doc
+}
+
+func (s *openAPISchemas) operation(route Route) map[string]interface{} {
+	api := route.API
+
+	op := map[string]interface{}{}
+
+	if route.Name != "" {
+		op["operationId"] = route.Name
+	}
+
+	if api.Summary != "" {
+		op["summary"] = api.Summary
+	}
+
+	if api.Description != "" {
+		op["description"] = api.Description
+	}
+
+	if len(api.Tags) > 0 {
+		op["tags"] = api.Tags
+	}
+
+	if route.Deprecation != nil {
+		op["deprecated"] = true
+	}
+
+	params := make([]interface{}, 0)
+
+	for _, match := range routeVarRe.FindAllStringSubmatch(route.Path, -1) {
+		params = append(params, map[string]interface{}{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   routeParamSchema(strings.TrimPrefix(match[2], ":")),
+		})
+	}
+
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	if api.Request != nil {
+		op["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  jsonContent(s.schema(reflect.TypeOf(api.Request))),
+		}
+	}
+
+	status := api.ResponseStatus
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	response := map[string]interface{}{
+		"description": http.StatusText(status),
+	}
+
+	if api.Response != nil {
+		response["content"] = jsonContent(s.schema(reflect.TypeOf(api.Response)))
+	}
+
+	op["responses"] = map[string]interface{}{
+		strconv.Itoa(status): response,
+	}
+
+	return op
+}
+
+func (s *openAPISchemas) schema(t reflect.Type) map[string]interface{} {
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := s.schema(t.Elem())
+		if _, ok := schema["$ref"]; !ok {
+			schema["nullable"] = true
+		}
+
+		return schema
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+
+		return map[string]interface{}{"type": "array", "items": s.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": s.schema(t.Elem())}
+	case reflect.Struct:
+		return s.structSchema(t)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema returns a reference to the component schema of named structs and an inline schema otherwise.
+func (s *openAPISchemas) structSchema(t reflect.Type) map[string]interface{} {
+	if t.Name() == "" {
+		return s.objectSchema(t)
+	}
+
+	name, ok := s.names[t]
+	if !ok {
+		name = t.Name()
+		if _, taken := s.components[name]; taken {
+			name = strings.ReplaceAll(t.String(), ".", "_")
+		}
+
+		s.names[t] = name
+		// Reserve the name before generating the schema so that recursive types reference it.
+		s.components[name] = map[string]interface{}{}
+		s.components[name] = s.objectSchema(t)
+	}
+
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+func (s *openAPISchemas) objectSchema(t reflect.Type) map[string]interface{} {
+	var (
+		properties = make(map[string]interface{})
+		required   = make([]string, 0)
+	)
+
+	s.addFields(t, properties, &required)
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// addFields adds the json fields of t to properties. The fields of embedded structs are added as if they were
+// declared in t, like encoding/json does.
+func (s *openAPISchemas) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if idx := strings.Index(tag, ","); idx >= 0 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			s.addFields(field.Type, properties, required)
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = s.schema(field.Type)
+
+		if strings.Contains(field.Tag.Get("valid"), "required") && !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func jsonContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
+
+func stringOrDefault(val, defaultVal string) string {
+	if val == "" {
+		return defaultVal
+	}
+
+	return val
+}
+
+// openAPIRoutes returns the routes that serve the OpenAPI document and Swagger UI.
+func openAPIRoutes(config OpenAPIConfig, basePath string, routes []Route) []Route {
+	specPath := stringOrDefault(config.Path, defaultOpenAPIPath)
+
+	spec, err := json.Marshal(NewOpenAPIDocument(config, basePath, routes))
+
+	out := []Route{
+		{
+			Path:    specPath,
+			Methods: []string{http.MethodGet},
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				if err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write(spec)
+			},
+		},
+	}
+
+	if config.SwaggerUIPath == "" {
+		return out
+	}
+
+	page := strings.ReplaceAll(swaggerUIHTML, "{{SPEC_URL}}", template.JSEscapeString(basePath+specPath))
+
+	return append(out, Route{
+		Path:    config.SwaggerUIPath,
+		Methods: []string{http.MethodGet},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(page))
+		},
+	})
+}
+
+const swaggerUIHTML = `<!doctype html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>API Docs</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@4/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@4/swagger-ui-bundle.js"></script>
+  <script>SwaggerUIBundle({url: "{{SPEC_URL}}", dom_id: "#swagger-ui"});</script>
+</body>
+</html>
+`
@@ -0,0 +1,148 @@
+package chttp_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+type openAPITestPost struct {
+	ID        int64             `json:"id"`
+	Title     string            `json:"title" valid:"required"`
+	Tags      []string          `json:"tags,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	Parent    *openAPITestPost  `json:"parent"`
+	Meta      map[string]string `json:"meta"`
+}
+
+func TestNewOpenAPIDocument(t *testing.T) {
+	t.Parallel()
+
+	doc := chttp.NewOpenAPIDocument(chttp.OpenAPIConfig{Title: "Blog"}, "/app", []chttp.Route{
+		{
+			Name:    "getPost",
+			Path:    "/posts/{id:[0-9]+}",
+			Methods: []string{http.MethodGet},
+			API: &chttp.RouteAPI{
+				Summary:  "Get a post",
+				Response: openAPITestPost{},
+			},
+		},
+		{
+			Path:    "/posts",
+			Methods: []string{http.MethodPost},
+			API: &chttp.RouteAPI{
+				Tags: []string{"posts"},
+				Request: struct {
+					Title string `json:"title" valid:"required"`
+				}{},
+				Response:       openAPITestPost{},
+				ResponseStatus: http.StatusCreated,
+			},
+		},
+		{
+			Path:    "/undocumented",
+			Methods: []string{http.MethodGet},
+		},
+	})
+
+	out, err := json.Marshal(doc)
+	assert.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"openapi": "3.0.3",
+		"info": {"title": "Blog", "version": "1.0.0"},
+		"servers": [{"url": "/app"}],
+		"paths": {
+			"/posts/{id}": {
+				"get": {
+					"operationId": "getPost",
+					"summary": "Get a post",
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {"200": {
+						"description": "OK",
+						"content": {"application/json": {"schema": {"$ref": "#/components/schemas/openAPITestPost"}}}
+					}}
+				}
+			},
+			"/posts": {
+				"post": {
+					"tags": ["posts"],
+					"requestBody": {
+						"required": true,
+						"content": {"application/json": {"schema": {
+							"type": "object",
+							"properties": {"title": {"type": "string"}},
+							"required": ["title"]
+						}}}
+					},
+					"responses": {"201": {
+						"description": "Created",
+						"content": {"application/json": {"schema": {"$ref": "#/components/schemas/openAPITestPost"}}}
+					}}
+				}
+			}
+		},
+		"components": {"schemas": {"openAPITestPost": {
+			"type": "object",
+			"properties": {
+				"id": {"type": "integer", "format": "int64"},
+				"title": {"type": "string"},
+				"tags": {"type": "array", "items": {"type": "string"}},
+				"created_at": {"type": "string", "format": "date-time"},
+				"parent": {"$ref": "#/components/schemas/openAPITestPost"},
+				"meta": {"type": "object", "additionalProperties": {"type": "string"}}
+			},
+			"required": ["title"]
+		}}}
+	}`, string(out))
+}
+
+func TestNewHandler_OpenAPI(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		OpenAPI: chttp.OpenAPIConfig{
+			Enabled:       true,
+			SwaggerUIPath: "/docs",
+		},
+		Routers: []chttp.Router{chttptest.NewRouter([]chttp.Route{
+			{
+				Path:    "/posts",
+				Methods: []string{http.MethodGet},
+				Handler: func(w http.ResponseWriter, r *http.Request) {},
+				API:     &chttp.RouteAPI{Response: []openAPITestPost{}},
+			},
+		})},
+		Logger: clogger.NewNoop(),
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/openapi.json") //nolint:noctx
+	assert.NoError(t, err)
+
+	var doc struct {
+		Paths map[string]interface{} `json:"paths"`
+	}
+
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Contains(t, doc.Paths, "/posts")
+
+	resp, err = http.Get(server.URL + "/docs") //nolint:noctx
+	assert.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Contains(t, string(body), `url: "/openapi.json"`)
+}
@@ -1,35 +1,55 @@
 package chttp
 
 import (
+	"fmt"
 	"net/http"
 	"runtime/debug"
+	"strings"
 
 	"github.com/gocopper/copper/clogger"
 )
 
-func panicLoggerMiddleware(logger clogger.Logger) Middleware {
+// ErrorReporter can be implemented to send errors to an external service (ex. Sentry). It is used by NewHandler to
+// report panics that were recovered while handling requests.
+type ErrorReporter interface {
+	ReportError(r *http.Request, err error)
+}
+
+func panicLoggerMiddleware(logger clogger.Logger, rw *ReaderWriter, reporter ErrorReporter) Middleware {
 	mw := func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
-				log := logger.WithTags(map[string]interface{}{
-					"path": r.URL.Path,
-				})
+				var (
+					err   error
+					stack = string(debug.Stack())
+					log   = logger.WithTags(map[string]interface{}{
+						"path": r.URL.Path,
+					})
+				)
 
-				switch r := recover().(type) {
+				switch rec := recover().(type) {
 				case nil:
-					break
+					return
 				case error:
+					err = rec
+
 					log.WithTags(map[string]interface{}{
-						"stack": string(debug.Stack()),
-					}).Error("Recovered from a panic while handling HTTP request", r)
-					w.WriteHeader(http.StatusInternalServerError)
+						"stack": stack,
+					}).Error("Recovered from a panic while handling HTTP request", rec)
 				default:
+					err = fmt.Errorf("panic: %v", rec) //nolint:goerr113
+
 					log.WithTags(map[string]interface{}{
-						"error": r,
-						"stack": string(debug.Stack()),
+						"error": rec,
+						"stack": stack,
 					}).Error("Recovered from a panic while handling HTTP request", nil)
-					w.WriteHeader(http.StatusInternalServerError)
 				}
+
+				if reporter != nil {
+					reporter.ReportError(r, err)
+				}
+
+				writePanicResponse(w, r, rw, err)
 			}()
 
 			next.ServeHTTP(w, r)
@@ -38,3 +58,31 @@ func panicLoggerMiddleware(logger clogger.Logger) Middleware {
 
 	return HandleMiddleware(mw)
 }
+
+// writePanicResponse responds with an internal server error. If a ReaderWriter is available, the response is either
+// a JSON error or the internal-error.html page (or the debug error page, if enabled) based on the request.
+func writePanicResponse(w http.ResponseWriter, r *http.Request, rw *ReaderWriter, err error) {
+	if rw == nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "application/json") ||
+		strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		rw.WriteJSON(w, WriteJSONParams{
+			StatusCode: http.StatusInternalServerError,
+			Data:       map[string]string{"error": http.StatusText(http.StatusInternalServerError)},
+		})
+
+		return
+	}
+
+	if rw.canRenderErrorDebug(r) {
+		rw.writeErrorDebugPage(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	rw.WriteHTML(w, r, WriteHTMLParams{
+		StatusCode: http.StatusInternalServerError,
+	})
+}
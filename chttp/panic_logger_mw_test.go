@@ -2,6 +2,7 @@ package chttp_test
 
 import (
 	"errors"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -120,3 +121,57 @@ func TestPanicLoggerMiddleware_PanicNonError(t *testing.T) {
 	assert.Nil(t, logs[0].Error)
 	assert.Contains(t, logs[0].Tags["stack"], "panic_logger_mw.go")
 }
+
+type recordingErrorReporter struct {
+	errs []error
+}
+
+func (r *recordingErrorReporter) ReportError(req *http.Request, err error) {
+	r.errs = append(r.errs, err)
+}
+
+func TestPanicLoggerMiddleware_ReportAndJSON(t *testing.T) {
+	t.Parallel()
+
+	var (
+		router = chttptest.NewRouter([]chttp.Route{
+			{
+				Path:    "/",
+				Methods: []string{http.MethodGet},
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					panic("test-error")
+				},
+			},
+		})
+
+		reporter = &recordingErrorReporter{}
+
+		handler = chttp.NewHandler(chttp.NewHandlerParams{
+			Routers:       []chttp.Router{router},
+			RW:            chttptest.NewReaderWriter(t),
+			ErrorReporter: reporter,
+			Logger:        clogger.NewNoop(),
+		})
+	)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil) //nolint:noctx
+	assert.NoError(t, err)
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, resp.Body.Close())
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"error":"Internal Server Error"}`, string(body))
+	assert.Equal(t, 1, len(reporter.errs))
+	assert.EqualError(t, reporter.errs[0], "panic: test-error")
+}
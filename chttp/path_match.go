@@ -0,0 +1,119 @@
+package chttp
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Values for Config.TrailingSlash.
+const (
+	// TrailingSlashRedirect redirects requests to the same path with or without a trailing slash when only the
+	// other one has a route.
+	TrailingSlashRedirect = "redirect"
+
+	// TrailingSlashRewrite serves requests with the route for the same path with or without a trailing slash when
+	// only the other one has a route.
+	TrailingSlashRewrite = "rewrite"
+)
+
+// pathMatchMiddleware retries requests that do not match any route with the path's trailing slash toggled and/or
+// with the path in lowercase. If one of them matches a route, the request is either redirected to it or served with
+// it. Requests that match a route (including ones that only fail to match its methods) are not changed. Since the
+// lowercase path is used for case-insensitive matches, routes should be declared in lowercase and their vars are
+// lowercased as well.
+func pathMatchMiddleware(router *mux.Router, trailingSlash string, caseInsensitive bool, table *routeTable) Middleware {
+	mw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if matchesRoute(router, r, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			target, ok := pathMatchCandidate(router, r, trailingSlash, caseInsensitive)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if trailingSlash == TrailingSlashRedirect {
+				redirectToPath(w, r, target, table)
+				return
+			}
+
+			r2 := r.Clone(r.Context())
+			r2.URL.Path = target
+			r2.URL.RawPath = ""
+
+			next.ServeHTTP(w, r2)
+		})
+	}
+
+	return HandleMiddleware(mw)
+}
+
+func pathMatchCandidate(router *mux.Router, r *http.Request, trailingSlash string, caseInsensitive bool) (string,
+	bool) {
+	candidates := make([]string, 0, 3) //nolint:gomnd
+
+	if trailingSlash != "" && r.URL.Path != "/" {
+		candidates = append(candidates, toggleTrailingSlash(r.URL.Path))
+	}
+
+	if caseInsensitive {
+		lower := strings.ToLower(r.URL.Path)
+
+		if lower != r.URL.Path {
+			candidates = append(candidates, lower)
+		}
+
+		if trailingSlash != "" && lower != "/" {
+			candidates = append(candidates, toggleTrailingSlash(lower))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if matchesRoute(router, r, candidate) {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+func matchesRoute(router *mux.Router, r *http.Request, path string) bool {
+	var match mux.RouteMatch
+
+	r2 := r.Clone(r.Context())
+	r2.URL.Path = path
+	r2.URL.RawPath = ""
+
+	return router.Match(r2, &match) || match.MatchErr == mux.ErrMethodMismatch
+}
+
+func toggleTrailingSlash(path string) string {
+	if strings.HasSuffix(path, "/") {
+		return strings.TrimSuffix(path, "/")
+	}
+
+	return path + "/"
+}
+
+// redirectToPath redirects to the given path under the app's base path. Requests other than GET and HEAD are
+// redirected with PermanentRedirect so that clients resend the method and body.
+func redirectToPath(w http.ResponseWriter, r *http.Request, path string, table *routeTable) {
+	target := WithBasePath(context.WithValue(r.Context(), ctxRouteTableKey, table), path)
+
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+
+	statusCode := http.StatusMovedPermanently
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		statusCode = http.StatusPermanentRedirect
+	}
+
+	http.Redirect(w, r, target, statusCode)
+}
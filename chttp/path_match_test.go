@@ -0,0 +1,79 @@
+package chttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHandler_TrailingSlashAndCase(t *testing.T) {
+	t.Parallel()
+
+	routers := []chttp.Router{chttptest.NewRouter([]chttp.Route{
+		{
+			Path:    "/users",
+			Methods: []string{http.MethodGet, http.MethodPost},
+			Handler: func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(r.URL.Path)) },
+		},
+		{
+			Path:    "/docs/",
+			Methods: []string{http.MethodGet},
+			Handler: func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(r.URL.Path)) },
+		},
+	})}
+
+	handler := func(trailingSlash string, caseInsensitive bool) http.Handler {
+		return chttp.NewHandler(chttp.NewHandlerParams{
+			BasePath:             "/app",
+			TrailingSlash:        trailingSlash,
+			CaseInsensitivePaths: caseInsensitive,
+			Routers:              routers,
+			Logger:               clogger.NewNoop(),
+		})
+	}
+
+	testCases := map[string]struct {
+		handler      http.Handler
+		method       string
+		url          string
+		wantCode     int
+		wantLocation string
+		wantBody     string
+	}{
+		"strict":               {handler("", false), http.MethodGet, "/app/users/", 404, "", ""},
+		"redirect add":         {handler("redirect", false), http.MethodGet, "/app/docs?q=1", 301, "/app/docs/?q=1", ""},
+		"redirect remove":      {handler("redirect", false), http.MethodGet, "/app/users/", 301, "/app/users", ""},
+		"redirect post":        {handler("redirect", false), http.MethodPost, "/app/users/", 308, "/app/users", ""},
+		"redirect exact":       {handler("redirect", false), http.MethodGet, "/app/users", 200, "", "/users"},
+		"method mismatch":      {handler("redirect", false), http.MethodPost, "/app/docs/", 405, "", ""},
+		"rewrite":              {handler("rewrite", false), http.MethodGet, "/app/users/", 200, "", "/users"},
+		"case sensitive":       {handler("rewrite", false), http.MethodGet, "/app/Users", 404, "", ""},
+		"case insensitive":     {handler("", true), http.MethodGet, "/app/Users", 200, "", "/users"},
+		"case and slash":       {handler("rewrite", true), http.MethodGet, "/app/USERS/", 200, "", "/users"},
+		"case redirect":        {handler("redirect", true), http.MethodGet, "/app/Docs/", 301, "/app/docs/", ""},
+		"case insensitive 404": {handler("", true), http.MethodGet, "/app/Users/", 404, "", ""},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := httptest.NewRecorder()
+			tc.handler.ServeHTTP(resp, httptest.NewRequest(tc.method, "http://example.com"+tc.url, nil))
+
+			assert.Equal(t, tc.wantCode, resp.Code)
+			assert.Equal(t, tc.wantLocation, resp.Header().Get("Location"))
+
+			if tc.wantBody != "" {
+				assert.Equal(t, tc.wantBody, resp.Body.String())
+			}
+		})
+	}
+}
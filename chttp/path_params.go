@@ -0,0 +1,82 @@
+package chttp
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/gocopper/copper/cerrors"
+)
+
+// PathParamInt reads the path parameter with the given name as an int. If the parameter is not a valid int, a
+// BadRequest response is sent back and the function returns false.
+func (rw *ReaderWriter) PathParamInt(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	val, err := strconv.Atoi(URLParams(r)[name])
+	if err != nil {
+		rw.writePathParamError(w, r, cerrors.New(err, "path param must be an integer", map[string]interface{}{
+			"param": name,
+		}))
+
+		return 0, false
+	}
+
+	return val, true
+}
+
+// PathParamUUID reads the path parameter with the given name and verifies that it is a valid UUID. If it is not, a
+// BadRequest response is sent back and the function returns false.
+func (rw *ReaderWriter) PathParamUUID(w http.ResponseWriter, r *http.Request, name string) (string, bool) {
+	val := URLParams(r)[name]
+
+	if !govalidator.IsUUID(val) {
+		rw.writePathParamError(w, r, cerrors.New(nil, "path param must be a uuid", map[string]interface{}{
+			"param": name,
+		}))
+
+		return "", false
+	}
+
+	return val, true
+}
+
+// BindPathParams reads the path parameters into the dest struct using the 'param' struct tag. Fields can be strings,
+// bools, ints, uints, or floats. For example:
+//
+//	type params struct {
+//	  ID   int    `param:"id"`
+//	  Slug string `param:"slug" valid:"alphanum"`
+//	}
+//
+// If the dest struct has validate tags on it, the struct is also validated. If a param cannot be parsed or the
+// validation fails, a BadRequest response is sent back and the function returns false.
+func (rw *ReaderWriter) BindPathParams(w http.ResponseWriter, r *http.Request, dest interface{}) bool {
+	params := URLParams(r)
+
+	err := bindValues(dest, "param", func(name string) (string, bool) {
+		val, ok := params[name]
+		return val, ok
+	})
+	if err != nil {
+		rw.writePathParamError(w, r, cerrors.New(err, "invalid path params", nil))
+		return false
+	}
+
+	ok, err := govalidator.ValidateStruct(dest)
+	if !ok {
+		rw.writePathParamError(w, r, cerrors.New(err, "path params validation failed", nil))
+		return false
+	}
+
+	return true
+}
+
+func (rw *ReaderWriter) writePathParamError(w http.ResponseWriter, r *http.Request, err error) {
+	rw.logger.WithTags(map[string]interface{}{
+		"url": r.URL.String(),
+	}).Warn("Failed to read path params", err)
+
+	rw.WriteJSON(w, WriteJSONParams{
+		StatusCode: http.StatusBadRequest,
+		Data:       err,
+	})
+}
@@ -0,0 +1,101 @@
+package chttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReaderWriter_PathParamInt(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rw     = chttptest.NewReaderWriter(t)
+		id     int
+		router = chttptest.NewRouter([]chttp.Route{
+			{
+				Path: "/posts/{id}",
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					id, _ = rw.PathParamInt(w, r, "id")
+				},
+			},
+		})
+	)
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{router},
+		Logger:  clogger.NewNoop(),
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/posts/42") //nolint:noctx
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 42, id)
+
+	resp, err = http.Get(server.URL + "/posts/abc") //nolint:noctx
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestReaderWriter_BindPathParams(t *testing.T) {
+	t.Parallel()
+
+	type params struct {
+		ID   uint   `param:"id"`
+		UUID string `param:"uuid" valid:"uuid"`
+	}
+
+	var (
+		rw     = chttptest.NewReaderWriter(t)
+		bound  params
+		router = chttptest.NewRouter([]chttp.Route{
+			{
+				Path: "/posts/{id}/{uuid}",
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					_ = rw.BindPathParams(w, r, &bound)
+				},
+			},
+		})
+	)
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{router},
+		Logger:  clogger.NewNoop(),
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/posts/7/0b7a4ef0-3c2a-4b8e-9a0e-3f5cbb1f3b1e") //nolint:noctx
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, params{ID: 7, UUID: "0b7a4ef0-3c2a-4b8e-9a0e-3f5cbb1f3b1e"}, bound)
+
+	resp, err = http.Get(server.URL + "/posts/7/not-a-uuid") //nolint:noctx
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestReaderWriter_BindPathParams_NilDest(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rw   = chttptest.NewReaderWriter(t)
+		req  = httptest.NewRequest(http.MethodGet, "/posts/7", nil)
+		resp = httptest.NewRecorder()
+	)
+
+	assert.False(t, rw.BindPathParams(resp, req, nil))
+}
@@ -0,0 +1,110 @@
+package chttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/clogger"
+)
+
+type (
+	// NewProxyRouterParams holds the params needed to create a ProxyRouter.
+	NewProxyRouterParams struct {
+		Config Config
+		Logger clogger.Logger
+	}
+
+	// ProxyRouter provides routes that forward requests to other services using the reverse proxies configured in
+	// Config.Proxies. This is useful to gradually move routes from a legacy service to the app.
+	ProxyRouter struct {
+		routes []Route
+	}
+)
+
+// NewProxyRouter creates a new ProxyRouter.
+func NewProxyRouter(p NewProxyRouterParams) (*ProxyRouter, error) {
+	routes := make([]Route, 0, len(p.Config.Proxies)*2) //nolint:gomnd
+
+	for _, config := range p.Config.Proxies {
+		target, err := url.Parse(config.Target)
+		if err != nil || target.Scheme == "" || target.Host == "" {
+			return nil, cerrors.New(err, "invalid proxy target", map[string]interface{}{
+				"prefix": config.Prefix,
+				"target": config.Target,
+			})
+		}
+
+		prefix := "/" + strings.Trim(config.Prefix, "/")
+		handler := newProxyHandler(config, prefix, target, p.Logger)
+
+		routes = append(routes, Route{
+			Path:    prefix,
+			Handler: handler,
+		}, Route{
+			Path:    strings.TrimSuffix(prefix, "/") + "/{path:.*}",
+			Handler: handler,
+		})
+	}
+
+	return &ProxyRouter{routes: routes}, nil
+}
+
+// Routes defines the HTTP routes for this router.
+func (ro *ProxyRouter) Routes() []Route {
+	return ro.routes
+}
+
+func newProxyHandler(config ProxyConfig, prefix string, target *url.URL, logger clogger.Logger) http.HandlerFunc {
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	director := proxy.Director
+
+	proxy.Director = func(r *http.Request) {
+		if config.StripPrefix && prefix != "/" {
+			r.URL.Path = "/" + strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+			r.URL.RawPath = ""
+		}
+
+		director(r)
+
+		r.Host = target.Host
+
+		for _, h := range config.RemoveHeaders {
+			r.Header.Del(h)
+		}
+
+		for h, v := range config.SetHeaders {
+			r.Header.Set(h, v)
+		}
+	}
+
+	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		logger.WithTags(map[string]interface{}{
+			"prefix": prefix,
+			"target": target.String(),
+			"url":    r.URL.String(),
+		}).Error("Failed to proxy request", err)
+
+		if errors.Is(err, context.DeadlineExceeded) {
+			w.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+
+		w.WriteHeader(http.StatusBadGateway)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if config.Timeout > 0 {
+			ctx, cancel := context.WithTimeout(r.Context(), config.Timeout)
+			defer cancel()
+
+			r = r.WithContext(ctx)
+		}
+
+		proxy.ServeHTTP(w, r)
+	}
+}
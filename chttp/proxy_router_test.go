@@ -0,0 +1,80 @@
+package chttp_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxyRouter(t *testing.T) {
+	t.Parallel()
+
+	legacy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		_, _ = w.Write([]byte(r.Host + " " + r.URL.Path + " " + r.Header.Get("X-Service") + r.Header.Get("Cookie")))
+	}))
+	defer legacy.Close()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"base.toml": `
+[[chttp.proxies]]
+prefix = "/legacy"
+target = "` + legacy.URL + `"
+strip_prefix = true
+timeout = "50ms"
+set_headers = { "X-Service" = "legacy" }
+remove_headers = ["Cookie"]
+`,
+	})
+
+	configs, err := cconfig.New(cconfig.Path(path.Join(dir, "base.toml")))
+	assert.NoError(t, err)
+
+	config, err := chttp.LoadConfig(configs)
+	assert.NoError(t, err)
+
+	router, err := chttp.NewProxyRouter(chttp.NewProxyRouterParams{
+		Config: config,
+		Logger: clogger.NewNoop(),
+	})
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{router},
+		Logger:  clogger.NewNoop(),
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/legacy/users/1", nil) //nolint:noctx
+	assert.NoError(t, err)
+
+	req.Header.Set("Cookie", "session=1")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, legacy.Listener.Addr().String()+" /users/1 legacy", string(body))
+
+	resp, err = http.Get(server.URL + "/legacy/slow") //nolint:noctx
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+}
@@ -4,8 +4,10 @@ import (
 	// Used to embed error.html
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"html/template"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -15,19 +17,39 @@ import (
 )
 
 type (
-	// WriteHTMLParams holds the params for the WriteHTML function in ReaderWriter
+	// WriteHTMLParams holds the params for the WriteHTML function in ReaderWriter.
+	// If ETag is true, a strong ETag is computed from the rendered page. If LastModified is set, it is sent in the
+	// Last-Modified header. Either one enables NotModified responses to conditional GET requests.
 	WriteHTMLParams struct {
 		StatusCode     int
 		Error          error
 		Data           interface{}
 		PageTemplate   string
 		LayoutTemplate string
+		ETag           bool
+		LastModified   time.Time
 	}
 
-	// WriteJSONParams holds the params for the WriteJSON function in ReaderWriter
+	// WriteJSONParams holds the params for the WriteJSON function in ReaderWriter.
+	// ETag and LastModified work like they do in WriteHTMLParams. Since WriteJSON does not take the request, Request
+	// must be set for conditional requests to be answered with NotModified.
 	WriteJSONParams struct {
-		StatusCode int
-		Data       interface{}
+		StatusCode   int
+		Data         interface{}
+		Request      *http.Request
+		ETag         bool
+		LastModified time.Time
+	}
+
+	// WriteProblemParams holds the params for the WriteProblem function in ReaderWriter. The fields are defined by
+	// RFC 7807. Extensions are written as additional top-level members of the problem object.
+	WriteProblemParams struct {
+		Type       string
+		Title      string
+		Status     int
+		Detail     string
+		Instance   string
+		Extensions map[string]interface{}
 	}
 
 	// ReaderWriter provides functions to read data from HTTP requests and write response bodies in various formats
@@ -56,45 +78,135 @@ func NewReaderWriter(html *HTMLRenderer, config Config, logger clogger.Logger) *
 // WriteJSON writes a JSON response to the http.ResponseWriter. It can be configured with status code and data using
 // WriteJSONParams.
 func (rw *ReaderWriter) WriteJSON(w http.ResponseWriter, p WriteJSONParams) {
-	if p.StatusCode > 0 {
-		w.WriteHeader(p.StatusCode)
-	}
-
 	if p.Data == nil {
+		if p.StatusCode > 0 {
+			w.WriteHeader(p.StatusCode)
+		}
+
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-
 	errData, ok := p.Data.(error)
 	if ok {
-		err := json.NewEncoder(w).Encode(map[string]string{
+		p.Data = map[string]string{
 			"error": errData.Error(),
-		})
+		}
+	} else {
+		data, err := runResponseHooks(p.Request, p.Data)
 		if err != nil {
-			rw.logger.Error("Failed to marshal error response as json", err)
+			rw.logger.Error("Failed to run response hooks", err)
 			w.WriteHeader(http.StatusInternalServerError)
+
+			return
 		}
 
-		return
+		p.Data = data
 	}
 
-	err := json.NewEncoder(w).Encode(p.Data)
+	rw.writeJSON(w, "application/json", p.StatusCode, p.Data, validators{
+		r:            p.Request,
+		etag:         p.ETag,
+		lastModified: p.LastModified,
+	})
+}
+
+// WriteProblem writes an RFC 7807 problem details response with the application/problem+json content type. If the
+// status is not set, it defaults to InternalServerError.
+func (rw *ReaderWriter) WriteProblem(w http.ResponseWriter, p WriteProblemParams) {
+	if p.Status == 0 {
+		p.Status = http.StatusInternalServerError
+	}
+
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	problem := make(map[string]interface{}, len(p.Extensions)+5) //nolint:gomnd
+
+	for k, v := range p.Extensions {
+		problem[k] = v
+	}
+
+	problem["type"] = p.Type
+	problem["title"] = p.Title
+	problem["status"] = p.Status
+
+	if p.Detail != "" {
+		problem["detail"] = p.Detail
+	}
+
+	if p.Instance != "" {
+		problem["instance"] = p.Instance
+	}
+
+	rw.writeJSON(w, "application/problem+json", p.Status, problem, validators{})
+}
+
+// WriteCreated writes a Created response with the Location header set to the given location. If data is not nil, it
+// is written as the JSON body.
+func (rw *ReaderWriter) WriteCreated(w http.ResponseWriter, location string, data interface{}) {
+	w.Header().Set("Location", location)
+
+	rw.WriteJSON(w, WriteJSONParams{
+		StatusCode: http.StatusCreated,
+		Data:       data,
+	})
+}
+
+// Redirect redirects the request to the given url. If the status code is not set, it defaults to SeeOther so that
+// form submissions are redirected with a GET request. Absolute paths (ex. /login) are prefixed with the app's base
+// path.
+func (rw *ReaderWriter) Redirect(w http.ResponseWriter, r *http.Request, url string, statusCode int) {
+	if statusCode == 0 {
+		statusCode = http.StatusSeeOther
+	}
+
+	http.Redirect(w, r, WithBasePath(r.Context(), url), statusCode)
+}
+
+func (rw *ReaderWriter) writeJSON(w http.ResponseWriter, contentType string, statusCode int, data interface{},
+	v validators) {
+	out, err := json.Marshal(data)
 	if err != nil {
 		rw.logger.Error("Failed to marshal response as json", err)
 		w.WriteHeader(http.StatusInternalServerError)
 
 		return
 	}
+
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	out = append(out, '\n')
+
+	if v.writeNotModified(w, statusCode, out) {
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+
+	_, _ = w.Write(out)
 }
 
 // ReadJSON reads JSON from the http.Request into the body var. If the body struct has validate tags on it, the
-// struct is also validated. If the validation fails, a BadRequest response is sent back and the function returns
-// false.
+// struct is also validated. If the body is not valid JSON, a BadRequest response is sent back. If the validation
+// fails, an UnprocessableEntity response is sent back with the errors for each field. In both cases, the function
+// returns false.
 func (rw *ReaderWriter) ReadJSON(w http.ResponseWriter, req *http.Request, body interface{}) bool {
 	url := req.URL.String()
 
 	err := json.NewDecoder(req.Body).Decode(body)
+	if err != nil && isBodyTooLarge(err) {
+		rw.writeBodyTooLarge(w, req, err)
+		return false
+	}
+
 	if err != nil {
 		rw.logger.Warn("Failed to read body", cerrors.New(err, "invalid json", map[string]interface{}{
 			"url": url,
@@ -108,9 +220,53 @@ func (rw *ReaderWriter) ReadJSON(w http.ResponseWriter, req *http.Request, body
 		return false
 	}
 
-	ok, err := govalidator.ValidateStruct(body)
-	if !ok {
-		rw.logger.Warn("Failed to read body", cerrors.New(err, "data validation failed", map[string]interface{}{
+	return rw.runRequestHooks(w, req, body) && rw.validateBody(w, req, body)
+}
+
+// ReadForm reads the URL-encoded or multipart form values from the http.Request into the body var using the 'form'
+// struct tag. Fields can be strings, bools, ints, uints, or floats. For example:
+//
+//	type body struct {
+//	  Email string `form:"email" valid:"email"`
+//	  Age   int    `form:"age"`
+//	}
+//
+// If a value cannot be parsed, a BadRequest response is sent back. Like ReadJSON, the body struct is validated and
+// an UnprocessableEntity response is sent back if the validation fails. In both cases, the function returns false.
+func (rw *ReaderWriter) ReadForm(w http.ResponseWriter, req *http.Request, body interface{}) bool {
+	const maxMultipartMemory = 32 << 20
+
+	url := req.URL.String()
+
+	err := req.ParseMultipartForm(maxMultipartMemory)
+	if err != nil && isBodyTooLarge(err) {
+		rw.writeBodyTooLarge(w, req, err)
+		return false
+	}
+
+	if err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		rw.logger.Warn("Failed to read body", cerrors.New(err, "invalid form", map[string]interface{}{
+			"url": url,
+		}))
+
+		rw.WriteJSON(w, WriteJSONParams{
+			StatusCode: http.StatusBadRequest,
+			Data:       err,
+		})
+
+		return false
+	}
+
+	err = bindValues(body, "form", func(name string) (string, bool) {
+		vals, ok := req.Form[name]
+		if !ok || len(vals) == 0 {
+			return "", false
+		}
+
+		return vals[0], true
+	})
+	if err != nil {
+		rw.logger.Warn("Failed to read body", cerrors.New(err, "invalid form values", map[string]interface{}{
 			"url": url,
 		}))
 
@@ -122,11 +278,59 @@ func (rw *ReaderWriter) ReadJSON(w http.ResponseWriter, req *http.Request, body
 		return false
 	}
 
-	return true
+	return rw.runRequestHooks(w, req, body) && rw.validateBody(w, req, body)
+}
+
+func (rw *ReaderWriter) runRequestHooks(w http.ResponseWriter, req *http.Request, body interface{}) bool {
+	err := runRequestHooks(req, body)
+	if err == nil {
+		return true
+	}
+
+	rw.logger.Warn("Failed to read body", cerrors.New(err, "request hook failed", map[string]interface{}{
+		"url": req.URL.String(),
+	}))
+
+	rw.WriteJSON(w, WriteJSONParams{
+		StatusCode: http.StatusBadRequest,
+		Data:       err,
+	})
+
+	return false
+}
+
+func (rw *ReaderWriter) writeBodyTooLarge(w http.ResponseWriter, req *http.Request, err error) {
+	rw.logger.Warn("Failed to read body", cerrors.New(err, "request body too large", map[string]interface{}{
+		"url": req.URL.String(),
+	}))
+
+	writeBodyTooLarge(w)
+}
+
+func (rw *ReaderWriter) validateBody(w http.ResponseWriter, req *http.Request, body interface{}) bool {
+	ok, err := govalidator.ValidateStruct(body)
+	if ok {
+		return true
+	}
+
+	rw.logger.Warn("Failed to read body", cerrors.New(err, "data validation failed", map[string]interface{}{
+		"url": req.URL.String(),
+	}))
+
+	rw.WriteJSON(w, WriteJSONParams{
+		StatusCode: http.StatusUnprocessableEntity,
+		Data: map[string]interface{}{
+			"error":  "validation failed",
+			"fields": govalidator.ErrorsByField(err),
+		},
+	})
+
+	return false
 }
 
-// WriteHTMLError handles the given error. In render_error is configured to true, it writes an HTML page with the error.
-// Errors are always logged.
+// WriteHTMLError handles the given error. If render_html_error is configured to true, it writes a debug page with the
+// error chain, request, stack trace, and the template source (for template errors). Otherwise, the internal-error.html
+// page is rendered. Errors are always logged.
 func (rw *ReaderWriter) WriteHTMLError(w http.ResponseWriter, r *http.Request, err error) {
 	rw.WriteHTML(w, r, WriteHTMLParams{
 		Error: err,
@@ -162,30 +366,52 @@ func (rw *ReaderWriter) WriteHTML(w http.ResponseWriter, r *http.Request, p Writ
 		}).Error("Failed to handle request", p.Error)
 	}
 
-	if p.Error != nil && rw.config.RenderHTMLError {
-		w.WriteHeader(p.StatusCode)
-		w.Header().Set("content-type", "text/html")
-
-		errorHTMLTmpl := template.Must(template.New("chtml/error.html").Parse(errorHTML))
-
-		_ = errorHTMLTmpl.Execute(w, map[string]interface{}{
-			"Error": p.Error.Error(),
-		})
-
+	if p.Error != nil && rw.canRenderErrorDebug(r) {
+		rw.writeErrorDebugPage(w, r, p.StatusCode, p.Error)
 		return
 	}
 
 	out, err := rw.html.render(r, p.LayoutTemplate, p.PageTemplate, p.Data)
 	if err != nil {
-		rw.logger.Error("Failed to render html template", cerrors.WithTags(err, map[string]interface{}{
+		err = cerrors.New(err, "failed to render html template", map[string]interface{}{
 			"layout": p.LayoutTemplate,
 			"page":   p.PageTemplate,
-		}))
+		})
+
+		rw.logger.Error("Failed to render html template", err)
+
+		if rw.canRenderErrorDebug(r) {
+			rw.writeErrorDebugPage(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
 		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	if rw.config.DeterministicHTML {
+		normalized, err := normalizeHTML(string(out))
+		if err != nil {
+			rw.logger.Warn("Failed to normalize html", cerrors.New(err, "failed to normalize html", map[string]interface{}{
+				"layout": p.LayoutTemplate,
+				"page":   p.PageTemplate,
+			}))
+		} else {
+			out = template.HTML(normalized) //nolint:gosec
+		}
+	}
+
+	var (
+		body = []byte(out)
+		v    = validators{r: r, etag: p.ETag, lastModified: p.LastModified}
+	)
+
+	if v.writeNotModified(w, p.StatusCode, body) {
 		return
 	}
 
-	w.WriteHeader(p.StatusCode)
 	w.Header().Set("content-type", "text/html")
-	_, _ = w.Write([]byte(out))
+	w.WriteHeader(p.StatusCode)
+	_, _ = w.Write(body)
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gocopper/copper/chttp/chttptest"
@@ -59,13 +60,56 @@ func TestReaderWriter_ReadJSON_Validator(t *testing.T) {
 
 	rw := chttptest.NewReaderWriter(t)
 
+	resp := httptest.NewRecorder()
+
 	ok := rw.ReadJSON(
-		httptest.NewRecorder(),
+		resp,
 		httptest.NewRequest(http.MethodGet, "/", bytes.NewReader([]byte(`{"key": "value"}`))),
 		&body,
 	)
 
 	assert.False(t, ok)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"fields":{"key":"value does not validate as email"}`)
+}
+
+func TestReaderWriter_ReadForm(t *testing.T) {
+	t.Parallel()
+
+	var body struct {
+		Email string `form:"email" valid:"email"`
+		Age   int    `form:"age"`
+	}
+
+	rw := chttptest.NewReaderWriter(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("email=test@example.com&age=30"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	ok := rw.ReadForm(httptest.NewRecorder(), req, &body)
+
+	assert.True(t, ok)
+	assert.Equal(t, "test@example.com", body.Email)
+	assert.Equal(t, 30, body.Age)
+}
+
+func TestReaderWriter_ReadForm_Invalid(t *testing.T) {
+	t.Parallel()
+
+	var body struct {
+		Age int `form:"age"`
+	}
+
+	rw := chttptest.NewReaderWriter(t)
+	resp := httptest.NewRecorder()
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("age=thirty"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	ok := rw.ReadForm(resp, req, &body)
+
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
 }
 
 func TestReaderWriter_WriteJSON_Data(t *testing.T) {
@@ -119,6 +163,56 @@ func TestReaderWriter_WriteJSON_Error(t *testing.T) {
 	assert.Contains(t, resp.Body.String(), `{"error":"test-err"}`)
 }
 
+func TestReaderWriter_WriteProblem(t *testing.T) {
+	t.Parallel()
+
+	rw := chttptest.NewReaderWriter(t)
+	resp := httptest.NewRecorder()
+
+	rw.WriteProblem(resp, chttp.WriteProblemParams{
+		Status: http.StatusConflict,
+		Detail: "email is already taken",
+		Extensions: map[string]interface{}{
+			"field": "email",
+		},
+	})
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Equal(t, "application/problem+json", resp.Header().Get("content-type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Conflict",
+		"status": 409,
+		"detail": "email is already taken",
+		"field": "email"
+	}`, resp.Body.String())
+}
+
+func TestReaderWriter_WriteCreated(t *testing.T) {
+	t.Parallel()
+
+	rw := chttptest.NewReaderWriter(t)
+	resp := httptest.NewRecorder()
+
+	rw.WriteCreated(resp, "/posts/1", map[string]int{"id": 1})
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, "/posts/1", resp.Header().Get("location"))
+	assert.Contains(t, resp.Body.String(), `{"id":1}`)
+}
+
+func TestReaderWriter_Redirect(t *testing.T) {
+	t.Parallel()
+
+	rw := chttptest.NewReaderWriter(t)
+	resp := httptest.NewRecorder()
+
+	rw.Redirect(resp, httptest.NewRequest(http.MethodPost, "/posts", nil), "/posts/1", 0)
+
+	assert.Equal(t, http.StatusSeeOther, resp.Code)
+	assert.Equal(t, "/posts/1", resp.Header().Get("location"))
+}
+
 func TestReaderWriter_WriteHTML(t *testing.T) {
 	t.Parallel()
 
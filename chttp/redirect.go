@@ -0,0 +1,117 @@
+package chttp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gocopper/copper/clogger"
+)
+
+// RedirectRule redirects requests for the From path to another path, URL, or named route. For example:
+//
+//	[[chttp.redirects]]
+//	from = "/pricing-2020"
+//	to = "/pricing"
+//
+//	[[chttp.redirects]]
+//	from = "/blog/*"
+//	to = "https://blog.example.com/*"
+//	host = "www.example.com"
+//	status_code = 302
+type RedirectRule struct {
+	// From is the request path to redirect. If it ends with "*", it matches any path with the given prefix, and the
+	// rest of the path replaces the "*" in To.
+	From string `toml:"from"`
+
+	// To is the path or URL to redirect to. Absolute paths are prefixed with the app's base path.
+	To string `toml:"to"`
+
+	// Route redirects to the named route (see Route.Name) instead of To. The route must not have any vars.
+	Route string `toml:"route"`
+
+	// StatusCode defaults to MovedPermanently.
+	StatusCode int `toml:"status_code"`
+
+	// Host limits the rule to requests for the given host.
+	Host string `toml:"host"`
+}
+
+// redirectMiddleware applies the redirect rules before the request is routed so that paths without a route can be
+// redirected. Rules are checked in order and the first match wins. Query strings are kept unless the target has
+// its own.
+func redirectMiddleware(rules []RedirectRule, table *routeTable, logger clogger.Logger) Middleware {
+	mw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, rule := range rules {
+				target, ok := rule.match(r)
+				if !ok {
+					continue
+				}
+
+				ctx := context.WithValue(r.Context(), ctxRouteTableKey, table)
+
+				if rule.Route != "" {
+					u, err := URL(r.WithContext(ctx), rule.Route, nil)
+					if err != nil {
+						logger.WithTags(map[string]interface{}{
+							"from":  rule.From,
+							"route": rule.Route,
+						}).Warn("Failed to generate redirect url", err)
+
+						break
+					}
+
+					target = u
+				} else {
+					target = WithBasePath(ctx, target)
+				}
+
+				if r.URL.RawQuery != "" && !strings.Contains(target, "?") {
+					target += "?" + r.URL.RawQuery
+				}
+
+				statusCode := rule.StatusCode
+				if statusCode == 0 {
+					statusCode = http.StatusMovedPermanently
+				}
+
+				http.Redirect(w, r, target, statusCode)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	return HandleMiddleware(mw)
+}
+
+// match returns the target path if the rule matches the request.
+func (rule RedirectRule) match(r *http.Request) (string, bool) {
+	if rule.Host != "" && !strings.EqualFold(requestHostname(r), rule.Host) {
+		return "", false
+	}
+
+	prefix := strings.TrimSuffix(rule.From, "*")
+	if prefix == rule.From {
+		return rule.To, r.URL.Path == rule.From
+	}
+
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		return "", false
+	}
+
+	return strings.Replace(rule.To, "*", strings.TrimPrefix(r.URL.Path, prefix), 1), true
+}
+
+func requestHostname(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		return r.Host
+	}
+
+	return host
+}
@@ -0,0 +1,60 @@
+package chttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHandler_Redirects(t *testing.T) {
+	t.Parallel()
+
+	handler := chttp.NewHandler(chttp.NewHandlerParams{
+		BasePath: "/app",
+		Redirects: []chttp.RedirectRule{
+			{From: "/pricing-2020", To: "/pricing"},
+			{From: "/about-us", Route: "about", StatusCode: http.StatusFound},
+			{From: "/blog/*", To: "https://blog.example.com/*", Host: "www.example.com"},
+		},
+		Routers: []chttp.Router{chttptest.NewRouter([]chttp.Route{
+			{
+				Name:    "about",
+				Path:    "/about",
+				Handler: func(w http.ResponseWriter, r *http.Request) {},
+			},
+		})},
+		Logger: clogger.NewNoop(),
+	})
+
+	testCases := map[string]struct {
+		url          string
+		wantCode     int
+		wantLocation string
+	}{
+		"path":              {"http://example.com/app/pricing-2020?ref=ad", 301, "/app/pricing?ref=ad"},
+		"route":             {"http://example.com/app/about-us", 302, "/app/about"},
+		"prefix":            {"http://www.example.com/app/blog/2021/hello", 301, "https://blog.example.com/2021/hello"},
+		"host mismatch":     {"http://example.com/app/blog/2021/hello", 404, ""},
+		"no match":          {"http://example.com/app/about", 200, ""},
+		"outside base path": {"http://example.com/pricing-2020", 404, ""},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			assert.Equal(t, tc.wantCode, resp.Code)
+			assert.Equal(t, tc.wantLocation, resp.Header().Get("Location"))
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package chttp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/gocopper/copper/clogger"
+)
+
+// RequestIDHeader is the header used to read and echo the request id.
+const RequestIDHeader = "X-Request-ID"
+
+type ctxRequestID string
+
+const ctxRequestIDKey = ctxRequestID("chttp/request-id")
+
+// NewRequestIDMiddleware creates a new RequestIDMiddleware.
+func NewRequestIDMiddleware() *RequestIDMiddleware {
+	return &RequestIDMiddleware{}
+}
+
+// RequestIDMiddleware assigns an id to each request so that logs across the app can be correlated. The id is read
+// from the X-Request-ID header (ex. when set by a load balancer) or generated if it is missing or invalid. It is
+// stored in the request context, added to the request's logger (see clogger.FromCtx), and echoed in the response
+// headers.
+type RequestIDMiddleware struct{}
+
+// Handle sets the request id in the request context and the response headers.
+func (mw *RequestIDMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !isValidRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+
+		ctx := context.WithValue(r.Context(), ctxRequestIDKey, id)
+		ctx = clogger.WithCtxTags(ctx, map[string]interface{}{
+			"requestID": id,
+		})
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestID returns the id of the request that the given context belongs to. It returns an empty string if the
+// RequestIDMiddleware has not run for the request.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(ctxRequestIDKey).(string)
+	return id
+}
+
+func newRequestID() string {
+	const idBytes = 16
+
+	b := make([]byte, idBytes)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// isValidRequestID verifies that an incoming request id is reasonably sized and only has printable ASCII characters
+// so that it is safe to log and echo back.
+func isValidRequestID(id string) bool {
+	const maxLen = 128
+
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
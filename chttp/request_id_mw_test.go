@@ -0,0 +1,97 @@
+package chttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logs      = make([]clogger.RecordedLog, 0)
+		handlerID string
+		router    = chttptest.NewRouter([]chttp.Route{
+			{
+				Path: "/",
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					handlerID = chttp.RequestID(r.Context())
+				},
+			},
+		})
+	)
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{router},
+		GlobalMiddlewares: []chttp.Middleware{
+			chttp.NewRequestLoggerMiddleware(clogger.NewRecorder(&logs)),
+			chttp.NewRequestIDMiddleware(),
+		},
+		Logger: clogger.NewNoop(),
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL) //nolint:noctx
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Len(t, handlerID, 32)
+	assert.Equal(t, handlerID, resp.Header.Get(chttp.RequestIDHeader))
+	assert.Equal(t, handlerID, logs[0].Tags["requestID"])
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil) //nolint:noctx
+	assert.NoError(t, err)
+
+	req.Header.Set(chttp.RequestIDHeader, "lb-request-id")
+
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, "lb-request-id", handlerID)
+	assert.Equal(t, "lb-request-id", resp.Header.Get(chttp.RequestIDHeader))
+}
+
+func TestRequestIDMiddleware_CtxLogger(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logs   = make([]clogger.RecordedLog, 0)
+		router = chttptest.NewRouter([]chttp.Route{
+			{
+				Path: "/users/{id}",
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					clogger.FromCtx(r.Context()).Info("Loaded user")
+				},
+			},
+		})
+	)
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers:           []chttp.Router{router},
+		GlobalMiddlewares: []chttp.Middleware{chttp.NewRequestIDMiddleware()},
+		Logger:            clogger.NewRecorder(&logs),
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/users/1", nil) //nolint:noctx
+	assert.NoError(t, err)
+
+	req.Header.Set(chttp.RequestIDHeader, "lb-request-id")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Len(t, logs, 1)
+	assert.Equal(t, map[string]interface{}{
+		"route":     "/users/{id}",
+		"requestID": "lb-request-id",
+	}, logs[0].Tags)
+}
@@ -6,21 +6,35 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"strings"
 
 	"github.com/gocopper/copper/clogger"
 )
 
 var errRWIsNotHijacker = errors.New("internal response writer is not http.Hijacker")
 
-// NewRequestLoggerMiddleware creates a new RequestLoggerMiddleware.
+// NewRequestLoggerMiddleware creates a new RequestLoggerMiddleware that does not warn about oversized responses.
 func NewRequestLoggerMiddleware(logger clogger.Logger) *RequestLoggerMiddleware {
-	return &RequestLoggerMiddleware{logger: logger}
+	return NewRequestLoggerMiddlewareWithConfig(Config{}, logger)
 }
 
-// RequestLoggerMiddleware logs each request's HTTP method, path, and status code along with user uuid
-// (from basic auth) if any.
+// NewRequestLoggerMiddlewareWithConfig creates a new RequestLoggerMiddleware that warns about the responses that are
+// larger than Config.WarnHTMLResponseBytes and Config.WarnJSONResponseBytes.
+func NewRequestLoggerMiddlewareWithConfig(config Config, logger clogger.Logger) *RequestLoggerMiddleware {
+	return &RequestLoggerMiddleware{
+		warnHTMLBytes: config.WarnHTMLResponseBytes,
+		warnJSONBytes: config.WarnJSONResponseBytes,
+		logger:        clogger.Named(logger, "chttp"),
+	}
+}
+
+// RequestLoggerMiddleware logs each request's HTTP method, path, status code, response size, and client IP (see
+// ClientIP) along with user uuid (from basic auth) if any. It can also log a warning for HTML and JSON responses that
+// are larger than Config.WarnHTMLResponseBytes and Config.WarnJSONResponseBytes.
 type RequestLoggerMiddleware struct {
-	logger clogger.Logger
+	warnHTMLBytes int
+	warnJSONBytes int
+	logger        clogger.Logger
 }
 
 // Handle wraps the current request with a request/response recorder. It records the method path and the
@@ -36,6 +50,7 @@ func (mw *RequestLoggerMiddleware) Handle(next http.Handler) http.Handler {
 			tags = map[string]interface{}{
 				"method": r.Method,
 				"url":    r.URL.Path,
+				"ip":     ClientIP(r),
 			}
 		)
 
@@ -47,14 +62,55 @@ func (mw *RequestLoggerMiddleware) Handle(next http.Handler) http.Handler {
 		next.ServeHTTP(&loggerRw, r)
 
 		tags["statusCode"] = loggerRw.statusCode
+		tags["bytes"] = loggerRw.bytes
+
+		// The request id may be set by a middleware that runs after this one, in which case it is only available
+		// in the response headers.
+		if id := RequestID(r.Context()); id != "" {
+			tags["requestID"] = id
+		} else if id := loggerRw.Header().Get(RequestIDHeader); id != "" {
+			tags["requestID"] = id
+		}
 
 		mw.logger.WithTags(tags).Info(fmt.Sprintf("%s %s %d", r.Method, r.URL.Path, loggerRw.statusCode))
+
+		mw.warnIfOversized(r, &loggerRw)
 	})
 }
 
+func (mw *RequestLoggerMiddleware) warnIfOversized(r *http.Request, rw *requestLoggerRw) {
+	var (
+		contentType = strings.ToLower(rw.Header().Get("Content-Type"))
+		limit       int
+	)
+
+	switch {
+	case strings.HasPrefix(contentType, "text/html"):
+		limit = mw.warnHTMLBytes
+	case strings.HasPrefix(contentType, "application/json"), strings.Contains(contentType, "+json"):
+		limit = mw.warnJSONBytes
+	}
+
+	if limit <= 0 || rw.bytes <= limit {
+		return
+	}
+
+	route, _ := r.Context().Value(ctxRoutePathKey).(string)
+
+	mw.logger.WithTags(map[string]interface{}{
+		"method":      r.Method,
+		"url":         r.URL.Path,
+		"route":       route,
+		"contentType": contentType,
+		"bytes":       rw.bytes,
+		"limit":       limit,
+	}).Warn("Response is larger than expected", nil)
+}
+
 type requestLoggerRw struct {
 	internal   http.ResponseWriter
 	statusCode int
+	bytes      int
 }
 
 func (rw *requestLoggerRw) Hijack() (net.Conn, *bufio.ReadWriter, error) {
@@ -66,12 +122,21 @@ func (rw *requestLoggerRw) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return h.Hijack()
 }
 
+func (rw *requestLoggerRw) Flush() {
+	if f, ok := rw.internal.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 func (rw *requestLoggerRw) Header() http.Header {
 	return rw.internal.Header()
 }
 
 func (rw *requestLoggerRw) Write(b []byte) (int, error) {
-	return rw.internal.Write(b)
+	n, err := rw.internal.Write(b)
+	rw.bytes += n
+
+	return n, err
 }
 
 func (rw *requestLoggerRw) WriteHeader(statusCode int) {
@@ -54,3 +54,43 @@ func TestNewRequestLoggerMiddleware(t *testing.T) {
 	assert.Equal(t, clogger.LevelInfo, logs[0].Level)
 	assert.Equal(t, "GET /test 201", logs[0].Msg)
 }
+
+func TestRequestLoggerMiddleware_OversizedResponse(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logs   = make([]clogger.RecordedLog, 0)
+		logger = clogger.NewRecorder(&logs)
+		rw     = chttptest.NewReaderWriter(t)
+		router = chttptest.NewRouter([]chttp.Route{
+			{
+				Path:    "/posts",
+				Methods: []string{http.MethodGet},
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					rw.WriteJSON(w, chttp.WriteJSONParams{Data: []string{"post-1", "post-2"}})
+				},
+			},
+		})
+		handler = chttp.NewHandler(chttp.NewHandlerParams{
+			Routers: []chttp.Router{router},
+			GlobalMiddlewares: []chttp.Middleware{
+				chttp.NewRequestLoggerMiddlewareWithConfig(chttp.Config{WarnJSONResponseBytes: 10}, logger),
+			},
+			Logger: clogger.NewNoop(),
+		})
+	)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/posts") //nolint:noctx
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, 2, len(logs))
+	assert.Equal(t, 20, logs[0].Tags["bytes"])
+	assert.Equal(t, clogger.LevelWarn, logs[1].Level)
+	assert.Equal(t, "/posts", logs[1].Tags["route"])
+	assert.Equal(t, 20, logs[1].Tags["bytes"])
+	assert.Equal(t, 10, logs[1].Tags["limit"])
+}
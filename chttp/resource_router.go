@@ -0,0 +1,248 @@
+package chttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+// ErrResourceNotFound can be returned by a ResourceRepository when an item does not exist. It is written as a
+// NotFound response.
+var ErrResourceNotFound = errors.New("resource not found")
+
+type (
+	// ResourceRepository is the storage used by ResourceRouter for a single type of resource.
+	ResourceRepository interface {
+		// New returns a pointer to a new, empty item that request bodies are read into.
+		New() interface{}
+
+		// List returns the items that match the given filters (from the request's query params).
+		List(ctx context.Context, filters url.Values) (interface{}, error)
+
+		// Get returns the item with the given id or ErrResourceNotFound.
+		Get(ctx context.Context, id string) (interface{}, error)
+
+		// Create saves a new item and returns its id.
+		Create(ctx context.Context, item interface{}) (string, error)
+
+		// Update replaces the item with the given id or returns ErrResourceNotFound.
+		Update(ctx context.Context, id string, item interface{}) error
+
+		// Delete removes the item with the given id or returns ErrResourceNotFound.
+		Delete(ctx context.Context, id string) error
+	}
+
+	// ResourceHandlers can be used to override the handler of any of the routes registered by ResourceRouter. Nil
+	// handlers use the default implementation.
+	ResourceHandlers struct {
+		List   http.HandlerFunc
+		Get    http.HandlerFunc
+		Create http.HandlerFunc
+		Update http.HandlerFunc
+		Delete http.HandlerFunc
+	}
+
+	// NewResourceRouterParams holds the params needed to create a ResourceRouter.
+	NewResourceRouterParams struct {
+		// Path is the collection path (ex. /api/posts). Items are served at Path/{id}.
+		Path       string
+		Repository ResourceRepository
+		RW         *ReaderWriter
+
+		// Middlewares and Requires are applied to every route of the resource.
+		Middlewares []Middleware
+		Requires    []string
+
+		// ListPageTemplate and GetPageTemplate enable HTML views. If they are set, requests that accept text/html
+		// are rendered with the given page templates and the item(s) as data.
+		ListPageTemplate string
+		GetPageTemplate  string
+
+		Handlers ResourceHandlers
+	}
+
+	// ResourceRouter registers the standard REST routes for a resource backed by a ResourceRepository:
+	//
+	//	GET    /path       list items, filtered by the query params
+	//	POST   /path       create an item
+	//	GET    /path/{id}  get an item
+	//	PUT    /path/{id}  update an item
+	//	PATCH  /path/{id}  update an item
+	//	DELETE /path/{id}  delete an item
+	//
+	// Request bodies are read with ReaderWriter.ReadJSON so they are validated like any other JSON body.
+	ResourceRouter struct {
+		path     string
+		repo     ResourceRepository
+		rw       *ReaderWriter
+		mws      []Middleware
+		requires []string
+		listPage string
+		getPage  string
+		handlers ResourceHandlers
+	}
+)
+
+// NewResourceRouter creates a new ResourceRouter.
+func NewResourceRouter(p NewResourceRouterParams) *ResourceRouter {
+	return &ResourceRouter{
+		path:     strings.TrimSuffix(p.Path, "/"),
+		repo:     p.Repository,
+		rw:       p.RW,
+		mws:      p.Middlewares,
+		requires: p.Requires,
+		listPage: p.ListPageTemplate,
+		getPage:  p.GetPageTemplate,
+		handlers: p.Handlers,
+	}
+}
+
+// Routes defines the HTTP routes for the resource.
+func (ro *ResourceRouter) Routes() []Route {
+	itemPath := ro.path + "/{id}"
+
+	return []Route{
+		ro.route(ro.path, http.MethodGet, ro.handlers.List, ro.HandleList),
+		ro.route(ro.path, http.MethodPost, ro.handlers.Create, ro.HandleCreate),
+		ro.route(itemPath, http.MethodGet, ro.handlers.Get, ro.HandleGet),
+		ro.route(itemPath, http.MethodPut, ro.handlers.Update, ro.HandleUpdate),
+		ro.route(itemPath, http.MethodPatch, ro.handlers.Update, ro.HandleUpdate),
+		ro.route(itemPath, http.MethodDelete, ro.handlers.Delete, ro.HandleDelete),
+	}
+}
+
+// HandleList lists the items that match the request's query params.
+func (ro *ResourceRouter) HandleList(w http.ResponseWriter, r *http.Request) {
+	items, err := ro.repo.List(r.Context(), r.URL.Query())
+	if err != nil {
+		ro.writeError(w, r, cerrors.New(err, "failed to list items", nil))
+		return
+	}
+
+	ro.write(w, r, ro.listPage, items)
+}
+
+// HandleGet gets the item with the id in the path.
+func (ro *ResourceRouter) HandleGet(w http.ResponseWriter, r *http.Request) {
+	id := URLParams(r)["id"]
+
+	item, err := ro.repo.Get(r.Context(), id)
+	if err != nil {
+		ro.writeError(w, r, cerrors.New(err, "failed to get item", map[string]interface{}{
+			"id": id,
+		}))
+
+		return
+	}
+
+	ro.write(w, r, ro.getPage, item)
+}
+
+// HandleCreate creates an item from the JSON body.
+func (ro *ResourceRouter) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	item := ro.repo.New()
+
+	if !ro.rw.ReadJSON(w, r, item) {
+		return
+	}
+
+	id, err := ro.repo.Create(r.Context(), item)
+	if err != nil {
+		ro.writeError(w, r, cerrors.New(err, "failed to create item", nil))
+		return
+	}
+
+	ro.rw.WriteCreated(w, path.Join(ro.path, id), item)
+}
+
+// HandleUpdate updates the item with the id in the path from the JSON body.
+func (ro *ResourceRouter) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	var (
+		id   = URLParams(r)["id"]
+		item = ro.repo.New()
+	)
+
+	if !ro.rw.ReadJSON(w, r, item) {
+		return
+	}
+
+	err := ro.repo.Update(r.Context(), id, item)
+	if err != nil {
+		ro.writeError(w, r, cerrors.New(err, "failed to update item", map[string]interface{}{
+			"id": id,
+		}))
+
+		return
+	}
+
+	ro.rw.WriteJSON(w, WriteJSONParams{
+		Data: item,
+	})
+}
+
+// HandleDelete deletes the item with the id in the path.
+func (ro *ResourceRouter) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	id := URLParams(r)["id"]
+
+	err := ro.repo.Delete(r.Context(), id)
+	if err != nil {
+		ro.writeError(w, r, cerrors.New(err, "failed to delete item", map[string]interface{}{
+			"id": id,
+		}))
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ro *ResourceRouter) route(p, method string, override, handler http.HandlerFunc) Route {
+	if override != nil {
+		handler = override
+	}
+
+	return Route{
+		Middlewares: ro.mws,
+		Path:        p,
+		Methods:     []string{method},
+		Handler:     handler,
+		Requires:    ro.requires,
+	}
+}
+
+func (ro *ResourceRouter) write(w http.ResponseWriter, r *http.Request, page string, data interface{}) {
+	if page != "" && strings.Contains(r.Header.Get("Accept"), "text/html") {
+		ro.rw.WriteHTML(w, r, WriteHTMLParams{
+			PageTemplate: page,
+			Data:         data,
+		})
+
+		return
+	}
+
+	ro.rw.WriteJSON(w, WriteJSONParams{
+		Data: data,
+	})
+}
+
+func (ro *ResourceRouter) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, ErrResourceNotFound) {
+		ro.rw.WriteJSON(w, WriteJSONParams{
+			StatusCode: http.StatusNotFound,
+			Data:       ErrResourceNotFound,
+		})
+
+		return
+	}
+
+	ro.rw.logger.WithTags(map[string]interface{}{
+		"url": r.URL.String(),
+	}).Error("Failed to handle resource request", err)
+
+	w.WriteHeader(http.StatusInternalServerError)
+}
@@ -0,0 +1,129 @@
+package chttp_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPost struct {
+	Title string `json:"title" valid:"required"`
+}
+
+type testPostRepo struct {
+	posts map[string]*testPost
+}
+
+func (r *testPostRepo) New() interface{} {
+	return &testPost{}
+}
+
+func (r *testPostRepo) List(ctx context.Context, filters url.Values) (interface{}, error) {
+	posts := make([]*testPost, 0)
+
+	for _, post := range r.posts {
+		if title := filters.Get("title"); title == "" || title == post.Title {
+			posts = append(posts, post)
+		}
+	}
+
+	return posts, nil
+}
+
+func (r *testPostRepo) Get(ctx context.Context, id string) (interface{}, error) {
+	post, ok := r.posts[id]
+	if !ok {
+		return nil, chttp.ErrResourceNotFound
+	}
+
+	return post, nil
+}
+
+func (r *testPostRepo) Create(ctx context.Context, item interface{}) (string, error) {
+	id := strconv.Itoa(len(r.posts) + 1)
+	r.posts[id] = item.(*testPost)
+
+	return id, nil
+}
+
+func (r *testPostRepo) Update(ctx context.Context, id string, item interface{}) error {
+	if _, ok := r.posts[id]; !ok {
+		return chttp.ErrResourceNotFound
+	}
+
+	r.posts[id] = item.(*testPost)
+
+	return nil
+}
+
+func (r *testPostRepo) Delete(ctx context.Context, id string) error {
+	if _, ok := r.posts[id]; !ok {
+		return chttp.ErrResourceNotFound
+	}
+
+	delete(r.posts, id)
+
+	return nil
+}
+
+func TestResourceRouter(t *testing.T) {
+	t.Parallel()
+
+	router := chttp.NewResourceRouter(chttp.NewResourceRouterParams{
+		Path:       "/api/posts",
+		Repository: &testPostRepo{posts: make(map[string]*testPost)},
+		RW:         chttptest.NewReaderWriter(t),
+	})
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{router},
+		Logger:  clogger.NewNoop(),
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		method   string
+		path     string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{http.MethodPost, "/api/posts", `{"title":"a"}`, http.StatusCreated, `{"title":"a"}`},
+		{http.MethodPost, "/api/posts", `{}`, http.StatusUnprocessableEntity, ""},
+		{http.MethodPost, "/api/posts", `{"title":"b"}`, http.StatusCreated, `{"title":"b"}`},
+		{http.MethodGet, "/api/posts/1", "", http.StatusOK, `{"title":"a"}`},
+		{http.MethodGet, "/api/posts?title=b", "", http.StatusOK, `[{"title":"b"}]`},
+		{http.MethodPut, "/api/posts/1", `{"title":"c"}`, http.StatusOK, `{"title":"c"}`},
+		{http.MethodDelete, "/api/posts/2", "", http.StatusNoContent, ""},
+		{http.MethodGet, "/api/posts", "", http.StatusOK, `[{"title":"c"}]`},
+		{http.MethodGet, "/api/posts/2", "", http.StatusNotFound, `{"error":"resource not found"}`},
+	}
+
+	for _, tc := range testCases {
+		req, err := http.NewRequest(tc.method, server.URL+tc.path, strings.NewReader(tc.body)) //nolint:noctx
+		assert.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+
+		body, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+
+		assert.Equal(t, tc.wantCode, resp.StatusCode, tc.method+" "+tc.path)
+
+		if tc.wantBody != "" {
+			assert.JSONEq(t, tc.wantBody, string(body), tc.method+" "+tc.path)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package chttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/clogger"
+)
+
+// Modes for Config.ResponseSchemaValidation.
+const (
+	ResponseSchemaValidationLog  = "log"
+	ResponseSchemaValidationFail = "fail"
+)
+
+// NewResponseSchemaMiddlewareParams holds the params needed to create a ResponseSchemaMiddleware.
+type NewResponseSchemaMiddlewareParams struct {
+	// Schema is a value of the type that the route's JSON responses should decode into (ex. Post{} or []Post{}).
+	// Struct fields can use govalidator tags (ex. `valid:"required"`) for further checks.
+	Schema interface{}
+	Config Config
+	Logger clogger.Logger
+}
+
+// NewResponseSchemaMiddleware creates a new ResponseSchemaMiddleware.
+func NewResponseSchemaMiddleware(p NewResponseSchemaMiddlewareParams) *ResponseSchemaMiddleware {
+	return &ResponseSchemaMiddleware{
+		schema: reflect.TypeOf(p.Schema),
+		mode:   p.Config.ResponseSchemaValidation,
+		logger: p.Logger,
+	}
+}
+
+// ResponseSchemaMiddleware validates a route's successful JSON responses against a declared schema to catch handlers
+// that drift from what clients expect. Responses with unknown fields, mismatched types, or failing validation tags
+// are logged, and with the "fail" mode, replaced with a 500 response. It is a no-op unless
+// Config.ResponseSchemaValidation is set, so it can be left on routes and only enabled in dev or staging.
+type ResponseSchemaMiddleware struct {
+	schema reflect.Type
+	mode   string
+	logger clogger.Logger
+}
+
+// Handle buffers the response, validates it, and then writes it to the client.
+func (mw *ResponseSchemaMiddleware) Handle(next http.Handler) http.Handler {
+	if mw.mode != ResponseSchemaValidationLog && mw.mode != ResponseSchemaValidationFail {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := responseSchemaRw{
+			header:     w.Header(),
+			statusCode: http.StatusOK,
+		}
+
+		next.ServeHTTP(&rw, r)
+
+		err := mw.validate(&rw)
+		if err != nil {
+			mw.logger.WithTags(map[string]interface{}{
+				"method": r.Method,
+				"url":    r.URL.Path,
+			}).Error("Response does not match schema", err)
+
+			if mw.mode == ResponseSchemaValidationFail {
+				w.Header().Del("Content-Length")
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"error":"response does not match schema"}` + "\n"))
+
+				return
+			}
+		}
+
+		w.WriteHeader(rw.statusCode)
+		_, _ = w.Write(rw.body.Bytes())
+	})
+}
+
+func (mw *ResponseSchemaMiddleware) validate(rw *responseSchemaRw) error {
+	if mw.schema == nil || rw.statusCode < 200 || rw.statusCode >= 300 ||
+		!strings.Contains(rw.header.Get("Content-Type"), "json") {
+		return nil
+	}
+
+	dest := reflect.New(mw.schema)
+
+	dec := json.NewDecoder(bytes.NewReader(rw.body.Bytes()))
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(dest.Interface())
+	if err != nil {
+		return cerrors.New(err, "failed to decode response into schema", map[string]interface{}{
+			"schema": mw.schema.String(),
+		})
+	}
+
+	return validateSchemaValue(dest.Elem())
+}
+
+func validateSchemaValue(v reflect.Value) error {
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+
+		return validateSchemaValue(v.Elem())
+	case reflect.Struct:
+		_, err := govalidator.ValidateStruct(v.Addr().Interface())
+		if err != nil {
+			return cerrors.New(err, "response failed validation", map[string]interface{}{
+				"fields": govalidator.ErrorsByField(err),
+			})
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			err := validateSchemaValue(v.Index(i))
+			if err != nil {
+				return cerrors.New(err, "response item failed validation", map[string]interface{}{
+					"index": i,
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+type responseSchemaRw struct {
+	header      http.Header
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rw *responseSchemaRw) Header() http.Header {
+	return rw.header
+}
+
+func (rw *responseSchemaRw) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	return rw.body.Write(b)
+}
+
+func (rw *responseSchemaRw) WriteHeader(statusCode int) {
+	if rw.wroteHeader {
+		return
+	}
+
+	rw.wroteHeader = true
+	rw.statusCode = statusCode
+}
@@ -0,0 +1,81 @@
+package chttp_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseSchemaMiddleware(t *testing.T) {
+	t.Parallel()
+
+	type post struct {
+		ID    int    `json:"id"`
+		Title string `json:"title" valid:"required"`
+	}
+
+	testCases := []struct {
+		name     string
+		mode     string
+		body     string
+		wantCode int
+		wantBody string
+		wantLogs int
+	}{
+		{"Match", chttp.ResponseSchemaValidationFail, `[{"id":1,"title":"a"}]`, 200, `[{"id":1,"title":"a"}]`, 0},
+		{"UnknownField", chttp.ResponseSchemaValidationLog, `[{"id":1,"name":"a"}]`, 200, `[{"id":1,"name":"a"}]`, 1},
+		{"FailedValidation", chttp.ResponseSchemaValidationFail, `[{"id":1}]`, 500,
+			`{"error":"response does not match schema"}` + "\n", 1},
+		{"Disabled", "", `{"id":"1"}`, 200, `{"id":"1"}`, 0},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			logs := make([]clogger.RecordedLog, 0)
+			logger := clogger.NewRecorder(&logs)
+
+			mw := chttp.NewResponseSchemaMiddleware(chttp.NewResponseSchemaMiddlewareParams{
+				Schema: []post{},
+				Config: chttp.Config{ResponseSchemaValidation: tc.mode},
+				Logger: logger,
+			})
+
+			server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+				Routers: []chttp.Router{chttptest.NewRouter([]chttp.Route{
+					{
+						Path:        "/posts",
+						Methods:     []string{http.MethodGet},
+						Middlewares: []chttp.Middleware{mw},
+						Handler: func(w http.ResponseWriter, r *http.Request) {
+							w.Header().Set("Content-Type", "application/json")
+							_, _ = w.Write([]byte(tc.body))
+						},
+					},
+				})},
+				Logger: clogger.NewNoop(),
+			}))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/posts") //nolint:noctx
+			assert.NoError(t, err)
+
+			body, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err)
+			assert.NoError(t, resp.Body.Close())
+
+			assert.Equal(t, tc.wantCode, resp.StatusCode)
+			assert.Equal(t, tc.wantBody, string(body))
+			assert.Equal(t, tc.wantLogs, len(logs))
+		})
+	}
+}
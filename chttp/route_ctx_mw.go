@@ -3,6 +3,8 @@ package chttp
 import (
 	"context"
 	"net/http"
+
+	"github.com/gocopper/copper/clogger"
 )
 
 type ctxRoutePath string
@@ -21,6 +23,21 @@ func setRoutePathInCtxMiddleware(path string) Middleware {
 	return HandleMiddleware(mw)
 }
 
+// setLoggerInCtxMiddleware stores a logger tagged with the route path in the request context (see clogger.FromCtx).
+func setLoggerInCtxMiddleware(logger clogger.Logger, path string) Middleware {
+	var mw = func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := clogger.WithCtx(r.Context(), logger.WithTags(map[string]interface{}{
+				"route": path,
+			}))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+
+	return HandleMiddleware(mw)
+}
+
 // RawRoutePath returns the route path that matched for the given http.Request. This path includes the raw URL
 // variables. For example, a route path "/foo/{id}" will be returned as-is (i.e. {id} will NOT be replaced with the
 // actual url path)
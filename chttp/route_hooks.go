@@ -0,0 +1,81 @@
+package chttp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+type ctxRouteHooks string
+
+const ctxRouteHooksKey = ctxRouteHooks("chttp/route-hooks")
+
+type (
+	// RequestHook transforms the body decoded by ReadJSON or ReadForm before it is validated. The body is the pointer
+	// that was passed to ReadJSON or ReadForm, so hooks can type-assert it to the route's request struct and mutate it
+	// (ex. to trim or normalize fields). If the hook returns an error, a BadRequest response is sent back.
+	RequestHook func(r *http.Request, body interface{}) error
+
+	// ResponseHook transforms the data written by WriteJSON (ex. to add computed fields) and returns the data that
+	// should be written instead. The hooks only run if WriteJSONParams.Request is set. If the hook returns an error,
+	// an InternalServerError response is sent back.
+	ResponseHook func(r *http.Request, data interface{}) (interface{}, error)
+
+	routeHooks struct {
+		request  []RequestHook
+		response []ResponseHook
+	}
+)
+
+func setRouteHooksInCtxMiddleware(hooks routeHooks) Middleware {
+	var mw = func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), ctxRouteHooksKey, hooks)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+
+	return HandleMiddleware(mw)
+}
+
+func runRequestHooks(r *http.Request, body interface{}) error {
+	hooks, ok := r.Context().Value(ctxRouteHooksKey).(routeHooks)
+	if !ok {
+		return nil
+	}
+
+	for _, hook := range hooks.request {
+		err := hook(r, body)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runResponseHooks(r *http.Request, data interface{}) (interface{}, error) {
+	if r == nil {
+		return data, nil
+	}
+
+	hooks, ok := r.Context().Value(ctxRouteHooksKey).(routeHooks)
+	if !ok {
+		return data, nil
+	}
+
+	for i, hook := range hooks.response {
+		var err error
+
+		data, err = hook(r, data)
+		if err != nil {
+			return nil, cerrors.New(err, "response hook failed", map[string]interface{}{
+				"hook": i,
+			})
+		}
+	}
+
+	return data, nil
+}
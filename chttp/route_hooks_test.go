@@ -0,0 +1,90 @@
+package chttp_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteHooks(t *testing.T) {
+	t.Parallel()
+
+	type post struct {
+		Title string `json:"title"`
+		Slug  string `json:"slug"`
+	}
+
+	rw := chttptest.NewReaderWriter(t)
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{chttptest.NewRouter([]chttp.Route{
+			{
+				Path:    "/posts",
+				Methods: []string{http.MethodPost},
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					var body post
+
+					if !rw.ReadJSON(w, r, &body) {
+						return
+					}
+
+					rw.WriteJSON(w, chttp.WriteJSONParams{
+						Data:    body,
+						Request: r,
+					})
+				},
+				RequestHooks: []chttp.RequestHook{
+					func(r *http.Request, body interface{}) error {
+						p := body.(*post)
+						if p.Title == "" {
+							return errors.New("title is required") //nolint:goerr113
+						}
+
+						p.Title = strings.TrimSpace(p.Title)
+
+						return nil
+					},
+				},
+				ResponseHooks: []chttp.ResponseHook{
+					func(r *http.Request, data interface{}) (interface{}, error) {
+						p := data.(post)
+						p.Slug = strings.ToLower(strings.ReplaceAll(p.Title, " ", "-"))
+
+						return p, nil
+					},
+				},
+			},
+		})},
+		Logger: clogger.NewNoop(),
+	}))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/posts", "application/json", //nolint:noctx
+		strings.NewReader(`{"title":"  Hello World  "}`))
+	assert.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"title":"Hello World","slug":"hello-world"}`+"\n", string(body))
+
+	resp, err = http.Post(server.URL+"/posts", "application/json", strings.NewReader(`{}`)) //nolint:noctx
+	assert.NoError(t, err)
+
+	body, err = io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"title is required"}`+"\n", string(body))
+}
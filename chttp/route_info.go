@@ -0,0 +1,113 @@
+package chttp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// RouteInfo describes a registered route. It is used to debug unmatched requests and to audit the routes that an
+// app exposes.
+type RouteInfo struct {
+	Name        string   `json:"name,omitempty"`
+	Path        string   `json:"path"`
+	Methods     []string `json:"methods,omitempty"`
+	Handler     string   `json:"handler"`
+	Middlewares []string `json:"middlewares,omitempty"`
+	Requires    []string `json:"requires,omitempty"`
+}
+
+// ListRoutes returns the routes of the given routers, including localized paths, sorted by path. It can be used to
+// print the routes from a CLI command with WriteRouteTable.
+func ListRoutes(routers []Router) []RouteInfo {
+	routes := make([]Route, 0)
+	for _, router := range routers {
+		routes = append(routes, router.Routes()...)
+	}
+
+	return newRouteInfos(expandLocalizedRoutes(routes))
+}
+
+// Routes returns the routes registered with the handler that is serving the request with the given context. It
+// returns nil if the request was not handled by a handler created with NewHandler.
+func Routes(ctx context.Context) []RouteInfo {
+	table, ok := ctx.Value(ctxRouteTableKey).(*routeTable)
+	if !ok {
+		return nil
+	}
+
+	return table.infos
+}
+
+// WriteRouteTable writes the routes as an aligned table with a header row.
+func WriteRouteTable(w io.Writer, routes []RouteInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:gomnd
+
+	_, _ = fmt.Fprintln(tw, "METHODS\tPATH\tNAME\tHANDLER\tMIDDLEWARES\tREQUIRES")
+
+	for _, r := range routes {
+		methods := strings.Join(r.Methods, ",")
+		if methods == "" {
+			methods = "*"
+		}
+
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", methods, r.Path, r.Name, r.Handler,
+			strings.Join(r.Middlewares, ","), strings.Join(r.Requires, ","))
+	}
+
+	return tw.Flush()
+}
+
+func newRouteInfos(routes []Route) []RouteInfo {
+	infos := make([]RouteInfo, 0, len(routes))
+
+	for _, route := range routes {
+		info := RouteInfo{
+			Name:     route.Name,
+			Path:     route.Path,
+			Methods:  route.Methods,
+			Handler:  funcName(route.Handler),
+			Requires: route.Requires,
+		}
+
+		for _, mw := range route.Middlewares {
+			info.Middlewares = append(info.Middlewares, middlewareName(mw))
+		}
+
+		infos = append(infos, info)
+	}
+
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].Path < infos[j].Path
+	})
+
+	return infos
+}
+
+func middlewareName(mw Middleware) string {
+	if fn, ok := mw.(*middlewareFuncHandler); ok {
+		return funcName(fn.fn)
+	}
+
+	return reflect.TypeOf(mw).String()
+}
+
+func funcName(fn interface{}) string {
+	val := reflect.ValueOf(fn)
+	if val.Kind() != reflect.Func || val.IsNil() {
+		return ""
+	}
+
+	f := runtime.FuncForPC(val.Pointer())
+	if f == nil {
+		return ""
+	}
+
+	// Method values are named with a -fm suffix (ex. pkg.(*Router).HandleIndex-fm).
+	return strings.TrimSuffix(f.Name(), "-fm")
+}
@@ -0,0 +1,72 @@
+package chttp_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func handleRouteInfoTest(w http.ResponseWriter, r *http.Request) {}
+
+func TestListRoutes(t *testing.T) {
+	t.Parallel()
+
+	routes := chttp.ListRoutes([]chttp.Router{chttptest.NewRouter([]chttp.Route{
+		{
+			Name:           "about",
+			Path:           "/about",
+			Methods:        []string{http.MethodGet},
+			Handler:        handleRouteInfoTest,
+			LocalizedPaths: map[string]string{"fr": "/a-propos"},
+		},
+		{
+			Path:        "/admin",
+			Handler:     handleRouteInfoTest,
+			Middlewares: []chttp.Middleware{chttp.NewRequestIDMiddleware()},
+			Requires:    []string{"role:admin"},
+		},
+	})})
+
+	assert.Equal(t, []chttp.RouteInfo{
+		{
+			Name:    "about",
+			Path:    "/a-propos",
+			Methods: []string{http.MethodGet},
+			Handler: "github.com/gocopper/copper/chttp_test.handleRouteInfoTest",
+		},
+		{
+			Name:    "about",
+			Path:    "/about",
+			Methods: []string{http.MethodGet},
+			Handler: "github.com/gocopper/copper/chttp_test.handleRouteInfoTest",
+		},
+		{
+			Path:        "/admin",
+			Handler:     "github.com/gocopper/copper/chttp_test.handleRouteInfoTest",
+			Middlewares: []string{"*chttp.RequestIDMiddleware"},
+			Requires:    []string{"role:admin"},
+		},
+	}, routes)
+
+	var out strings.Builder
+
+	assert.NoError(t, chttp.WriteRouteTable(&out, routes))
+	assert.Equal(t, strings.Join([]string{
+		"METHODS  PATH       NAME   HANDLER                                                    MIDDLEWARES                 REQUIRES",
+		"GET      /a-propos  about  github.com/gocopper/copper/chttp_test.handleRouteInfoTest                              ",
+		"GET      /about     about  github.com/gocopper/copper/chttp_test.handleRouteInfoTest                              ",
+		"*        /admin            github.com/gocopper/copper/chttp_test.handleRouteInfoTest  *chttp.RequestIDMiddleware  role:admin",
+		"",
+	}, "\n"), out.String())
+}
+
+func TestRoutes_NotHandled(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, chttp.Routes(context.Background()))
+}
@@ -0,0 +1,71 @@
+package chttp
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RouteParamTypes are the types that can be used instead of a regular expression to constrain a route's vars (ex.
+// /posts/{id:int} or /users/{id:uuid}). Requests with vars that do not match are not routed to the route.
+var RouteParamTypes = map[string]string{ //nolint:gochecknoglobals
+	"int":   `[0-9]+`,
+	"uuid":  `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+	"slug":  `[a-z0-9]+(?:-[a-z0-9]+)*`,
+	"alpha": `[a-zA-Z]+`,
+}
+
+const (
+	rankStatic = iota
+	rankConstrained
+	rankUnconstrained
+	rankCatchAll
+)
+
+var routeParamTypeRe = regexp.MustCompile(`\{([^}:]+):([a-z]+)\}`)
+
+// expandRouteParamTypes replaces the types of the path's vars (see RouteParamTypes) with their regular expressions.
+// The unexpanded path is used everywhere else (ex. URL) since the expressions may have braces.
+func expandRouteParamTypes(path string) string {
+	return routeParamTypeRe.ReplaceAllStringFunc(path, func(v string) string {
+		match := routeParamTypeRe.FindStringSubmatch(v)
+
+		re, ok := RouteParamTypes[match[2]]
+		if !ok {
+			return v
+		}
+
+		return "{" + match[1] + ":" + re + "}"
+	})
+}
+
+// routeParamSchema returns the OpenAPI schema for a var with the given type (see RouteParamTypes).
+func routeParamSchema(typ string) map[string]interface{} {
+	switch typ {
+	case "int":
+		return map[string]interface{}{"type": "integer"}
+	case "uuid":
+		return map[string]interface{}{"type": "string", "format": "uuid"}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
+
+// routeParamRank ranks a part of a route's path so that more specific parts are routed first: static parts, then
+// constrained vars (ex. {id:int}), then unconstrained vars (ex. {id}), then vars that match anything (ex. {path:.*}).
+func routeParamRank(part string) int {
+	if !strings.HasPrefix(part, "{") || !strings.HasSuffix(part, "}") {
+		return rankStatic
+	}
+
+	i := strings.Index(part, ":")
+	if i == -1 {
+		return rankUnconstrained
+	}
+
+	switch part[i+1 : len(part)-1] {
+	case ".*", ".+":
+		return rankCatchAll
+	}
+
+	return rankConstrained
+}
@@ -0,0 +1,75 @@
+package chttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHandler_RouteParamTypes(t *testing.T) {
+	t.Parallel()
+
+	handle := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(name + " " + chttp.URLParams(r)["id"]))
+		}
+	}
+
+	handler := chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{chttptest.NewRouter([]chttp.Route{
+			{Path: "/posts/{id}", Handler: handle("any")},
+			{Path: "/posts/{id:int}", Handler: handle("int")},
+			{Path: "/posts/{id:uuid}", Handler: handle("uuid")},
+			{Path: "/posts/{id:[a-z]{3}}", Handler: handle("regex")},
+			{Path: "/users/{id:int}", Handler: handle("user")},
+			{Path: "/{path:.*}", Handler: handle("catchall")},
+		})},
+		Logger: clogger.NewNoop(),
+	})
+
+	testCases := map[string]struct {
+		path string
+		want string
+	}{
+		"int":         {"/posts/42", "int 42"},
+		"uuid":        {"/posts/0b7c3d2e-4f5a-4b6c-8d9e-0f1a2b3c4d5e", "uuid 0b7c3d2e-4f5a-4b6c-8d9e-0f1a2b3c4d5e"},
+		"regex":       {"/posts/abc", "regex abc"},
+		"fallback":    {"/posts/hello-world", "any hello-world"},
+		"invalid int": {"/users/abc", "catchall "},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.Equal(t, tc.want, resp.Body.String())
+		})
+	}
+}
+
+func TestNewHandler_RouteParamTypes_NotFound(t *testing.T) {
+	t.Parallel()
+
+	handler := chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{chttptest.NewRouter([]chttp.Route{
+			{Path: "/users/{id:int}", Handler: func(w http.ResponseWriter, r *http.Request) {}},
+		})},
+		Logger: clogger.NewNoop(),
+	})
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/users/abc", nil))
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
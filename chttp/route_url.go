@@ -0,0 +1,176 @@
+package chttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+type (
+	ctxLocale     string
+	ctxRouteTable string
+
+	// routeTable holds the named routes and the app's base path so that URLs can be generated. It also describes all
+	// of the routes for Routes.
+	routeTable struct {
+		routes   map[string]Route
+		basePath string
+		infos    []RouteInfo
+	}
+)
+
+const (
+	ctxLocaleKey     = ctxLocale("chttp/locale")
+	ctxRouteTableKey = ctxRouteTable("chttp/route-table")
+)
+
+var (
+	errRouteNotFound = errors.New("route not found")
+	errMissingURLVar = errors.New("missing url var")
+	routeVarRe       = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+	errNoRouteInCtx  = errors.New("request was not handled by chttp")
+)
+
+func newRouteTable(routes []Route, basePath string) *routeTable {
+	table := routeTable{
+		routes:   make(map[string]Route),
+		basePath: basePath,
+	}
+
+	for _, route := range routes {
+		if route.Name != "" {
+			table.routes[route.Name] = route
+		}
+	}
+
+	return &table
+}
+
+// expandLocalizedRoutes adds a copy of each route for each of its localized paths.
+func expandLocalizedRoutes(routes []Route) []Route {
+	expanded := make([]Route, 0, len(routes))
+
+	for _, route := range routes {
+		expanded = append(expanded, route)
+
+		locales := make([]string, 0, len(route.LocalizedPaths))
+		for locale := range route.LocalizedPaths {
+			locales = append(locales, locale)
+		}
+
+		sort.Strings(locales)
+
+		for _, locale := range locales {
+			localized := route
+			localized.Path = route.LocalizedPaths[locale]
+			localized.LocalizedPaths = nil
+			localized.locale = locale
+
+			expanded = append(expanded, localized)
+		}
+	}
+
+	return expanded
+}
+
+func setLocaleInCtxMiddleware(locale string) Middleware {
+	mw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(WithLocale(r.Context(), locale)))
+		})
+	}
+
+	return HandleMiddleware(mw)
+}
+
+func setRouteTableInCtxMiddleware(table *routeTable) Middleware {
+	mw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxRouteTableKey, table)))
+		})
+	}
+
+	return HandleMiddleware(mw)
+}
+
+// WithLocale returns a context with the given locale. Localized routes set the locale automatically, but apps can
+// also set it from a middleware (ex. based on a cookie or the Accept-Language header).
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, ctxLocaleKey, locale)
+}
+
+// Locale returns the locale of the request that the given context belongs to, or an empty string if it is not set.
+func Locale(ctx context.Context) string {
+	locale, _ := ctx.Value(ctxLocaleKey).(string)
+	return locale
+}
+
+// URL generates the URL path for the route with the given name. The route's vars (ex. {id}) are replaced with the
+// given vars. If the route has a localized path for the request's locale, it is used instead of the default path.
+// The path includes the app's base path.
+func URL(r *http.Request, name string, vars map[string]string) (string, error) {
+	table, ok := r.Context().Value(ctxRouteTableKey).(*routeTable)
+	if !ok {
+		return "", errNoRouteInCtx
+	}
+
+	route, ok := table.routes[name]
+	if !ok {
+		return "", cerrors.New(errRouteNotFound, "failed to generate url", map[string]interface{}{
+			"name": name,
+		})
+	}
+
+	p := route.Path
+	if localized, ok := route.LocalizedPaths[Locale(r.Context())]; ok {
+		p = localized
+	}
+
+	var err error
+
+	out := routeVarRe.ReplaceAllStringFunc(p, func(v string) string {
+		varName := routeVarRe.FindStringSubmatch(v)[1]
+
+		val, ok := vars[varName]
+		if !ok {
+			err = cerrors.New(errMissingURLVar, "failed to generate url", map[string]interface{}{
+				"name": name,
+				"var":  varName,
+			})
+		}
+
+		return url.PathEscape(val)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return table.basePath + out, nil
+}
+
+// BasePath returns the base path that the app is mounted at (see NewHandlerParams.BasePath), or an empty string if
+// the app is mounted at the root.
+func BasePath(ctx context.Context) string {
+	table, ok := ctx.Value(ctxRouteTableKey).(*routeTable)
+	if !ok {
+		return ""
+	}
+
+	return table.basePath
+}
+
+// WithBasePath prefixes the given path with the app's base path. Paths that are not absolute (ex. full URLs) are
+// returned as-is.
+func WithBasePath(ctx context.Context, p string) string {
+	if !strings.HasPrefix(p, "/") || strings.HasPrefix(p, "//") {
+		return p
+	}
+
+	return BasePath(ctx) + p
+}
@@ -0,0 +1,62 @@
+package chttp_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURL_LocalizedPaths(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{chttptest.NewRouter([]chttp.Route{
+			{
+				Name:           "about",
+				Path:           "/about",
+				LocalizedPaths: map[string]string{"en": "/en/about", "fr": "/fr/a-propos"},
+				Methods:        []string{http.MethodGet},
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(chttp.Locale(r.Context()) + " "))
+				},
+			},
+			{
+				Name:           "post",
+				Path:           "/posts/{id:[0-9]+}",
+				LocalizedPaths: map[string]string{"fr": "/articles/{id:[0-9]+}"},
+				Methods:        []string{http.MethodGet},
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					url, err := chttp.URL(r, "post", map[string]string{"id": "2"})
+					assert.NoError(t, err)
+
+					_, _ = w.Write([]byte(url))
+				},
+			},
+		})},
+		Logger: clogger.NewNoop(),
+	}))
+	defer server.Close()
+
+	for path, want := range map[string]string{
+		"/about":       " ",
+		"/en/about":    "en ",
+		"/fr/a-propos": "fr ",
+		"/posts/1":     "/posts/2",
+		"/articles/1":  "/articles/2",
+	} {
+		resp, err := http.Get(server.URL + path) //nolint:noctx
+		assert.NoError(t, err)
+
+		body, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+
+		assert.Equal(t, want, string(body), path)
+	}
+}
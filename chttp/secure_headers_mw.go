@@ -0,0 +1,116 @@
+package chttp
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultHSTSMaxAge        = 365 * 24 * time.Hour
+	defaultFrameOptions      = "DENY"
+	defaultReferrerPolicy    = "strict-origin-when-cross-origin"
+	defaultPermissionsPolicy = "camera=(), microphone=(), geolocation=()"
+
+	disabledSecureHeader = "-"
+)
+
+// NewSecureHeadersMiddleware creates a new SecureHeadersMiddleware.
+func NewSecureHeadersMiddleware(config Config) *SecureHeadersMiddleware {
+	c := config.SecureHeaders
+
+	headers := map[string]string{
+		"X-Content-Type-Options": "nosniff",
+		"X-Frame-Options":        secureHeaderValue(c.FrameOptions, defaultFrameOptions),
+		"Referrer-Policy":        secureHeaderValue(c.ReferrerPolicy, defaultReferrerPolicy),
+		"Permissions-Policy":     secureHeaderValue(c.PermissionsPolicy, defaultPermissionsPolicy),
+	}
+
+	for name, val := range headers {
+		if val == "" {
+			delete(headers, name)
+		}
+	}
+
+	// The trusted proxies are validated by LoadConfig. If they are invalid, no proxy is trusted.
+	trusted, _ := parseIPNets(config.TrustedProxies)
+
+	return &SecureHeadersMiddleware{
+		headers: headers,
+		hsts:    hstsHeaderValue(c),
+		trusted: trusted,
+	}
+}
+
+// SecureHeadersMiddleware sets security headers (Strict-Transport-Security, X-Content-Type-Options,
+// X-Frame-Options, Referrer-Policy, and Permissions-Policy) on every response. Handlers can override them by setting
+// the headers themselves. Strict-Transport-Security is only set on HTTPS requests, which are detected with the
+// X-Forwarded-Proto header when they come through one of Config.TrustedProxies.
+type SecureHeadersMiddleware struct {
+	headers map[string]string
+	hsts    string
+	trusted []*net.IPNet
+}
+
+// Handle sets the security headers before calling the next handler.
+func (mw *SecureHeadersMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+
+		for name, val := range mw.headers {
+			h.Set(name, val)
+		}
+
+		if mw.hsts != "" && isHTTPS(r, mw.trusted) {
+			h.Set("Strict-Transport-Security", mw.hsts)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func secureHeaderValue(val, defaultVal string) string {
+	switch val {
+	case "":
+		return defaultVal
+	case disabledSecureHeader:
+		return ""
+	default:
+		return val
+	}
+}
+
+func hstsHeaderValue(c SecureHeadersConfig) string {
+	maxAge := c.HSTSMaxAge
+	if maxAge == 0 {
+		maxAge = defaultHSTSMaxAge
+	}
+
+	if maxAge < 0 {
+		return ""
+	}
+
+	directives := []string{"max-age=" + strconv.Itoa(int(maxAge.Seconds()))}
+
+	if c.HSTSIncludeSubdomains {
+		directives = append(directives, "includeSubDomains")
+	}
+
+	if c.HSTSPreload {
+		directives = append(directives, "preload")
+	}
+
+	return strings.Join(directives, "; ")
+}
+
+// isHTTPS returns true if the request was made over TLS, either directly or through a proxy that terminates TLS. The
+// X-Forwarded-Proto header is only read from the trusted proxies since it can be set by anyone.
+func isHTTPS(r *http.Request, trusted []*net.IPNet) bool {
+	if r.TLS != nil {
+		return true
+	}
+
+	return ipNetsContain(trusted, peerIP(r)) && strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
@@ -0,0 +1,88 @@
+package chttp_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecureHeadersMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mw      = chttp.NewSecureHeadersMiddleware(chttp.Config{})
+		handler = mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		resp    = httptest.NewRecorder()
+	)
+
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, "nosniff", resp.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", resp.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "strict-origin-when-cross-origin", resp.Header().Get("Referrer-Policy"))
+	assert.Equal(t, "camera=(), microphone=(), geolocation=()", resp.Header().Get("Permissions-Policy"))
+	assert.Empty(t, resp.Header().Get("Strict-Transport-Security"))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.TLS = &tls.ConnectionState{}
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	assert.Equal(t, "max-age=31536000", resp.Header().Get("Strict-Transport-Security"))
+}
+
+func TestSecureHeadersMiddleware_Config(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mw = chttp.NewSecureHeadersMiddleware(chttp.Config{
+			TrustedProxies: []string{"192.0.2.0/24"},
+			SecureHeaders: chttp.SecureHeadersConfig{
+				HSTSMaxAge:            time.Hour,
+				HSTSIncludeSubdomains: true,
+				HSTSPreload:           true,
+				FrameOptions:          "SAMEORIGIN",
+				PermissionsPolicy:     "-",
+			},
+		})
+		handler = mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Referrer-Policy", "no-referrer")
+		}))
+		req  = httptest.NewRequest(http.MethodGet, "/", nil)
+		resp = httptest.NewRecorder()
+	)
+
+	req.Header.Set("X-Forwarded-Proto", "https")
+
+	handler.ServeHTTP(resp, req)
+
+	assert.Equal(t, "max-age=3600; includeSubDomains; preload", resp.Header().Get("Strict-Transport-Security"))
+	assert.Equal(t, "SAMEORIGIN", resp.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "no-referrer", resp.Header().Get("Referrer-Policy"))
+	assert.Empty(t, resp.Header().Values("Permissions-Policy"))
+}
+
+func TestSecureHeadersMiddleware_UntrustedForwardedProto(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mw = chttp.NewSecureHeadersMiddleware(chttp.Config{
+			TrustedProxies: []string{"10.0.0.0/8"},
+		})
+		handler = mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		req     = httptest.NewRequest(http.MethodGet, "/", nil)
+		resp    = httptest.NewRecorder()
+	)
+
+	req.Header.Set("X-Forwarded-Proto", "https")
+
+	handler.ServeHTTP(resp, req)
+
+	assert.Empty(t, resp.Header().Get("Strict-Transport-Security"))
+}
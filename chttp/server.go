@@ -4,12 +4,36 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/gocopper/copper/cerrors"
 	"github.com/gocopper/copper/clifecycle"
 	"github.com/gocopper/copper/clogger"
+	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
+// ServerEvent represents a change in the state of a Server. Events are logged with an 'event' tag and delivered to
+// the funcs registered with Server.OnEvent.
+type ServerEvent string
+
+// Events emitted by Server in the order that they occur.
+const (
+	ServerEventStarted   = ServerEvent("started")
+	ServerEventListening = ServerEvent("listening")
+	ServerEventDraining  = ServerEvent("draining")
+	ServerEventStopped   = ServerEvent("stopped")
+)
+
+const defaultReadHeaderTimeout = 10 * time.Second
+
 // NewServerParams holds the params needed to create a server.
 type NewServerParams struct {
 	Handler   http.Handler
@@ -20,12 +44,29 @@ type NewServerParams struct {
 
 // NewServer creates a new server.
 func NewServer(p NewServerParams) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	readHeaderTimeout := p.Config.ReadHeaderTimeout
+	if readHeaderTimeout == 0 {
+		readHeaderTimeout = defaultReadHeaderTimeout
+	}
+
 	return &Server{
-		handler:  p.Handler,
-		config:   p.Config,
-		logger:   p.Logger,
-		lc:       p.Lifecycle,
-		internal: http.Server{},
+		handler: p.Handler,
+		config:  p.Config,
+		logger:  clogger.Named(p.Logger, "chttp"),
+		lc:      p.Lifecycle,
+		internal: http.Server{
+			ReadTimeout:       p.Config.ReadTimeout,
+			ReadHeaderTimeout: readHeaderTimeout,
+			WriteTimeout:      p.Config.WriteTimeout,
+			IdleTimeout:       p.Config.IdleTimeout,
+			MaxHeaderBytes:    p.Config.MaxHeaderBytes,
+		},
+		ctx:        ctx,
+		cancel:     cancel,
+		onEvent:    make([]func(ServerEvent), 0),
+		onShutdown: make([]func(ctx context.Context) error, 0),
 	}
 }
 
@@ -37,29 +78,371 @@ type Server struct {
 	lc      *clifecycle.Lifecycle
 
 	internal http.Server
+	ctx      context.Context
+	cancel   context.CancelFunc
+
+	mu         sync.Mutex
+	addrs      []net.Addr
+	onEvent    []func(ServerEvent)
+	onShutdown []func(ctx context.Context) error
+}
+
+// Addrs returns the addresses that the server is listening on. It is empty until the server emits the listening
+// event. When the server is configured with port 0, the addresses include the ports picked by the OS.
+func (s *Server) Addrs() []net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]net.Addr{}, s.addrs...)
+}
+
+// Context returns a context that is canceled once the server has stopped. Subsystems that should only stop after the
+// server has finished handling requests can wait on it.
+func (s *Server) Context() context.Context {
+	return s.ctx
+}
+
+// OnShutdown registers the provided fn to run when the server starts shutting down, before its listeners are closed.
+// The fns run in order and are given a context that expires with the drain timeout.
+func (s *Server) OnShutdown(fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onShutdown = append(s.onShutdown, fn)
 }
 
-// Run configures an HTTP server using the provided app config and starts it.
+// OnEvent registers the provided fn to be called for each ServerEvent. The fn is called synchronously, so it should
+// return quickly. This can be used by tests or orchestration tooling to wait for the server to be ready.
+func (s *Server) OnEvent(fn func(ServerEvent)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onEvent = append(s.onEvent, fn)
+}
+
+// Run configures an HTTP server using the provided app config and starts it. If TLS is configured, the server
+// supports both HTTP/1.1 and HTTP/2. Otherwise, HTTP/2 is only supported if h2c is enabled.
+// Run returns once the server is listening, or with an error if the server fails to listen.
 func (s *Server) Run() error {
-	s.internal.Addr = fmt.Sprintf(":%d", s.config.Port)
+	var (
+		useAutoCert = len(s.config.AutoCertDomains) > 0
+		useTLS      = useAutoCert || (s.config.TLSCertFile != "" && s.config.TLSKeyFile != "")
+	)
+
 	s.internal.Handler = s.handler
 
-	s.lc.OnStop(func(ctx context.Context) error {
-		s.logger.Info("Shutting down http server..")
+	s.emit(ServerEventStarted, map[string]interface{}{
+		"addrs":    s.listenAddrs(),
+		"tls":      useTLS,
+		"autocert": useAutoCert,
+		"h2c":      !useTLS && s.config.EnableH2C,
+	})
+
+	if !useTLS && s.config.EnableH2C {
+		s.internal.Handler = h2c.NewHandler(s.handler, &http2.Server{})
+	}
+
+	listeners, err := s.listen()
+	if err != nil {
+		return err
+	}
+
+	// The challenge server is only started once the server is listening so that it is not left running on error
+	if useAutoCert {
+		s.runAutoCertChallengeServer()
+	}
+
+	s.lc.OnStopNamed("http server", s.shutdown)
+
+	addrs := make([]string, len(listeners))
+
+	s.mu.Lock()
+	for i := range listeners {
+		s.addrs = append(s.addrs, listeners[i].Addr())
+		addrs[i] = listeners[i].Addr().String()
+	}
+	s.mu.Unlock()
 
-		return s.internal.Shutdown(ctx)
+	s.emit(ServerEventListening, map[string]interface{}{
+		"addrs": addrs,
+	})
+
+	for i := range listeners {
+		go s.serve(listeners[i], useTLS, useAutoCert)
+	}
+
+	return nil
+}
+
+// shutdown runs the OnShutdown hooks and gracefully stops the server. Errors from the hooks are logged so that they
+// do not prevent the server from stopping. Connections that are still active when the drain timeout passes are
+// closed.
+func (s *Server) shutdown(ctx context.Context) error {
+	if s.config.DrainTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, s.config.DrainTimeout)
+		defer cancel()
+	}
+
+	s.emit(ServerEventDraining, nil)
+
+	s.mu.Lock()
+	hooks := append([]func(ctx context.Context) error{}, s.onShutdown...)
+	s.mu.Unlock()
+
+	for _, hook := range hooks {
+		err := hook(ctx)
+		if err != nil {
+			s.logger.Error("Failed to run shutdown hook", err)
+		}
+	}
+
+	err := s.internal.Shutdown(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		// Shutdown leaves the connections that are still active open once the drain timeout passes
+		_ = s.internal.Close()
+	}
+
+	s.cancel()
+	s.emit(ServerEventStopped, nil)
+
+	return err
+}
+
+func (s *Server) serve(ln net.Listener, useTLS, useAutoCert bool) {
+	var err error
+
+	switch {
+	case useAutoCert:
+		// The certificates are provided by the autocert manager in the server's TLSConfig
+		err = s.internal.ServeTLS(ln, "", "")
+	case useTLS:
+		err = s.internal.ServeTLS(ln, s.config.TLSCertFile, s.config.TLSKeyFile)
+	default:
+		err = s.internal.Serve(ln)
+	}
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.logger.WithTags(map[string]interface{}{
+			"addr": ln.Addr().String(),
+		}).Error("Server did not close cleanly", err)
+	}
+}
+
+func (s *Server) listenAddrs() []string {
+	if len(s.config.Listen) > 0 {
+		return s.config.Listen
+	}
+
+	return []string{fmt.Sprintf(":%d", s.config.Port)}
+}
+
+// listen opens a listener for each of the configured addresses. If any of them fails, the listeners that were
+// already opened are closed.
+func (s *Server) listen() ([]net.Listener, error) {
+	const (
+		unixPrefix = "unix:"
+		systemd    = "systemd"
+	)
+
+	var (
+		addrs     = s.listenAddrs()
+		listeners = make([]net.Listener, 0, len(addrs))
+	)
+
+	for _, addr := range addrs {
+		var (
+			lns []net.Listener
+			err error
+		)
+
+		switch {
+		case addr == systemd:
+			lns, err = listenSystemd()
+		case strings.HasPrefix(addr, unixPrefix):
+			var ln net.Listener
+
+			ln, err = listenUnix(strings.TrimPrefix(addr, unixPrefix), s.config.UnixSocketMode)
+			lns = []net.Listener{ln}
+		default:
+			var ln net.Listener
+
+			ln, err = net.Listen("tcp", addr)
+			lns = []net.Listener{ln}
+		}
+
+		if err != nil {
+			for i := range listeners {
+				_ = listeners[i].Close()
+			}
+
+			return nil, cerrors.New(err, "failed to listen", map[string]interface{}{
+				"addr": addr,
+			})
+		}
+
+		listeners = append(listeners, lns...)
+	}
+
+	return listeners, nil
+}
+
+// listenUnix listens on the unix socket at the given path. A stale socket file left behind by a previous run is
+// removed first. If mode is set, the socket's file permissions are changed to it.
+func listenUnix(path, mode string) (net.Listener, error) {
+	info, err := os.Stat(path)
+	if err == nil && info.Mode()&os.ModeSocket != 0 {
+		err = os.Remove(path)
+		if err != nil {
+			return nil, cerrors.New(err, "failed to remove stale unix socket", map[string]interface{}{
+				"path": path,
+			})
+		}
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if mode == "" {
+		return ln, nil
+	}
+
+	perm, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		_ = ln.Close()
+
+		return nil, cerrors.New(err, "invalid unix socket mode", map[string]interface{}{
+			"mode": mode,
+		})
+	}
+
+	err = os.Chmod(path, os.FileMode(perm))
+	if err != nil {
+		_ = ln.Close()
+
+		return nil, cerrors.New(err, "failed to set unix socket mode", map[string]interface{}{
+			"path": path,
+			"mode": mode,
+		})
+	}
+
+	return ln, nil
+}
+
+// listenSystemd returns listeners for the sockets passed in by systemd socket activation. See sd_listen_fds(3) for
+// the protocol. The environment variables are unset so that child processes do not inherit the sockets.
+func listenSystemd() ([]net.Listener, error) {
+	const firstFD = 3
+
+	defer func() {
+		_ = os.Unsetenv("LISTEN_PID")
+		_ = os.Unsetenv("LISTEN_FDS")
+		_ = os.Unsetenv("LISTEN_FDNAMES")
+	}()
+
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, cerrors.New(nil, "no sockets were passed in by systemd", map[string]interface{}{
+			"LISTEN_PID": os.Getenv("LISTEN_PID"),
+		})
+	}
+
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n < 1 {
+		return nil, cerrors.New(err, "invalid LISTEN_FDS", map[string]interface{}{
+			"LISTEN_FDS": os.Getenv("LISTEN_FDS"),
+		})
+	}
+
+	listeners := make([]net.Listener, 0, n)
+
+	for fd := firstFD; fd < firstFD+n; fd++ {
+		f := os.NewFile(uintptr(fd), fmt.Sprintf("systemd-fd-%d", fd))
+
+		ln, err := net.FileListener(f)
+
+		// FileListener dups the fd, so the original can be closed regardless of the result.
+		_ = f.Close()
+
+		if err != nil {
+			for i := range listeners {
+				_ = listeners[i].Close()
+			}
+
+			return nil, cerrors.New(err, "failed to listen on systemd socket", map[string]interface{}{
+				"fd": fd,
+			})
+		}
+
+		listeners = append(listeners, ln)
+	}
+
+	return listeners, nil
+}
+
+func (s *Server) emit(event ServerEvent, tags map[string]interface{}) {
+	var msg string
+
+	switch event {
+	case ServerEventStarted:
+		msg = "Starting http server.."
+	case ServerEventListening:
+		msg = "Listening for http requests.."
+	case ServerEventDraining:
+		msg = "Shutting down http server.."
+	case ServerEventStopped:
+		msg = "Stopped http server"
+	}
+
+	s.logger.
+		WithTags(tags).
+		WithTags(map[string]interface{}{"event": string(event)}).
+		Info(msg)
+
+	s.mu.Lock()
+	fns := append([]func(ServerEvent){}, s.onEvent...)
+	s.mu.Unlock()
+
+	for _, fn := range fns {
+		fn(event)
+	}
+}
+
+// runAutoCertChallengeServer configures the server to get its TLS certificates from Let's Encrypt and starts a
+// plaintext HTTP server that responds to HTTP-01 challenges and redirects all other requests to HTTPS.
+func (s *Server) runAutoCertChallengeServer() {
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(s.config.AutoCertDomains...),
+		Cache:      autocert.DirCache(s.config.AutoCertCacheDir),
+		Email:      s.config.AutoCertEmail,
+	}
+
+	s.internal.TLSConfig = m.TLSConfig()
+
+	challengeServer := http.Server{
+		Addr:    fmt.Sprintf(":%d", s.config.AutoCertHTTPPort),
+		Handler: m.HTTPHandler(nil),
+	}
+
+	s.lc.OnStopNamed("autocert challenge server", func(ctx context.Context) error {
+		return challengeServer.Shutdown(ctx)
 	})
 
 	go func() {
 		s.logger.
-			WithTags(map[string]interface{}{"port": s.config.Port}).
-			Info("Starting http server..")
+			WithTags(map[string]interface{}{
+				"port":    s.config.AutoCertHTTPPort,
+				"domains": s.config.AutoCertDomains,
+			}).
+			Info("Starting autocert challenge server..")
 
-		err := s.internal.ListenAndServe()
+		err := challengeServer.ListenAndServe()
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
-			s.logger.Error("Server did not close cleanly", err)
+			s.logger.Error("Autocert challenge server did not close cleanly", err)
 		}
 	}()
-
-	return nil
 }
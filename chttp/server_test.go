@@ -1,7 +1,14 @@
 package chttp_test
 
 import (
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"os"
+	"path"
+	"strings"
 	"testing"
 	"time"
 
@@ -9,6 +16,7 @@ import (
 	"github.com/gocopper/copper/clifecycle"
 	"github.com/gocopper/copper/clogger"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
 )
 
 func TestServer_Run(t *testing.T) {
@@ -16,32 +24,218 @@ func TestServer_Run(t *testing.T) {
 
 	logger := clogger.New()
 	lc := clifecycle.New()
+	events := make([]chttp.ServerEvent, 0)
 
 	server := chttp.NewServer(chttp.NewServerParams{
 		Handler:   http.NotFoundHandler(),
-		Config:    chttp.Config{Port: 8999},
+		Config:    chttp.Config{Port: 0},
 		Logger:    logger,
 		Lifecycle: lc,
 	})
 
-	go func() {
-		err := server.Run()
-		assert.NoError(t, err)
-	}()
+	server.OnEvent(func(event chttp.ServerEvent) {
+		events = append(events, event)
+	})
+
+	assert.NoError(t, server.Run())
+	assert.Equal(t, []chttp.ServerEvent{chttp.ServerEventStarted, chttp.ServerEventListening}, events)
+	assert.Equal(t, 1, len(server.Addrs()))
+
+	url := "http://" + server.Addrs()[0].String()
+
+	resp, err := http.Get(url) //nolint:noctx
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	lc.Stop(logger)
+
+	assert.Equal(t, []chttp.ServerEvent{
+		chttp.ServerEventStarted,
+		chttp.ServerEventListening,
+		chttp.ServerEventDraining,
+		chttp.ServerEventStopped,
+	}, events)
+
+	_, err = http.Get(url) //nolint:noctx,bodyclose
+	assert.Contains(t, err.Error(), "connect: connection refused")
+}
+
+func TestServer_Run_H2C(t *testing.T) {
+	t.Parallel()
+
+	logger := clogger.New()
+	lc := clifecycle.New()
+
+	server := chttp.NewServer(chttp.NewServerParams{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(r.Proto))
+		}),
+		Config:    chttp.Config{Listen: []string{"127.0.0.1:0"}, EnableH2C: true},
+		Logger:    logger,
+		Lifecycle: lc,
+	})
+
+	assert.NoError(t, server.Run())
+
+	client := http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				return net.Dial(network, addr)
+			},
+		},
+	}
+
+	resp, err := client.Get("http://" + server.Addrs()[0].String()) //nolint:noctx
+	assert.NoError(t, err)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, resp.Body.Close())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "HTTP/2.0", string(body))
+
+	lc.Stop(logger)
+}
+
+func TestServer_Run_MultipleListeners(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger   = clogger.New()
+		lc       = clifecycle.New()
+		sockPath = path.Join(t.TempDir(), "app.sock")
+	)
+
+	server := chttp.NewServer(chttp.NewServerParams{
+		Handler: http.NotFoundHandler(),
+		Config: chttp.Config{
+			Listen:         []string{"127.0.0.1:0", "unix:" + sockPath},
+			UnixSocketMode: "0600",
+		},
+		Logger:    logger,
+		Lifecycle: lc,
+	})
+
+	assert.NoError(t, server.Run())
 
-	time.Sleep(50 * time.Millisecond) // wait for server to start
+	info, err := os.Stat(sockPath)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
 
-	resp, err := http.Get("http://127.0.0.1:8999") //nolint:noctx
+	resp, err := http.Get("http://" + server.Addrs()[0].String()) //nolint:noctx
 	assert.NoError(t, err)
 	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	unixClient := http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", sockPath)
+			},
+		},
+	}
 
+	resp, err = unixClient.Get("http://unix/") //nolint:noctx
+	assert.NoError(t, err)
 	assert.NoError(t, resp.Body.Close())
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 
 	lc.Stop(logger)
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logs      = make([]clogger.RecordedLog, 0)
+		logger    = clogger.NewRecorder(&logs)
+		lc        = clifecycle.New()
+		started   = make(chan struct{})
+		release   = make(chan struct{})
+		hookCalls = 0
+	)
 
-	time.Sleep(50 * time.Millisecond) // wait for server to stop
+	server := chttp.NewServer(chttp.NewServerParams{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+		}),
+		Config:    chttp.Config{Listen: []string{"127.0.0.1:0"}, DrainTimeout: 50 * time.Millisecond},
+		Logger:    logger,
+		Lifecycle: lc,
+	})
+
+	server.OnShutdown(func(ctx context.Context) error {
+		assert.NoError(t, server.Context().Err())
+
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+
+		hookCalls++
+
+		return nil
+	})
+
+	assert.NoError(t, server.Run())
+
+	clientErr := make(chan error)
+
+	go func() {
+		resp, err := http.Get("http://" + server.Addrs()[0].String()) //nolint:noctx
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+
+		clientErr <- err
+	}()
+
+	<-started
+
+	err := lc.Stop(logger)
+
+	// The connection is closed once the drain timeout passes even though the handler has not returned
+	assert.Error(t, <-clientErr)
+	close(release)
+
+	assert.Equal(t, 1, hookCalls)
+	assert.ErrorIs(t, server.Context().Err(), context.Canceled)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, "Failed to run cleanup func", logs[len(logs)-2].Msg)
+	assert.ErrorIs(t, logs[len(logs)-2].Error, context.DeadlineExceeded)
+	assert.Equal(t, "Shutdown report", logs[len(logs)-1].Msg)
+}
+
+func TestServer_Limits(t *testing.T) {
+	t.Parallel()
+
+	logger := clogger.New()
+	lc := clifecycle.New()
+
+	server := chttp.NewServer(chttp.NewServerParams{
+		Handler: http.NotFoundHandler(),
+		Config: chttp.Config{
+			Port:           0,
+			MaxHeaderBytes: 1,
+		},
+		Logger:    logger,
+		Lifecycle: lc,
+	})
+
+	assert.NoError(t, server.Run())
+	defer lc.Stop(logger)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+		"http://"+server.Addrs()[0].String(), nil)
+	assert.NoError(t, err)
+
+	req.Header.Set("X-Large-Header", strings.Repeat("a", 8192))
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
 
-	_, err = http.Get("http://127.0.0.1:8999") //nolint:noctx,bodyclose
-	assert.EqualError(t, err, "Get \"http://127.0.0.1:8999\": dial tcp 127.0.0.1:8999: connect: connection refused")
+	assert.Equal(t, http.StatusRequestHeaderFieldsTooLarge, resp.StatusCode)
 }
@@ -0,0 +1,192 @@
+package chttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+const defaultSSEHeartbeat = 15 * time.Second
+
+var (
+	errSSENotSupported = errors.New("response writer does not support flushing")
+	errSSEClosed       = errors.New("client disconnected")
+)
+
+type (
+	// WriteSSEParams holds the params for the WriteSSE function in ReaderWriter.
+	WriteSSEParams struct {
+		// Heartbeat is how often a comment is sent to keep the connection open through proxies. It defaults to 15s.
+		Heartbeat time.Duration
+
+		// Stream is called with the stream to send events on. The response ends when it returns.
+		Stream func(s *SSEStream) error
+	}
+
+	// SSEEvent is a single server-sent event. Data is written as-is if it is a string or []byte and as JSON otherwise.
+	// Multi-line data is split into multiple data fields.
+	SSEEvent struct {
+		ID    string
+		Event string
+		Data  interface{}
+		Retry time.Duration
+	}
+
+	// SSEStream sends server-sent events to a client.
+	SSEStream struct {
+		w           http.ResponseWriter
+		flusher     http.Flusher
+		r           *http.Request
+		lastEventID string
+
+		mu     sync.Mutex
+		closed bool
+	}
+)
+
+// WriteSSE starts a server-sent events (text/event-stream) response and calls p.Stream to send events. Heartbeats
+// are sent while the stream is open. Stream should return once the client disconnects, which can be detected with
+// SSEStream.Done or by SSEStream.Send returning an error. Clients that reconnect send the id of the last event they
+// received, which is available with SSEStream.LastEventID so that the stream can resume.
+func (rw *ReaderWriter) WriteSSE(w http.ResponseWriter, r *http.Request, p WriteSSEParams) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		rw.logger.Error("Failed to start event stream", errSSENotSupported)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	if p.Heartbeat <= 0 {
+		p.Heartbeat = defaultSSEHeartbeat
+	}
+
+	s := SSEStream{
+		w:           w,
+		flusher:     flusher,
+		r:           r,
+		lastEventID: r.Header.Get("Last-Event-ID"),
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	stopHeartbeat := make(chan struct{})
+	heartbeatDone := make(chan struct{})
+
+	go func() {
+		defer close(heartbeatDone)
+
+		ticker := time.NewTicker(p.Heartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stopHeartbeat:
+				return
+			case <-r.Context().Done():
+				return
+			case <-ticker.C:
+				_ = s.write(": heartbeat\n\n")
+			}
+		}
+	}()
+
+	err := p.Stream(&s)
+
+	close(stopHeartbeat)
+	<-heartbeatDone
+
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	if err != nil && !errors.Is(err, errSSEClosed) {
+		rw.logger.WithTags(map[string]interface{}{
+			"url": r.URL.String(),
+		}).Error("Event stream failed", err)
+	}
+}
+
+// LastEventID returns the id of the last event that the client received before reconnecting, if any.
+func (s *SSEStream) LastEventID() string {
+	return s.lastEventID
+}
+
+// Done returns a channel that is closed when the client disconnects.
+func (s *SSEStream) Done() <-chan struct{} {
+	return s.r.Context().Done()
+}
+
+// Send writes the event and flushes it to the client. It returns an error if the client has disconnected.
+func (s *SSEStream) Send(e SSEEvent) error {
+	var data string
+
+	switch d := e.Data.(type) {
+	case string:
+		data = d
+	case []byte:
+		data = string(d)
+	default:
+		out, err := json.Marshal(d)
+		if err != nil {
+			return cerrors.New(err, "failed to marshal event data", nil)
+		}
+
+		data = string(out)
+	}
+
+	var msg strings.Builder
+
+	if e.ID != "" {
+		_, _ = fmt.Fprintf(&msg, "id: %s\n", sanitizeSSEField(e.ID))
+	}
+
+	if e.Event != "" {
+		_, _ = fmt.Fprintf(&msg, "event: %s\n", sanitizeSSEField(e.Event))
+	}
+
+	if e.Retry > 0 {
+		_, _ = fmt.Fprintf(&msg, "retry: %d\n", e.Retry.Milliseconds())
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		_, _ = fmt.Fprintf(&msg, "data: %s\n", line)
+	}
+
+	msg.WriteString("\n")
+
+	return s.write(msg.String())
+}
+
+func (s *SSEStream) write(msg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || s.r.Context().Err() != nil {
+		return errSSEClosed
+	}
+
+	_, err := s.w.Write([]byte(msg))
+	if err != nil {
+		return cerrors.New(err, "failed to write event", nil)
+	}
+
+	s.flusher.Flush()
+
+	return nil
+}
+
+func sanitizeSSEField(val string) string {
+	return strings.NewReplacer("\n", "", "\r", "").Replace(val)
+}
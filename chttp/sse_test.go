@@ -0,0 +1,71 @@
+package chttp_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReaderWriter_WriteSSE(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger = clogger.NewNoop()
+		rw     = chttptest.NewReaderWriter(t)
+	)
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{chttptest.NewRouter([]chttp.Route{
+			{
+				Path:    "/events",
+				Methods: []string{http.MethodGet},
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					rw.WriteSSE(w, r, chttp.WriteSSEParams{
+						Heartbeat: 10 * time.Millisecond,
+						Stream: func(s *chttp.SSEStream) error {
+							err := s.Send(chttp.SSEEvent{
+								ID:    "2",
+								Event: "progress",
+								Data:  map[string]string{"resumedFrom": s.LastEventID()},
+							})
+							if err != nil {
+								return err
+							}
+
+							time.Sleep(25 * time.Millisecond)
+
+							return s.Send(chttp.SSEEvent{Data: "line 1\nline 2"})
+						},
+					})
+				},
+			},
+		})},
+		GlobalMiddlewares: []chttp.Middleware{chttp.NewRequestLoggerMiddleware(logger)},
+		Logger:            logger,
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/events", nil) //nolint:noctx
+	assert.NoError(t, err)
+
+	req.Header.Set("Last-Event-ID", "1")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Contains(t, string(body), "id: 2\nevent: progress\ndata: {\"resumedFrom\":\"1\"}\n\n")
+	assert.Contains(t, string(body), ": heartbeat\n\n")
+	assert.Contains(t, string(body), "data: line 1\ndata: line 2\n\n")
+}
@@ -0,0 +1,233 @@
+package chttp
+
+import (
+	"html/template"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template/parse"
+)
+
+// templateContract holds the fields of the root data that a template accesses.
+type templateContract struct {
+	fields map[string]bool
+
+	// usesAllData is true if the root data is passed as a whole to something that cannot be analyzed (ex. a func).
+	usesAllData bool
+}
+
+// newTemplateContract walks the parse tree of the named template (and the templates it calls with the root data) to
+// find the fields of the root data that it accesses. Fields accessed inside range and with blocks are relative to
+// another value, so they are not included unless they are accessed through $.
+func newTemplateContract(tmpl *template.Template, name string) *templateContract {
+	c := templateContract{fields: make(map[string]bool)}
+
+	c.walkTemplate(tmpl, name, make(map[string]bool))
+
+	return &c
+}
+
+// check returns the fields that the template accesses but the data does not have, and the top-level fields that the
+// data has but the template does not access.
+func (c *templateContract) check(data interface{}) (missing, unused []string) {
+	missing = make([]string, 0)
+	unused = make([]string, 0)
+
+	used := make(map[string]bool)
+
+	for field := range c.fields {
+		path := strings.Split(field, ".")
+		used[path[0]] = true
+
+		if !hasFieldPath(reflect.ValueOf(data), path) {
+			missing = append(missing, field)
+		}
+	}
+
+	if !c.usesAllData {
+		for _, field := range topLevelFields(reflect.ValueOf(data)) {
+			if !used[field] {
+				unused = append(unused, field)
+			}
+		}
+	}
+
+	sort.Strings(missing)
+	sort.Strings(unused)
+
+	return missing, unused
+}
+
+func (c *templateContract) walkTemplate(tmpl *template.Template, name string, visited map[string]bool) {
+	if visited[name] {
+		return
+	}
+
+	visited[name] = true
+
+	t := tmpl.Lookup(name)
+	if t == nil || t.Tree == nil || t.Tree.Root == nil {
+		return
+	}
+
+	c.walk(tmpl, t.Tree.Root, true, visited)
+}
+
+func (c *templateContract) walk(tmpl *template.Template, node parse.Node, dotIsRoot bool, visited map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, child := range n.Nodes {
+			c.walk(tmpl, child, dotIsRoot, visited)
+		}
+	case *parse.ActionNode:
+		c.walkPipe(n.Pipe, dotIsRoot)
+	case *parse.IfNode:
+		c.walkPipe(n.Pipe, dotIsRoot)
+		c.walk(tmpl, n.List, dotIsRoot, visited)
+		c.walk(tmpl, n.ElseList, dotIsRoot, visited)
+	case *parse.RangeNode:
+		c.walkPipe(n.Pipe, dotIsRoot)
+		c.walk(tmpl, n.List, false, visited)
+		c.walk(tmpl, n.ElseList, dotIsRoot, visited)
+	case *parse.WithNode:
+		c.walkPipe(n.Pipe, dotIsRoot)
+		c.walk(tmpl, n.List, false, visited)
+		c.walk(tmpl, n.ElseList, dotIsRoot, visited)
+	case *parse.TemplateNode:
+		if n.Pipe == nil || !dotIsRoot {
+			return
+		}
+
+		if isDotPipe(n.Pipe) {
+			c.walkTemplate(tmpl, n.Name, visited)
+			return
+		}
+
+		c.walkPipe(n.Pipe, dotIsRoot)
+	}
+}
+
+func (c *templateContract) walkPipe(pipe *parse.PipeNode, dotIsRoot bool) {
+	if pipe == nil {
+		return
+	}
+
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			c.walkArg(arg, dotIsRoot)
+		}
+	}
+}
+
+func (c *templateContract) walkArg(arg parse.Node, dotIsRoot bool) {
+	switch a := arg.(type) {
+	case *parse.FieldNode:
+		if dotIsRoot {
+			c.fields[strings.Join(a.Ident, ".")] = true
+		}
+	case *parse.VariableNode:
+		if a.Ident[0] == "$" && len(a.Ident) > 1 {
+			c.fields[strings.Join(a.Ident[1:], ".")] = true
+		} else if a.Ident[0] == "$" {
+			c.usesAllData = true
+		}
+	case *parse.DotNode:
+		if dotIsRoot {
+			c.usesAllData = true
+		}
+	case *parse.PipeNode:
+		c.walkPipe(a, dotIsRoot)
+	}
+}
+
+func isDotPipe(pipe *parse.PipeNode) bool {
+	if len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+
+	_, ok := pipe.Cmds[0].Args[0].(*parse.DotNode)
+
+	return ok
+}
+
+// hasFieldPath checks if the value has the given path of map keys, struct fields, or methods. Values that cannot be
+// inspected further (ex. nil pointers or interface{} values) are assumed to have the rest of the path.
+func hasFieldPath(v reflect.Value, path []string) bool {
+	for _, name := range path {
+		if !v.IsValid() {
+			return true
+		}
+
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return true
+			}
+
+			v = v.Elem()
+		}
+
+		switch v.Kind() { //nolint:exhaustive
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return true
+			}
+
+			val := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !val.IsValid() {
+				return false
+			}
+
+			v = val
+		case reflect.Struct:
+			if m := reflect.New(v.Type()).MethodByName(name); m.IsValid() {
+				return true
+			}
+
+			field := v.FieldByName(name)
+			if !field.IsValid() {
+				return false
+			}
+
+			v = field
+		default:
+			return true
+		}
+	}
+
+	return true
+}
+
+func topLevelFields(v reflect.Value) []string {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	fields := make([]string, 0)
+
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+
+		for _, key := range v.MapKeys() {
+			fields = append(fields, key.String())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				fields = append(fields, v.Type().Field(i).Name)
+			}
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,53 @@
+package chttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLRenderer_CheckTemplateData(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logs    = make([]clogger.RecordedLog, 0)
+		logger  = clogger.NewRecorder(&logs)
+		config  = chttp.Config{CheckTemplateData: true}
+		htmlDir = fstest.MapFS{
+			"src/layouts/main.html": &fstest.MapFile{
+				Data: []byte(`<title>{{ .Title }}</title>{{ template "content" . }}`),
+			},
+			"src/pages/posts.html": &fstest.MapFile{
+				Data: []byte(`{{ define "content" }}{{ .User.Name }}` +
+					`{{ range .Posts }}{{ .Body }}{{ $.Footer }}{{ end }}{{ end }}`),
+			},
+		}
+	)
+
+	renderer, err := chttp.NewHTMLRenderer(chttp.NewHTMLRendererParams{
+		HTMLDir: htmlDir,
+		Config:  config,
+		Logger:  logger,
+	})
+	assert.NoError(t, err)
+
+	chttp.NewReaderWriter(renderer, config, logger).WriteHTML(httptest.NewRecorder(),
+		httptest.NewRequest(http.MethodGet, "/", nil), chttp.WriteHTMLParams{
+			PageTemplate: "posts.html",
+			Data: map[string]interface{}{
+				"Title": "Posts",
+				"User":  map[string]string{"Email": "user@example.com"},
+				"Posts": []map[string]string{{"Body": "Hello"}},
+				"Draft": true,
+			},
+		})
+
+	assert.Equal(t, 1, len(logs))
+	assert.Equal(t, []string{"Footer", "User.Name"}, logs[0].Tags["missing"])
+	assert.Equal(t, []string{"Draft"}, logs[0].Tags["unused"])
+}
@@ -0,0 +1,89 @@
+package chttp
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"sort"
+)
+
+type ctxTheme string
+
+const ctxThemeKey = ctxTheme("chttp/theme")
+
+// Theme overrides the app's templates and static files for a request. For example, white-label apps can resolve the
+// tenant in a middleware and set its theme so that the tenant's partials, stylesheets, and logos are used instead of
+// the app's. Files that are not in the theme are served from the app's HTMLDir and StaticDir.
+type Theme struct {
+	// HTMLDir has the same layout as the app's HTMLDir (ex. src/partials/header.html).
+	HTMLDir fs.FS
+
+	// StaticDir has the same layout as the app's StaticDir.
+	StaticDir fs.FS
+}
+
+// WithTheme returns a context with the given theme. WriteHTML and the HTMLRouter's static files use it.
+func WithTheme(ctx context.Context, theme Theme) context.Context {
+	return context.WithValue(ctx, ctxThemeKey, theme)
+}
+
+// ThemeFromCtx returns the theme set with WithTheme, if any.
+func ThemeFromCtx(ctx context.Context) (Theme, bool) {
+	theme, ok := ctx.Value(ctxThemeKey).(Theme)
+
+	return theme, ok
+}
+
+// overlayFS serves files from top and falls back to base for files that top does not have. Directories list the
+// files of both so that globs (ex. src/partials/*.html) match files from either.
+type overlayFS struct {
+	top  fs.FS
+	base fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	f, err := o.top.Open(name)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return f, err
+	}
+
+	return o.base.Open(name)
+}
+
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	topEntries, topErr := fs.ReadDir(o.top, name)
+	if topErr != nil && !errors.Is(topErr, fs.ErrNotExist) {
+		return nil, topErr
+	}
+
+	baseEntries, baseErr := fs.ReadDir(o.base, name)
+	if baseErr != nil && !errors.Is(baseErr, fs.ErrNotExist) {
+		return nil, baseErr
+	}
+
+	if topErr != nil && baseErr != nil {
+		return nil, baseErr
+	}
+
+	var (
+		entries = make([]fs.DirEntry, 0, len(topEntries)+len(baseEntries))
+		seen    = make(map[string]bool, len(topEntries))
+	)
+
+	for _, entry := range topEntries {
+		seen[entry.Name()] = true
+		entries = append(entries, entry)
+	}
+
+	for _, entry := range baseEntries {
+		if !seen[entry.Name()] {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	return entries, nil
+}
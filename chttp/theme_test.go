@@ -0,0 +1,89 @@
+package chttp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTheme_HTML(t *testing.T) {
+	t.Parallel()
+
+	var (
+		config  = chttp.Config{}
+		htmlDir = fstest.MapFS{
+			"src/layouts/main.html":    {Data: []byte(`{{ partial "header" . }} {{ partial "footer" . }}`)},
+			"src/pages/index.html":     {Data: []byte(``)},
+			"src/partials/header.html": {Data: []byte(`app header`)},
+			"src/partials/footer.html": {Data: []byte(`app footer`)},
+		}
+		theme = chttp.Theme{HTMLDir: fstest.MapFS{
+			"src/partials/header.html": {Data: []byte(`tenant header`)},
+		}}
+	)
+
+	renderer, err := chttp.NewHTMLRenderer(chttp.NewHTMLRendererParams{
+		HTMLDir: htmlDir,
+		Config:  config,
+		Logger:  clogger.NewNoop(),
+	})
+	assert.NoError(t, err)
+
+	rw := chttp.NewReaderWriter(renderer, config, clogger.NewNoop())
+
+	testCases := map[string]struct {
+		req  *http.Request
+		want string
+	}{
+		"no theme": {httptest.NewRequest(http.MethodGet, "/", nil), "app header app footer"},
+		"theme": {
+			httptest.NewRequest(http.MethodGet, "/", nil).WithContext(chttp.WithTheme(context.Background(), theme)),
+			"tenant header app footer",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := httptest.NewRecorder()
+			rw.WriteHTML(resp, tc.req, chttp.WriteHTMLParams{PageTemplate: "index.html"})
+
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.Equal(t, tc.want, resp.Body.String())
+		})
+	}
+}
+
+func TestWithTheme_StaticFile(t *testing.T) {
+	t.Parallel()
+
+	router, err := chttp.NewHTMLRouter(chttp.NewHTMLRouterParams{
+		StaticDir: fstest.MapFS{
+			"static/logo.svg": {Data: []byte("app logo")},
+			"static/app.css":  {Data: []byte("app css")},
+		},
+	})
+	assert.NoError(t, err)
+
+	theme := chttp.Theme{StaticDir: fstest.MapFS{
+		"static/logo.svg": {Data: []byte("tenant logo")},
+	}}
+
+	for path, want := range map[string]string{"/static/logo.svg": "tenant logo", "/static/app.css": "app css"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		resp := httptest.NewRecorder()
+
+		router.HandleStaticFile(resp, req.WithContext(chttp.WithTheme(req.Context(), theme)))
+
+		assert.Equal(t, want, resp.Body.String())
+	}
+}
@@ -0,0 +1,255 @@
+package chttp
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+const (
+	sniffLen           = 512
+	maxUploadFormBytes = 1 << 20
+)
+
+var (
+	errUploadTooLarge         = errors.New("file is too large")
+	errUploadTypeNotAllowed   = errors.New("file type is not allowed")
+	errUploadExtNotAllowed    = errors.New("file extension is not allowed")
+	errUploadMissing          = errors.New("file is required")
+	errUploadFormValuesTooBig = errors.New("form values are too large")
+)
+
+// ReadUploadParams holds the params for the ReadUpload function in ReaderWriter.
+type ReadUploadParams struct {
+	// Field is the name of the form field with the file.
+	Field string
+
+	// Dest is where the file's content is written. The file is streamed, so it is not held in memory or written to
+	// a temporary file. If the file turns out to be too large, Dest has the first MaxBytes bytes and should be
+	// discarded.
+	Dest io.Writer
+
+	// MaxBytes limits the size of the file. There is no limit if it is not set.
+	MaxBytes int64
+
+	// AllowedTypes lists the allowed content types (ex. image/png or image/*). The content type is detected from
+	// the file's content instead of the one sent by the client. All types are allowed if it is empty.
+	AllowedTypes []string
+
+	// AllowedExtensions lists the allowed file name extensions (ex. .png). All extensions are allowed if it is empty.
+	AllowedExtensions []string
+}
+
+// Upload describes a file read by ReadUpload.
+type Upload struct {
+	Filename    string
+	ContentType string
+	Size        int64
+}
+
+// ReadUpload streams the file in the multipart form field p.Field to p.Dest after validating its type and extension.
+// The other form values are available with req.FormValue or ReadForm after it returns. If the file is missing or not
+// valid, an UnprocessableEntity response with the reason is sent back, in the same format as the validation errors
+// of ReadJSON and ReadForm, and false is returned.
+func (rw *ReaderWriter) ReadUpload(w http.ResponseWriter, req *http.Request, p ReadUploadParams) (Upload, bool) {
+	var (
+		upload   Upload
+		found    bool
+		values   = make(url.Values)
+		valuesSz int64
+	)
+
+	mr, err := req.MultipartReader()
+	if err != nil {
+		rw.writeUploadError(w, req, cerrors.New(err, "invalid multipart form", nil))
+		return Upload{}, false
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			rw.writeUploadError(w, req, err)
+			return Upload{}, false
+		}
+
+		switch {
+		case part.FileName() == "":
+			valuesSz, err = readFormValue(part, values, valuesSz)
+		case part.FormName() == p.Field && !found:
+			found = true
+			upload, err = readUploadPart(part, p)
+		}
+
+		_ = part.Close()
+
+		if err != nil {
+			var validationErr uploadValidationError
+			if errors.As(err, &validationErr) {
+				rw.writeUploadValidationError(w, req, p.Field, validationErr)
+				return Upload{}, false
+			}
+
+			rw.writeUploadError(w, req, err)
+
+			return Upload{}, false
+		}
+	}
+
+	setUploadFormValues(req, values)
+
+	if !found {
+		rw.writeUploadValidationError(w, req, p.Field, uploadValidationError{errUploadMissing})
+		return Upload{}, false
+	}
+
+	return upload, true
+}
+
+type uploadValidationError struct {
+	err error
+}
+
+func (e uploadValidationError) Error() string {
+	return e.err.Error()
+}
+
+func readUploadPart(part *multipart.Part, p ReadUploadParams) (Upload, error) {
+	upload := Upload{Filename: filepath.Base(part.FileName())}
+
+	if len(p.AllowedExtensions) > 0 && !containsFold(p.AllowedExtensions, filepath.Ext(upload.Filename)) {
+		return Upload{}, uploadValidationError{errUploadExtNotAllowed}
+	}
+
+	r := bufio.NewReaderSize(part, sniffLen)
+
+	head, err := r.Peek(sniffLen)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return Upload{}, err
+	}
+
+	upload.ContentType = http.DetectContentType(head)
+
+	if len(p.AllowedTypes) > 0 && !contentTypeAllowed(p.AllowedTypes, upload.ContentType) {
+		return Upload{}, uploadValidationError{errUploadTypeNotAllowed}
+	}
+
+	var src io.Reader = r
+	if p.MaxBytes > 0 {
+		src = io.LimitReader(r, p.MaxBytes+1)
+	}
+
+	upload.Size, err = io.Copy(p.Dest, src)
+	if err != nil {
+		return Upload{}, cerrors.New(err, "failed to write upload", map[string]interface{}{
+			"filename": upload.Filename,
+		})
+	}
+
+	if p.MaxBytes > 0 && upload.Size > p.MaxBytes {
+		return Upload{}, uploadValidationError{errUploadTooLarge}
+	}
+
+	return upload, nil
+}
+
+func readFormValue(part *multipart.Part, values url.Values, size int64) (int64, error) {
+	b, err := io.ReadAll(io.LimitReader(part, maxUploadFormBytes-size+1))
+	if err != nil {
+		return size, err
+	}
+
+	size += int64(len(b))
+	if size > maxUploadFormBytes {
+		return size, errUploadFormValuesTooBig
+	}
+
+	values.Add(part.FormName(), string(b))
+
+	return size, nil
+}
+
+// setUploadFormValues makes the form values available to req.FormValue and ReadForm since the multipart body can
+// only be read once.
+func setUploadFormValues(req *http.Request, values url.Values) {
+	req.PostForm = values
+	req.Form = make(url.Values)
+
+	for k, v := range req.URL.Query() {
+		req.Form[k] = append(req.Form[k], v...)
+	}
+
+	for k, v := range values {
+		req.Form[k] = append(req.Form[k], v...)
+	}
+
+	req.MultipartForm = &multipart.Form{Value: values}
+}
+
+func contentTypeAllowed(allowed []string, contentType string) bool {
+	contentType = strings.TrimSpace(strings.Split(contentType, ";")[0])
+
+	for _, t := range allowed {
+		if strings.EqualFold(t, contentType) {
+			return true
+		}
+
+		if strings.HasSuffix(t, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(t, "*")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (rw *ReaderWriter) writeUploadValidationError(w http.ResponseWriter, req *http.Request, field string,
+	err uploadValidationError) {
+	rw.logger.Warn("Failed to read upload", cerrors.New(err.err, "upload validation failed", map[string]interface{}{
+		"url":   req.URL.String(),
+		"field": field,
+	}))
+
+	rw.WriteJSON(w, WriteJSONParams{
+		StatusCode: http.StatusUnprocessableEntity,
+		Data: map[string]interface{}{
+			"error":  "validation failed",
+			"fields": map[string]string{field: err.Error()},
+		},
+	})
+}
+
+func (rw *ReaderWriter) writeUploadError(w http.ResponseWriter, req *http.Request, err error) {
+	if isBodyTooLarge(err) {
+		rw.writeBodyTooLarge(w, req, err)
+		return
+	}
+
+	rw.logger.Warn("Failed to read upload", cerrors.New(err, "invalid upload", map[string]interface{}{
+		"url": req.URL.String(),
+	}))
+
+	rw.WriteJSON(w, WriteJSONParams{
+		StatusCode: http.StatusBadRequest,
+		Data:       err,
+	})
+}
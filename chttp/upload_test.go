@@ -0,0 +1,140 @@
+package chttp_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n0000000000") //nolint:gochecknoglobals
+
+func newUploadRequest(t *testing.T, filename string, content []byte) *http.Request {
+	t.Helper()
+
+	var (
+		body bytes.Buffer
+		mw   = multipart.NewWriter(&body)
+	)
+
+	assert.NoError(t, mw.WriteField("title", "My avatar"))
+
+	if filename != "" {
+		fw, err := mw.CreateFormFile("avatar", filename)
+		assert.NoError(t, err)
+
+		_, err = fw.Write(content)
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/avatar?user=1", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	return req
+}
+
+func TestReaderWriter_ReadUpload(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rw   = chttp.NewReaderWriter(nil, chttp.Config{}, clogger.NewNoop())
+		dest bytes.Buffer
+		resp = httptest.NewRecorder()
+		req  = newUploadRequest(t, "me.PNG", pngHeader)
+	)
+
+	upload, ok := rw.ReadUpload(resp, req, chttp.ReadUploadParams{
+		Field:             "avatar",
+		Dest:              &dest,
+		MaxBytes:          1024,
+		AllowedTypes:      []string{"image/*"},
+		AllowedExtensions: []string{".png", ".jpg"},
+	})
+
+	assert.True(t, ok)
+	assert.Equal(t, chttp.Upload{Filename: "me.PNG", ContentType: "image/png", Size: int64(len(pngHeader))}, upload)
+	assert.Equal(t, pngHeader, dest.Bytes())
+	assert.Equal(t, "My avatar", req.FormValue("title"))
+	assert.Equal(t, "1", req.FormValue("user"))
+
+	var form struct {
+		Title string `form:"title"`
+	}
+
+	assert.True(t, rw.ReadForm(resp, req, &form))
+	assert.Equal(t, "My avatar", form.Title)
+}
+
+func TestReaderWriter_ReadUpload_Invalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		filename string
+		content  []byte
+		want     string
+	}{
+		"too large":     {"me.png", append(pngHeader, make([]byte, 32)...), "file is too large"},
+		"type":          {"me.png", []byte("<html></html>"), "file type is not allowed"},
+		"extension":     {"me.exe", pngHeader, "file extension is not allowed"},
+		"missing field": {"", nil, "file is required"},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				rw   = chttp.NewReaderWriter(nil, chttp.Config{}, clogger.NewNoop())
+				dest bytes.Buffer
+				resp = httptest.NewRecorder()
+			)
+
+			_, ok := rw.ReadUpload(resp, newUploadRequest(t, tc.filename, tc.content), chttp.ReadUploadParams{
+				Field:             "avatar",
+				Dest:              &dest,
+				MaxBytes:          32,
+				AllowedTypes:      []string{"image/png"},
+				AllowedExtensions: []string{".png"},
+			})
+
+			assert.False(t, ok)
+			assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+			var body struct {
+				Error  string            `json:"error"`
+				Fields map[string]string `json:"fields"`
+			}
+
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, "validation failed", body.Error)
+			assert.Equal(t, map[string]string{"avatar": tc.want}, body.Fields)
+		})
+	}
+}
+
+func TestReaderWriter_ReadUpload_NotMultipart(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rw   = chttp.NewReaderWriter(nil, chttp.Config{}, clogger.NewNoop())
+		resp = httptest.NewRecorder()
+	)
+
+	_, ok := rw.ReadUpload(resp, httptest.NewRequest(http.MethodPost, "/", nil), chttp.ReadUploadParams{
+		Field: "avatar",
+		Dest:  &bytes.Buffer{},
+	})
+
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
@@ -8,15 +8,32 @@ import (
 var WireModule = wire.NewSet( //nolint:gochecknoglobals
 	LoadConfig,
 	NewReaderWriter,
-	NewRequestLoggerMiddleware,
+	NewRequestLoggerMiddlewareWithConfig,
+	NewCompressMiddleware,
+	NewRequestIDMiddleware,
+	NewMaxBodySizeMiddleware,
+	NewSecureHeadersMiddleware,
+	NewIPFilter,
+	NewMaintenanceMiddleware,
+	wire.Struct(new(NewIdempotencyMiddlewareParams), "*"),
+	NewIdempotencyMiddleware,
 	wire.Struct(new(NewServerParams), "*"),
 	NewServer,
 	wire.Struct(new(NewHTMLRouterParams), "*"),
 	NewHTMLRouter,
+	wire.Struct(new(NewProxyRouterParams), "*"),
+	NewProxyRouter,
 	wire.Struct(new(NewHTMLRendererParams), "*"),
 	NewHTMLRenderer,
 )
 
+// WireModuleMemoryIdempotencyStore provides a MemoryIdempotencyStore as the IdempotencyStore for the
+// IdempotencyMiddleware.
+var WireModuleMemoryIdempotencyStore = wire.NewSet( //nolint:gochecknoglobals
+	NewMemoryIdempotencyStore,
+	wire.Bind(new(IdempotencyStore), new(*MemoryIdempotencyStore)),
+)
+
 // WireModuleEmptyHTML provides empty/default values for html and static dirs. This can be used to satisfy
 // wire when the project does not use/need html rendering.
 var WireModuleEmptyHTML = wire.NewSet( //nolint:gochecknoglobals
@@ -2,7 +2,11 @@ package clifecycle
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
+
+	"github.com/gocopper/copper/cerrors"
 )
 
 const defaultStopTimeout = 10 * time.Second
@@ -11,7 +15,7 @@ const defaultStopTimeout = 10 * time.Second
 // New to create a Copper app.
 func New() *Lifecycle {
 	return &Lifecycle{
-		onStop:      make([]func(ctx context.Context) error, 0),
+		onStop:      make([]stopFunc, 0),
 		stopTimeout: defaultStopTimeout,
 	}
 }
@@ -22,28 +26,97 @@ func New() *Lifecycle {
 // Packages such as chttp use Lifecycle to gracefully stop the HTTP
 // server before the app exits.
 type Lifecycle struct {
-	onStop      []func(ctx context.Context) error
+	onStop      []stopFunc
 	stopTimeout time.Duration
 }
 
+type stopFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
 // OnStop registers the provided fn to run before the app exits. The fn
 // is given a context with a deadline. Once the deadline expires, the
 // app may exit forcefully.
 func (lc *Lifecycle) OnStop(fn func(ctx context.Context) error) {
-	lc.onStop = append(lc.onStop, fn)
+	lc.OnStopNamed("", fn)
+}
+
+// OnStopNamed is like OnStop but names the fn (ex. "http server") so
+// that it can be identified in the shutdown report.
+func (lc *Lifecycle) OnStopNamed(name string, fn func(ctx context.Context) error) {
+	lc.onStop = append(lc.onStop, stopFunc{name: name, fn: fn})
 }
 
 // Stop runs all of the registered stop funcs in order along with a
-// context with a configured timeout.
-func (lc *Lifecycle) Stop(logger Logger) {
-	for _, fn := range lc.onStop {
-		ctx, cancel := context.WithTimeout(context.Background(), lc.stopTimeout)
-
-		err := fn(ctx)
-		if err != nil {
-			logger.Error("Failed to run cleanup func", err)
+// context with a configured timeout. A stop func that does not return
+// before its timeout is abandoned so that the next ones can run.
+// Stop returns the failures of all of the stop funcs joined with
+// cerrors.Join. Failures caused by a timeout match
+// context.DeadlineExceeded with errors.Is.
+func (lc *Lifecycle) Stop(logger Logger) error {
+	var (
+		errs     = make([]error, 0)
+		timedOut = 0
+	)
+
+	for i, sf := range lc.onStop {
+		name := sf.name
+		if name == "" {
+			name = fmt.Sprintf("stop func #%d", i+1)
 		}
 
-		cancel()
+		err := lc.runStopFunc(sf.fn)
+		if err == nil {
+			continue
+		}
+
+		isTimeout := errors.Is(err, context.DeadlineExceeded)
+		if isTimeout {
+			timedOut++
+		}
+
+		err = cerrors.New(err, "failed to run stop func", map[string]interface{}{
+			"name":     name,
+			"timedOut": isTimeout,
+		})
+
+		logger.Error("Failed to run cleanup func", err)
+
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	err := cerrors.New(cerrors.Join(errs...), "app did not stop cleanly", map[string]interface{}{
+		"stopFuncs": len(lc.onStop),
+		"failed":    len(errs),
+		"timedOut":  timedOut,
+	})
+
+	logger.Error("Shutdown report", err)
+
+	return err
+}
+
+func (lc *Lifecycle) runStopFunc(fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), lc.stopTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return cerrors.New(ctx.Err(), "stop func did not return before the timeout", map[string]interface{}{
+			"timeout": lc.stopTimeout.String(),
+		})
 	}
 }
@@ -0,0 +1,77 @@
+package clifecycle_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/clifecycle"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycle_Stop(t *testing.T) {
+	t.Parallel()
+
+	var (
+		lc    = clifecycle.New()
+		calls = make([]string, 0)
+	)
+
+	lc.OnStopNamed("a", func(ctx context.Context) error {
+		calls = append(calls, "a")
+
+		return nil
+	})
+
+	lc.OnStop(func(ctx context.Context) error {
+		calls = append(calls, "b")
+
+		return nil
+	})
+
+	assert.NoError(t, lc.Stop(clogger.NewNoop()))
+	assert.Equal(t, []string{"a", "b"}, calls)
+}
+
+func TestLifecycle_Stop_Errors(t *testing.T) {
+	t.Parallel()
+
+	var (
+		lc   = clifecycle.New()
+		logs = make([]clogger.RecordedLog, 0)
+	)
+
+	lc.OnStopNamed("http server", func(ctx context.Context) error {
+		return context.DeadlineExceeded
+	})
+
+	lc.OnStopNamed("logger", func(ctx context.Context) error {
+		return nil
+	})
+
+	lc.OnStop(func(ctx context.Context) error {
+		return errors.New("failed to close") //nolint:goerr113
+	})
+
+	err := lc.Stop(clogger.NewRecorder(&logs))
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	var cErr cerrors.Error
+
+	assert.True(t, errors.As(err, &cErr))
+	assert.Equal(t, map[string]interface{}{"stopFuncs": 3, "failed": 2, "timedOut": 1}, cErr.Tags)
+
+	var joined cerrors.Joined
+
+	assert.True(t, errors.As(err, &joined))
+	assert.Equal(t, 2, len(joined.Errors))
+
+	assert.Equal(t, 3, len(logs))
+	assert.Equal(t, "Failed to run cleanup func", logs[0].Msg)
+	assert.Contains(t, logs[0].Error.Error(), "name=http server,timedOut=true")
+	assert.Contains(t, logs[1].Error.Error(), "name=stop func #3,timedOut=false")
+	assert.Equal(t, "Shutdown report", logs[2].Msg)
+}
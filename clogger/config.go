@@ -32,7 +32,29 @@ func LoadConfig(appConfig cconfig.Loader) (Config, error) {
 
 // Config holds the params needed to configure Logger
 type Config struct {
-	Out    string `toml:"out"`
-	Err    string `toml:"err"`
+	// Out is the path of the file that debug and info logs are appended to. It defaults to stdout.
+	Out string `toml:"out"`
+
+	// Err is the path of the file that warn and error logs are appended to. It defaults to stderr.
+	Err string `toml:"err"`
+
+	// Level is the lowest level that is logged: "debug" (default), "info", "warn", or "error". For example, it can be
+	// set to "info" in production and to "debug" while investigating an issue.
+	Level Level `toml:"level"`
+
+	// Levels overrides Level for the loggers created with Logger.Named, by name. For example:
+	//
+	//	[clogger.levels]
+	//	csql = "debug"
+	//	chttp = "warn"
+	//
+	// A logger named "chttp.server" uses the level of "chttp.server" if it is set, then the level of "chttp".
+	Levels map[string]Level `toml:"levels"`
+
+	// Sampling limits how often identical messages are written (see SamplingConfig).
+	Sampling SamplingConfig `toml:"sampling"`
+
+	// Format is "plain" (default) for human-readable lines, or "json" for one JSON object per entry with the time,
+	// level, message, tags, and the chain of errors, so logs can be ingested without parsing console output.
 	Format Format `toml:"format"`
 }
@@ -0,0 +1,35 @@
+package clogger
+
+import "context"
+
+type ctxKey string
+
+const ctxLoggerKey = ctxKey("clogger/logger")
+
+// WithCtx returns a context that carries the given logger, so that code deeper in the call stack can log with the
+// same tags using FromCtx. chttp stores a logger tagged with the route and request id in each request's context.
+func WithCtx(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, ctxLoggerKey, logger)
+}
+
+// FromCtx returns the logger stored in the context by WithCtx. If there is none, it returns a console logger (see
+// New) so that logs are not lost.
+func FromCtx(ctx context.Context) Logger {
+	logger, ok := ctx.Value(ctxLoggerKey).(Logger)
+	if !ok {
+		return New()
+	}
+
+	return logger
+}
+
+// WithCtxTags returns a context whose logger has the given tags in addition to its existing ones (ex. the user id once
+// the request is authenticated). The context is returned as is if it does not have a logger.
+func WithCtxTags(ctx context.Context, tags map[string]interface{}) context.Context {
+	logger, ok := ctx.Value(ctxLoggerKey).(Logger)
+	if !ok {
+		return ctx
+	}
+
+	return WithCtx(ctx, logger.WithTags(tags))
+}
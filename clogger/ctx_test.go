@@ -0,0 +1,26 @@
+package clogger_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromCtx(t *testing.T) {
+	t.Parallel()
+
+	var logs []clogger.RecordedLog
+
+	ctx := clogger.WithCtxTags(context.Background(), map[string]interface{}{"userID": "1"})
+	assert.NotNil(t, clogger.FromCtx(ctx))
+
+	ctx = clogger.WithCtx(ctx, clogger.NewRecorder(&logs).WithTags(map[string]interface{}{"requestID": "abc"}))
+	ctx = clogger.WithCtxTags(ctx, map[string]interface{}{"userID": "1"})
+
+	clogger.FromCtx(ctx).Info("test info log")
+
+	assert.Len(t, logs, 1)
+	assert.Equal(t, map[string]interface{}{"requestID": "abc", "userID": "1"}, logs[0].Tags)
+}
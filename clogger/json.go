@@ -0,0 +1,104 @@
+package clogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+// jsonEntry is a log entry written with FormatJSON. Each entry is written as a single line.
+type jsonEntry struct {
+	Time   string                 `json:"ts"`
+	Level  string                 `json:"level"`
+	Logger string                 `json:"logger,omitempty"`
+	Msg    string                 `json:"msg"`
+	Tags   map[string]interface{} `json:"tags,omitempty"`
+	Error  []jsonError            `json:"error,omitempty"`
+}
+
+// jsonError is one error in the chain of a logged error, starting with the outermost error.
+type jsonError struct {
+	Msg  string                 `json:"msg"`
+	Tags map[string]interface{} `json:"tags,omitempty"`
+}
+
+func (l *logger) logJSON(dest io.Writer, lvl Level, err error) {
+	entry := jsonEntry{
+		Time:   time.Now().Format(time.RFC3339Nano),
+		Level:  lvl.String(),
+		Logger: l.name,
+		Tags:   jsonTags(l.tags),
+	}
+
+	switch cerr := err.(type) { //nolint:errorlint
+	case cerrors.Error:
+		entry.Msg = cerr.Message
+		entry.Tags = jsonTags(mergeTags(l.tags, cerr.Tags))
+		entry.Error = errorChain(cerr.Cause)
+	default:
+		entry.Msg = err.Error()
+	}
+
+	line, marshalErr := json.Marshal(entry)
+	if marshalErr != nil {
+		// Tags that cannot be encoded (ex. funcs) are written with their default format instead of dropping the entry
+		entry.Tags = stringTags(entry.Tags)
+
+		for i := range entry.Error {
+			entry.Error[i].Tags = stringTags(entry.Error[i].Tags)
+		}
+
+		line, _ = json.Marshal(entry)
+	}
+
+	_, _ = dest.Write(append(line, '\n'))
+}
+
+// errorChain returns the chain of errors wrapped by err. The chain ends at the first error that is not a cerrors.Error
+// since its message may already include its causes.
+func errorChain(err error) []jsonError {
+	chain := make([]jsonError, 0)
+
+	for err != nil {
+		cerr, ok := err.(cerrors.Error) //nolint:errorlint
+		if !ok {
+			return append(chain, jsonError{Msg: err.Error()})
+		}
+
+		chain = append(chain, jsonError{Msg: cerr.Message, Tags: jsonTags(cerr.Tags)})
+		err = cerr.Cause
+	}
+
+	return chain
+}
+
+// jsonTags returns the tags with errors replaced by their messages, since errors are usually encoded as empty objects.
+func jsonTags(tags map[string]interface{}) map[string]interface{} {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	out := make(map[string]interface{}, len(tags))
+
+	for k, v := range tags {
+		switch val := v.(type) {
+		case error:
+			out[k] = val.Error()
+		default:
+			out[k] = v
+		}
+	}
+
+	return out
+}
+
+func stringTags(tags map[string]interface{}) map[string]interface{} {
+	for k, v := range tags {
+		tags[k] = fmt.Sprintf("%+v", v)
+	}
+
+	return tags
+}
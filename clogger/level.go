@@ -1,5 +1,11 @@
 package clogger
 
+import (
+	"strings"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
 // Level represents the severity level of a log.
 type Level int
 
@@ -25,3 +31,33 @@ func (l Level) String() string {
 		return "UNKNOWN"
 	}
 }
+
+// ParseLevel parses a level name (debug, info, warn, or error). It is not case-sensitive.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return 0, cerrors.New(nil, "invalid log level", map[string]interface{}{
+			"level": s,
+		})
+	}
+}
+
+// UnmarshalText parses the level with ParseLevel so that it can be set in config files.
+func (l *Level) UnmarshalText(text []byte) error {
+	lvl, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+
+	*l = lvl
+
+	return nil
+}
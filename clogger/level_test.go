@@ -1,8 +1,11 @@
 package clogger_test
 
 import (
+	"path"
 	"testing"
 
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
 	"github.com/gocopper/copper/clogger"
 	"github.com/stretchr/testify/assert"
 )
@@ -16,3 +19,58 @@ func TestLevel_String(t *testing.T) {
 	assert.Equal(t, "ERROR", clogger.LevelError.String())
 	assert.Equal(t, "UNKNOWN", clogger.Level(-99).String())
 }
+
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+
+	for name, want := range map[string]clogger.Level{
+		"debug":   clogger.LevelDebug,
+		"INFO":    clogger.LevelInfo,
+		"warning": clogger.LevelWarn,
+		" error ": clogger.LevelError,
+	} {
+		lvl, err := clogger.ParseLevel(name)
+		assert.NoError(t, err)
+		assert.Equal(t, want, lvl)
+	}
+
+	_, err := clogger.ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_Level(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[clogger]
+			level = "info"
+		`,
+	})
+
+	loader, err := cconfig.New(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+
+	config, err := clogger.LoadConfig(loader)
+	assert.NoError(t, err)
+	assert.Equal(t, clogger.LevelInfo, config.Level)
+}
+
+func TestLoadConfig_Levels(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[clogger.levels]
+			csql = "debug"
+			chttp = "warn"
+		`,
+	})
+
+	loader, err := cconfig.New(cconfig.Path(path.Join(dir, "test.toml")))
+	assert.NoError(t, err)
+
+	config, err := clogger.LoadConfig(loader)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]clogger.Level{"csql": clogger.LevelDebug, "chttp": clogger.LevelWarn}, config.Levels)
+}
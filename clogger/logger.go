@@ -1,26 +1,58 @@
 package clogger
 
 import (
-	"encoding/json"
 	"errors"
 	"io"
 	"log"
 	"os"
-	"time"
 
+	"github.com/gocopper/copper/cclock"
 	"github.com/gocopper/copper/cerrors"
 )
 
 // Logger can be used to log messages and errors.
 type Logger interface {
 	WithTags(tags map[string]interface{}) Logger
-
 	Debug(msg string)
 	Info(msg string)
 	Warn(msg string, err error)
 	Error(msg string, err error)
 }
 
+// NamedLogger is a Logger that supports component names and levels. The loggers in this package implement it, while
+// other loggers can implement it optionally. Use the Named and Enabled funcs to call it on any Logger.
+type NamedLogger interface {
+	Logger
+
+	// Named returns a Logger for a component of the app (ex. "csql"). Its logs include the name, and its level can
+	// be set with Config.Levels. Names of nested loggers are joined with dots (ex. "chttp.server").
+	Named(name string) Logger
+
+	// Enabled returns true if logs at the given level are written. It can be used to skip building expensive log
+	// messages that would be dropped.
+	Enabled(lvl Level) bool
+}
+
+// Named returns a Logger for a component of the app (see NamedLogger). It returns the logger as is if it does not
+// implement NamedLogger.
+func Named(logger Logger, name string) Logger {
+	if l, ok := logger.(NamedLogger); ok {
+		return l.Named(name)
+	}
+
+	return logger
+}
+
+// Enabled returns true if the logger writes logs at the given level (see NamedLogger). Loggers that do not implement
+// NamedLogger are assumed to write every level.
+func Enabled(logger Logger, lvl Level) bool {
+	if l, ok := logger.(NamedLogger); ok {
+		return l.Enabled(lvl)
+	}
+
+	return true
+}
+
 // New returns a Logger implementation that can logs to console.
 func New() Logger {
 	return NewWithWriters(os.Stdout, os.Stderr, FormatPlain)
@@ -54,11 +86,18 @@ func NewWithConfig(config Config) (Logger, error) {
 		}
 	}
 
-	return NewWithWriters(outFile, errFile, config.Format), nil
+	return NewSampler(&logger{
+		out:    outFile,
+		err:    errFile,
+		tags:   make(map[string]interface{}),
+		format: config.Format,
+		levels: newLevels(config.Level, config.Levels, nil),
+	}, config.Sampling, cclock.New()), nil
 }
 
 // NewWithWriters creates a Logger that uses the provided writers. out is
 // used for debug and info levels. err is used for warn and error levels.
+// Logs at every level are written.
 func NewWithWriters(out, err io.Writer, format Format) Logger {
 	return &logger{
 		out:    out,
@@ -73,15 +112,26 @@ type logger struct {
 	err    io.Writer
 	tags   map[string]interface{}
 	format Format
+	name   string
+	levels *levels
 }
 
 func (l *logger) WithTags(tags map[string]interface{}) Logger {
-	return &logger{
-		out:    l.out,
-		err:    l.err,
-		tags:   mergeTags(l.tags, tags),
-		format: l.format,
-	}
+	clone := *l
+	clone.tags = mergeTags(l.tags, tags)
+
+	return &clone
+}
+
+func (l *logger) Named(name string) Logger {
+	clone := *l
+	clone.name = joinName(l.name, name)
+
+	return &clone
+}
+
+func (l *logger) Enabled(lvl Level) bool {
+	return lvl >= l.levels.levelFor(l.name)
 }
 
 func (l *logger) Debug(msg string) {
@@ -101,6 +151,10 @@ func (l *logger) Error(msg string, err error) {
 }
 
 func (l *logger) log(dest io.Writer, lvl Level, err error) {
+	if !l.Enabled(lvl) {
+		return
+	}
+
 	switch l.format {
 	case FormatJSON:
 		l.logJSON(dest, lvl, err)
@@ -111,31 +165,11 @@ func (l *logger) log(dest io.Writer, lvl Level, err error) {
 	}
 }
 
-func (l *logger) logJSON(dest io.Writer, lvl Level, err error) {
-	var dict = map[string]interface{}{
-		"ts":    time.Now().Format(time.RFC3339),
-		"level": lvl.String(),
-	}
-
-	dict = mergeTags(dict, l.tags)
-
-	switch cerr := err.(type) {
-	case cerrors.Error:
-		dict["msg"] = cerr.Message
-
-		dict = mergeTags(dict, cerr.Tags)
-
-		if cerr.Cause != nil {
-			dict["error"] = cerr.Cause.Error()
-		}
-	default:
-		dict["msg"] = err.Error()
+func (l *logger) logPlain(dest io.Writer, lvl Level, err error) {
+	prefix := "[" + lvl.String() + "] "
+	if l.name != "" {
+		prefix += "[" + l.name + "] "
 	}
 
-	jsonStr, _ := json.Marshal(dict)
-	_, _ = dest.Write([]byte(string(jsonStr) + "\n"))
-}
-
-func (l *logger) logPlain(dest io.Writer, lvl Level, err error) {
-	log.New(dest, "", log.LstdFlags).Printf("[%s] %s", lvl.String(), cerrors.WithTags(err, l.tags).Error())
+	log.New(dest, "", log.LstdFlags).Print(prefix + cerrors.WithTags(err, l.tags).Error())
 }
@@ -2,11 +2,14 @@ package clogger_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/gocopper/copper/cerrors"
 	"github.com/gocopper/copper/clogger"
 	"github.com/stretchr/testify/assert"
 )
@@ -164,3 +167,129 @@ func TestLogger_Error(t *testing.T) {
 
 	assert.Contains(t, buf.String(), "[ERROR] test error log because\n> test-error")
 }
+
+func TestLogger_JSON(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf    bytes.Buffer
+		logger = clogger.NewWithWriters(&buf, &buf, clogger.FormatJSON)
+	)
+
+	logger.WithTags(map[string]interface{}{
+		"requestID": "abc",
+	}).Error("failed to handle request", cerrors.New(errors.New("connection refused"), "failed to query db", //nolint:goerr113
+		map[string]interface{}{"table": "users"}))
+
+	var entry struct {
+		Time  time.Time              `json:"ts"`
+		Level string                 `json:"level"`
+		Msg   string                 `json:"msg"`
+		Tags  map[string]interface{} `json:"tags"`
+		Error []struct {
+			Msg  string                 `json:"msg"`
+			Tags map[string]interface{} `json:"tags"`
+		} `json:"error"`
+	}
+
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.False(t, entry.Time.IsZero())
+	assert.Equal(t, "ERROR", entry.Level)
+	assert.Equal(t, "failed to handle request", entry.Msg)
+	assert.Equal(t, map[string]interface{}{"requestID": "abc"}, entry.Tags)
+	assert.Len(t, entry.Error, 2)
+	assert.Equal(t, "failed to query db", entry.Error[0].Msg)
+	assert.Equal(t, map[string]interface{}{"table": "users"}, entry.Error[0].Tags)
+	assert.Equal(t, "connection refused", entry.Error[1].Msg)
+
+	buf.Reset()
+
+	logger.WithTags(map[string]interface{}{
+		"fn": func() {},
+	}).Info("test info log")
+
+	assert.Contains(t, buf.String(), `"msg":"test info log"`)
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("\n")))
+}
+
+func TestLogger_Level(t *testing.T) {
+	t.Parallel()
+
+	log, err := ioutil.TempFile("", "*")
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, os.Remove(log.Name()))
+	})
+
+	logger, err := clogger.NewWithConfig(clogger.Config{
+		Out:   log.Name(),
+		Err:   log.Name(),
+		Level: clogger.LevelWarn,
+	})
+	assert.NoError(t, err)
+
+	assert.False(t, clogger.Enabled(logger, clogger.LevelInfo))
+	assert.True(t, clogger.Enabled(logger.WithTags(nil), clogger.LevelError))
+
+	logger.Debug("test debug log")
+	logger.Info("test info log")
+	logger.Warn("test warn log", nil)
+
+	logs, err := ioutil.ReadFile(log.Name())
+	assert.NoError(t, err)
+	assert.NotContains(t, string(logs), "test debug log")
+	assert.NotContains(t, string(logs), "test info log")
+	assert.Contains(t, string(logs), "[WARN] test warn log")
+}
+
+func TestLogger_Named(t *testing.T) {
+	t.Parallel()
+
+	log, err := ioutil.TempFile("", "*")
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, os.Remove(log.Name()))
+	})
+
+	logger, err := clogger.NewWithConfig(clogger.Config{
+		Out:   log.Name(),
+		Err:   log.Name(),
+		Level: clogger.LevelInfo,
+		Levels: map[string]clogger.Level{
+			"csql":  clogger.LevelDebug,
+			"chttp": clogger.LevelWarn,
+		},
+	})
+	assert.NoError(t, err)
+
+	logger.Debug("root debug log")
+	clogger.Named(logger, "csql").Debug("csql debug log")
+	clogger.Named(logger, "chttp").Info("chttp info log")
+	clogger.Named(clogger.Named(logger, "chttp"), "server").
+		WithTags(map[string]interface{}{"addr": ":7501"}).
+		Warn("server warn log", nil)
+	clogger.Named(logger, "cmailer").Info("cmailer info log")
+
+	logs, err := ioutil.ReadFile(log.Name())
+	assert.NoError(t, err)
+	assert.NotContains(t, string(logs), "root debug log")
+	assert.Contains(t, string(logs), "[DEBUG] [csql] csql debug log")
+	assert.NotContains(t, string(logs), "chttp info log")
+	assert.Contains(t, string(logs), "[WARN] [chttp.server] server warn log where addr=:7501")
+	assert.Contains(t, string(logs), "[INFO] [cmailer] cmailer info log")
+}
+
+type externalLogger struct {
+	clogger.Logger
+}
+
+func TestNamed_ExternalLogger(t *testing.T) {
+	t.Parallel()
+
+	logger := externalLogger{Logger: clogger.NewNoop()}
+
+	assert.Equal(t, logger, clogger.Named(logger, "csql"))
+	assert.True(t, clogger.Enabled(logger, clogger.LevelDebug))
+}
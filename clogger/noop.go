@@ -12,6 +12,14 @@ func (l *noop) WithTags(tags map[string]interface{}) Logger {
 	return l
 }
 
+func (l *noop) Named(name string) Logger {
+	return l
+}
+
+func (l *noop) Enabled(lvl Level) bool {
+	return false
+}
+
 func (l *noop) Debug(msg string) {}
 
 func (l *noop) Info(msg string) {}
@@ -12,56 +12,75 @@ func NewRecorder(logs *[]RecordedLog) Logger {
 
 // RecordedLog represents a single log.
 type RecordedLog struct {
-	Level Level
-	Tags  map[string]interface{}
-	Msg   string
-	Error error
+	Level  Level
+	Logger string
+	Tags   map[string]interface{}
+	Msg    string
+	Error  error
 }
 
 type recorder struct {
 	Logs *[]RecordedLog
+	name string
 	tags map[string]interface{}
 }
 
 func (l *recorder) WithTags(tags map[string]interface{}) Logger {
 	return &recorder{
 		Logs: l.Logs,
+		name: l.name,
 		tags: mergeTags(l.tags, tags),
 	}
 }
 
+func (l *recorder) Named(name string) Logger {
+	return &recorder{
+		Logs: l.Logs,
+		name: joinName(l.name, name),
+		tags: l.tags,
+	}
+}
+
+func (l *recorder) Enabled(lvl Level) bool {
+	return true
+}
+
 func (l *recorder) Debug(msg string) {
 	*l.Logs = append(*l.Logs, RecordedLog{
-		Level: LevelDebug,
-		Tags:  mergeTags(l.tags, nil),
-		Msg:   msg,
-		Error: nil,
+		Level:  LevelDebug,
+		Logger: l.name,
+		Tags:   mergeTags(l.tags, nil),
+		Msg:    msg,
+		Error:  nil,
 	})
 }
 
 func (l *recorder) Info(msg string) {
 	*l.Logs = append(*l.Logs, RecordedLog{
-		Level: LevelInfo,
-		Tags:  mergeTags(l.tags, nil),
-		Msg:   msg,
-		Error: nil,
+		Level:  LevelInfo,
+		Logger: l.name,
+		Tags:   mergeTags(l.tags, nil),
+		Msg:    msg,
+		Error:  nil,
 	})
 }
 
 func (l *recorder) Warn(msg string, err error) {
 	*l.Logs = append(*l.Logs, RecordedLog{
-		Level: LevelWarn,
-		Tags:  mergeTags(l.tags, nil),
-		Msg:   msg,
-		Error: err,
+		Level:  LevelWarn,
+		Logger: l.name,
+		Tags:   mergeTags(l.tags, nil),
+		Msg:    msg,
+		Error:  err,
 	})
 }
 
 func (l *recorder) Error(msg string, err error) {
 	*l.Logs = append(*l.Logs, RecordedLog{
-		Level: LevelError,
-		Tags:  mergeTags(l.tags, nil),
-		Msg:   msg,
-		Error: err,
+		Level:  LevelError,
+		Logger: l.name,
+		Tags:   mergeTags(l.tags, nil),
+		Msg:    msg,
+		Error:  err,
 	})
 }
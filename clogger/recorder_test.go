@@ -127,3 +127,14 @@ func TestRecorder_Error(t *testing.T) {
 	assert.Empty(t, log.Tags)
 	assert.EqualError(t, log.Error, "test-err")
 }
+
+func TestRecorder_Named(t *testing.T) {
+	t.Parallel()
+
+	var logs []clogger.RecordedLog
+
+	clogger.Named(clogger.Named(clogger.NewRecorder(&logs), "chttp"), "server").Info("test info log")
+
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "chttp.server", logs[0].Logger)
+}
@@ -0,0 +1,158 @@
+package clogger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+)
+
+const defaultSamplingInterval = time.Second
+
+// SamplingConfig configures log sampling, which limits how often identical messages are written so that logging in
+// hot paths does not overwhelm the log sinks during traffic spikes. Messages are identical if they have the same
+// level, logger name, and text. Tags are not compared. For example:
+//
+//	[clogger.sampling]
+//	first = 10
+//	thereafter = 100
+//
+// writes the first 10 entries of each message every second, then 1 in every 100.
+type SamplingConfig struct {
+	// First is the number of entries of each message that are written every interval. Sampling is disabled if it is
+	// 0.
+	First int `toml:"first"`
+
+	// Thereafter writes 1 in every Thereafter entries of a message once First have been written in the interval. If
+	// it is 0, the rest are dropped.
+	Thereafter int `toml:"thereafter"`
+
+	// Interval is how often the counts are reset. It defaults to 1s.
+	Interval time.Duration `toml:"interval"`
+
+	// Levels lists the levels that are sampled. It defaults to debug and info, so warnings and errors are always
+	// written.
+	Levels []Level `toml:"levels"`
+}
+
+// NewSampler returns a Logger that samples the entries written to the given logger (see SamplingConfig). It returns
+// the logger as is if sampling is disabled.
+func NewSampler(logger Logger, config SamplingConfig, clock cclock.Clock) Logger {
+	if config.First <= 0 {
+		return logger
+	}
+
+	if config.Interval <= 0 {
+		config.Interval = defaultSamplingInterval
+	}
+
+	levels := map[Level]bool{LevelDebug: true, LevelInfo: true}
+
+	if len(config.Levels) > 0 {
+		levels = make(map[Level]bool)
+
+		for _, lvl := range config.Levels {
+			levels[lvl] = true
+		}
+	}
+
+	return &sampler{
+		logger: logger,
+		counts: &sampleCounts{
+			config: config,
+			levels: levels,
+			clock:  clock,
+			counts: make(map[sampleKey]int),
+		},
+	}
+}
+
+type sampler struct {
+	logger Logger
+	name   string
+	counts *sampleCounts
+}
+
+func (l *sampler) WithTags(tags map[string]interface{}) Logger {
+	return &sampler{logger: l.logger.WithTags(tags), name: l.name, counts: l.counts}
+}
+
+func (l *sampler) Named(name string) Logger {
+	return &sampler{logger: Named(l.logger, name), name: joinName(l.name, name), counts: l.counts}
+}
+
+func (l *sampler) Enabled(lvl Level) bool {
+	return Enabled(l.logger, lvl)
+}
+
+func (l *sampler) Debug(msg string) {
+	if l.sample(LevelDebug, msg) {
+		l.logger.Debug(msg)
+	}
+}
+
+func (l *sampler) Info(msg string) {
+	if l.sample(LevelInfo, msg) {
+		l.logger.Info(msg)
+	}
+}
+
+func (l *sampler) Warn(msg string, err error) {
+	if l.sample(LevelWarn, msg) {
+		l.logger.Warn(msg, err)
+	}
+}
+
+func (l *sampler) Error(msg string, err error) {
+	if l.sample(LevelError, msg) {
+		l.logger.Error(msg, err)
+	}
+}
+
+// sample returns true if the entry should be written. Entries below the logger's level are not counted since they are
+// dropped anyway.
+func (l *sampler) sample(lvl Level, msg string) bool {
+	return Enabled(l.logger, lvl) && l.counts.allow(sampleKey{level: lvl, name: l.name, msg: msg})
+}
+
+type sampleKey struct {
+	level Level
+	name  string
+	msg   string
+}
+
+// sampleCounts counts the entries of each message in the current interval. It is shared by the loggers created with
+// WithTags and Named.
+type sampleCounts struct {
+	config SamplingConfig
+	levels map[Level]bool
+	clock  cclock.Clock
+
+	mu          sync.Mutex
+	counts      map[sampleKey]int
+	windowStart time.Time
+}
+
+func (s *sampleCounts) allow(key sampleKey) bool {
+	if !s.levels[key.level] {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// All of the counts are reset at once so that messages that are no longer logged do not stay in memory
+	if now := s.clock.Now(); now.Sub(s.windowStart) >= s.config.Interval {
+		s.counts = make(map[sampleKey]int)
+		s.windowStart = now
+	}
+
+	s.counts[key]++
+	n := s.counts[key]
+
+	if n <= s.config.First {
+		return true
+	}
+
+	return s.config.Thereafter > 0 && (n-s.config.First)%s.config.Thereafter == 0
+}
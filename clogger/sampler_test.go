@@ -0,0 +1,65 @@
+package clogger_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSampler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logs   = make([]clogger.RecordedLog, 0)
+		clock  = cclock.NewFake(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+		logger = clogger.NewSampler(clogger.NewRecorder(&logs), clogger.SamplingConfig{
+			First:      2,
+			Thereafter: 3,
+		}, clock)
+	)
+
+	for i := 0; i < 10; i++ {
+		logger.WithTags(map[string]interface{}{"i": i}).Debug("cache hit")
+		clogger.Named(logger, "csql").Debug("cache hit")
+		logger.Error("failed to query db", nil)
+	}
+
+	var (
+		sampled = make([]interface{}, 0)
+		named   = 0
+		errs    = 0
+	)
+
+	for _, log := range logs {
+		switch {
+		case log.Level == clogger.LevelError:
+			errs++
+		case log.Logger == "csql":
+			named++
+		default:
+			sampled = append(sampled, log.Tags["i"])
+		}
+	}
+
+	// The first 2 are written, then 1 in every 3
+	assert.Equal(t, []interface{}{0, 1, 4, 7}, sampled)
+	assert.Equal(t, 4, named)
+	assert.Equal(t, 10, errs)
+
+	clock.Advance(time.Second)
+	logs = logs[:0]
+
+	logger.Debug("cache hit")
+	assert.Len(t, logs, 1)
+}
+
+func TestNewSampler_Disabled(t *testing.T) {
+	t.Parallel()
+
+	logger := clogger.NewRecorder(nil)
+
+	assert.Equal(t, logger, clogger.NewSampler(logger, clogger.SamplingConfig{}, cclock.New()))
+}
@@ -1,5 +1,11 @@
 package clogger
 
+import (
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
 func mergeTags(t1, t2 map[string]interface{}) map[string]interface{} {
 	merged := make(map[string]interface{})
 
@@ -30,3 +36,53 @@ func formatToZapEncoding(f Format) string {
 		return "console"
 	}
 }
+
+func levelToZap(lvl Level) zapcore.Level {
+	switch lvl {
+	case LevelInfo:
+		return zapcore.InfoLevel
+	case LevelWarn:
+		return zapcore.WarnLevel
+	case LevelError:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
+
+func joinName(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + "." + name
+}
+
+// levelFor returns the level set for the named logger or its closest parent in levels, or def if none are set.
+func levelFor(name string, def Level, levels map[string]Level) Level {
+	for {
+		if lvl, ok := levels[name]; ok {
+			return lvl
+		}
+
+		i := strings.LastIndex(name, ".")
+		if i == -1 {
+			return def
+		}
+
+		name = name[:i]
+	}
+}
+
+// minLevel returns the lowest of the levels.
+func minLevel(def Level, levels map[string]Level) Level {
+	lowest := def
+
+	for _, lvl := range levels {
+		if lvl < lowest {
+			lowest = lvl
+		}
+	}
+
+	return lowest
+}
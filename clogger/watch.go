@@ -0,0 +1,84 @@
+package clogger
+
+import (
+	"sync"
+
+	"github.com/gocopper/copper/cconfig"
+	"go.uber.org/zap"
+)
+
+// WatchLevels updates the levels of the logger, and of the loggers created from it, each time the clogger config
+// changes so that Config.Level and Config.Levels can be changed without a restart (ex. to log at debug level while
+// investigating an issue). It only works with the loggers created by NewWithConfig and NewZapLogger. The returned func
+// stops watching.
+func WatchLevels(logger Logger, watcher *cconfig.Watcher) (cancel func()) {
+	state := levelsOf(logger)
+	if state == nil {
+		return func() {}
+	}
+
+	return watcher.OnChange("clogger", func(l cconfig.Loader) {
+		config, err := LoadConfig(l)
+		if err != nil {
+			logger.Warn("Failed to reload log levels", err)
+			return
+		}
+
+		state.set(config.Level, config.Levels)
+	})
+}
+
+// levels holds the levels of a logger and of the loggers created from it so that they can be changed at runtime.
+type levels struct {
+	mu     sync.RWMutex
+	root   Level
+	byName map[string]Level
+
+	// zap is the level of the zap core, if any. It is kept at the lowest of the levels so that named loggers can log
+	// below the root level.
+	zap *zap.AtomicLevel
+}
+
+func newLevels(root Level, byName map[string]Level, zapLevel *zap.AtomicLevel) *levels {
+	s := &levels{zap: zapLevel}
+	s.set(root, byName)
+
+	return s
+}
+
+// levelFor returns the level of the named logger. A nil levels logs everything.
+func (s *levels) levelFor(name string) Level {
+	if s == nil {
+		return LevelDebug
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return levelFor(name, s.root, s.byName)
+}
+
+func (s *levels) set(root Level, byName map[string]Level) {
+	s.mu.Lock()
+	s.root = root
+	s.byName = byName
+	s.mu.Unlock()
+
+	if s.zap != nil {
+		s.zap.SetLevel(levelToZap(minLevel(root, byName)))
+	}
+}
+
+// levelsOf returns the levels of the logger, if it supports changing them.
+func levelsOf(l Logger) *levels {
+	switch l := l.(type) {
+	case *sampler:
+		return levelsOf(l.logger)
+	case *logger:
+		return l.levels
+	case *zapLogger:
+		return l.levels
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,87 @@
+package clogger_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/gocopper/copper/clifecycle"
+	"github.com/gocopper/copper/clogger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchLevels(t *testing.T) {
+	t.Parallel()
+
+	dir := cconfigtest.SetupDirWithConfigs(t, map[string]string{
+		"test.toml": `
+			[clogger]
+			level = "warn"
+		`,
+	})
+
+	fp := path.Join(dir, "test.toml")
+
+	loader, err := cconfig.New(cconfig.Path(fp))
+	assert.NoError(t, err)
+
+	watcher, err := cconfig.NewWatcher(cconfig.NewWatcherParams{Loader: loader})
+	assert.NoError(t, err)
+
+	config, err := clogger.LoadConfig(loader)
+	assert.NoError(t, err)
+
+	config.Out = path.Join(dir, "out.log")
+
+	console, err := clogger.NewWithConfig(config)
+	assert.NoError(t, err)
+
+	zapLogger, err := clogger.NewZapLogger(config, clifecycle.New())
+	assert.NoError(t, err)
+
+	for _, logger := range []clogger.Logger{console, zapLogger} {
+		cancel := clogger.WatchLevels(logger, watcher)
+		defer cancel()
+	}
+
+	named := []clogger.Logger{clogger.Named(console, "csql"), clogger.Named(zapLogger, "csql")}
+
+	for _, logger := range append(named, console, zapLogger) {
+		assert.False(t, clogger.Enabled(logger, clogger.LevelInfo))
+	}
+
+	assert.NoError(t, ioutil.WriteFile(fp, []byte(`
+		[clogger]
+		level = "info"
+
+		[clogger.levels]
+		csql = "debug"
+	`), os.ModePerm))
+	assert.NoError(t, watcher.Reload())
+
+	for _, logger := range []clogger.Logger{console, zapLogger} {
+		assert.True(t, clogger.Enabled(logger, clogger.LevelInfo))
+		assert.False(t, clogger.Enabled(logger, clogger.LevelDebug))
+	}
+
+	for _, logger := range named {
+		assert.True(t, clogger.Enabled(logger, clogger.LevelDebug))
+	}
+
+	overrides, err := cconfig.NewOverrides(cconfig.NewOverridesParams{
+		Loader:      loader,
+		AllowedKeys: []string{"clogger.level"},
+	})
+	assert.NoError(t, err)
+
+	_, err = overrides.Set("clogger.level", "error", time.Minute, "ops")
+	assert.NoError(t, err)
+
+	for _, logger := range []clogger.Logger{console, zapLogger} {
+		assert.False(t, clogger.Enabled(logger, clogger.LevelWarn))
+	}
+}
@@ -3,6 +3,7 @@ package clogger
 import (
 	"context"
 
+	"github.com/gocopper/copper/cclock"
 	"github.com/gocopper/copper/cerrors"
 	"github.com/gocopper/copper/clifecycle"
 	"go.uber.org/zap"
@@ -29,8 +30,13 @@ func NewZapLogger(config Config, lc *clifecycle.Lifecycle) (Logger, error) {
 		encoderConfig = zap.NewProductionEncoderConfig()
 	}
 
+	var (
+		zapLevel = zap.NewAtomicLevel()
+		levels   = newLevels(config.Level, config.Levels, &zapLevel)
+	)
+
 	z, err := zap.Config{
-		Level:            zap.NewAtomicLevelAt(zap.DebugLevel),
+		Level:            zapLevel,
 		Encoding:         formatToZapEncoding(config.Format),
 		EncoderConfig:    encoderConfig,
 		OutputPaths:      []string{outPath},
@@ -40,7 +46,7 @@ func NewZapLogger(config Config, lc *clifecycle.Lifecycle) (Logger, error) {
 		return nil, cerrors.New(err, "failed to create zap logger", nil)
 	}
 
-	lc.OnStop(func(ctx context.Context) error {
+	lc.OnStopNamed("logger", func(ctx context.Context) error {
 		// Skip sync if logs are written to stderr because it will throw an error:
 		// https://github.com/uber-go/zap/issues/880
 		if outPath == OutStdErr && errOutPath == OutStdErr {
@@ -50,36 +56,61 @@ func NewZapLogger(config Config, lc *clifecycle.Lifecycle) (Logger, error) {
 		return z.Sync()
 	})
 
-	return &zapLogger{
-		zap:  z.Sugar(),
-		tags: make(map[string]interface{}),
-	}, nil
+	return NewSampler(&zapLogger{
+		zap:    z.Sugar(),
+		tags:   make(map[string]interface{}),
+		levels: levels,
+	}, config.Sampling, cclock.New()), nil
 }
 
+// zapLogger checks the level of its name before writing to zap since the zap core's level is the lowest of the levels
+// (see levels).
 type zapLogger struct {
-	zap  *zap.SugaredLogger
-	tags map[string]interface{}
+	zap    *zap.SugaredLogger
+	name   string
+	tags   map[string]interface{}
+	levels *levels
 }
 
 func (l *zapLogger) WithTags(tags map[string]interface{}) Logger {
-	return &zapLogger{
-		zap:  l.zap,
-		tags: mergeTags(l.tags, tags),
-	}
+	clone := *l
+	clone.tags = mergeTags(l.tags, tags)
+
+	return &clone
+}
+
+func (l *zapLogger) Named(name string) Logger {
+	clone := *l
+	clone.name = joinName(l.name, name)
+	clone.zap = l.zap.Named(name)
+
+	return &clone
+}
+
+func (l *zapLogger) Enabled(lvl Level) bool {
+	return lvl >= l.levels.levelFor(l.name) && l.zap.Desugar().Core().Enabled(levelToZap(lvl))
 }
 
 func (l *zapLogger) Debug(msg string) {
-	l.zap.Debugw(msg, tagsToKVs(l.tags)...)
+	if l.Enabled(LevelDebug) {
+		l.zap.Debugw(msg, tagsToKVs(l.tags)...)
+	}
 }
 
 func (l *zapLogger) Info(msg string) {
-	l.zap.Infow(msg, tagsToKVs(l.tags)...)
+	if l.Enabled(LevelInfo) {
+		l.zap.Infow(msg, tagsToKVs(l.tags)...)
+	}
 }
 
 func (l *zapLogger) Warn(msg string, err error) {
-	l.zap.With("error", err).Warnw(msg, tagsToKVs(l.tags)...)
+	if l.Enabled(LevelWarn) {
+		l.zap.With("error", err).Warnw(msg, tagsToKVs(l.tags)...)
+	}
 }
 
 func (l *zapLogger) Error(msg string, err error) {
-	l.zap.With("error", err).Errorw(msg, tagsToKVs(l.tags)...)
+	if l.Enabled(LevelError) {
+		l.zap.With("error", err).Errorw(msg, tagsToKVs(l.tags)...)
+	}
 }
@@ -0,0 +1,25 @@
+package cmetrics
+
+import (
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cerrors"
+)
+
+// LoadConfig loads the cmetrics config from the app config
+func LoadConfig(appConfig cconfig.Loader) (Config, error) {
+	var config Config
+
+	err := appConfig.Load("cmetrics", &config)
+	if err != nil {
+		return Config{}, cerrors.New(err, "failed to load metrics config", nil)
+	}
+
+	return config, nil
+}
+
+// Config configures the cmetrics module
+type Config struct {
+	// EnableEndpoint serves the metrics in the Prometheus text format at Path.
+	EnableEndpoint bool   `toml:"enable_endpoint"`
+	Path           string `toml:"path" default:"/metrics"`
+}
@@ -0,0 +1,2 @@
+// Package cmetrics collects Prometheus metrics for the app and serves them on an opt-in /metrics endpoint.
+package cmetrics
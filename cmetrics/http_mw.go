@@ -0,0 +1,133 @@
+package cmetrics
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/chttp"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var errRWIsNotHijacker = errors.New("internal response writer is not http.Hijacker")
+
+// NewHTTPMiddleware creates a new HTTPMiddleware and registers its collectors with the given registry.
+func NewHTTPMiddleware(registry *prometheus.Registry) (*HTTPMiddleware, error) {
+	labels := []string{"method", "route", "status"}
+
+	mw := &HTTPMiddleware{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Number of HTTP requests handled.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Time taken to handle HTTP requests.",
+			Buckets: prometheus.DefBuckets,
+		}, labels),
+		responseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_response_size_bytes",
+			Help:    "Size of HTTP response bodies.",
+			Buckets: prometheus.ExponentialBuckets(128, 4, 8), //nolint:gomnd
+		}, labels),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "Number of HTTP requests currently being handled.",
+		}),
+	}
+
+	for _, c := range []prometheus.Collector{mw.requests, mw.duration, mw.responseSize, mw.inFlight} {
+		err := registry.Register(c)
+		if err != nil {
+			return nil, cerrors.New(err, "failed to register http metrics", nil)
+		}
+	}
+
+	return mw, nil
+}
+
+// HTTPMiddleware records the count, duration, and response size of HTTP requests labeled by method, route pattern
+// (ex. /posts/{id}), and status code, along with the number of in-flight requests. Route patterns are used instead of
+// paths to keep the number of label values small, so the middleware must be used as a global or route middleware.
+type HTTPMiddleware struct {
+	requests     *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	responseSize *prometheus.HistogramVec
+	inFlight     prometheus.Gauge
+}
+
+// Handle records the metrics for each request.
+func (mw *HTTPMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			start = time.Now()
+			rw    = metricsRw{
+				internal:   w,
+				statusCode: http.StatusOK,
+			}
+		)
+
+		mw.inFlight.Inc()
+		defer mw.inFlight.Dec()
+
+		next.ServeHTTP(&rw, r)
+
+		labels := prometheus.Labels{
+			"method": r.Method,
+			"route":  chttp.RawRoutePath(r),
+			"status": strconv.Itoa(rw.statusCode),
+		}
+
+		mw.requests.With(labels).Inc()
+		mw.duration.With(labels).Observe(time.Since(start).Seconds())
+		mw.responseSize.With(labels).Observe(float64(rw.size))
+	})
+}
+
+type metricsRw struct {
+	internal    http.ResponseWriter
+	statusCode  int
+	size        int
+	wroteHeader bool
+}
+
+func (rw *metricsRw) Header() http.Header {
+	return rw.internal.Header()
+}
+
+func (rw *metricsRw) WriteHeader(statusCode int) {
+	if !rw.wroteHeader {
+		rw.statusCode = statusCode
+		rw.wroteHeader = true
+	}
+
+	rw.internal.WriteHeader(statusCode)
+}
+
+func (rw *metricsRw) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+
+	n, err := rw.internal.Write(b)
+	rw.size += n
+
+	return n, err
+}
+
+func (rw *metricsRw) Flush() {
+	if f, ok := rw.internal.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (rw *metricsRw) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := rw.internal.(http.Hijacker)
+	if !ok {
+		return nil, nil, errRWIsNotHijacker
+	}
+
+	return h.Hijack()
+}
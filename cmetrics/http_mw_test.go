@@ -0,0 +1,63 @@
+package cmetrics_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clogger"
+	"github.com/gocopper/copper/cmetrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	t.Parallel()
+
+	registry := cmetrics.NewRegistry()
+
+	mw, err := cmetrics.NewHTTPMiddleware(registry)
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{
+			chttptest.NewRouter([]chttp.Route{
+				{
+					Path:    "/posts/{id}",
+					Methods: []string{http.MethodGet},
+					Handler: func(w http.ResponseWriter, r *http.Request) {
+						_, _ = w.Write([]byte("post"))
+					},
+				},
+			}),
+			cmetrics.NewRouter(cmetrics.NewRouterParams{
+				Config:   cmetrics.Config{EnableEndpoint: true, Path: "/metrics"},
+				Registry: registry,
+			}),
+		},
+		GlobalMiddlewares: []chttp.Middleware{mw},
+		Logger:            clogger.NewNoop(),
+	}))
+	defer server.Close()
+
+	for _, path := range []string{"/posts/1", "/posts/2"} {
+		resp, err := http.Get(server.URL + path) //nolint:noctx
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+	}
+
+	resp, err := http.Get(server.URL + "/metrics") //nolint:noctx
+	assert.NoError(t, err)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `http_requests_total{method="GET",route="/posts/{id}",status="200"} 2`)
+	assert.Contains(t, string(body), `http_response_size_bytes_sum{method="GET",route="/posts/{id}",status="200"} 8`)
+	assert.Contains(t, string(body), `http_requests_in_flight 1`)
+	assert.Contains(t, string(body), `go_goroutines`)
+}
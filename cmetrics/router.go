@@ -0,0 +1,46 @@
+package cmetrics
+
+import (
+	"net/http"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type (
+	// NewRouterParams holds the params needed to create a Router.
+	NewRouterParams struct {
+		Config   Config
+		Registry *prometheus.Registry
+	}
+
+	// Router serves the metrics endpoint if it is enabled with Config.EnableEndpoint.
+	Router struct {
+		config   Config
+		registry *prometheus.Registry
+	}
+)
+
+// NewRouter creates a new Router.
+func NewRouter(p NewRouterParams) *Router {
+	return &Router{
+		config:   p.Config,
+		registry: p.Registry,
+	}
+}
+
+// Routes defines the HTTP routes for this router.
+func (ro *Router) Routes() []chttp.Route {
+	if !ro.config.EnableEndpoint {
+		return nil
+	}
+
+	return []chttp.Route{
+		{
+			Path:    ro.config.Path,
+			Methods: []string{http.MethodGet},
+			Handler: promhttp.HandlerFor(ro.registry, promhttp.HandlerOpts{}).ServeHTTP,
+		},
+	}
+}
@@ -0,0 +1,12 @@
+package cmetrics
+
+import "github.com/google/wire"
+
+// WireModule can be used as part of google/wire setup.
+var WireModule = wire.NewSet( //nolint:gochecknoglobals
+	LoadConfig,
+	NewRegistry,
+	NewHTTPMiddleware,
+	wire.Struct(new(NewRouterParams), "*"),
+	NewRouter,
+)
@@ -0,0 +1,67 @@
+package csession
+
+import (
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cerrors"
+)
+
+// Store types that can be used in Config.Store
+const (
+	StoreCookie = "cookie"
+	StoreMemory = "memory"
+)
+
+const (
+	defaultCookieName = "session"
+	defaultMaxAge     = 7 * 24 * time.Hour
+)
+
+// LoadConfig loads the csession config from the app config
+func LoadConfig(appConfig cconfig.Loader) (Config, error) {
+	var config Config
+
+	err := appConfig.Load("csession", &config)
+	if err != nil {
+		return Config{}, cerrors.New(err, "failed to load session config", nil)
+	}
+
+	if config.CookieName == "" {
+		config.CookieName = defaultCookieName
+	}
+
+	if config.MaxAge == 0 {
+		config.MaxAge = defaultMaxAge
+	}
+
+	return config, nil
+}
+
+// Config configures the csession module
+type Config struct {
+	// Store is the type of store created by NewStore. It can be "cookie" (default) or "memory". To keep sessions
+	// in a SQL database, use SQLWireModule instead of WireModule.
+	Store string `toml:"store"`
+
+	// Secret is used to derive the key that encrypts the session data in the cookie store. It is required if the
+	// cookie store is used.
+	Secret string `toml:"secret"`
+
+	// CookieName is the name of the session cookie. It defaults to "session".
+	CookieName string `toml:"cookie_name"`
+
+	// MaxAge is how long a session lives after it was last saved. It defaults to 7 days.
+	MaxAge time.Duration `toml:"max_age"`
+
+	// Domain and Path set the scope of the session cookie.
+	Domain string `toml:"domain"`
+	Path   string `toml:"path"`
+
+	// Secure marks the session cookie so that it is only sent over HTTPS.
+	Secure bool `toml:"secure"`
+
+	// CSRFExemptPaths are URL path prefixes (ex. /api/) that CSRFMiddleware does not check. They can be used for
+	// APIs that authenticate with tokens instead of the session cookie.
+	CSRFExemptPaths []string `toml:"csrf_exempt_paths"`
+}
@@ -0,0 +1,117 @@
+package csession
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cerrors"
+)
+
+// maxCookieBytes is the size limit that browsers commonly enforce for a single cookie.
+const maxCookieBytes = 4096
+
+// NewCookieStore creates a new CookieStore that encrypts the session values with a key derived from secret.
+func NewCookieStore(secret string, clock cclock.Clock) (*CookieStore, error) {
+	if secret == "" {
+		return nil, cerrors.New(nil, "cookie session store requires a secret", nil)
+	}
+
+	key := sha256.Sum256([]byte(secret))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, cerrors.New(err, "failed to create cipher", nil)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to create gcm cipher", nil)
+	}
+
+	return &CookieStore{
+		aead:  aead,
+		clock: clock,
+	}, nil
+}
+
+// CookieStore keeps the session values in the session cookie itself. The values are encrypted and authenticated
+// with AES-GCM, so they can neither be read nor changed by the client. Since the whole session is sent with every
+// request, it should only hold small values. Saving a session that does not fit in a cookie fails.
+type CookieStore struct {
+	aead  cipher.AEAD
+	clock cclock.Clock
+}
+
+type cookiePayload struct {
+	Values    map[string]json.RawMessage `json:"v"`
+	ExpiresAt int64                      `json:"e"`
+}
+
+// Load decrypts the values in the token. Tokens that cannot be decrypted are treated as an empty session.
+func (s *CookieStore) Load(ctx context.Context, token string) (map[string]json.RawMessage, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(sealed) < s.aead.NonceSize() {
+		return nil, nil
+	}
+
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, nil
+	}
+
+	var payload cookiePayload
+
+	err = json.Unmarshal(plaintext, &payload)
+	if err != nil {
+		return nil, nil
+	}
+
+	if !s.clock.Now().Before(time.Unix(payload.ExpiresAt, 0)) {
+		return nil, nil
+	}
+
+	return payload.Values, nil
+}
+
+// Save encrypts the values and returns them as the new token.
+func (s *CookieStore) Save(ctx context.Context, token string, values map[string]json.RawMessage,
+	expiresAt time.Time) (string, error) {
+	plaintext, err := json.Marshal(cookiePayload{
+		Values:    values,
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", cerrors.New(err, "failed to marshal session", nil)
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", cerrors.New(err, "failed to generate nonce", nil)
+	}
+
+	out := base64.RawURLEncoding.EncodeToString(s.aead.Seal(nonce, nonce, plaintext, nil))
+	if len(out) > maxCookieBytes {
+		return "", cerrors.New(nil, "session is too large for the cookie store", map[string]interface{}{
+			"size": len(out),
+			"max":  maxCookieBytes,
+		})
+	}
+
+	return out, nil
+}
+
+// Delete is a no-op since the session only lives in the cookie, which is removed by the Middleware.
+func (s *CookieStore) Delete(ctx context.Context, token string) error {
+	return nil
+}
@@ -0,0 +1,67 @@
+package csession_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/csession"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCookieStore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		now    = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock  = cclock.NewFake(now)
+		values = map[string]json.RawMessage{"user_id": json.RawMessage("42")}
+	)
+
+	store, err := csession.NewCookieStore("test-secret", clock)
+	assert.NoError(t, err)
+
+	token, err := store.Save(ctx, "", values, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.NotContains(t, token, "user_id")
+
+	loaded, err := store.Load(ctx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, values, loaded)
+
+	otherStore, err := csession.NewCookieStore("other-secret", clock)
+	assert.NoError(t, err)
+
+	loaded, err = otherStore.Load(ctx, token)
+	assert.NoError(t, err)
+	assert.Nil(t, loaded)
+
+	clock.Advance(time.Hour)
+
+	loaded, err = store.Load(ctx, token)
+	assert.NoError(t, err)
+	assert.Nil(t, loaded)
+}
+
+func TestCookieStore_TooLarge(t *testing.T) {
+	t.Parallel()
+
+	store, err := csession.NewCookieStore("test-secret", cclock.New())
+	assert.NoError(t, err)
+
+	_, err = store.Save(context.Background(), "", map[string]json.RawMessage{
+		"data": json.RawMessage(`"` + strings.Repeat("a", 4096) + `"`),
+	}, time.Now().Add(time.Hour))
+	assert.Error(t, err)
+}
+
+func TestNewCookieStore_NoSecret(t *testing.T) {
+	t.Parallel()
+
+	_, err := csession.NewCookieStore("", cclock.New())
+	assert.Error(t, err)
+}
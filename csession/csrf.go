@@ -0,0 +1,138 @@
+package csession
+
+import (
+	"context"
+	"crypto/subtle"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/clogger"
+)
+
+// Names used to send the CSRF token with a request.
+const (
+	CSRFHeader    = "X-CSRF-Token"
+	CSRFFormField = "csrf_token"
+)
+
+const csrfSessionKey = "_csrf"
+
+type (
+	// NewCSRFMiddlewareParams holds the params needed to create CSRFMiddleware
+	NewCSRFMiddlewareParams struct {
+		Config Config
+		Logger clogger.Logger
+	}
+
+	// CSRFMiddleware rejects requests with unsafe methods (POST, PUT, PATCH, DELETE) that do not carry the
+	// session's CSRF token in the X-CSRF-Token header or the csrf_token form field. The token is stored in the
+	// session, so the middleware must run after Middleware.
+	CSRFMiddleware struct {
+		exemptPaths []string
+		logger      clogger.Logger
+	}
+)
+
+// NewCSRFMiddleware creates a new CSRFMiddleware.
+func NewCSRFMiddleware(p NewCSRFMiddlewareParams) *CSRFMiddleware {
+	return &CSRFMiddleware{
+		exemptPaths: p.Config.CSRFExemptPaths,
+		logger:      p.Logger,
+	}
+}
+
+// Handle verifies the CSRF token for unsafe requests and responds with Forbidden if it is missing or invalid.
+func (mw *CSRFMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isSafeMethod(r.Method) || mw.isExempt(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		expected := CSRFToken(r.Context())
+
+		actual := r.Header.Get(CSRFHeader)
+		if actual == "" {
+			actual = r.PostFormValue(CSRFFormField)
+		}
+
+		if expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(actual)) != 1 {
+			mw.logger.WithTags(map[string]interface{}{
+				"method": r.Method,
+				"url":    r.URL.Path,
+			}).Warn("Rejected request with an invalid csrf token", nil)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"invalid csrf token"}` + "\n"))
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (mw *CSRFMiddleware) isExempt(path string) bool {
+	for _, prefix := range mw.exemptPaths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// CSRFToken returns the CSRF token stored in the session, creating one if the session does not have it yet. It
+// returns an empty string if there is no session in the context.
+func CSRFToken(ctx context.Context) string {
+	sess := FromCtx(ctx)
+	if sess == nil {
+		return ""
+	}
+
+	token := sess.GetString(csrfSessionKey)
+	if token != "" {
+		return token
+	}
+
+	token, err := newSessionID()
+	if err != nil {
+		return ""
+	}
+
+	err = sess.Set(csrfSessionKey, token)
+	if err != nil {
+		return ""
+	}
+
+	return token
+}
+
+// NewCSRFFieldRenderFunc creates the csrfField template function that renders a hidden input with the CSRF token.
+// It can be used in forms:
+//
+//	<form method="post">{{ csrfField }}</form>
+func NewCSRFFieldRenderFunc() chttp.HTMLRenderFunc {
+	return chttp.HTMLRenderFunc{
+		Name: "csrfField",
+		Func: func(r *http.Request) interface{} {
+			return func() template.HTML {
+				// nolint:gosec
+				return template.HTML(`<input type="hidden" name="` + CSRFFormField + `" value="` +
+					template.HTMLEscapeString(CSRFToken(r.Context())) + `">`)
+			}
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package csession_test
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/clogger"
+	"github.com/gocopper/copper/csession"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		clock  = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		logger = clogger.New()
+		config = csession.Config{
+			CookieName:      "session",
+			MaxAge:          time.Hour,
+			CSRFExemptPaths: []string{"/api/"},
+		}
+		sessionMw = csession.NewMiddleware(csession.NewMiddlewareParams{
+			Store:  csession.NewMemoryStore(clock),
+			Config: config,
+			Clock:  clock,
+			Logger: logger,
+		})
+		csrfMw = csession.NewCSRFMiddleware(csession.NewCSRFMiddlewareParams{
+			Config: config,
+			Logger: logger,
+		})
+		token   string
+		handler = sessionMw.Handle(csrfMw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token = csession.CSRFToken(r.Context())
+			w.WriteHeader(http.StatusOK)
+		})))
+	)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotEmpty(t, token)
+
+	cookie := resp.Result().Cookies()[0] //nolint:bodyclose
+
+	serve := func(req *http.Request) int {
+		req.AddCookie(cookie)
+
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		return resp.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, serve(httptest.NewRequest(http.MethodPost, "/", nil)))
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set(csession.CSRFHeader, "wrong")
+	assert.Equal(t, http.StatusForbidden, serve(req))
+
+	req = httptest.NewRequest(http.MethodDelete, "/", nil)
+	req.Header.Set(csession.CSRFHeader, token)
+	assert.Equal(t, http.StatusOK, serve(req))
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(url.Values{
+		csession.CSRFFormField: []string{token},
+	}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	assert.Equal(t, http.StatusOK, serve(req))
+
+	assert.Equal(t, http.StatusOK, serve(httptest.NewRequest(http.MethodPost, "/api/posts", nil)))
+}
+
+func TestNewCSRFFieldRenderFunc(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sess = csession.New()
+		req  = httptest.NewRequest(http.MethodGet, "/", nil).
+			WithContext(csession.WithSession(context.Background(), sess))
+		renderFunc = csession.NewCSRFFieldRenderFunc()
+	)
+
+	field := renderFunc.Func(req).(func() template.HTML)()
+
+	assert.Equal(t, "csrfField", renderFunc.Name)
+	assert.Equal(t, template.HTML(`<input type="hidden" name="csrf_token" value="`+
+		csession.CSRFToken(req.Context())+`">`), field)
+}
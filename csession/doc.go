@@ -0,0 +1,3 @@
+// Package csession provides cookie-based sessions. The session data is kept in a Store (an encrypted cookie, memory,
+// or a SQL database) and is loaded and saved for each request by the Middleware.
+package csession
@@ -0,0 +1,181 @@
+package csession
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/clogger"
+)
+
+var errRWIsNotHijacker = errors.New("internal response writer is not http.Hijacker")
+
+type (
+	// NewMiddlewareParams holds the params needed to create Middleware
+	NewMiddlewareParams struct {
+		Store  Store
+		Config Config
+		Clock  cclock.Clock
+		Logger clogger.Logger
+	}
+
+	// Middleware loads the session from the store at the start of the request and makes it available via FromCtx.
+	// If the session was changed, it is saved right before the response headers are written so that the session
+	// cookie can be updated.
+	Middleware struct {
+		store  Store
+		config Config
+		clock  cclock.Clock
+		logger clogger.Logger
+	}
+)
+
+// NewMiddleware creates a new Middleware.
+func NewMiddleware(p NewMiddlewareParams) *Middleware {
+	return &Middleware{
+		store:  p.Store,
+		config: p.Config,
+		clock:  p.Clock,
+		logger: p.Logger,
+	}
+}
+
+// Handle loads the session for the request and saves it once the handler starts writing the response.
+func (mw *Middleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sess := New()
+
+		cookie, err := r.Cookie(mw.config.CookieName)
+		if err == nil && cookie.Value != "" {
+			values, err := mw.store.Load(r.Context(), cookie.Value)
+			if err != nil {
+				mw.logger.WithTags(map[string]interface{}{
+					"url": r.URL.Path,
+				}).Warn("Failed to load session", err)
+			}
+
+			if values != nil {
+				sess.token = cookie.Value
+				sess.values = values
+			}
+		}
+
+		sessionRw := sessionRw{
+			internal: w,
+			save: func() {
+				mw.save(w, r, sess)
+			},
+		}
+
+		next.ServeHTTP(&sessionRw, r.WithContext(WithSession(r.Context(), sess)))
+
+		sessionRw.saveOnce()
+	})
+}
+
+func (mw *Middleware) save(w http.ResponseWriter, r *http.Request, sess *Session) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	if (sess.destroyed || sess.regenerated) && sess.token != "" {
+		err := mw.store.Delete(r.Context(), sess.token)
+		if err != nil {
+			mw.logger.WithTags(map[string]interface{}{
+				"url": r.URL.Path,
+			}).Warn("Failed to delete session", err)
+		}
+
+		sess.token = ""
+	}
+
+	if !sess.dirty || len(sess.values) == 0 {
+		if sess.destroyed || sess.regenerated {
+			mw.setCookie(w, "", -1)
+		}
+
+		return
+	}
+
+	token, err := mw.store.Save(r.Context(), sess.token, sess.values, mw.clock.Now().Add(mw.config.MaxAge))
+	if err != nil {
+		mw.logger.WithTags(map[string]interface{}{
+			"url": r.URL.Path,
+		}).Error("Failed to save session", err)
+
+		return
+	}
+
+	sess.token = token
+	sess.dirty = false
+	sess.destroyed = false
+	sess.regenerated = false
+
+	mw.setCookie(w, token, int(mw.config.MaxAge.Seconds()))
+}
+
+func (mw *Middleware) setCookie(w http.ResponseWriter, value string, maxAge int) {
+	path := mw.config.Path
+	if path == "" {
+		path = "/"
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     mw.config.CookieName,
+		Value:    value,
+		Path:     path,
+		Domain:   mw.config.Domain,
+		MaxAge:   maxAge,
+		Secure:   mw.config.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// sessionRw saves the session before the first write to the internal response writer.
+type sessionRw struct {
+	internal http.ResponseWriter
+	save     func()
+	saved    bool
+}
+
+func (rw *sessionRw) saveOnce() {
+	if rw.saved {
+		return
+	}
+
+	rw.saved = true
+	rw.save()
+}
+
+func (rw *sessionRw) Header() http.Header {
+	return rw.internal.Header()
+}
+
+func (rw *sessionRw) WriteHeader(statusCode int) {
+	rw.saveOnce()
+	rw.internal.WriteHeader(statusCode)
+}
+
+func (rw *sessionRw) Write(b []byte) (int, error) {
+	rw.saveOnce()
+	return rw.internal.Write(b)
+}
+
+func (rw *sessionRw) Flush() {
+	rw.saveOnce()
+
+	if f, ok := rw.internal.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (rw *sessionRw) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := rw.internal.(http.Hijacker)
+	if !ok {
+		return nil, nil, errRWIsNotHijacker
+	}
+
+	return h.Hijack()
+}
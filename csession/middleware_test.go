@@ -0,0 +1,218 @@
+package csession_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/clifecycle"
+	"github.com/gocopper/copper/clogger"
+	"github.com/gocopper/copper/csession"
+	"github.com/gocopper/copper/csql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		clock  = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		config = csession.Config{
+			CookieName: "session",
+			MaxAge:     time.Hour,
+		}
+		mw = csession.NewMiddleware(csession.NewMiddlewareParams{
+			Store:  csession.NewMemoryStore(clock),
+			Config: config,
+			Clock:  clock,
+			Logger: clogger.New(),
+		})
+		handler = mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sess := csession.FromCtx(r.Context())
+
+			switch r.URL.Path {
+			case "/login":
+				assert.NoError(t, sess.Set("user_id", 42))
+			case "/logout":
+				sess.Destroy()
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}))
+	)
+
+	serve := func(path string, cookie *http.Cookie) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		return resp.Result() //nolint:bodyclose
+	}
+
+	resp := serve("/", nil)
+	assert.Empty(t, resp.Cookies())
+
+	resp = serve("/login", nil)
+	assert.Len(t, resp.Cookies(), 1)
+
+	cookie := resp.Cookies()[0]
+	assert.Equal(t, "session", cookie.Name)
+	assert.Equal(t, 3600, cookie.MaxAge)
+	assert.True(t, cookie.HttpOnly)
+
+	var (
+		userID int
+		req    = httptest.NewRequest(http.MethodGet, "/", nil)
+	)
+
+	req.AddCookie(cookie)
+
+	mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ok, err := csession.FromCtx(r.Context()).Get("user_id", &userID)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "", csession.FromCtx(r.Context()).GetString("user_id"))
+	})).ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, 42, userID)
+
+	resp = serve("/logout", cookie)
+	assert.Len(t, resp.Cookies(), 1)
+	assert.Equal(t, -1, resp.Cookies()[0].MaxAge)
+
+	resp = serve("/login", cookie)
+	assert.NotEqual(t, cookie.Value, resp.Cookies()[0].Value)
+}
+
+func TestMiddleware_Expired(t *testing.T) {
+	t.Parallel()
+
+	var (
+		clock = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		store = csession.NewMemoryStore(clock)
+		mw    = csession.NewMiddleware(csession.NewMiddlewareParams{
+			Store: store,
+			Config: csession.Config{
+				CookieName: "session",
+				MaxAge:     time.Hour,
+			},
+			Clock:  clock,
+			Logger: clogger.New(),
+		})
+		found bool
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	resp := httptest.NewRecorder()
+
+	mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, csession.FromCtx(r.Context()).Set("user_id", 42))
+		w.WriteHeader(http.StatusNoContent)
+	})).ServeHTTP(resp, req)
+
+	cookies := resp.Result().Cookies() //nolint:bodyclose
+	assert.Len(t, cookies, 1)
+
+	clock.Advance(2 * time.Hour)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookies[0])
+
+	mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		found = csession.FromCtx(r.Context()).Has("user_id")
+	})).ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.False(t, found)
+}
+
+func TestMiddleware_Regenerate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger = clogger.New()
+		lc     = clifecycle.New()
+		clock  = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	)
+
+	defer lc.Stop(logger)
+
+	db, err := csql.NewDBConnection(lc, csql.Config{
+		Dialect: "sqlite",
+		DSN:     ":memory:",
+	}, logger)
+	assert.NoError(t, err)
+
+	sqlStore := csession.NewSQLStore(csession.NewSQLStoreParams{
+		DB:    db,
+		Clock: clock,
+	})
+	assert.NoError(t, sqlStore.Migration().Run())
+
+	stores := map[string]csession.Store{
+		"memory": csession.NewMemoryStore(clock),
+		"sql":    sqlStore,
+	}
+
+	for name, store := range stores {
+		store := store
+
+		t.Run(name, func(t *testing.T) {
+			mw := csession.NewMiddleware(csession.NewMiddlewareParams{
+				Store: store,
+				Config: csession.Config{
+					CookieName: "session",
+					MaxAge:     time.Hour,
+				},
+				Clock:  clock,
+				Logger: logger,
+			})
+
+			serve := func(cookie *http.Cookie, fn func(sess *csession.Session)) *http.Response {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				if cookie != nil {
+					req.AddCookie(cookie)
+				}
+
+				resp := httptest.NewRecorder()
+
+				mw.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					fn(csession.FromCtx(r.Context()))
+				})).ServeHTTP(resp, req)
+
+				return resp.Result() //nolint:bodyclose
+			}
+
+			resp := serve(nil, func(sess *csession.Session) {
+				assert.NoError(t, sess.Set("cart_id", 7))
+			})
+			assert.Len(t, resp.Cookies(), 1)
+
+			oldCookie := resp.Cookies()[0]
+
+			resp = serve(oldCookie, func(sess *csession.Session) {
+				sess.Regenerate()
+				assert.NoError(t, sess.Set("user_id", 42))
+			})
+			assert.Len(t, resp.Cookies(), 1)
+
+			newCookie := resp.Cookies()[0]
+			assert.NotEqual(t, oldCookie.Value, newCookie.Value)
+
+			serve(newCookie, func(sess *csession.Session) {
+				assert.Equal(t, 7, sess.GetInt("cart_id"))
+				assert.Equal(t, 42, sess.GetInt("user_id"))
+			})
+
+			serve(oldCookie, func(sess *csession.Session) {
+				assert.False(t, sess.Has("cart_id"))
+				assert.False(t, sess.Has("user_id"))
+			})
+		})
+	}
+}
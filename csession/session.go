@@ -0,0 +1,156 @@
+package csession
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+type ctxKey string
+
+const sessionCtxKey = ctxKey("csession/session")
+
+// Session holds the values of a single user's session. Values are stored as JSON, so they can be read back into
+// any type that they can be unmarshalled to. A Session is safe to use from multiple goroutines.
+type Session struct {
+	mu          sync.Mutex
+	token       string
+	values      map[string]json.RawMessage
+	dirty       bool
+	destroyed   bool
+	regenerated bool
+}
+
+// FromCtx returns the session loaded by the Middleware. It returns nil if the Middleware was not used.
+func FromCtx(ctx context.Context) *Session {
+	s, ok := ctx.Value(sessionCtxKey).(*Session)
+	if !ok {
+		return nil
+	}
+
+	return s
+}
+
+// WithSession returns a context that holds the given session. It is used by the Middleware and can be used in tests.
+func WithSession(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, sessionCtxKey, s)
+}
+
+// New creates an empty session that is not stored yet.
+func New() *Session {
+	return &Session{
+		values: make(map[string]json.RawMessage),
+	}
+}
+
+// Set stores the value for the given key. The value must be JSON serializable.
+func (s *Session) Set(key string, val interface{}) error {
+	out, err := json.Marshal(val)
+	if err != nil {
+		return cerrors.New(err, "failed to marshal session value", map[string]interface{}{
+			"key": key,
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = out
+	s.dirty = true
+
+	return nil
+}
+
+// Get reads the value for the given key into dest. It returns false if the key is not set.
+func (s *Session) Get(key string, dest interface{}) (bool, error) {
+	s.mu.Lock()
+	raw, ok := s.values[key]
+	s.mu.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+
+	err := json.Unmarshal(raw, dest)
+	if err != nil {
+		return false, cerrors.New(err, "failed to unmarshal session value", map[string]interface{}{
+			"key": key,
+		})
+	}
+
+	return true, nil
+}
+
+// GetString returns the string stored for the given key or an empty string if it is not set or is not a string.
+func (s *Session) GetString(key string) string {
+	var val string
+
+	_, _ = s.Get(key, &val)
+
+	return val
+}
+
+// GetInt returns the int stored for the given key or 0 if it is not set or is not an int.
+func (s *Session) GetInt(key string) int {
+	var val int
+
+	_, _ = s.Get(key, &val)
+
+	return val
+}
+
+// GetBool returns the bool stored for the given key or false if it is not set or is not a bool.
+func (s *Session) GetBool(key string) bool {
+	var val bool
+
+	_, _ = s.Get(key, &val)
+
+	return val
+}
+
+// Has returns true if the given key is set.
+func (s *Session) Has(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.values[key]
+
+	return ok
+}
+
+// Delete removes the value for the given key.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.values[key]; !ok {
+		return
+	}
+
+	delete(s.values, key)
+	s.dirty = true
+}
+
+// Destroy removes all values and deletes the session from the store at the end of the request. The session cookie
+// is removed as well. Values set after Destroy are saved in a new session.
+func (s *Session) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values = make(map[string]json.RawMessage)
+	s.destroyed = true
+	s.dirty = false
+}
+
+// Regenerate keeps the values but moves them to a new session at the end of the request, deleting the old session
+// from the store. It should be called when the user's privileges change (ex. on login) so that a session token that
+// was planted before the change (session fixation) cannot be used afterwards.
+func (s *Session) Regenerate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.regenerated = true
+	s.dirty = true
+}
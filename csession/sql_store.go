@@ -0,0 +1,142 @@
+package csession
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/csql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	// NewSQLStoreParams holds the params needed to create a SQLStore
+	NewSQLStoreParams struct {
+		DB    *gorm.DB
+		Clock cclock.Clock
+	}
+
+	// SQLStore keeps sessions in the sessions table of a SQL database (ex. Postgres) so that they are shared
+	// between instances of the app. It is provided by SQLWireModule. The table is created by the migration
+	// returned from SQLStore.Migration.
+	SQLStore struct {
+		db    *gorm.DB
+		clock cclock.Clock
+	}
+
+	sqlSession struct {
+		ID        string    `gorm:"primaryKey"`
+		Data      string    `gorm:"not null"`
+		ExpiresAt time.Time `gorm:"not null;index"`
+	}
+
+	sqlStoreMigration struct {
+		db *gorm.DB
+	}
+)
+
+// NewSQLStore creates a new SQLStore.
+func NewSQLStore(p NewSQLStoreParams) *SQLStore {
+	return &SQLStore{
+		db:    p.DB,
+		clock: p.Clock,
+	}
+}
+
+func (sqlSession) TableName() string {
+	return "sessions"
+}
+
+// Migration returns a csql.Migration that creates the sessions table.
+func (s *SQLStore) Migration() csql.Migration {
+	return &sqlStoreMigration{db: s.db}
+}
+
+func (m *sqlStoreMigration) Run() error {
+	err := m.db.AutoMigrate(&sqlSession{})
+	if err != nil {
+		return cerrors.New(err, "failed to migrate sessions table", nil)
+	}
+
+	return nil
+}
+
+// Load returns the values for the session with the given id.
+func (s *SQLStore) Load(ctx context.Context, token string) (map[string]json.RawMessage, error) {
+	var sess sqlSession
+
+	err := csql.GetConn(ctx, s.db).
+		Where("id = ? AND expires_at > ?", token, s.clock.Now()).
+		First(&sess).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, cerrors.New(err, "failed to query session", nil)
+	}
+
+	var values map[string]json.RawMessage
+
+	err = json.Unmarshal([]byte(sess.Data), &values)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to unmarshal session", nil)
+	}
+
+	return values, nil
+}
+
+// Save upserts the values for the session with the given id. A new id is generated if the token is empty.
+func (s *SQLStore) Save(ctx context.Context, token string, values map[string]json.RawMessage,
+	expiresAt time.Time) (string, error) {
+	if token == "" {
+		id, err := newSessionID()
+		if err != nil {
+			return "", err
+		}
+
+		token = id
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", cerrors.New(err, "failed to marshal session", nil)
+	}
+
+	err = csql.GetConn(ctx, s.db).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&sqlSession{
+			ID:        token,
+			Data:      string(data),
+			ExpiresAt: expiresAt,
+		}).Error
+	if err != nil {
+		return "", cerrors.New(err, "failed to save session", nil)
+	}
+
+	return token, nil
+}
+
+// Delete removes the session with the given id.
+func (s *SQLStore) Delete(ctx context.Context, token string) error {
+	err := csql.GetConn(ctx, s.db).Delete(&sqlSession{ID: token}).Error
+	if err != nil {
+		return cerrors.New(err, "failed to delete session", nil)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes all expired sessions. It can be run periodically to keep the sessions table small.
+func (s *SQLStore) DeleteExpired(ctx context.Context) error {
+	err := csql.GetConn(ctx, s.db).Where("expires_at <= ?", s.clock.Now()).Delete(&sqlSession{}).Error
+	if err != nil {
+		return cerrors.New(err, "failed to delete expired sessions", nil)
+	}
+
+	return nil
+}
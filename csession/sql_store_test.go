@@ -0,0 +1,70 @@
+package csession_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/clifecycle"
+	"github.com/gocopper/copper/clogger"
+	"github.com/gocopper/copper/csession"
+	"github.com/gocopper/copper/csql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLStore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		logger = clogger.New()
+		lc     = clifecycle.New()
+		now    = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock  = cclock.NewFake(now)
+		values = map[string]json.RawMessage{"user_id": json.RawMessage("42")}
+	)
+
+	defer lc.Stop(logger)
+
+	db, err := csql.NewDBConnection(lc, csql.Config{
+		Dialect: "sqlite",
+		DSN:     ":memory:",
+	}, logger)
+	assert.NoError(t, err)
+
+	store := csession.NewSQLStore(csession.NewSQLStoreParams{
+		DB:    db,
+		Clock: clock,
+	})
+
+	assert.NoError(t, store.Migration().Run())
+
+	token, err := store.Save(ctx, "", values, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+
+	loaded, err := store.Load(ctx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, values, loaded)
+
+	values["user_id"] = json.RawMessage("43")
+
+	sameToken, err := store.Save(ctx, token, values, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, token, sameToken)
+
+	loaded, err = store.Load(ctx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, values, loaded)
+
+	clock.Advance(time.Hour)
+
+	loaded, err = store.Load(ctx, token)
+	assert.NoError(t, err)
+	assert.Nil(t, loaded)
+
+	assert.NoError(t, store.DeleteExpired(ctx))
+	assert.NoError(t, store.Delete(ctx, token))
+}
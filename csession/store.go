@@ -0,0 +1,163 @@
+package csession
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cerrors"
+)
+
+const sessionIDBytes = 32
+
+// memorySweepInterval is how often the MemoryStore removes the expired sessions.
+const memorySweepInterval = time.Minute
+
+type (
+	// Store loads and saves session values. The token is the value of the session cookie. Stores that keep the
+	// values on the server use a random session id as the token, while the cookie store uses the encrypted values.
+	Store interface {
+		// Load returns the values for the given token. It returns nil values (and no error) if the session does not
+		// exist or has expired.
+		Load(ctx context.Context, token string) (map[string]json.RawMessage, error)
+
+		// Save stores the values until expiresAt and returns the token to be set in the session cookie. The token is
+		// empty for a new session.
+		Save(ctx context.Context, token string, values map[string]json.RawMessage, expiresAt time.Time) (string, error)
+
+		// Delete removes the session for the given token.
+		Delete(ctx context.Context, token string) error
+	}
+
+	// NewStoreParams holds the params needed to create a Store with NewStore
+	NewStoreParams struct {
+		Config Config
+		Clock  cclock.Clock
+	}
+)
+
+// NewStore creates the Store configured by Config.Store.
+func NewStore(p NewStoreParams) (Store, error) {
+	switch p.Config.Store {
+	case "", StoreCookie:
+		return NewCookieStore(p.Config.Secret, p.Clock)
+	case StoreMemory:
+		return NewMemoryStore(p.Clock), nil
+	default:
+		return nil, cerrors.New(nil, "unknown session store", map[string]interface{}{
+			"store": p.Config.Store,
+		})
+	}
+}
+
+// NewMemoryStore creates a new MemoryStore.
+func NewMemoryStore(clock cclock.Clock) *MemoryStore {
+	return &MemoryStore{
+		clock:    clock,
+		sessions: make(map[string]memorySession),
+	}
+}
+
+// MemoryStore keeps sessions in memory. Sessions are lost when the app restarts and are not shared between
+// instances of the app, so it is best suited for development and tests. Expired sessions are removed as new ones are
+// saved.
+type MemoryStore struct {
+	clock cclock.Clock
+
+	mu       sync.Mutex
+	sessions map[string]memorySession
+	sweptAt  time.Time
+}
+
+type memorySession struct {
+	values    map[string]json.RawMessage
+	expiresAt time.Time
+}
+
+// Load returns the values for the session with the given id.
+func (s *MemoryStore) Load(ctx context.Context, token string) (map[string]json.RawMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[token]
+	if !ok {
+		return nil, nil
+	}
+
+	if !s.clock.Now().Before(sess.expiresAt) {
+		delete(s.sessions, token)
+		return nil, nil
+	}
+
+	return copyValues(sess.values), nil
+}
+
+// Save stores the values for the session with the given id. A new id is generated if the token is empty.
+func (s *MemoryStore) Save(ctx context.Context, token string, values map[string]json.RawMessage,
+	expiresAt time.Time) (string, error) {
+	if token == "" {
+		id, err := newSessionID()
+		if err != nil {
+			return "", err
+		}
+
+		token = id
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+
+	if now.Sub(s.sweptAt) >= memorySweepInterval {
+		for t, sess := range s.sessions {
+			if !now.Before(sess.expiresAt) {
+				delete(s.sessions, t)
+			}
+		}
+
+		s.sweptAt = now
+	}
+
+	s.sessions[token] = memorySession{
+		values:    copyValues(values),
+		expiresAt: expiresAt,
+	}
+
+	return token, nil
+}
+
+// Delete removes the session with the given id.
+func (s *MemoryStore) Delete(ctx context.Context, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, token)
+
+	return nil
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, sessionIDBytes)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", cerrors.New(err, "failed to generate session id", nil)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func copyValues(values map[string]json.RawMessage) map[string]json.RawMessage {
+	out := make(map[string]json.RawMessage, len(values))
+
+	for k, v := range values {
+		out[k] = v
+	}
+
+	return out
+}
@@ -0,0 +1,26 @@
+package csession
+
+import "github.com/google/wire"
+
+// WireModule can be used as part of google/wire setup. It provides the store configured by Config.Store.
+var WireModule = wire.NewSet( //nolint:gochecknoglobals
+	LoadConfig,
+	wire.Struct(new(NewStoreParams), "*"),
+	NewStore,
+	wire.Struct(new(NewMiddlewareParams), "*"),
+	NewMiddleware,
+	wire.Struct(new(NewCSRFMiddlewareParams), "*"),
+	NewCSRFMiddleware,
+)
+
+// SQLWireModule can be used in place of WireModule to keep sessions in the app's SQL database.
+var SQLWireModule = wire.NewSet( //nolint:gochecknoglobals
+	LoadConfig,
+	wire.Struct(new(NewSQLStoreParams), "*"),
+	NewSQLStore,
+	wire.Bind(new(Store), new(*SQLStore)),
+	wire.Struct(new(NewMiddlewareParams), "*"),
+	NewMiddleware,
+	wire.Struct(new(NewCSRFMiddlewareParams), "*"),
+	NewCSRFMiddleware,
+)
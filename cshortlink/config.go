@@ -0,0 +1,37 @@
+package cshortlink
+
+import (
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cerrors"
+)
+
+const defaultPath = "/s"
+
+// LoadConfig loads the cshortlink config from the app config
+func LoadConfig(appConfig cconfig.Loader) (Config, error) {
+	var config Config
+
+	err := appConfig.Load("cshortlink", &config)
+	if err != nil {
+		return Config{}, cerrors.New(err, "failed to load shortlink config", nil)
+	}
+
+	if config.Path == "" {
+		config.Path = defaultPath
+	}
+
+	return config, nil
+}
+
+// Config configures the cshortlink module
+type Config struct {
+	// Secret signs the short codes so that guessed codes are rejected without a database query. It is required.
+	Secret string `toml:"secret"`
+
+	// Path is the path prefix that the Router mounts the links at. It defaults to /s.
+	Path string `toml:"path"`
+
+	// PublicURL is the scheme and host used for the short URLs (ex. https://exm.pl). If it is not set, the URLs are
+	// built from the request.
+	PublicURL string `toml:"public_url"`
+}
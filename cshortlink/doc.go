@@ -0,0 +1,3 @@
+// Package cshortlink creates short links (ex. for SMS or email) that redirect to a longer URL. Links are stored with
+// csql, can expire, count their hits, and have a QR code endpoint.
+package cshortlink
@@ -0,0 +1,119 @@
+package cshortlink
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/clogger"
+	"github.com/skip2/go-qrcode"
+)
+
+const qrCodeSize = 256
+
+type (
+	// NewRouterParams holds the params needed to create a Router
+	NewRouterParams struct {
+		Service *Service
+		Config  Config
+		RW      *chttp.ReaderWriter
+		Logger  clogger.Logger
+	}
+
+	// Router serves the short links under Config.Path. GET {path}/{code} redirects to the link's URL and
+	// GET {path}/{code}/qr returns a PNG QR code of the short URL.
+	Router struct {
+		service *Service
+		config  Config
+		rw      *chttp.ReaderWriter
+		logger  clogger.Logger
+	}
+)
+
+// NewRouter creates a new Router.
+func NewRouter(p NewRouterParams) *Router {
+	return &Router{
+		service: p.Service,
+		config:  p.Config,
+		rw:      p.RW,
+		logger:  p.Logger,
+	}
+}
+
+// Routes returns the shortlink routes.
+func (ro *Router) Routes() []chttp.Route {
+	return []chttp.Route{
+		{
+			Name:    "shortlink",
+			Path:    ro.config.Path + "/{code}",
+			Methods: []string{http.MethodGet},
+			Handler: ro.HandleRedirect,
+		},
+		{
+			Name:    "shortlinkQR",
+			Path:    ro.config.Path + "/{code}/qr",
+			Methods: []string{http.MethodGet},
+			Handler: ro.HandleQRCode,
+		},
+	}
+}
+
+// HandleRedirect redirects to the link's URL and counts the hit.
+func (ro *Router) HandleRedirect(w http.ResponseWriter, r *http.Request) {
+	link, err := ro.service.Resolve(r.Context(), chttp.URLParams(r)["code"])
+	if err != nil {
+		ro.writeError(w, err)
+		return
+	}
+
+	http.Redirect(w, r, link.URL, http.StatusFound)
+}
+
+// HandleQRCode writes a PNG QR code that encodes the short URL.
+func (ro *Router) HandleQRCode(w http.ResponseWriter, r *http.Request) {
+	link, err := ro.service.Get(r.Context(), chttp.URLParams(r)["code"])
+	if err != nil {
+		ro.writeError(w, err)
+		return
+	}
+
+	png, err := qrcode.Encode(ro.ShortURL(r, link.Code), qrcode.Medium, qrCodeSize)
+	if err != nil {
+		ro.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	_, _ = w.Write(png)
+}
+
+// ShortURL returns the absolute short URL for the given code. It uses Config.PublicURL, or the request's scheme and
+// host if it is not set.
+func (ro *Router) ShortURL(r *http.Request, code string) string {
+	base := strings.TrimSuffix(ro.config.PublicURL, "/")
+
+	if base == "" {
+		scheme := "http"
+		if r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https") {
+			scheme = "https"
+		}
+
+		base = scheme + "://" + r.Host
+	}
+
+	return base + chttp.WithBasePath(r.Context(), ro.config.Path+"/"+code)
+}
+
+func (ro *Router) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		ro.rw.WriteJSON(w, chttp.WriteJSONParams{StatusCode: http.StatusNotFound, Data: err})
+	case errors.Is(err, ErrExpired):
+		ro.rw.WriteJSON(w, chttp.WriteJSONParams{StatusCode: http.StatusGone, Data: err})
+	default:
+		ro.logger.Error("Failed to handle shortlink request", err)
+		ro.rw.WriteJSON(w, chttp.WriteJSONParams{StatusCode: http.StatusInternalServerError})
+	}
+}
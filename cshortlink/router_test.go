@@ -0,0 +1,101 @@
+package cshortlink_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clifecycle"
+	"github.com/gocopper/copper/clogger"
+	"github.com/gocopper/copper/cshortlink"
+	"github.com/gocopper/copper/csql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger = clogger.New()
+		lc     = clifecycle.New()
+		clock  = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		config = cshortlink.Config{Secret: "test-secret", Path: "/s"}
+	)
+
+	defer lc.Stop(logger)
+
+	db, err := csql.NewDBConnection(lc, csql.Config{
+		Dialect: "sqlite",
+		DSN:     ":memory:",
+	}, logger)
+	assert.NoError(t, err)
+
+	svc, err := cshortlink.NewService(cshortlink.NewServiceParams{DB: db, Config: config, Clock: clock})
+	assert.NoError(t, err)
+	assert.NoError(t, svc.Migration().Run())
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{cshortlink.NewRouter(cshortlink.NewRouterParams{
+			Service: svc,
+			Config:  config,
+			RW:      chttptest.NewReaderWriter(t),
+			Logger:  logger,
+		})},
+		Logger: logger,
+	}))
+	defer server.Close()
+
+	client := http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	get := func(path string) *http.Response {
+		resp, err := client.Get(server.URL + path) //nolint:noctx
+		assert.NoError(t, err)
+
+		return resp
+	}
+
+	link, err := svc.Create(context.Background(), "https://example.com/a/long/url", time.Hour)
+	assert.NoError(t, err)
+	assert.Len(t, link.Code, 12)
+
+	resp := get("/s/" + link.Code)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, "https://example.com/a/long/url", resp.Header.Get("Location"))
+
+	link, err = svc.Get(context.Background(), link.Code)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), link.Hits)
+
+	resp = get("/s/" + link.Code + "/qr")
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, "image/png", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "\x89PNG", string(body[:4]))
+
+	forged := link.Code[:8] + "AAAA"
+	if forged == link.Code {
+		forged = link.Code[:8] + "BBBB"
+	}
+
+	resp = get("/s/" + forged)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	clock.Advance(time.Hour)
+
+	resp = get("/s/" + link.Code)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusGone, resp.StatusCode)
+}
@@ -0,0 +1,186 @@
+package cshortlink
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/csql"
+	"gorm.io/gorm"
+)
+
+const (
+	codeIDBytes  = 6
+	codeSigBytes = 3
+)
+
+var (
+	// ErrNotFound is returned when a short code is invalid or does not exist.
+	ErrNotFound = errors.New("shortlink not found")
+
+	// ErrExpired is returned when a short link has expired.
+	ErrExpired = errors.New("shortlink expired")
+)
+
+type (
+	// Link is a short link stored in the shortlinks table.
+	Link struct {
+		Code      string `gorm:"primaryKey"`
+		URL       string `gorm:"not null"`
+		Hits      int64  `gorm:"not null;default:0"`
+		ExpiresAt *time.Time
+		CreatedAt time.Time
+	}
+
+	// NewServiceParams holds the params needed to create a Service
+	NewServiceParams struct {
+		DB     *gorm.DB
+		Config Config
+		Clock  cclock.Clock
+	}
+
+	// Service creates and resolves short links.
+	Service struct {
+		db     *gorm.DB
+		secret []byte
+		clock  cclock.Clock
+	}
+
+	migration struct {
+		db *gorm.DB
+	}
+)
+
+// TableName is the table that links are stored in.
+func (Link) TableName() string {
+	return "shortlinks"
+}
+
+// NewService creates a new Service.
+func NewService(p NewServiceParams) (*Service, error) {
+	if p.Config.Secret == "" {
+		return nil, cerrors.New(nil, "shortlink service requires a secret", nil)
+	}
+
+	return &Service{
+		db:     p.DB,
+		secret: []byte(p.Config.Secret),
+		clock:  p.Clock,
+	}, nil
+}
+
+// Migration returns a csql.Migration that creates the shortlinks table.
+func (s *Service) Migration() csql.Migration {
+	return &migration{db: s.db}
+}
+
+func (m *migration) Run() error {
+	err := m.db.AutoMigrate(&Link{})
+	if err != nil {
+		return cerrors.New(err, "failed to migrate shortlinks table", nil)
+	}
+
+	return nil
+}
+
+// Create stores a new short link to the given URL. If ttl is not zero, the link expires after it.
+func (s *Service) Create(ctx context.Context, url string, ttl time.Duration) (*Link, error) {
+	id := make([]byte, codeIDBytes)
+
+	_, err := rand.Read(id)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to generate short code", nil)
+	}
+
+	encodedID := base64.RawURLEncoding.EncodeToString(id)
+
+	link := Link{
+		Code:      encodedID + s.sign(encodedID),
+		URL:       url,
+		CreatedAt: s.clock.Now(),
+	}
+
+	if ttl > 0 {
+		expiresAt := link.CreatedAt.Add(ttl)
+		link.ExpiresAt = &expiresAt
+	}
+
+	err = csql.GetConn(ctx, s.db).Create(&link).Error
+	if err != nil {
+		return nil, cerrors.New(err, "failed to create shortlink", map[string]interface{}{
+			"url": url,
+		})
+	}
+
+	return &link, nil
+}
+
+// Get returns the link for the given code without counting a hit.
+func (s *Service) Get(ctx context.Context, code string) (*Link, error) {
+	if !s.verify(code) {
+		return nil, ErrNotFound
+	}
+
+	var link Link
+
+	err := csql.GetConn(ctx, s.db).Where(&Link{Code: code}).First(&link).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, cerrors.New(err, "failed to query shortlink", map[string]interface{}{
+			"code": code,
+		})
+	}
+
+	if link.ExpiresAt != nil && !s.clock.Now().Before(*link.ExpiresAt) {
+		return nil, ErrExpired
+	}
+
+	return &link, nil
+}
+
+// Resolve returns the link for the given code and counts a hit.
+func (s *Service) Resolve(ctx context.Context, code string) (*Link, error) {
+	link, err := s.Get(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	err = csql.GetConn(ctx, s.db).
+		Model(&Link{}).
+		Where(&Link{Code: code}).
+		UpdateColumn("hits", gorm.Expr("hits + 1")).Error
+	if err != nil {
+		return nil, cerrors.New(err, "failed to count shortlink hit", map[string]interface{}{
+			"code": code,
+		})
+	}
+
+	link.Hits++
+
+	return link, nil
+}
+
+func (s *Service) sign(id string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	_, _ = mac.Write([]byte(id))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:codeSigBytes])
+}
+
+func (s *Service) verify(code string) bool {
+	idLen := base64.RawURLEncoding.EncodedLen(codeIDBytes)
+	if len(code) != idLen+base64.RawURLEncoding.EncodedLen(codeSigBytes) {
+		return false
+	}
+
+	return hmac.Equal([]byte(code[idLen:]), []byte(s.sign(code[:idLen])))
+}
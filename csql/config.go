@@ -21,4 +21,8 @@ func LoadConfig(appConfig cconfig.Loader) (Config, error) {
 type Config struct {
 	Dialect string `toml:"dialect"`
 	DSN     string `toml:"dsn"`
+
+	// QueryBudget is the number of queries a request can run before QueryBudgetMiddleware logs a warning. The
+	// middleware is disabled if it is not set.
+	QueryBudget int `toml:"query_budget"`
 }
@@ -38,7 +38,12 @@ func NewDBConnection(lc *clifecycle.Lifecycle, config Config, logger clogger.Log
 		return nil, cerrors.New(err, "failed to open db connection", nil)
 	}
 
-	lc.OnStop(func(ctx context.Context) error {
+	err = registerQueryCounter(db)
+	if err != nil {
+		return nil, err
+	}
+
+	lc.OnStopNamed("database connection", func(ctx context.Context) error {
 		logger.Info("Closing database connection..")
 
 		sqlDB, err := db.DB()
@@ -0,0 +1,33 @@
+package csql
+
+import (
+	"context"
+
+	"github.com/gocopper/copper/cerrors"
+	"gorm.io/gorm"
+)
+
+// NewHealthChecker creates a HealthChecker for the given db connection.
+func NewHealthChecker(db *gorm.DB) *HealthChecker {
+	return &HealthChecker{db: db}
+}
+
+// HealthChecker checks that the database is reachable. It can be registered with chealth.
+type HealthChecker struct {
+	db *gorm.DB
+}
+
+// Check pings the database.
+func (c *HealthChecker) Check(ctx context.Context) error {
+	sqlDB, err := c.db.DB()
+	if err != nil {
+		return cerrors.New(err, "failed to get sql db", nil)
+	}
+
+	err = sqlDB.PingContext(ctx)
+	if err != nil {
+		return cerrors.New(err, "failed to ping db", nil)
+	}
+
+	return nil
+}
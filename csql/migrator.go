@@ -19,7 +19,7 @@ type NewMigratorParams struct {
 func NewMigrator(p NewMigratorParams) *Migrator {
 	return &Migrator{
 		migrations: p.Migrations,
-		logger:     p.Logger,
+		logger:     clogger.Named(p.Logger, "csql"),
 	}
 }
 
@@ -0,0 +1,157 @@
+package csql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/clogger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils"
+)
+
+const (
+	queryCounterCtxKey  = ctxKey("csql/query-counter")
+	queryBudgetTopSites = 5
+)
+
+type queryCounter struct {
+	mu    sync.Mutex
+	count int
+	sites map[string]int
+}
+
+func (c *queryCounter) add(site string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.count++
+	c.sites[site]++
+}
+
+// topSites returns the call sites that ran the most queries formatted as "file:line (count)".
+func (c *queryCounter) topSites(n int) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sites := make([]string, 0, len(c.sites))
+	for site := range c.sites {
+		sites = append(sites, site)
+	}
+
+	sort.Slice(sites, func(i, j int) bool {
+		if c.sites[sites[i]] != c.sites[sites[j]] {
+			return c.sites[sites[i]] > c.sites[sites[j]]
+		}
+
+		return sites[i] < sites[j]
+	})
+
+	if len(sites) > n {
+		sites = sites[:n]
+	}
+
+	for i, site := range sites {
+		sites[i] = fmt.Sprintf("%s (%d)", site, c.sites[site])
+	}
+
+	return sites
+}
+
+// registerQueryCounter adds gorm callbacks that count the queries run with a context created by WithQueryCounter.
+func registerQueryCounter(db *gorm.DB) error {
+	count := func(tx *gorm.DB) {
+		counter, ok := tx.Statement.Context.Value(queryCounterCtxKey).(*queryCounter)
+		if !ok {
+			return
+		}
+
+		counter.add(utils.FileWithLineNum())
+	}
+
+	cb := db.Callback()
+
+	for name, err := range map[string]error{
+		"query":  cb.Query().After("gorm:query").Register("csql:count_query", count),
+		"create": cb.Create().After("gorm:create").Register("csql:count_create", count),
+		"update": cb.Update().After("gorm:update").Register("csql:count_update", count),
+		"delete": cb.Delete().After("gorm:delete").Register("csql:count_delete", count),
+		"row":    cb.Row().After("gorm:row").Register("csql:count_row", count),
+		"raw":    cb.Raw().After("gorm:raw").Register("csql:count_raw", count),
+	} {
+		if err != nil {
+			return cerrors.New(err, "failed to register query counter", map[string]interface{}{
+				"callback": name,
+			})
+		}
+	}
+
+	return nil
+}
+
+// WithQueryCounter returns a context that counts the queries run with it (ex. using GetConn).
+func WithQueryCounter(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryCounterCtxKey, &queryCounter{
+		sites: make(map[string]int),
+	})
+}
+
+// QueryCount returns the number of queries run with a context created by WithQueryCounter.
+func QueryCount(ctx context.Context) int {
+	counter, ok := ctx.Value(queryCounterCtxKey).(*queryCounter)
+	if !ok {
+		return 0
+	}
+
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+
+	return counter.count
+}
+
+// NewQueryBudgetMiddleware creates a new QueryBudgetMiddleware.
+func NewQueryBudgetMiddleware(config Config, logger clogger.Logger) *QueryBudgetMiddleware {
+	return &QueryBudgetMiddleware{
+		budget: config.QueryBudget,
+		logger: clogger.Named(logger, "csql"),
+	}
+}
+
+// QueryBudgetMiddleware counts the queries run while handling each request and logs a warning with the call sites
+// that ran the most queries when Config.QueryBudget is exceeded. This makes N+1 queries visible during development.
+// Queries are only counted if they are run with the request's context.
+type QueryBudgetMiddleware struct {
+	budget int
+	logger clogger.Logger
+}
+
+// Handle counts the queries run by the next handler.
+func (mw *QueryBudgetMiddleware) Handle(next http.Handler) http.Handler {
+	if mw.budget <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := WithQueryCounter(r.Context())
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+
+		count := QueryCount(ctx)
+		if count <= mw.budget {
+			return
+		}
+
+		counter := ctx.Value(queryCounterCtxKey).(*queryCounter)
+
+		mw.logger.WithTags(map[string]interface{}{
+			"method":   r.Method,
+			"url":      r.URL.Path,
+			"queries":  count,
+			"budget":   mw.budget,
+			"topSites": counter.topSites(queryBudgetTopSites),
+		}).Warn("Request exceeded its query budget", nil)
+	})
+}
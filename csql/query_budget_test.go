@@ -0,0 +1,56 @@
+package csql_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocopper/copper/clifecycle"
+	"github.com/gocopper/copper/clogger"
+	"github.com/gocopper/copper/csql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryBudgetMiddleware(t *testing.T) {
+	t.Parallel()
+
+	type item struct {
+		ID int
+	}
+
+	var (
+		logs   = make([]clogger.RecordedLog, 0)
+		logger = clogger.NewRecorder(&logs)
+		lc     = clifecycle.New()
+		config = csql.Config{
+			Dialect:     "sqlite",
+			DSN:         ":memory:",
+			QueryBudget: 2,
+		}
+	)
+
+	defer lc.Stop(logger)
+
+	db, err := csql.NewDBConnection(lc, config, logger)
+	assert.NoError(t, err)
+
+	assert.NoError(t, db.AutoMigrate(&item{}))
+
+	handler := csql.NewQueryBudgetMiddleware(config, logger).Handle(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			for i := 1; i <= 3; i++ {
+				var it item
+
+				_ = csql.GetConn(r.Context(), db).Find(&it, i).Error
+			}
+		}))
+
+	logs = logs[:0]
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items", nil))
+
+	assert.Equal(t, 1, len(logs))
+	assert.Equal(t, "Request exceeded its query budget", logs[0].Msg)
+	assert.Equal(t, 3, logs[0].Tags["queries"])
+	assert.Contains(t, logs[0].Tags["topSites"].([]string)[0], "query_budget_test.go")
+}
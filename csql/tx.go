@@ -0,0 +1,16 @@
+package csql
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// RunInTx runs fn in a database transaction. The transaction is stored in the context given to fn so that GetConn
+// returns it. The transaction is committed if fn returns nil and rolled back otherwise. If the context already holds
+// a transaction, a nested transaction (savepoint) is used.
+func RunInTx(ctx context.Context, db *gorm.DB, fn func(ctx context.Context) error) error {
+	return GetConn(ctx, db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, connCtxKey, tx))
+	})
+}
@@ -0,0 +1,55 @@
+package csql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gocopper/copper/clifecycle"
+	"github.com/gocopper/copper/clogger"
+	"github.com/gocopper/copper/csql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunInTx(t *testing.T) {
+	t.Parallel()
+
+	type item struct {
+		ID int
+	}
+
+	var (
+		logger = clogger.New()
+		lc     = clifecycle.New()
+		errFn  = errors.New("test-err")
+	)
+
+	defer lc.Stop(logger)
+
+	db, err := csql.NewDBConnection(lc, csql.Config{
+		Dialect: "sqlite",
+		DSN:     ":memory:",
+	}, logger)
+	assert.NoError(t, err)
+
+	assert.NoError(t, db.AutoMigrate(&item{}))
+
+	err = csql.RunInTx(context.Background(), db, func(ctx context.Context) error {
+		assert.NoError(t, csql.GetConn(ctx, db).Create(&item{ID: 1}).Error)
+
+		return nil
+	})
+	assert.NoError(t, err)
+
+	err = csql.RunInTx(context.Background(), db, func(ctx context.Context) error {
+		assert.NoError(t, csql.GetConn(ctx, db).Create(&item{ID: 2}).Error)
+
+		return errFn
+	})
+	assert.ErrorIs(t, err, errFn)
+
+	var count int64
+
+	assert.NoError(t, db.Model(&item{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
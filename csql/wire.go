@@ -6,6 +6,8 @@ import "github.com/google/wire"
 var WireModule = wire.NewSet(
 	NewDBConnection,
 	NewMigrator,
+	NewHealthChecker,
+	NewQueryBudgetMiddleware,
 	LoadConfig,
 
 	wire.Struct(new(NewMigratorParams), "*"),
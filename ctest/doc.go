@@ -0,0 +1,3 @@
+// Package ctest helps write end-to-end tests that boot a Copper app in-process with a temp database and drive it
+// over HTTP.
+package ctest
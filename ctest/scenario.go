@@ -0,0 +1,196 @@
+package ctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper"
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cconfig/cconfigtest"
+	"github.com/gocopper/copper/clifecycle"
+	"github.com/gocopper/copper/clogger"
+	"github.com/gocopper/copper/csql"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// Params holds the params needed to create a new Scenario.
+type Params struct {
+	// Config is the TOML config for the app. It is written to a temp file and loaded with cconfig.
+	Config string
+
+	// Migrate runs the database migrations before the fixtures are inserted.
+	Migrate func(db *gorm.DB) error
+
+	// Fixtures are inserted into the database in order. Each fixture should be a pointer to a model or a slice of
+	// models.
+	Fixtures []interface{}
+
+	// Handler creates the app's http.Handler using the scenario's app and database.
+	Handler func(app *copper.App, db *gorm.DB) (http.Handler, error)
+}
+
+// Scenario holds an app that is running in-process along with its database and an HTTP client that can be used to
+// make requests to it. The client keeps cookies across requests. The app's clock is a fake that can be moved forward
+// with Clock.Advance to test expiry and timeouts.
+type Scenario struct {
+	App    *copper.App
+	DB     *gorm.DB
+	Clock  *cclock.Fake
+	Logs   *[]clogger.RecordedLog
+	Server *httptest.Server
+	Client *http.Client
+
+	t *testing.T
+}
+
+// Response holds the status code, headers, and the body of a response received by the Scenario's client.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// New boots the app as configured by the given Params. It creates a temp sqlite database, runs the migrations,
+// inserts the fixtures, and starts a test http server with the app's handler. Everything is cleaned up once the test
+// completes.
+func New(t *testing.T, p Params) *Scenario {
+	t.Helper()
+
+	var (
+		logs   = make([]clogger.RecordedLog, 0)
+		logger = clogger.NewRecorder(&logs)
+		lc     = clifecycle.New()
+		dir    = cconfigtest.SetupDirWithConfigs(t, map[string]string{
+			"test.toml": p.Config,
+		})
+	)
+
+	config, err := cconfig.NewWithKeyOverrides(cconfig.Path(path.Join(dir, "test.toml")))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	db, err := csql.NewDBConnection(lc, csql.Config{
+		Dialect: "sqlite",
+		DSN:     path.Join(dir, "test.db"),
+	}, logger)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	t.Cleanup(func() { lc.Stop(logger) })
+
+	if p.Migrate != nil {
+		if !assert.NoError(t, p.Migrate(db)) {
+			t.FailNow()
+		}
+	}
+
+	for i := range p.Fixtures {
+		if !assert.NoError(t, db.Create(p.Fixtures[i]).Error) {
+			t.FailNow()
+		}
+	}
+
+	clock := cclock.NewFake(time.Now())
+
+	app := copper.NewApp(lc, config, logger)
+	app.Clock = clock
+
+	handler, err := p.Handler(app, db)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	jar, err := cookiejar.New(nil)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return &Scenario{
+		App:    app,
+		DB:     db,
+		Clock:  clock,
+		Logs:   &logs,
+		Server: server,
+		Client: &http.Client{Jar: jar},
+
+		t: t,
+	}
+}
+
+// Get makes a GET request to the given path.
+func (s *Scenario) Get(path string) Response {
+	s.t.Helper()
+
+	return s.Do(http.MethodGet, path, "", nil)
+}
+
+// PostJSON makes a POST request to the given path with the JSON encoded body.
+func (s *Scenario) PostJSON(path string, body interface{}) Response {
+	s.t.Helper()
+
+	data, err := json.Marshal(body)
+	assert.NoError(s.t, err)
+
+	return s.Do(http.MethodPost, path, "application/json", bytes.NewReader(data))
+}
+
+// PostForm makes a POST request to the given path with the URL-encoded form values.
+func (s *Scenario) PostForm(path string, values url.Values) Response {
+	s.t.Helper()
+
+	return s.Do(http.MethodPost, path, "application/x-www-form-urlencoded", strings.NewReader(values.Encode()))
+}
+
+// Do makes a request to the given path with the method, content type, and body. The response body is read fully
+// and closed.
+func (s *Scenario) Do(method, path, contentType string, body io.Reader) Response {
+	s.t.Helper()
+
+	req, err := http.NewRequest(method, s.Server.URL+path, body) //nolint:noctx
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.Client.Do(req)
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(s.t, resp.Body.Close())
+	assert.NoError(s.t, err)
+
+	return Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       respBody,
+	}
+}
+
+// DecodeJSON decodes the response body into dest and fails the test if the body is not valid JSON.
+func (r Response) DecodeJSON(t *testing.T, dest interface{}) {
+	t.Helper()
+
+	assert.NoError(t, json.Unmarshal(r.Body, dest))
+}
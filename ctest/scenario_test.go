@@ -0,0 +1,103 @@
+package ctest_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper"
+	"github.com/gocopper/copper/ctest"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type item struct {
+	ID   int
+	Name string
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	s := ctest.New(t, ctest.Params{
+		Migrate: func(db *gorm.DB) error {
+			return db.AutoMigrate(&item{})
+		},
+		Fixtures: []interface{}{
+			&[]item{{ID: 1, Name: "first"}, {ID: 2, Name: "second"}},
+		},
+		Handler: func(app *copper.App, db *gorm.DB) (http.Handler, error) {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var items []item
+
+				err := db.Order("id").Find(&items).Error
+				if err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+
+					return
+				}
+
+				for _, it := range items {
+					_, _ = w.Write([]byte(it.Name + "\n"))
+				}
+			}), nil
+		},
+	})
+
+	resp := s.Get("/items")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "first\nsecond\n", string(resp.Body))
+}
+
+func TestScenario_Cookies(t *testing.T) {
+	t.Parallel()
+
+	s := ctest.New(t, ctest.Params{
+		Handler: func(app *copper.App, db *gorm.DB) (http.Handler, error) {
+			mux := http.NewServeMux()
+
+			mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+				http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+			})
+
+			mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+				cookie, err := r.Cookie("session")
+				if err != nil {
+					w.WriteHeader(http.StatusUnauthorized)
+
+					return
+				}
+
+				_, _ = w.Write([]byte(cookie.Value))
+			})
+
+			return mux, nil
+		},
+	})
+
+	assert.Equal(t, http.StatusUnauthorized, s.Get("/me").StatusCode)
+	assert.Equal(t, http.StatusOK, s.Get("/login").StatusCode)
+
+	resp := s.Get("/me")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "abc", string(resp.Body))
+}
+
+func TestScenario_Clock(t *testing.T) {
+	t.Parallel()
+
+	s := ctest.New(t, ctest.Params{
+		Handler: func(app *copper.App, db *gorm.DB) (http.Handler, error) {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(app.Clock.Now().Format(time.RFC3339Nano)))
+			}), nil
+		},
+	})
+
+	start := s.Clock.Now()
+
+	s.Clock.Advance(time.Hour)
+
+	resp := s.Get("/")
+	assert.Equal(t, start.Add(time.Hour).Format(time.RFC3339Nano), string(resp.Body))
+}
@@ -0,0 +1,85 @@
+package cwebhooks
+
+import (
+	"time"
+
+	"github.com/gocopper/copper/cconfig"
+	"github.com/gocopper/copper/cerrors"
+)
+
+const (
+	defaultMaxAttempts    = 8
+	defaultInitialBackoff = 30 * time.Second
+	defaultMaxBackoff     = 6 * time.Hour
+	defaultTimeout        = 10 * time.Second
+	defaultPollInterval   = 5 * time.Second
+	defaultBatchSize      = 50
+)
+
+// LoadConfig loads the cwebhooks config from the app config
+func LoadConfig(appConfig cconfig.Loader) (Config, error) {
+	var config Config
+
+	err := appConfig.Load("cwebhooks", &config)
+	if err != nil {
+		return Config{}, cerrors.New(err, "failed to load webhooks config", nil)
+	}
+
+	return config.withDefaults(), nil
+}
+
+// Config configures the cwebhooks module
+type Config struct {
+	// MaxAttempts is the number of times a delivery is attempted before it is marked as failed. It defaults to 8.
+	MaxAttempts int `toml:"max_attempts"`
+
+	// InitialBackoff is the delay before the first retry. It doubles with each retry up to MaxBackoff. They default
+	// to 30s and 6h.
+	InitialBackoff time.Duration `toml:"initial_backoff"`
+	MaxBackoff     time.Duration `toml:"max_backoff"`
+
+	// Timeout limits how long an endpoint has to respond. It defaults to 10s.
+	Timeout time.Duration `toml:"timeout"`
+
+	// PollInterval is how often the Worker checks for deliveries that are due. It defaults to 5s.
+	PollInterval time.Duration `toml:"poll_interval"`
+
+	// BatchSize limits the number of deliveries that are sent in each poll. It defaults to 50.
+	BatchSize int `toml:"batch_size"`
+
+	// AllowHTTP allows endpoints with http URLs. Only https URLs are allowed by default.
+	AllowHTTP bool `toml:"allow_http"`
+
+	// AllowPrivateNetworks allows endpoints that resolve to private, loopback, or link-local addresses (ex.
+	// localhost in development). They are refused by default so that endpoints cannot be used to reach internal
+	// services.
+	AllowPrivateNetworks bool `toml:"allow_private_networks"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = defaultMaxAttempts
+	}
+
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = defaultInitialBackoff
+	}
+
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = defaultMaxBackoff
+	}
+
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+
+	if c.PollInterval <= 0 {
+		c.PollInterval = defaultPollInterval
+	}
+
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultBatchSize
+	}
+
+	return c
+}
@@ -0,0 +1,4 @@
+// Package cwebhooks delivers webhooks to external systems. Apps register endpoints and publish events. Each delivery
+// is stored with csql, signed with the endpoint's secret, and retried with exponential backoff by the Worker until it
+// succeeds. Every attempt is recorded so that failed deliveries can be inspected.
+package cwebhooks
@@ -0,0 +1,74 @@
+package cwebhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader holds the signature of a webhook's body in the format t=<unix timestamp>,v1=<hex hmac>.
+const SignatureHeader = "Webhook-Signature"
+
+var (
+	// ErrInvalidSignature is returned by Verify when a signature is missing, malformed, or does not match.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+
+	// ErrSignatureExpired is returned by Verify when a signature's timestamp is outside of the tolerance.
+	ErrSignatureExpired = errors.New("webhook signature expired")
+)
+
+// Sign returns the value of the SignatureHeader for the body. The HMAC-SHA256 covers the timestamp and the body so
+// that receivers can reject replayed webhooks.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+
+	return "t=" + ts + ",v1=" + signature(secret, ts, body)
+}
+
+// Verify checks a SignatureHeader value for the body. It can be used by apps that receive webhooks sent by this
+// package. Signatures with a timestamp that is more than tolerance away from now are rejected.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var ts, sig string
+
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2) //nolint:gomnd
+		if len(kv) != 2 {                                     //nolint:gomnd
+			continue
+		}
+
+		switch kv[0] {
+		case "t":
+			ts = kv[1]
+		case "v1":
+			sig = kv[1]
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || sig == "" {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(signature(secret, ts, body))) {
+		return ErrInvalidSignature
+	}
+
+	if d := now.Sub(time.Unix(unix, 0)); d > tolerance || d < -tolerance {
+		return ErrSignatureExpired
+	}
+
+	return nil
+}
+
+func signature(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(ts))
+	_, _ = mac.Write([]byte("."))
+	_, _ = mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,59 @@
+package cwebhooks_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cwebhooks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now  = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		body = []byte(`{"id":1}`)
+		sig  = cwebhooks.Sign("secret", now, body)
+	)
+
+	testCases := []struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		want   error
+	}{
+		{name: "valid", secret: "secret", header: sig, body: body, now: now.Add(time.Minute)},
+		{name: "wrong secret", secret: "other", header: sig, body: body, now: now, want: cwebhooks.ErrInvalidSignature},
+		{
+			name:   "tampered body",
+			secret: "secret",
+			header: sig,
+			body:   []byte(`{"id":2}`),
+			now:    now,
+			want:   cwebhooks.ErrInvalidSignature,
+		},
+		{name: "malformed", secret: "secret", header: "v1=abc", body: body, now: now, want: cwebhooks.ErrInvalidSignature},
+		{
+			name:   "expired",
+			secret: "secret",
+			header: sig,
+			body:   body,
+			now:    now.Add(10 * time.Minute),
+			want:   cwebhooks.ErrSignatureExpired,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := cwebhooks.Verify(tc.secret, tc.header, tc.body, 5*time.Minute, tc.now)
+			assert.Equal(t, tc.want, err)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package cwebhooks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+
+	"github.com/gocopper/copper/cerrors"
+)
+
+var (
+	// ErrInvalidURL is returned when an endpoint URL is not an https URL (or an http URL if Config.AllowHTTP is set).
+	ErrInvalidURL = errors.New("invalid webhook endpoint url")
+
+	// ErrBlockedAddress is returned when an endpoint resolves to a private, loopback, or link-local address and
+	// Config.AllowPrivateNetworks is not set.
+	ErrBlockedAddress = errors.New("webhook endpoint address is not allowed")
+)
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which is often used for internal services.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)} //nolint:gochecknoglobals,gomnd
+
+// checkURL returns an error if the endpoint URL cannot be used with the config. Hosts that are names are checked
+// when they are resolved since they can resolve to a different address on each delivery.
+func checkURL(config Config, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return cerrors.New(ErrInvalidURL, "failed to parse url", map[string]interface{}{
+			"url":   rawURL,
+			"cause": err.Error(),
+		})
+	}
+
+	if u.Scheme != "https" && (u.Scheme != "http" || !config.AllowHTTP) {
+		return cerrors.New(ErrInvalidURL, "url must use https", map[string]interface{}{
+			"url": rawURL,
+		})
+	}
+
+	if u.Hostname() == "" {
+		return cerrors.New(ErrInvalidURL, "url must have a host", map[string]interface{}{
+			"url": rawURL,
+		})
+	}
+
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !config.AllowPrivateNetworks && isBlockedIP(ip) {
+		return cerrors.New(ErrBlockedAddress, "url points to a private address", map[string]interface{}{
+			"url": rawURL,
+		})
+	}
+
+	return nil
+}
+
+// newClient creates the client that sends deliveries. It does not follow redirects, and it refuses to connect to
+// private addresses unless they are allowed, so that endpoints cannot be used to reach internal services.
+func newClient(config Config) *http.Client {
+	dialer := &net.Dialer{Timeout: config.Timeout}
+
+	if !config.AllowPrivateNetworks {
+		// The address is checked after it is resolved so that a name cannot resolve to a private address
+		dialer.Control = func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || isBlockedIP(ip) {
+				return cerrors.New(ErrBlockedAddress, "refused to connect to webhook endpoint", map[string]interface{}{
+					"address": address,
+				})
+			}
+
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	return &http.Client{
+		Timeout:   config.Timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func isBlockedIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip)
+}
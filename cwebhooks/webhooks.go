@@ -0,0 +1,553 @@
+package cwebhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/csql"
+	"gorm.io/gorm"
+)
+
+const (
+	// EventHeader holds the name of the event that is being delivered.
+	EventHeader = "Webhook-Event"
+
+	// DeliveryIDHeader holds the ID of the delivery. It stays the same across retries so that receivers can ignore
+	// deliveries they have already processed.
+	DeliveryIDHeader = "Webhook-Delivery-Id"
+
+	// StatusPending is the status of a delivery that has not succeeded yet and will be retried.
+	StatusPending = "pending"
+
+	// StatusSucceeded is the status of a delivery that the endpoint accepted with a 2xx response.
+	StatusSucceeded = "succeeded"
+
+	// StatusFailed is the status of a delivery that ran out of attempts or whose endpoint was removed.
+	StatusFailed = "failed"
+
+	idBytes           = 16
+	secretBytes       = 32
+	maxErrorBodyBytes = 512
+	allEvents         = "*"
+	userAgent         = "copper-webhooks"
+)
+
+// ErrNotFound is returned when an endpoint or a delivery does not exist.
+var ErrNotFound = errors.New("webhook not found")
+
+type (
+	// Endpoint is a URL that receives webhooks. It is stored in the webhook_endpoints table.
+	Endpoint struct {
+		ID     string `gorm:"primaryKey"`
+		URL    string `gorm:"not null"`
+		Secret string `gorm:"not null"`
+
+		// Events is a comma-separated list of the events that the endpoint receives, or * for all of them.
+		Events    string `gorm:"not null"`
+		Active    bool   `gorm:"not null"`
+		CreatedAt time.Time
+	}
+
+	// Delivery is an event to be delivered to an endpoint. It is stored in the webhook_deliveries table.
+	Delivery struct {
+		ID            string    `gorm:"primaryKey"`
+		EndpointID    string    `gorm:"not null;index"`
+		Event         string    `gorm:"not null"`
+		Payload       string    `gorm:"not null"`
+		Status        string    `gorm:"not null;index:idx_webhook_deliveries_due,priority:1"`
+		Attempts      int       `gorm:"not null;default:0"`
+		NextAttemptAt time.Time `gorm:"not null;index:idx_webhook_deliveries_due,priority:2"`
+		CreatedAt     time.Time
+		UpdatedAt     time.Time
+	}
+
+	// Attempt is a single try at sending a delivery. It is stored in the webhook_attempts table.
+	Attempt struct {
+		ID         uint   `gorm:"primaryKey"`
+		DeliveryID string `gorm:"not null;index"`
+		StatusCode int
+		Error      string
+		Duration   time.Duration
+		CreatedAt  time.Time
+	}
+
+	// NewServiceParams holds the params needed to create a Service
+	NewServiceParams struct {
+		DB     *gorm.DB
+		Config Config
+
+		// Clock is used to schedule and time deliveries. It defaults to the system clock.
+		Clock cclock.Clock
+	}
+
+	// Service registers endpoints, publishes events, and delivers them.
+	Service struct {
+		db     *gorm.DB
+		config Config
+		clock  cclock.Clock
+		client *http.Client
+	}
+
+	migration struct {
+		db *gorm.DB
+	}
+)
+
+// TableName is the table that endpoints are stored in.
+func (Endpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+// TableName is the table that deliveries are stored in.
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// TableName is the table that attempts are stored in.
+func (Attempt) TableName() string {
+	return "webhook_attempts"
+}
+
+// NewService creates a new Service.
+func NewService(p NewServiceParams) *Service {
+	config := p.Config.withDefaults()
+
+	if p.Clock == nil {
+		p.Clock = cclock.New()
+	}
+
+	return &Service{
+		db:     p.DB,
+		config: config,
+		clock:  p.Clock,
+		client: newClient(config),
+	}
+}
+
+// Migration returns a csql.Migration that creates the webhook tables.
+func (s *Service) Migration() csql.Migration {
+	return &migration{db: s.db}
+}
+
+func (m *migration) Run() error {
+	err := m.db.AutoMigrate(&Endpoint{}, &Delivery{}, &Attempt{})
+	if err != nil {
+		return cerrors.New(err, "failed to migrate webhook tables", nil)
+	}
+
+	return nil
+}
+
+// RegisterEndpoint stores a new endpoint that receives the given events, or all of them if none are given. A random
+// secret is generated for signing its webhooks and should be shared with the endpoint's owner. It returns
+// ErrInvalidURL if the URL does not use https, and ErrBlockedAddress if it is a private address (see Config).
+func (s *Service) RegisterEndpoint(ctx context.Context, url string, events ...string) (*Endpoint, error) {
+	err := checkURL(s.config, url)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := randomHex(idBytes)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to generate endpoint id", nil)
+	}
+
+	secret, err := randomHex(secretBytes)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to generate endpoint secret", nil)
+	}
+
+	if len(events) == 0 {
+		events = []string{allEvents}
+	}
+
+	endpoint := Endpoint{
+		ID:        id,
+		URL:       url,
+		Secret:    secret,
+		Events:    strings.Join(events, ","),
+		Active:    true,
+		CreatedAt: s.clock.Now(),
+	}
+
+	err = csql.GetConn(ctx, s.db).Create(&endpoint).Error
+	if err != nil {
+		return nil, cerrors.New(err, "failed to create webhook endpoint", map[string]interface{}{
+			"url": url,
+		})
+	}
+
+	return &endpoint, nil
+}
+
+// GetEndpoint returns the endpoint with the given id.
+func (s *Service) GetEndpoint(ctx context.Context, id string) (*Endpoint, error) {
+	var endpoint Endpoint
+
+	err := csql.GetConn(ctx, s.db).Where(&Endpoint{ID: id}).First(&endpoint).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, cerrors.New(err, "failed to query webhook endpoint", map[string]interface{}{
+			"id": id,
+		})
+	}
+
+	return &endpoint, nil
+}
+
+// SetEndpointActive enables or disables the endpoint. Disabled endpoints do not receive new events, and their pending
+// deliveries fail.
+func (s *Service) SetEndpointActive(ctx context.Context, id string, active bool) error {
+	res := csql.GetConn(ctx, s.db).
+		Model(&Endpoint{}).
+		Where(&Endpoint{ID: id}).
+		UpdateColumn("active", active)
+	if res.Error != nil {
+		return cerrors.New(res.Error, "failed to update webhook endpoint", map[string]interface{}{
+			"id": id,
+		})
+	}
+
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteEndpoint deletes the endpoint. Its pending deliveries fail.
+func (s *Service) DeleteEndpoint(ctx context.Context, id string) error {
+	err := csql.GetConn(ctx, s.db).Where(&Endpoint{ID: id}).Delete(&Endpoint{}).Error
+	if err != nil {
+		return cerrors.New(err, "failed to delete webhook endpoint", map[string]interface{}{
+			"id": id,
+		})
+	}
+
+	return nil
+}
+
+// Publish creates a delivery of the event for each active endpoint that receives it. The payload is encoded as
+// JSON. The deliveries are sent by DeliverDue, so if ctx has a transaction (see csql.RunInTx), they are only sent if
+// it commits.
+func (s *Service) Publish(ctx context.Context, event string, payload interface{}) ([]Delivery, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to encode webhook payload", map[string]interface{}{
+			"event": event,
+		})
+	}
+
+	var endpoints []Endpoint
+
+	err = csql.GetConn(ctx, s.db).Where("active = ?", true).Find(&endpoints).Error
+	if err != nil {
+		return nil, cerrors.New(err, "failed to query webhook endpoints", nil)
+	}
+
+	var (
+		now        = s.clock.Now()
+		deliveries = make([]Delivery, 0, len(endpoints))
+	)
+
+	for i := range endpoints {
+		if !endpoints[i].receives(event) {
+			continue
+		}
+
+		id, err := randomHex(idBytes)
+		if err != nil {
+			return nil, cerrors.New(err, "failed to generate delivery id", nil)
+		}
+
+		deliveries = append(deliveries, Delivery{
+			ID:            id,
+			EndpointID:    endpoints[i].ID,
+			Event:         event,
+			Payload:       string(body),
+			Status:        StatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return deliveries, nil
+	}
+
+	err = csql.GetConn(ctx, s.db).Create(&deliveries).Error
+	if err != nil {
+		return nil, cerrors.New(err, "failed to create webhook deliveries", map[string]interface{}{
+			"event": event,
+		})
+	}
+
+	return deliveries, nil
+}
+
+// GetDelivery returns the delivery with the given id.
+func (s *Service) GetDelivery(ctx context.Context, id string) (*Delivery, error) {
+	var delivery Delivery
+
+	err := csql.GetConn(ctx, s.db).Where(&Delivery{ID: id}).First(&delivery).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, cerrors.New(err, "failed to query webhook delivery", map[string]interface{}{
+			"id": id,
+		})
+	}
+
+	return &delivery, nil
+}
+
+// ListAttempts returns the attempts of the delivery, oldest first.
+func (s *Service) ListAttempts(ctx context.Context, deliveryID string) ([]Attempt, error) {
+	var attempts []Attempt
+
+	err := csql.GetConn(ctx, s.db).Where(&Attempt{DeliveryID: deliveryID}).Order("id").Find(&attempts).Error
+	if err != nil {
+		return nil, cerrors.New(err, "failed to query webhook attempts", map[string]interface{}{
+			"deliveryID": deliveryID,
+		})
+	}
+
+	return attempts, nil
+}
+
+// Redeliver schedules a delivery to be sent again right away, even if it has already succeeded or failed. It gets
+// a fresh set of attempts.
+func (s *Service) Redeliver(ctx context.Context, id string) error {
+	res := csql.GetConn(ctx, s.db).
+		Model(&Delivery{}).
+		Where(&Delivery{ID: id}).
+		Updates(map[string]interface{}{
+			"status":          StatusPending,
+			"attempts":        0,
+			"next_attempt_at": s.clock.Now(),
+			"updated_at":      s.clock.Now(),
+		})
+	if res.Error != nil {
+		return cerrors.New(res.Error, "failed to reschedule webhook delivery", map[string]interface{}{
+			"id": id,
+		})
+	}
+
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeliverDue sends up to Config.BatchSize pending deliveries whose next attempt is due and returns how many were
+// sent. Each delivery is claimed before it is sent, so DeliverDue can run on multiple instances of the app at once.
+// Failed deliveries are retried with exponential backoff until Config.MaxAttempts is reached.
+func (s *Service) DeliverDue(ctx context.Context) (int, error) {
+	var due []Delivery
+
+	err := csql.GetConn(ctx, s.db).
+		Where("status = ? AND next_attempt_at <= ?", StatusPending, s.clock.Now()).
+		Order("next_attempt_at").
+		Limit(s.config.BatchSize).
+		Find(&due).Error
+	if err != nil {
+		return 0, cerrors.New(err, "failed to query due webhook deliveries", nil)
+	}
+
+	sent := 0
+
+	for i := range due {
+		claimed, err := s.claim(ctx, &due[i])
+		if err != nil {
+			return sent, err
+		}
+
+		if !claimed {
+			continue
+		}
+
+		err = s.deliver(ctx, &due[i])
+		if err != nil {
+			return sent, err
+		}
+
+		sent++
+	}
+
+	return sent, nil
+}
+
+// claim counts an attempt for the delivery and pushes its next attempt past the request timeout so that other
+// instances do not send it at the same time. It returns false if another instance claimed it first.
+func (s *Service) claim(ctx context.Context, d *Delivery) (bool, error) {
+	now := s.clock.Now()
+
+	res := csql.GetConn(ctx, s.db).
+		Model(&Delivery{}).
+		Where("id = ? AND status = ? AND attempts = ?", d.ID, StatusPending, d.Attempts).
+		Updates(map[string]interface{}{
+			"attempts":        d.Attempts + 1,
+			"next_attempt_at": now.Add(2 * s.config.Timeout), //nolint:gomnd
+			"updated_at":      now,
+		})
+	if res.Error != nil {
+		return false, cerrors.New(res.Error, "failed to claim webhook delivery", map[string]interface{}{
+			"id": d.ID,
+		})
+	}
+
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+
+	d.Attempts++
+
+	return true, nil
+}
+
+func (s *Service) deliver(ctx context.Context, d *Delivery) error {
+	var (
+		start   = s.clock.Now()
+		attempt = Attempt{DeliveryID: d.ID, CreatedAt: start}
+		updates = map[string]interface{}{}
+	)
+
+	endpoint, err := s.GetEndpoint(ctx, d.EndpointID)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		attempt.Error = "endpoint does not exist"
+	case err != nil:
+		return err
+	case !endpoint.Active:
+		attempt.Error = "endpoint is disabled"
+	default:
+		attempt.StatusCode, err = s.send(ctx, endpoint, d)
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+	}
+
+	attempt.Duration = s.clock.Now().Sub(start)
+
+	switch {
+	case attempt.Error == "":
+		updates["status"] = StatusSucceeded
+	case endpoint == nil || !endpoint.Active || d.Attempts >= s.config.MaxAttempts:
+		updates["status"] = StatusFailed
+	default:
+		updates["next_attempt_at"] = s.clock.Now().Add(s.backoff(d.Attempts))
+	}
+
+	updates["updated_at"] = s.clock.Now()
+
+	return csql.RunInTx(ctx, s.db, func(ctx context.Context) error {
+		err := csql.GetConn(ctx, s.db).Create(&attempt).Error
+		if err != nil {
+			return cerrors.New(err, "failed to record webhook attempt", map[string]interface{}{
+				"deliveryID": d.ID,
+			})
+		}
+
+		err = csql.GetConn(ctx, s.db).Model(&Delivery{}).Where(&Delivery{ID: d.ID}).Updates(updates).Error
+		if err != nil {
+			return cerrors.New(err, "failed to update webhook delivery", map[string]interface{}{
+				"id": d.ID,
+			})
+		}
+
+		return nil
+	})
+}
+
+// send posts the delivery's payload to the endpoint. An error is returned if the request fails or the endpoint does
+// not respond with a 2xx status code.
+func (s *Service) send(ctx context.Context, endpoint *Endpoint, d *Delivery) (int, error) {
+	body := []byte(d.Payload)
+
+	// The URL is checked again in case the config changed since the endpoint was registered
+	err := checkURL(s.config, endpoint.URL)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(EventHeader, d.Event)
+	req.Header.Set(DeliveryIDHeader, d.ID)
+	req.Header.Set(SignatureHeader, Sign(endpoint.Secret, s.clock.Now(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, cerrors.New(nil, "endpoint responded with an unexpected status code",
+			map[string]interface{}{
+				"statusCode": resp.StatusCode,
+				"body":       strings.TrimSpace(string(respBody)),
+			})
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before the retry that follows the given number of attempts.
+func (s *Service) backoff(attempts int) time.Duration {
+	d := s.config.InitialBackoff
+
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= s.config.MaxBackoff {
+			return s.config.MaxBackoff
+		}
+	}
+
+	return d
+}
+
+func (e *Endpoint) receives(event string) bool {
+	for _, name := range strings.Split(e.Events, ",") {
+		if name == allEvents || name == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,292 @@
+package cwebhooks_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/clifecycle"
+	"github.com/gocopper/copper/clogger"
+	"github.com/gocopper/copper/csql"
+	"github.com/gocopper/copper/cwebhooks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_DeliverDue(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		received []*http.Request
+		bodies   [][]byte
+		status   = http.StatusInternalServerError
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		received = append(received, r)
+		bodies = append(bodies, body)
+
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	var (
+		ctx    = context.Background()
+		logger = clogger.NewNoop()
+		lc     = clifecycle.New()
+		clock  = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	)
+
+	defer lc.Stop(logger)
+
+	db, err := csql.NewDBConnection(lc, csql.Config{
+		Dialect: "sqlite",
+		DSN:     ":memory:",
+	}, logger)
+	assert.NoError(t, err)
+
+	svc := cwebhooks.NewService(cwebhooks.NewServiceParams{
+		DB: db,
+		Config: cwebhooks.Config{
+			MaxAttempts:          2,
+			InitialBackoff:       time.Minute,
+			AllowHTTP:            true,
+			AllowPrivateNetworks: true,
+		},
+		Clock: clock,
+	})
+	assert.NoError(t, svc.Migration().Run())
+
+	endpoint, err := svc.RegisterEndpoint(ctx, server.URL, "order.created")
+	assert.NoError(t, err)
+
+	_, err = svc.RegisterEndpoint(ctx, server.URL, "order.deleted")
+	assert.NoError(t, err)
+
+	deliveries, err := svc.Publish(ctx, "order.created", map[string]int{"id": 1})
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+
+	id := deliveries[0].ID
+
+	sent, err := svc.DeliverDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	assert.Len(t, received, 1)
+	assert.Equal(t, "order.created", received[0].Header.Get(cwebhooks.EventHeader))
+	assert.Equal(t, id, received[0].Header.Get(cwebhooks.DeliveryIDHeader))
+	assert.Equal(t, `{"id":1}`, string(bodies[0]))
+	assert.NoError(t, cwebhooks.Verify(endpoint.Secret, received[0].Header.Get(cwebhooks.SignatureHeader),
+		bodies[0], time.Minute, clock.Now()))
+
+	// the retry is not due until the backoff passes
+	sent, err = svc.DeliverDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	delivery, err := svc.GetDelivery(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, cwebhooks.StatusPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+
+	clock.Advance(time.Minute)
+
+	sent, err = svc.DeliverDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	delivery, err = svc.GetDelivery(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, cwebhooks.StatusFailed, delivery.Status)
+
+	mu.Lock()
+	status = http.StatusNoContent
+	mu.Unlock()
+
+	assert.NoError(t, svc.Redeliver(ctx, id))
+
+	sent, err = svc.DeliverDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	delivery, err = svc.GetDelivery(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, cwebhooks.StatusSucceeded, delivery.Status)
+
+	attempts, err := svc.ListAttempts(ctx, id)
+	assert.NoError(t, err)
+	assert.Len(t, attempts, 3)
+	assert.Equal(t, http.StatusInternalServerError, attempts[0].StatusCode)
+	assert.NotEmpty(t, attempts[0].Error)
+	assert.Equal(t, http.StatusNoContent, attempts[2].StatusCode)
+	assert.Empty(t, attempts[2].Error)
+}
+
+func TestService_DeliverDue_DisabledEndpoint(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		logger = clogger.NewNoop()
+		lc     = clifecycle.New()
+		clock  = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	)
+
+	defer lc.Stop(logger)
+
+	db, err := csql.NewDBConnection(lc, csql.Config{
+		Dialect: "sqlite",
+		DSN:     ":memory:",
+	}, logger)
+	assert.NoError(t, err)
+
+	svc := cwebhooks.NewService(cwebhooks.NewServiceParams{
+		DB:     db,
+		Config: cwebhooks.Config{AllowHTTP: true},
+		Clock:  clock,
+	})
+	assert.NoError(t, svc.Migration().Run())
+
+	endpoint, err := svc.RegisterEndpoint(ctx, "http://localhost:0")
+	assert.NoError(t, err)
+
+	deliveries, err := svc.Publish(ctx, "order.created", nil)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+
+	assert.NoError(t, svc.SetEndpointActive(ctx, endpoint.ID, false))
+
+	_, err = svc.DeliverDue(ctx)
+	assert.NoError(t, err)
+
+	delivery, err := svc.GetDelivery(ctx, deliveries[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, cwebhooks.StatusFailed, delivery.Status)
+
+	deliveries, err = svc.Publish(ctx, "order.created", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, deliveries)
+}
+
+func TestService_EndpointURL(t *testing.T) {
+	t.Parallel()
+
+	var redirected bool
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+
+	redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer redirect.Close()
+
+	var (
+		ctx    = context.Background()
+		logger = clogger.NewNoop()
+		lc     = clifecycle.New()
+		clock  = cclock.NewFake(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	)
+
+	defer lc.Stop(logger)
+
+	db, err := csql.NewDBConnection(lc, csql.Config{
+		Dialect: "sqlite",
+		DSN:     ":memory:",
+	}, logger)
+	assert.NoError(t, err)
+
+	newService := func(config cwebhooks.Config) *cwebhooks.Service {
+		return cwebhooks.NewService(cwebhooks.NewServiceParams{DB: db, Config: config, Clock: clock})
+	}
+
+	svc := newService(cwebhooks.Config{})
+	assert.NoError(t, svc.Migration().Run())
+
+	valid, err := svc.RegisterEndpoint(ctx, "https://example.com/webhooks", "order.created")
+	assert.NoError(t, err)
+	assert.NoError(t, svc.SetEndpointActive(ctx, valid.ID, false))
+
+	for rawURL, wantErr := range map[string]error{
+		"http://example.com/webhooks":             cwebhooks.ErrInvalidURL,
+		"ftp://example.com/webhooks":              cwebhooks.ErrInvalidURL,
+		"https:///webhooks":                       cwebhooks.ErrInvalidURL,
+		"https://169.254.169.254/latest/metadata": cwebhooks.ErrBlockedAddress,
+		"https://10.0.0.1/webhooks":               cwebhooks.ErrBlockedAddress,
+		"https://[::1]/webhooks":                  cwebhooks.ErrBlockedAddress,
+	} {
+		_, err = svc.RegisterEndpoint(ctx, rawURL)
+		assert.ErrorIs(t, err, wantErr, rawURL)
+	}
+
+	// The local test servers are refused when they are resolved unless private networks are allowed
+	svc = newService(cwebhooks.Config{AllowHTTP: true})
+
+	_, err = svc.RegisterEndpoint(ctx, strings.Replace(target.URL, "127.0.0.1", "localhost", 1), "order.created")
+	assert.NoError(t, err)
+
+	deliveries, err := svc.Publish(ctx, "order.created", nil)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+
+	_, err = svc.DeliverDue(ctx)
+	assert.NoError(t, err)
+	assert.False(t, redirected)
+
+	attempts, err := svc.ListAttempts(ctx, deliveries[0].ID)
+	assert.NoError(t, err)
+	assert.Contains(t, attempts[0].Error, "refused to connect to webhook endpoint")
+
+	// Redirects are not followed
+	svc = newService(cwebhooks.Config{AllowHTTP: true, AllowPrivateNetworks: true})
+
+	_, err = svc.RegisterEndpoint(ctx, redirect.URL, "order.updated")
+	assert.NoError(t, err)
+
+	deliveries, err = svc.Publish(ctx, "order.updated", nil)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+
+	_, err = svc.DeliverDue(ctx)
+	assert.NoError(t, err)
+	assert.False(t, redirected)
+
+	attempts, err = svc.ListAttempts(ctx, deliveries[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTemporaryRedirect, attempts[0].StatusCode)
+}
+
+func TestNewService_DefaultClock(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger = clogger.NewNoop()
+		lc     = clifecycle.New()
+	)
+
+	defer lc.Stop(logger)
+
+	db, err := csql.NewDBConnection(lc, csql.Config{
+		Dialect: "sqlite",
+		DSN:     ":memory:",
+	}, logger)
+	assert.NoError(t, err)
+
+	svc := cwebhooks.NewService(cwebhooks.NewServiceParams{DB: db})
+	assert.NoError(t, svc.Migration().Run())
+
+	_, err = svc.RegisterEndpoint(context.Background(), "https://example.com/webhooks", "order.created")
+	assert.NoError(t, err)
+}
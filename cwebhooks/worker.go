@@ -0,0 +1,103 @@
+package cwebhooks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gocopper/copper/cclock"
+	"github.com/gocopper/copper/clifecycle"
+	"github.com/gocopper/copper/clogger"
+)
+
+type (
+	// NewWorkerParams holds the params needed to create a Worker.
+	NewWorkerParams struct {
+		Service   *Service
+		Config    Config
+		Lifecycle *clifecycle.Lifecycle
+		Logger    clogger.Logger
+
+		// Clock is used to wait between polls. It defaults to the system clock.
+		Clock cclock.Clock
+	}
+
+	// Worker sends due deliveries every Config.PollInterval.
+	Worker struct {
+		svc    *Service
+		config Config
+		clock  cclock.Clock
+		lc     *clifecycle.Lifecycle
+		logger clogger.Logger
+
+		stopOnce sync.Once
+		stop     chan struct{}
+		done     chan struct{}
+	}
+)
+
+// NewWorker creates a new Worker.
+func NewWorker(p NewWorkerParams) *Worker {
+	if p.Clock == nil {
+		p.Clock = cclock.New()
+	}
+
+	return &Worker{
+		svc:    p.Service,
+		config: p.Config.withDefaults(),
+		clock:  p.Clock,
+		lc:     p.Lifecycle,
+		logger: p.Logger,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Run sends due deliveries until the app shuts down. It blocks, so it should be run in its own goroutine. On
+// shutdown, the deliveries that are being sent are allowed to finish.
+func (w *Worker) Run() error {
+	w.lc.OnStopNamed("webhook worker", w.shutdown)
+
+	defer close(w.done)
+
+	for {
+		w.deliverDue()
+
+		select {
+		case <-w.stop:
+			return nil
+		case <-w.clock.After(w.config.PollInterval):
+		}
+	}
+}
+
+// deliverDue sends batches of due deliveries until there are none left or the worker is stopped.
+func (w *Worker) deliverDue() {
+	for {
+		sent, err := w.svc.DeliverDue(context.Background())
+		if err != nil {
+			w.logger.Error("Failed to deliver webhooks", err)
+			return
+		}
+
+		select {
+		case <-w.stop:
+			return
+		default:
+		}
+
+		if sent < w.config.BatchSize {
+			return
+		}
+	}
+}
+
+func (w *Worker) shutdown(ctx context.Context) error {
+	w.stopOnce.Do(func() { close(w.stop) })
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
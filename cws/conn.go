@@ -0,0 +1,126 @@
+package cws
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	sendQueueSize  = 64
+	maxMessageSize = 64 << 10
+)
+
+// Conn is a websocket connection managed by a Hub. Messages are sent through a queue so that a slow client does not
+// block the sender. A client that cannot keep up with its queue is disconnected.
+type Conn struct {
+	hub  *Hub
+	ws   *websocket.Conn
+	send chan []byte
+
+	closeOnce sync.Once
+	done      chan struct{}
+
+	mu    sync.Mutex
+	rooms map[string]bool
+}
+
+func newConn(hub *Hub, ws *websocket.Conn) *Conn {
+	return &Conn{
+		hub:   hub,
+		ws:    ws,
+		send:  make(chan []byte, sendQueueSize),
+		done:  make(chan struct{}),
+		rooms: make(map[string]bool),
+	}
+}
+
+// Send queues a text message to be written to the connection. It returns false if the connection is closed or its
+// queue is full, in which case the connection is closed.
+func (c *Conn) Send(msg []byte) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+
+	select {
+	case c.send <- msg:
+		return true
+	default:
+		c.Close()
+		return false
+	}
+}
+
+// Close closes the connection and removes it from the hub.
+func (c *Conn) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.hub.remove(c)
+	})
+}
+
+// Done returns a channel that is closed when the connection is closed.
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *Conn) readPump(onMessage func(c *Conn, msg []byte)) {
+	defer c.Close()
+
+	c.ws.SetReadLimit(maxMessageSize)
+	_ = c.ws.SetReadDeadline(time.Now().Add(pongWait))
+
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, msg, err := c.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		if onMessage != nil {
+			onMessage(c, msg)
+		}
+	}
+}
+
+func (c *Conn) writePump(closeMsg func() []byte) {
+	ticker := time.NewTicker(pingPeriod)
+
+	defer func() {
+		ticker.Stop()
+		_ = c.ws.Close()
+	}()
+
+	for {
+		select {
+		case msg := <-c.send:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+
+			err := c.ws.WriteMessage(websocket.TextMessage, msg)
+			if err != nil {
+				c.Close()
+				return
+			}
+		case <-ticker.C:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+
+			err := c.ws.WriteMessage(websocket.PingMessage, nil)
+			if err != nil {
+				c.Close()
+				return
+			}
+		case <-c.done:
+			_ = c.ws.WriteControl(websocket.CloseMessage, closeMsg(), time.Now().Add(writeWait))
+			return
+		}
+	}
+}
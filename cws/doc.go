@@ -0,0 +1,3 @@
+// Package cws provides websocket connections that are managed by a Hub. The hub groups connections into rooms so that
+// messages can be broadcast to them, and closes all connections when the app shuts down.
+package cws
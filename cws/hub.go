@@ -0,0 +1,259 @@
+package cws
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gocopper/copper/cerrors"
+	"github.com/gocopper/copper/clifecycle"
+	"github.com/gocopper/copper/clogger"
+	"github.com/gorilla/websocket"
+)
+
+type (
+	// Handler holds the callbacks for a websocket endpoint. All of them are optional. OnMessage is called for each
+	// message read from the connection, one at a time.
+	Handler struct {
+		OnConnect func(c *Conn)
+		OnMessage func(c *Conn, msg []byte)
+		OnClose   func(c *Conn)
+	}
+
+	// NewHubParams holds the params needed to create a Hub.
+	NewHubParams struct {
+		Lifecycle *clifecycle.Lifecycle
+		Logger    clogger.Logger
+	}
+
+	// Hub tracks the open websocket connections and the rooms they have joined.
+	Hub struct {
+		logger   clogger.Logger
+		upgrader websocket.Upgrader
+
+		mu           sync.Mutex
+		conns        map[*Conn]bool
+		rooms        map[string]map[*Conn]bool
+		shuttingDown bool
+		wg           sync.WaitGroup
+	}
+)
+
+// NewHub creates a new Hub. Open connections are closed when the app shuts down.
+func NewHub(p NewHubParams) *Hub {
+	h := &Hub{
+		logger: p.Logger,
+		conns:  make(map[*Conn]bool),
+		rooms:  make(map[string]map[*Conn]bool),
+	}
+
+	p.Lifecycle.OnStopNamed("websocket hub", h.Shutdown)
+
+	return h
+}
+
+// HandlerFunc returns a http.HandlerFunc that upgrades requests to websocket connections. It can be used as the
+// handler of a chttp.Route.
+func (h *Hub) HandlerFunc(handler Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, err := h.Upgrade(w, r, handler)
+		if err != nil {
+			h.logger.WithTags(map[string]interface{}{
+				"url": r.URL.String(),
+			}).Warn("Failed to upgrade websocket connection", err)
+		}
+	}
+}
+
+// Upgrade upgrades the request to a websocket connection and adds it to the hub. The connection's read and write
+// loops run in their own goroutines, so Upgrade returns right away. Cross-origin requests are rejected.
+func (h *Hub) Upgrade(w http.ResponseWriter, r *http.Request, handler Handler) (*Conn, error) {
+	h.mu.Lock()
+	shuttingDown := h.shuttingDown
+	h.mu.Unlock()
+
+	if shuttingDown {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return nil, cerrors.New(nil, "hub is shutting down", nil)
+	}
+
+	ws, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return nil, cerrors.New(err, "failed to upgrade connection", nil)
+	}
+
+	c := newConn(h, ws)
+
+	// The hub may have started shutting down during the upgrade, in which case Shutdown would not close the
+	// connection or wait for its goroutines
+	h.mu.Lock()
+	if h.shuttingDown {
+		h.mu.Unlock()
+
+		_ = ws.WriteControl(websocket.CloseMessage, h.closeMessage(), time.Now().Add(writeWait))
+		_ = ws.Close()
+
+		return nil, cerrors.New(nil, "hub is shutting down", nil)
+	}
+
+	h.conns[c] = true
+	h.wg.Add(2) //nolint:gomnd
+	h.mu.Unlock()
+
+	if handler.OnConnect != nil {
+		handler.OnConnect(c)
+	}
+
+	go func() {
+		defer h.wg.Done()
+
+		c.writePump(h.closeMessage)
+	}()
+
+	go func() {
+		defer h.wg.Done()
+
+		c.readPump(handler.OnMessage)
+
+		if handler.OnClose != nil {
+			handler.OnClose(c)
+		}
+	}()
+
+	return c, nil
+}
+
+// Join adds the connection to the room.
+func (h *Hub) Join(c *Conn, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.conns[c] {
+		return
+	}
+
+	if h.rooms[room] == nil {
+		h.rooms[room] = make(map[*Conn]bool)
+	}
+
+	h.rooms[room][c] = true
+
+	c.mu.Lock()
+	c.rooms[room] = true
+	c.mu.Unlock()
+}
+
+// Leave removes the connection from the room.
+func (h *Hub) Leave(c *Conn, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.leaveLocked(c, room)
+}
+
+// Broadcast sends the message to all connections in the room and returns the number of connections it was queued
+// for.
+func (h *Hub) Broadcast(room string, msg []byte) int {
+	h.mu.Lock()
+
+	conns := make([]*Conn, 0, len(h.rooms[room]))
+	for c := range h.rooms[room] {
+		conns = append(conns, c)
+	}
+
+	h.mu.Unlock()
+
+	sent := 0
+
+	for _, c := range conns {
+		if c.Send(msg) {
+			sent++
+		}
+	}
+
+	return sent
+}
+
+// Count returns the number of open connections.
+func (h *Hub) Count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.conns)
+}
+
+// Shutdown stops accepting new connections, closes the open ones with a "going away" close message, and waits for
+// their goroutines to exit or for the context to expire.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.shuttingDown = true
+
+	conns := make([]*Conn, 0, len(h.conns))
+	for c := range h.conns {
+		conns = append(conns, c)
+	}
+
+	h.mu.Unlock()
+
+	for _, c := range conns {
+		c.Close()
+		// Unblock the read loop, which is waiting for the client.
+		_ = c.ws.UnderlyingConn().SetReadDeadline(time.Now())
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return cerrors.New(ctx.Err(), "timed out waiting for websocket connections to close", nil)
+	}
+}
+
+func (h *Hub) closeMessage() []byte {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.shuttingDown {
+		return websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	}
+
+	return websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+}
+
+func (h *Hub) remove(c *Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.conns, c)
+
+	c.mu.Lock()
+	rooms := make([]string, 0, len(c.rooms))
+	for room := range c.rooms {
+		rooms = append(rooms, room)
+	}
+	c.mu.Unlock()
+
+	for _, room := range rooms {
+		h.leaveLocked(c, room)
+	}
+}
+
+func (h *Hub) leaveLocked(c *Conn, room string) {
+	delete(h.rooms[room], c)
+
+	if len(h.rooms[room]) == 0 {
+		delete(h.rooms, room)
+	}
+
+	c.mu.Lock()
+	delete(c.rooms, room)
+	c.mu.Unlock()
+}
@@ -0,0 +1,75 @@
+package cws_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocopper/copper/chttp"
+	"github.com/gocopper/copper/chttp/chttptest"
+	"github.com/gocopper/copper/clifecycle"
+	"github.com/gocopper/copper/clogger"
+	"github.com/gocopper/copper/cws"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger = clogger.NewNoop()
+		lc     = clifecycle.New()
+		hub    = cws.NewHub(cws.NewHubParams{Lifecycle: lc, Logger: logger})
+	)
+
+	server := httptest.NewServer(chttp.NewHandler(chttp.NewHandlerParams{
+		Routers: []chttp.Router{chttptest.NewRouter([]chttp.Route{
+			{
+				Path:    "/ws",
+				Methods: []string{http.MethodGet},
+				Handler: hub.HandlerFunc(cws.Handler{
+					OnConnect: func(c *cws.Conn) {
+						hub.Join(c, "chat")
+					},
+					OnMessage: func(c *cws.Conn, msg []byte) {
+						hub.Broadcast("chat", msg)
+					},
+				}),
+			},
+		})},
+		GlobalMiddlewares: []chttp.Middleware{chttp.NewRequestLoggerMiddleware(logger)},
+		Logger:            logger,
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+	a, _, err := websocket.DefaultDialer.Dial(url, nil) //nolint:bodyclose
+	assert.NoError(t, err)
+
+	b, _, err := websocket.DefaultDialer.Dial(url, nil) //nolint:bodyclose
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return hub.Count() == 2 }, time.Second, time.Millisecond)
+
+	assert.NoError(t, a.WriteMessage(websocket.TextMessage, []byte("hello")))
+
+	for _, conn := range []*websocket.Conn{a, b} {
+		_, msg, err := conn.ReadMessage()
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", string(msg))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.NoError(t, hub.Shutdown(ctx))
+	assert.Equal(t, 0, hub.Count())
+
+	_, _, err = b.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway))
+}
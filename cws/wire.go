@@ -0,0 +1,9 @@
+package cws
+
+import "github.com/google/wire"
+
+// WireModule can be used as part of google/wire setup.
+var WireModule = wire.NewSet( //nolint:gochecknoglobals
+	wire.Struct(new(NewHubParams), "*"),
+	NewHub,
+)
@@ -2,6 +2,7 @@ package copper
 
 import (
 	"flag"
+	"os"
 
 	"github.com/gocopper/copper/cconfig"
 )
@@ -9,16 +10,28 @@ import (
 // Flags holds flag values passed in via command line. These can be used to configure the app environment
 // and override the config directory.
 type Flags struct {
-	ConfigPath cconfig.Path
+	ConfigPath      cconfig.Path
+	EnvPrefix       cconfig.EnvPrefix
+	ConfigOverrides cconfig.FlagOverrides
 }
 
-// NewFlags reads the command line flags and returns Flags with the values set.
+// NewFlags reads the command line flags and returns Flags with the values set. Config values can be overridden with
+// flags named after their keys (ex. --chttp.port=9000), --help-config lists the config keys, --dump-config prints
+// the effective config with secrets redacted, and --config-reference[=json] prints a reference of the config keys.
 func NewFlags() *Flags {
-	var configPath = flag.String("config", "./config/dev.toml", "Path to config file")
+	configOverrides, args := cconfig.ParseFlagOverrides(os.Args[1:])
 
-	flag.Parse()
+	var (
+		configPath = flag.String("config", "./config/dev.toml",
+			"Path to config file, or a directory with a config file for each environment")
+		envPrefix = flag.String("env-prefix", "", "Prefix of the environment variables that override config values")
+	)
+
+	_ = flag.CommandLine.Parse(args)
 
 	return &Flags{
-		ConfigPath: cconfig.Path(*configPath),
+		ConfigPath:      cconfig.Path(*configPath),
+		EnvPrefix:       cconfig.EnvPrefix(*envPrefix),
+		ConfigOverrides: configOverrides,
 	}
 }
@@ -4,6 +4,7 @@
 package copper
 
 import (
+	"github.com/gocopper/copper/cclock"
 	"github.com/gocopper/copper/cconfig"
 	"github.com/gocopper/copper/clifecycle"
 	"github.com/gocopper/copper/clogger"
@@ -14,20 +15,21 @@ import (
 func InitApp() (*App, error) {
 	panic(
 		wire.Build(
-			NewApp,
+			wire.Struct(new(App), "*"),
 			NewFlags,
 			clifecycle.New,
-			cconfig.NewWithKeyOverrides,
+			cconfig.NewWithFlagOverrides,
 			clogger.NewZapLogger,
 			clogger.LoadConfig,
+			cclock.New,
 
-			wire.FieldsOf(new(*Flags), "ConfigPath"),
+			wire.FieldsOf(new(*Flags), "ConfigPath", "EnvPrefix", "ConfigOverrides"),
 		),
 	)
 }
 
 // WireModule can be used as part of google/wire setup to include the app's
-// lifecycle, config, and logger.
+// lifecycle, config, logger, and clock.
 var WireModule = wire.NewSet(
-	wire.FieldsOf(new(*App), "Lifecycle", "Config", "Logger"),
+	wire.FieldsOf(new(*App), "Lifecycle", "Config", "Logger", "Clock"),
 )
@@ -6,6 +6,7 @@
 package copper
 
 import (
+	"github.com/gocopper/copper/cclock"
 	"github.com/gocopper/copper/cconfig"
 	"github.com/gocopper/copper/clifecycle"
 	"github.com/gocopper/copper/clogger"
@@ -19,7 +20,9 @@ func InitApp() (*App, error) {
 	lifecycle := clifecycle.New()
 	flags := NewFlags()
 	path := flags.ConfigPath
-	loader, err := cconfig.NewWithKeyOverrides(path)
+	envPrefix := flags.EnvPrefix
+	flagOverrides := flags.ConfigOverrides
+	loader, err := cconfig.NewWithFlagOverrides(path, envPrefix, flagOverrides)
 	if err != nil {
 		return nil, err
 	}
@@ -31,12 +34,18 @@ func InitApp() (*App, error) {
 	if err != nil {
 		return nil, err
 	}
-	app := NewApp(lifecycle, loader, logger)
+	clock := cclock.New()
+	app := &App{
+		Lifecycle: lifecycle,
+		Config:    loader,
+		Logger:    logger,
+		Clock:     clock,
+	}
 	return app, nil
 }
 
 // wire.go:
 
 // WireModule can be used as part of google/wire setup to include the app's
-// lifecycle, config, and logger.
-var WireModule = wire.NewSet(wire.FieldsOf(new(*App), "Lifecycle", "Config", "Logger"))
+// lifecycle, config, logger, and clock.
+var WireModule = wire.NewSet(wire.FieldsOf(new(*App), "Lifecycle", "Config", "Logger", "Clock"))